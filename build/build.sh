@@ -11,6 +11,7 @@ export GOARCH=amd64
 
 # generate binary assets
 go generate -x "${PKG}/pkg/migrations/"
+go generate -x "${PKG}/pkg/openapi/"
 
 go install \
     -v \
@@ -0,0 +1,387 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+func init() {
+	rootCmd.AddCommand(appKeysCmd)
+	appKeysCmd.AddCommand(appKeysListCmd)
+	appKeysCmd.AddCommand(appKeysRevokeCmd)
+	appKeysCmd.AddCommand(appKeysRotateCmd)
+	appKeysCmd.AddCommand(appKeysExpireCmd)
+	appKeysCmd.AddCommand(appKeysUpdateCmd)
+	appKeysCmd.AddCommand(appKeysQuotaCmd)
+	appKeysCmd.AddCommand(appKeysUsageCmd)
+
+	appKeysCmd.PersistentFlags().StringP("database-url", "d", "", "Connection string for a PostgreSQL instance")
+
+	appKeysRotateCmd.Flags().Duration("overlap", 24*time.Hour, "How long the current key remains valid alongside the new one")
+
+	appKeysExpireCmd.Flags().String("at", "", "The time at which the key expires in RFC3339 format, e.g. 2019-07-01T00:00:00Z")
+	appKeysExpireCmd.Flags().Duration("in", 0, "How long from now the key expires, e.g. 720h")
+	appKeysExpireCmd.Flags().Bool("never", false, "If present remove any expiry from the key")
+
+	appKeysUpdateCmd.Flags().Int("rate", 0, "The number of requests per second the app may make")
+	appKeysUpdateCmd.Flags().Int("burst", 0, "The number of requests the app may make at once, or 0 for twice the rate")
+	appKeysUpdateCmd.Flags().StringSlice("scope", nil, "A comma separated list of scopes replacing those of the app, any of: create-users, delete-users, export-users, manage-apps, audit-log, webhooks, update-locations, metadata, or timeseries")
+
+	appKeysQuotaCmd.Flags().String("scope", "", "The scope the quotas apply to")
+	appKeysQuotaCmd.Flags().Int("per-day", 0, "The number of requests the app may make per day, or 0 for no quota")
+	appKeysQuotaCmd.Flags().Int("per-month", 0, "The number of requests the app may make per month, or 0 for no quota")
+}
+
+var appKeysCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "Manage the api keys of client applications",
+	Long: `This command provides tools for listing client applications, changing their
+rate limits and scopes, and revoking, rotating or expiring their api keys. New
+keys are created with the api-key command.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// bound here rather than in init as other commands also bind these keys
+		for _, name := range []string{"database-url", "overlap", "at", "in", "never", "rate", "burst", "scope", "per-day", "per-month"} {
+			if flag := cmd.Flags().Lookup(name); flag != nil {
+				viper.BindPFlag(name, flag)
+			}
+		}
+	},
+}
+
+var appKeysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List client applications",
+	Long: `This command lists every client application along with its scopes and the
+state of its key.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			apps, err := db.ListApps(ctx)
+			if err != nil {
+				return errors.Wrap(err, "failed to list apps")
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(tw, "UID\tNAME\tSCOPE\tRATE\tBURST\tSTATE\tEXPIRES\tLAST USED")
+
+			for _, app := range apps {
+				fmt.Fprintf(
+					tw,
+					"%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\n",
+					app.UID,
+					app.Name,
+					formatAppScope(app.Roles),
+					app.Rate,
+					app.BurstSize(),
+					appState(&app),
+					formatAppTime(app.ExpiresAt),
+					formatAppTime(app.LastUsedAt),
+				)
+			}
+
+			return tw.Flush()
+		})
+	},
+}
+
+var appKeysRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke the api key of a client application",
+	Long: `This command revokes the api key of a client application, including any
+previous key still valid after a rotation. Revoking can't be undone. The UID of
+the app should be passed via a positional argument.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			app, err := db.RevokeApp(ctx, args[0])
+			if err != nil {
+				return errors.Wrap(err, "failed to revoke app")
+			}
+
+			fmt.Printf("App revoked: %s\n", app.UID)
+
+			return nil
+		})
+	},
+}
+
+var appKeysRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Generate a new api key for a client application",
+	Long: `This command generates a new api key for a client application. The current
+key remains valid for the overlap given, so that the client can switch to the
+new key without downtime. The UID of the app should be passed via a positional
+argument.
+
+For example:
+
+		$ kudzu apps rotate 5b8c1ad5 --overlap 48h`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		overlap := viper.GetDuration("overlap")
+		if overlap < 0 {
+			return errors.New("Overlap must not be negative")
+		}
+
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			app, err := db.RotateApp(ctx, args[0], overlap)
+			if err != nil {
+				return errors.Wrap(err, "failed to rotate app")
+			}
+
+			fmt.Printf("App rotated: key: %s, previous key valid until: %s\n", app.Key, formatAppTime(app.PreviousKeyExpiresAt))
+
+			return nil
+		})
+	},
+}
+
+var appKeysExpireCmd = &cobra.Command{
+	Use:   "expire",
+	Short: "Set when the api key of a client application expires",
+	Long: `This command sets when the api key of a client application expires, either at
+a given time, after a given duration, or never. The UID of the app should be
+passed via a positional argument.
+
+For example:
+
+		$ kudzu apps expire 5b8c1ad5 --in 720h`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var expiresAt null.Time
+
+		at, in, never := viper.GetString("at"), viper.GetDuration("in"), viper.GetBool("never")
+
+		switch {
+		case never && (at != "" || in != 0), at != "" && in != 0:
+			return errors.New("Must provide only one of --at, --in or --never")
+		case at != "":
+			t, err := time.Parse(time.RFC3339, at)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse expiry time")
+			}
+			expiresAt = null.TimeFrom(t)
+		case in != 0:
+			expiresAt = null.TimeFrom(time.Now().Add(in))
+		case !never:
+			return errors.New("Must provide one of --at, --in or --never")
+		}
+
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			app, err := db.SetAppExpiry(ctx, args[0], expiresAt)
+			if err != nil {
+				return errors.Wrap(err, "failed to set app expiry")
+			}
+
+			fmt.Printf("App expiry set: %s expires: %s\n", app.UID, formatAppTime(app.ExpiresAt))
+
+			return nil
+		})
+	},
+}
+
+var appKeysUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Change the rate limit or scopes of a client application",
+	Long: `This command changes the rate limit, burst or scopes of a client application,
+leaving anything not given unchanged. Changes take effect from the app's next
+request. The UID of the app should be passed via a positional argument.
+
+For example:
+
+		$ kudzu apps update 5b8c1ad5 --rate 10 --scope metadata,timeseries`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		update := &postgres.AppUpdate{}
+
+		if cmd.Flags().Changed("rate") {
+			rate := viper.GetInt("rate")
+			update.Rate = &rate
+		}
+
+		if cmd.Flags().Changed("burst") {
+			burst := null.NewInt(int64(viper.GetInt("burst")), viper.GetInt("burst") != 0)
+			update.Burst = &burst
+		}
+
+		if cmd.Flags().Changed("scope") {
+			update.Scope = postgres.ScopeClaims{}
+			for _, s := range viper.GetStringSlice("scope") {
+				update.Scope = append(update.Scope, postgres.ScopeClaim(s))
+			}
+		}
+
+		if update.Rate == nil && update.Burst == nil && update.Scope == nil {
+			return errors.New("Must provide at least one of --rate, --burst or --scope")
+		}
+
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			app, err := db.UpdateApp(ctx, args[0], update)
+			if err != nil {
+				return errors.Wrap(err, "failed to update app")
+			}
+
+			fmt.Printf("App updated: %s rate: %d burst: %d scope: %s\n", app.UID, app.Rate, app.BurstSize(), formatAppScope(app.Roles))
+
+			return nil
+		})
+	},
+}
+
+var appKeysQuotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Set the daily or monthly quotas of a client application",
+	Long: `This command sets the number of requests a client application may make per day
+or per month to routes requiring a scope, with days and months in UTC. Passing
+0 removes a quota, and quotas not given are left unchanged. The UID of the app
+should be passed via a positional argument.
+
+For example:
+
+		$ kudzu apps quota 5b8c1ad5 --scope timeseries --per-day 10000`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope := postgres.ScopeClaim(viper.GetString("scope"))
+		if scope == "" {
+			return errors.New("Must provide a scope")
+		}
+
+		quotas := []postgres.AppQuota{}
+
+		if cmd.Flags().Changed("per-day") {
+			quotas = append(quotas, postgres.AppQuota{Scope: scope, Period: postgres.DailyQuota, Limit: viper.GetInt("per-day")})
+		}
+
+		if cmd.Flags().Changed("per-month") {
+			quotas = append(quotas, postgres.AppQuota{Scope: scope, Period: postgres.MonthlyQuota, Limit: viper.GetInt("per-month")})
+		}
+
+		if len(quotas) == 0 {
+			return errors.New("Must provide at least one of --per-day or --per-month")
+		}
+
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			quotas, err := db.SetAppQuotas(ctx, args[0], quotas)
+			if err != nil {
+				return errors.Wrap(err, "failed to set app quotas")
+			}
+
+			fmt.Printf("App quotas set: %s\n", args[0])
+			for _, quota := range quotas {
+				fmt.Printf("  %s: %d per %s\n", quota.Scope, quota.Limit, quota.Period)
+			}
+
+			return nil
+		})
+	},
+}
+
+var appKeysUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show the usage of a client application",
+	Long: `This command shows the number of requests a client application has made today
+and this month for each scope, along with any quotas. The UID of the app should
+be passed via a positional argument.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			usage, err := db.GetAppUsage(ctx, args[0], time.Now())
+			if err != nil {
+				return errors.Wrap(err, "failed to get app usage")
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(tw, "SCOPE\tTODAY\tDAILY QUOTA\tTHIS MONTH\tMONTHLY QUOTA")
+
+			for _, u := range usage {
+				fmt.Fprintf(
+					tw,
+					"%s\t%d\t%s\t%d\t%s\n",
+					u.Scope,
+					u.Today,
+					formatQuota(u.DailyQuota),
+					u.ThisMonth,
+					formatQuota(u.MonthlyQuota),
+				)
+			}
+
+			return tw.Flush()
+		})
+	},
+}
+
+// withAppsDB starts the database, calls fn and stops the database again
+func withAppsDB(fn func(context.Context, *postgres.DB) error) error {
+	databaseURL := viper.GetString("database-url")
+	if databaseURL == "" {
+		return errors.New("Must provide a database url")
+	}
+
+	log := logger.NewLogger()
+
+	db := postgres.NewDB(databaseURL, viper.GetBool("verbose"))
+
+	err := db.Start()
+	if err != nil {
+		return errors.Wrap(err, "failed to start db")
+	}
+	defer db.Stop()
+
+	return fn(logger.ToContext(context.Background(), log), db)
+}
+
+// appState returns a short description of the state of an app's key
+func appState(app *postgres.App) string {
+	switch {
+	case app.RevokedAt.Valid:
+		return "revoked"
+	case app.ExpiresAt.Valid && !app.ExpiresAt.Time.After(time.Now()):
+		return "expired"
+	case app.PreviousKeyExpiresAt.Valid && app.PreviousKeyExpiresAt.Time.After(time.Now()):
+		return "rotating"
+	default:
+		return "active"
+	}
+}
+
+// formatAppTime formats an optional time for display, showing a dash if it is
+// not set
+func formatAppTime(t null.Time) string {
+	if !t.Valid {
+		return "-"
+	}
+
+	return t.Time.UTC().Format(time.RFC3339)
+}
+
+// formatAppScope formats the scope claims of an app for display
+func formatAppScope(claims postgres.ScopeClaims) string {
+	scope := []string{}
+	for _, claim := range claims {
+		scope = append(scope, string(claim))
+	}
+
+	return strings.Join(scope, ",")
+}
+
+// formatQuota formats an optional quota for display, showing a dash if there
+// is no quota
+func formatQuota(quota null.Int) string {
+	if !quota.Valid {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d", quota.Int64)
+}
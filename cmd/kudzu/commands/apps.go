@@ -17,18 +17,25 @@ func init() {
 
 	appsCmd.Flags().StringP("database-url", "d", "", "Connection string for a PostgreSQL instance")
 	appsCmd.Flags().StringP("name", "n", "", "The name of the client application")
-	appsCmd.Flags().StringSlice("scope", []string{"timeseries"}, "A comma separated list of scopes, one of: create-users, metadata, or timeseries")
+	appsCmd.Flags().StringSlice("scope", []string{"timeseries"}, "A comma separated list of scopes, any of: create-users, delete-users, export-users, manage-apps, audit-log, webhooks, update-locations, metadata, or timeseries")
 
 	viper.BindPFlag("database-url", appsCmd.Flags().Lookup("database-url"))
 	viper.BindPFlag("name", appsCmd.Flags().Lookup("name"))
+	appsCmd.Flags().Int("rate", 0, "The number of requests per second the app may make, defaults to 4")
+	appsCmd.Flags().Int("burst", 0, "The number of requests the app may make at once, defaults to twice the rate")
+
 	viper.BindPFlag("scope", appsCmd.Flags().Lookup("scope"))
+	viper.BindPFlag("rate", appsCmd.Flags().Lookup("rate"))
+	viper.BindPFlag("burst", appsCmd.Flags().Lookup("burst"))
 }
 
 var appsCmd = &cobra.Command{
 	Use:   "api-key",
 	Short: "Create new api keys for client applications",
 	Long: `This command allows new api keys to be created for client applications. The
-available scopes are: create-users, metadata or timeseries.`,
+available scopes are: create-users, delete-users, export-users, manage-apps,
+audit-log, webhooks, update-locations, metadata or timeseries. The rate limit, scopes and expiry of existing apps can be
+changed with the apps command.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		databaseURL := viper.GetString("database-url")
 		if databaseURL == "" {
@@ -63,7 +70,7 @@ available scopes are: create-users, metadata or timeseries.`,
 			claims = append(claims, postgres.ScopeClaim(s))
 		}
 
-		app, err := db.CreateApp(ctx, name, claims)
+		app, err := db.CreateApp(ctx, name, claims, viper.GetInt("rate"), viper.GetInt("burst"))
 		if err != nil {
 			return errors.Wrap(err, "failed to create app")
 		}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().StringP("database-url", "d", "", "Connection string for a PostgreSQL instance")
+	auditCmd.Flags().String("app", "", "Only show requests made by the app with this UID")
+	auditCmd.Flags().String("target", "", "Only show requests that acted on this target, e.g. user:5b8c1ad5 or location:2pxqk4")
+	auditCmd.Flags().String("route", "", "Only show requests to this route, e.g. /user/delete or /apps/:uid/revoke")
+	auditCmd.Flags().Duration("since", 0, "Only show requests made within this long of now, e.g. 24h, or 0 for no limit")
+	auditCmd.Flags().Int64("before", 0, "Only show requests older than the record with this id, for paging through results")
+	auditCmd.Flags().Int("limit", 100, fmt.Sprintf("The number of records to show, at most %d", postgres.MaxAuditRecords))
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Search the audit log",
+	Long: `This command searches the log of requests made by client applications that
+changed, or attempted to change, something such as a user, a location or an
+app. The most recent matching requests are shown first.
+
+For example, to find which app deleted a user:
+
+		$ kudzu audit --target user:5b8c1ad5 --route /user/delete`,
+	Args: cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// bound here rather than in init as other commands also bind these keys
+		for _, name := range []string{"database-url", "app", "target", "route", "since", "before", "limit"} {
+			viper.BindPFlag(name, cmd.Flags().Lookup(name))
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := &postgres.AuditFilter{
+			AppUID: viper.GetString("app"),
+			Target: viper.GetString("target"),
+			Route:  viper.GetString("route"),
+			Before: viper.GetInt64("before"),
+			Limit:  viper.GetInt("limit"),
+		}
+
+		if filter.Limit <= 0 || filter.Limit > postgres.MaxAuditRecords {
+			return errors.Errorf("Limit must be between 1 and %d", postgres.MaxAuditRecords)
+		}
+
+		since := viper.GetDuration("since")
+		if since < 0 {
+			return errors.New("Since must not be negative")
+		}
+
+		if since > 0 {
+			filter.Since = null.TimeFrom(time.Now().Add(-since))
+		}
+
+		return withAppsDB(func(ctx context.Context, db *postgres.DB) error {
+			records, err := db.ListAuditRecords(ctx, filter)
+			if err != nil {
+				return errors.Wrap(err, "failed to search audit log")
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tTIME\tAPP\tREQUEST ID\tMETHOD\tROUTE\tTARGETS\tSTATUS")
+
+			for _, record := range records {
+				fmt.Fprintf(
+					tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+					record.ID,
+					record.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+					orDash(record.AppUID),
+					orDash(record.RequestID),
+					record.Method,
+					record.Route,
+					orDash(strings.Join(record.Targets, ",")),
+					record.Status,
+				)
+			}
+
+			return tw.Flush()
+		})
+	},
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+func init() {
+	rootCmd.AddCommand(locationsCmd)
+	locationsCmd.AddCommand(locationsHistoryCmd)
+
+	locationsCmd.PersistentFlags().StringP("database-url", "d", "", "Connection string for a PostgreSQL instance")
+}
+
+var locationsCmd = &cobra.Command{
+	Use:   "locations",
+	Short: "Inspect device locations",
+	Long: `This command provides tools for inspecting the locations of devices stored
+in Postgres.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// bound here rather than in init as other commands also bind this key
+		viper.BindPFlag("database-url", cmd.Flags().Lookup("database-url"))
+	},
+}
+
+var locationsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the coordinate history of a device",
+	Long: `This command prints every recorded change to the coordinates of a device,
+including when the change happened and the UID of the app that made it. The
+UID of the device should be passed via a positional argument.
+
+For example:
+
+		$ kudzu locations history 2pxqk4`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		databaseURL := viper.GetString("database-url")
+		if databaseURL == "" {
+			return errors.New("Must provide a database url")
+		}
+
+		verbose := viper.GetBool("verbose")
+
+		log := logger.NewLogger()
+
+		db := postgres.NewDB(databaseURL, verbose)
+
+		err := db.Start()
+		if err != nil {
+			return errors.Wrap(err, "failed to start db")
+		}
+		defer db.Stop()
+
+		ctx := logger.ToContext(context.Background(), log)
+
+		changes, err := db.GetLocationHistory(ctx, args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to read location history")
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "CHANGED AT\tAPP\tPREVIOUS X\tPREVIOUS Y\tX\tY")
+
+		for _, c := range changes {
+			fmt.Fprintf(
+				w, "%s\t%s\t%s\t%s\t%v\t%v\n",
+				c.InsertedAt.UTC().Format("2006-01-02 15:04:05"),
+				orDash(c.AppUID.String),
+				formatNullFloat(c.PreviousLongitude),
+				formatNullFloat(c.PreviousLatitude),
+				c.NewLongitude,
+				c.NewLatitude,
+			)
+		}
+
+		return w.Flush()
+	},
+}
+
+// orDash returns the given string, or a dash if it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatNullFloat returns the float as a string, or a dash if it is null
+func formatNullFloat(f null.Float) string {
+	if !f.Valid {
+		return "-"
+	}
+	return strconv.FormatFloat(f.Float64, 'f', -1, 64)
+}
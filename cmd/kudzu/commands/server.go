@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/thingful/kudzu/pkg/app"
+	"github.com/thingful/kudzu/pkg/http"
 )
 
 func init() {
@@ -23,6 +24,10 @@ func init() {
 	serverCmd.Flags().Int("concurrency", 3, "The number of parallel go routines to spawn when fetching from Thingful")
 	serverCmd.Flags().Bool("no-indexer", false, "If present stop the indexer from running")
 	serverCmd.Flags().Int("server-timeout", 5, "HTTP server timeout in seconds")
+	serverCmd.Flags().Bool("validate-requests", false, "If present validate request bodies against the OpenAPI document")
+	serverCmd.Flags().String("rate-limiter", http.MemoryRateLimiter, "Where rate limits are tracked, either memory for each replica separately, or postgres to share limits across replicas")
+	serverCmd.Flags().Duration("app-cache-ttl", 30*time.Second, "How long verified API keys are cached for, or 0 to verify keys against the database on every request")
+	serverCmd.Flags().Duration("audit-retention", 365*24*time.Hour, "How long records are kept in the audit log, or 0 to keep them forever")
 
 	viper.BindPFlag("addr", serverCmd.Flags().Lookup("addr"))
 	viper.BindPFlag("database-url", serverCmd.Flags().Lookup("database-url"))
@@ -33,6 +38,10 @@ func init() {
 	viper.BindPFlag("concurrency", serverCmd.Flags().Lookup("concurrency"))
 	viper.BindPFlag("no-indexer", serverCmd.Flags().Lookup("no-indexer"))
 	viper.BindPFlag("server-timeout", serverCmd.Flags().Lookup("server-timeout"))
+	viper.BindPFlag("validate-requests", serverCmd.Flags().Lookup("validate-requests"))
+	viper.BindPFlag("rate-limiter", serverCmd.Flags().Lookup("rate-limiter"))
+	viper.BindPFlag("app-cache-ttl", serverCmd.Flags().Lookup("app-cache-ttl"))
+	viper.BindPFlag("audit-retention", serverCmd.Flags().Lookup("audit-retention"))
 }
 
 var serverCmd = &cobra.Command{
@@ -77,6 +86,21 @@ var serverCmd = &cobra.Command{
 			return errors.New("Must specify the Thingful API key")
 		}
 
+		rateLimiter := viper.GetString("rate-limiter")
+		if rateLimiter != http.MemoryRateLimiter && rateLimiter != http.PostgresRateLimiter {
+			return errors.New("Rate limiter must be one of memory or postgres")
+		}
+
+		appCacheTTL := viper.GetDuration("app-cache-ttl")
+		if appCacheTTL < 0 {
+			return errors.New("App cache TTL must not be negative")
+		}
+
+		auditRetention := viper.GetDuration("audit-retention")
+		if auditRetention < 0 {
+			return errors.New("Audit retention must not be negative")
+		}
+
 		e := backoff.ExecuteFunc(func(_ context.Context) error {
 			a := app.NewApp(&app.Config{
 				Addr:          addr,
@@ -89,6 +113,11 @@ var serverCmd = &cobra.Command{
 				Concurrency:   viper.GetInt("concurrency"),
 				NoIndexer:     viper.GetBool("no-indexer"),
 				ServerTimeout: serverTimeout,
+
+				ValidateRequests: viper.GetBool("validate-requests"),
+				RateLimiter:      rateLimiter,
+				AppCacheTTL:      appCacheTTL,
+				AuditRetention:   auditRetention,
 			})
 
 			return a.Start()
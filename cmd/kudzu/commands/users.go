@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/thingful/kudzu/pkg/client"
+	"github.com/thingful/kudzu/pkg/export"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/thingful"
+)
+
+func init() {
+	rootCmd.AddCommand(usersCmd)
+	usersCmd.AddCommand(usersExportCmd)
+
+	usersCmd.PersistentFlags().StringP("database-url", "d", "", "Connection string for a PostgreSQL instance")
+
+	usersExportCmd.Flags().StringP("output", "o", "", "Path of the zip file to write, defaults to kudzu-export-<uid>.zip")
+	usersExportCmd.Flags().Bool("observations", false, "If present include every observation of the user's things read from Thingful")
+	usersExportCmd.Flags().String("thingful-url", "https://api.thingful.net", "The server URL at which the Thingful API is available")
+	usersExportCmd.Flags().String("thingful-key", "", "A valid Thingful API key, required to include observations")
+	usersExportCmd.Flags().Int("client-timeout", 10, "HTTP client timeout in seconds")
+	usersExportCmd.Flags().Int("concurrency", 3, "The number of parallel go routines to spawn when fetching from Thingful")
+}
+
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Manage registered users",
+	Long: `This command provides tools for managing the users registered with kudzu and
+the data we hold about them.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// bound here rather than in init as other commands also bind these keys
+		for _, name := range []string{"database-url", "output", "observations", "thingful-url", "thingful-key", "client-timeout", "concurrency"} {
+			if flag := cmd.Flags().Lookup(name); flag != nil {
+				viper.BindPFlag(name, flag)
+			}
+		}
+	},
+}
+
+var usersExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export everything held about a user",
+	Long: `This command writes a zip archive of everything we hold about a user, for
+answering subject access requests. The archive contains the data as a single
+JSON document, along with a CSV file per type of record. Access and refresh
+tokens are never exported. The UID of the user should be passed via a
+positional argument.
+
+Observations are only included when the --observations flag is given, as they
+are read back from Thingful which for users with a long history is slow.
+
+For example:
+
+		$ kudzu users export 5b8c1ad5 --observations --thingful-key abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		databaseURL := viper.GetString("database-url")
+		if databaseURL == "" {
+			return errors.New("Must provide a database url")
+		}
+
+		withObservations := viper.GetBool("observations")
+
+		thingfulKey := viper.GetString("thingful-key")
+		if withObservations && thingfulKey == "" {
+			return errors.New("Must provide a thingful key to export observations")
+		}
+
+		output := viper.GetString("output")
+		if output == "" {
+			output = fmt.Sprintf("kudzu-export-%s.zip", args[0])
+		}
+
+		verbose := viper.GetBool("verbose")
+
+		log := logger.NewLogger()
+
+		db := postgres.NewDB(databaseURL, verbose)
+
+		err := db.Start()
+		if err != nil {
+			return errors.Wrap(err, "failed to start db")
+		}
+		defer db.Stop()
+
+		cl := client.NewClient(viper.GetInt("client-timeout"), verbose)
+		th := thingful.NewClient(cl, viper.GetString("thingful-url"), thingfulKey, verbose, viper.GetInt("concurrency"))
+
+		ctx := logger.ToContext(context.Background(), log)
+
+		archive, err := export.NewExporter(db, th).Export(ctx, args[0], withObservations)
+		if err != nil {
+			return errors.Wrap(err, "failed to export user")
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return errors.Wrap(err, "failed to create output file")
+		}
+		defer f.Close()
+
+		err = archive.WriteZip(f)
+		if err != nil {
+			return errors.Wrap(err, "failed to write archive")
+		}
+
+		fmt.Printf("Wrote export of %d things to %s\n", len(archive.Things), output)
+
+		return nil
+	},
+}
@@ -0,0 +1,220 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+const (
+	// maxNotificationAge is how old a reading can be for a change of state it
+	// causes to be notified. When indexing a new thing we work through its
+	// history, which updates the state of its alerts without notifying about
+	// things that happened long ago.
+	maxNotificationAge = 24 * time.Hour
+)
+
+// Transition describes how the state of an alert changed after a reading
+type Transition int
+
+const (
+	// NoTransition means the alert didn't start or stop firing
+	NoTransition Transition = iota
+
+	// Fired means the alert started firing
+	Fired
+
+	// Resolved means a firing alert was resolved
+	Resolved
+)
+
+// Apply updates the alert with the value of the rule's channel recorded at the
+// given time. A breach moves the alert into the pending state, and once the
+// rule has been breached for its duration the alert fires. A value that
+// doesn't breach the rule resolves the alert.
+func Apply(rule *postgres.AlertRule, alert *postgres.Alert, value float64, at time.Time) Transition {
+	alert.Value = value
+
+	if !rule.Breached(value) {
+		switch alert.State {
+		case postgres.AlertStateFiring:
+			alert.State = postgres.AlertStateResolved
+			alert.PendingSince.Valid = false
+			alert.ResolvedAt.SetValid(at)
+			return Resolved
+		case postgres.AlertStatePending:
+			// resolved before it fired, so there is nothing to announce
+			alert.State = postgres.AlertStateResolved
+			alert.PendingSince.Valid = false
+		}
+		return NoTransition
+	}
+
+	if alert.State == postgres.AlertStateFiring {
+		return NoTransition
+	}
+
+	if alert.State != postgres.AlertStatePending {
+		alert.State = postgres.AlertStatePending
+		alert.PendingSince.SetValid(at)
+	}
+
+	if at.Sub(alert.PendingSince.Time) < time.Duration(rule.Duration)*time.Second {
+		return NoTransition
+	}
+
+	alert.State = postgres.AlertStateFiring
+	alert.FiredAt.SetValid(at)
+	alert.ResolvedAt.Valid = false
+
+	return Fired
+}
+
+// shouldNotify returns true if the transition of the alert at the given time
+// should be sent to the notifier. Alerts only notify that they are firing once
+// per cooldown period, and only notify that they are resolved if the matching
+// firing notification was sent.
+func shouldNotify(rule *postgres.AlertRule, alert *postgres.Alert, transition Transition, at time.Time, now time.Time) bool {
+	if now.Sub(at) > maxNotificationAge {
+		return false
+	}
+
+	switch transition {
+	case Fired:
+		if !alert.NotifiedAt.Valid {
+			return true
+		}
+		return at.Sub(alert.NotifiedAt.Time) >= time.Duration(rule.Cooldown)*time.Second
+	case Resolved:
+		return alert.NotifiedAt.Valid && !alert.NotifiedAt.Time.Before(alert.FiredAt.Time)
+	default:
+		return false
+	}
+}
+
+// Evaluator applies alert rules to readings as they are indexed, keeping the
+// state of alerts in Postgres and sending notifications when alerts fire or
+// are resolved
+type Evaluator struct {
+	db       *postgres.DB
+	notifier Notifier
+}
+
+// NewEvaluator returns a new Evaluator that sends notifications via the given
+// notifier
+func NewEvaluator(db *postgres.DB, notifier Notifier) *Evaluator {
+	return &Evaluator{
+		db:       db,
+		notifier: notifier,
+	}
+}
+
+// Evaluate applies every rule for the thing to the given readings in the order
+// they were recorded
+func (e *Evaluator) Evaluate(ctx context.Context, thing *postgres.Thing, readings []flowerpower.Reading) error {
+	if len(readings) == 0 {
+		return nil
+	}
+
+	rules, err := e.db.GetAlertRulesForThing(ctx, thing.ID, thing.OwnerID.Int64)
+	if err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	sorted := make([]flowerpower.Reading, len(readings))
+	copy(sorted, readings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	for i := range rules {
+		err = e.evaluateRule(ctx, &rules[i], thing, sorted)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evaluateRule applies a single rule to the sorted readings, saving the new
+// state of the alert before notifying of any transitions
+func (e *Evaluator) evaluateRule(ctx context.Context, rule *postgres.AlertRule, thing *postgres.Thing, readings []flowerpower.Reading) error {
+	alert, err := e.db.GetAlert(ctx, rule.ID, thing.ID)
+	if err != nil {
+		if errors.Cause(err) != sql.ErrNoRows {
+			return err
+		}
+
+		alert = &postgres.Alert{
+			RuleID:  rule.ID,
+			ThingID: thing.ID,
+			State:   postgres.AlertStateResolved,
+		}
+	}
+
+	alert.RuleUID = rule.UID
+	alert.ThingUID = thing.UID.String
+
+	stored := alert.ID != 0
+	now := time.Now()
+	notifications := []*Notification{}
+
+	for _, reading := range readings {
+		value, ok := reading.Value(rule.Channel)
+		if !ok {
+			continue
+		}
+
+		at := reading.Timestamp.UTC()
+
+		transition := Apply(rule, alert, value, at)
+		if transition == NoTransition {
+			continue
+		}
+
+		if shouldNotify(rule, alert, transition, at, now) {
+			if transition == Fired {
+				alert.NotifiedAt.SetValid(at)
+			}
+
+			snapshot := *alert
+			notifications = append(notifications, &Notification{
+				Transition: transition,
+				Rule:       rule,
+				Alert:      &snapshot,
+			})
+		}
+	}
+
+	// alerts that have never been breached aren't stored
+	if !stored && alert.State == postgres.AlertStateResolved && !alert.FiredAt.Valid {
+		return nil
+	}
+
+	err = e.db.SaveAlert(ctx, alert)
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range notifications {
+		err = e.notifier.Notify(ctx, notification)
+		if err != nil {
+			log := logger.FromContext(ctx)
+			log.Log("msg", "failed to send alert notification", "ruleUID", rule.UID, "err", err)
+		}
+	}
+
+	return nil
+}
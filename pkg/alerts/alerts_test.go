@@ -0,0 +1,91 @@
+package alerts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/alerts"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+func TestApply(t *testing.T) {
+	start := time.Date(2019, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	type step struct {
+		offset     time.Duration
+		value      float64
+		transition alerts.Transition
+		state      string
+	}
+
+	testcases := []struct {
+		label string
+		rule  postgres.AlertRule
+		steps []step
+	}{
+		{
+			label: "fires immediately without a duration",
+			rule:  postgres.AlertRule{Comparator: postgres.LessThan, Threshold: 20},
+			steps: []step{
+				{0, 25, alerts.NoTransition, postgres.AlertStateResolved},
+				{time.Minute, 15, alerts.Fired, postgres.AlertStateFiring},
+				{2 * time.Minute, 10, alerts.NoTransition, postgres.AlertStateFiring},
+				{3 * time.Minute, 20, alerts.Resolved, postgres.AlertStateResolved},
+			},
+		},
+		{
+			label: "waits for the duration before firing",
+			rule:  postgres.AlertRule{Comparator: postgres.GreaterThanOrEqual, Threshold: 30, Duration: 3600},
+			steps: []step{
+				{0, 30, alerts.NoTransition, postgres.AlertStatePending},
+				{30 * time.Minute, 35, alerts.NoTransition, postgres.AlertStatePending},
+				{time.Hour, 31, alerts.Fired, postgres.AlertStateFiring},
+				{2 * time.Hour, 29, alerts.Resolved, postgres.AlertStateResolved},
+			},
+		},
+		{
+			label: "pending alerts resolve without firing",
+			rule:  postgres.AlertRule{Comparator: postgres.LessThanOrEqual, Threshold: 10, Duration: 3600},
+			steps: []step{
+				{0, 5, alerts.NoTransition, postgres.AlertStatePending},
+				{30 * time.Minute, 12, alerts.NoTransition, postgres.AlertStateResolved},
+				{45 * time.Minute, 8, alerts.NoTransition, postgres.AlertStatePending},
+				{105 * time.Minute, 8, alerts.Fired, postgres.AlertStateFiring},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			alert := &postgres.Alert{State: postgres.AlertStateResolved}
+
+			for _, s := range tc.steps {
+				transition := alerts.Apply(&tc.rule, alert, s.value, start.Add(s.offset))
+				assert.Equal(t, s.transition, transition)
+				assert.Equal(t, s.state, alert.State)
+				assert.Equal(t, s.value, alert.Value)
+			}
+		})
+	}
+}
+
+func TestApplyRecordsTimes(t *testing.T) {
+	rule := &postgres.AlertRule{Comparator: postgres.GreaterThan, Threshold: 0, Duration: 60}
+	alert := &postgres.Alert{State: postgres.AlertStateResolved}
+
+	first := time.Date(2019, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	alerts.Apply(rule, alert, 1, first)
+	assert.Equal(t, first, alert.PendingSince.Time)
+	assert.False(t, alert.FiredAt.Valid)
+
+	alerts.Apply(rule, alert, 1, first.Add(time.Minute))
+	assert.Equal(t, first.Add(time.Minute), alert.FiredAt.Time)
+
+	alerts.Apply(rule, alert, 0, first.Add(time.Hour))
+	assert.Equal(t, first.Add(time.Hour), alert.ResolvedAt.Time)
+	assert.False(t, alert.PendingSince.Valid)
+	assert.Equal(t, first.Add(time.Minute), alert.FiredAt.Time)
+}
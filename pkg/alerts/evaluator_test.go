@@ -0,0 +1,115 @@
+package alerts_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/alerts"
+	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+// recordingNotifier is a Notifier that keeps the notifications it is sent
+type recordingNotifier struct {
+	notifications []*alerts.Notification
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n *alerts.Notification) error {
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+type EvaluatorSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *EvaluatorSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *EvaluatorSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *EvaluatorSuite) TestEvaluate() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ('user1') RETURNING id`)
+	assert.Nil(s.T(), err)
+
+	thing := &postgres.Thing{
+		UID:        null.StringFrom("abc123"),
+		OwnerID:    null.IntFrom(userID),
+		Provider:   null.StringFrom("parrot"),
+		SerialNum:  "PA123",
+		LocationID: "abc123",
+	}
+
+	err = s.db.CreateThing(ctx, thing)
+	assert.Nil(s.T(), err)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.CreateAlertRule(ctx, app.UID, &postgres.AlertRule{
+		UserUID:    null.StringFrom("user1"),
+		Channel:    "soil_moisture",
+		Comparator: postgres.LessThan,
+		Threshold:  15,
+		Duration:   1800,
+		Cooldown:   86400,
+	})
+	assert.Nil(s.T(), err)
+
+	notifier := &recordingNotifier{}
+	evaluator := alerts.NewEvaluator(s.db, notifier)
+
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	// readings are applied in the order they were recorded
+	err = evaluator.Evaluate(ctx, thing, []flowerpower.Reading{
+		{Timestamp: now.Add(-30 * time.Minute), SoilMoisture: 10},
+		{Timestamp: now.Add(-60 * time.Minute), SoilMoisture: 12},
+		{Timestamp: now.Add(-90 * time.Minute), SoilMoisture: 20},
+	})
+	assert.Nil(s.T(), err)
+
+	assert.Len(s.T(), notifier.notifications, 1)
+	assert.Equal(s.T(), alerts.Fired, notifier.notifications[0].Transition)
+	assert.Equal(s.T(), 10.0, notifier.notifications[0].Alert.Value)
+
+	firing, err := s.db.ListAlerts(ctx, app.UID, postgres.AlertStateFiring)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), firing, 1)
+
+	// resolving and firing again within the cooldown only notifies once more
+	err = evaluator.Evaluate(ctx, thing, []flowerpower.Reading{
+		{Timestamp: now.Add(-20 * time.Minute), SoilMoisture: 30},
+		{Timestamp: now.Add(-10 * time.Minute), SoilMoisture: 5},
+		{Timestamp: now.Add(30 * time.Minute), SoilMoisture: 5},
+	})
+	assert.Nil(s.T(), err)
+
+	assert.Len(s.T(), notifier.notifications, 2)
+	assert.Equal(s.T(), alerts.Resolved, notifier.notifications[1].Transition)
+}
+
+func TestEvaluatorSuite(t *testing.T) {
+	suite.Run(t, new(EvaluatorSuite))
+}
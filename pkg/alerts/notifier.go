@@ -0,0 +1,67 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/guregu/null"
+
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// Notification is sent when an alert fires or is resolved
+type Notification struct {
+	Transition Transition
+	Rule       *postgres.AlertRule
+	Alert      *postgres.Alert
+}
+
+// Notifier is the interface we expect for a type that can tell the app that
+// created a rule about changes to its alerts
+type Notifier interface {
+	Notify(context.Context, *Notification) error
+}
+
+// AlertEventData is the data sent with alert webhook events
+type AlertEventData struct {
+	RuleUID    string    `json:"ruleUid"`
+	ThingUID   string    `json:"thingUid"`
+	Channel    string    `json:"channel"`
+	Comparator string    `json:"comparator"`
+	Threshold  float64   `json:"threshold"`
+	Value      float64   `json:"value"`
+	State      string    `json:"state"`
+	FiredAt    null.Time `json:"firedAt"`
+	ResolvedAt null.Time `json:"resolvedAt"`
+}
+
+// WebhookNotifier is a Notifier that sends alert.firing and alert.resolved
+// events to the webhooks of the app that created the rule
+type WebhookNotifier struct {
+	db *postgres.DB
+}
+
+// NewWebhookNotifier returns a new WebhookNotifier
+func NewWebhookNotifier(db *postgres.DB) *WebhookNotifier {
+	return &WebhookNotifier{db: db}
+}
+
+// Notify publishes the notification to the app's webhooks subscribed to the
+// matching event
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	event := postgres.AlertFiringEvent
+	if n.Transition == Resolved {
+		event = postgres.AlertResolvedEvent
+	}
+
+	return w.db.PublishAppWebhookEvent(ctx, n.Rule.AppID, event, &AlertEventData{
+		RuleUID:    n.Rule.UID,
+		ThingUID:   n.Alert.ThingUID,
+		Channel:    n.Rule.Channel,
+		Comparator: n.Rule.Comparator,
+		Threshold:  n.Rule.Threshold,
+		Value:      n.Alert.Value,
+		State:      n.Alert.State,
+		FiredAt:    n.Alert.FiredAt,
+		ResolvedAt: n.Alert.ResolvedAt,
+	})
+}
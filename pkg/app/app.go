@@ -12,8 +12,10 @@ import (
 	"github.com/thingful/kudzu/pkg/client"
 	"github.com/thingful/kudzu/pkg/http"
 	"github.com/thingful/kudzu/pkg/indexer"
+	"github.com/thingful/kudzu/pkg/jobs"
 	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/stream"
 	"github.com/thingful/kudzu/pkg/thingful"
 	"github.com/thingful/kudzu/pkg/version"
 	registry "github.com/thingful/retryable-registry-prometheus"
@@ -73,6 +75,22 @@ type Config struct {
 	Concurrency   int
 	NoIndexer     bool
 	ServerTimeout int
+
+	// ValidateRequests enables validation of incoming request bodies against
+	// our OpenAPI document
+	ValidateRequests bool
+
+	// RateLimiter is the rate limiter backend to use, one of memory or
+	// postgres
+	RateLimiter string
+
+	// AppCacheTTL is how long verified api keys are cached for, or zero to
+	// disable caching
+	AppCacheTTL time.Duration
+
+	// AuditRetention is how long records are kept in the audit log, or zero to
+	// keep them forever
+	AuditRetention time.Duration
 }
 
 // NewApp returns a new App instance with components configured but not yet
@@ -89,6 +107,10 @@ func NewApp(config *Config) *App {
 		"concurrency", config.Concurrency,
 		"noIndexer", config.NoIndexer,
 		"serverTimeout", config.ServerTimeout,
+		"validateRequests", config.ValidateRequests,
+		"rateLimiter", config.RateLimiter,
+		"appCacheTTL", config.AppCacheTTL,
+		"auditRetention", config.AuditRetention,
 	)
 
 	buildInfo.WithLabelValues(version.BinaryName, version.Version, version.BuildDate)
@@ -113,6 +135,26 @@ func NewApp(config *Config) *App {
 		NoIndexer: config.NoIndexer,
 	}, logger)
 
+	w := jobs.NewWorker(&jobs.Config{
+		DB:        db,
+		QuitChan:  quitChan,
+		WaitGroup: &wg,
+		Delay:     time.Duration(config.Delay) * time.Second,
+		Verbose:   config.Verbose,
+	}, logger)
+
+	w.Register(postgres.SyncThingQueue, jobs.NewSyncThingHandler(db, th))
+	w.Register(postgres.WebhookDeliveryQueue, jobs.NewDeliverWebhookHandler(db, client.NewPublicClient(config.ClientTimeout, config.Verbose).Client))
+	w.Register(postgres.UserDeletionQueue, jobs.NewDeleteUserHandler(db, th))
+
+	b := stream.NewBroker(&stream.Config{
+		DB:        db,
+		QuitChan:  quitChan,
+		ErrChan:   errChan,
+		WaitGroup: &wg,
+		Verbose:   config.Verbose,
+	}, logger)
+
 	h := http.NewHTTP(&http.Config{
 		DB:            db,
 		Client:        cl,
@@ -122,8 +164,14 @@ func NewApp(config *Config) *App {
 		ErrChan:       errChan,
 		WaitGroup:     &wg,
 		Indexer:       i,
+		Broker:        b,
 		ServerTimeout: config.ServerTimeout,
 		Verbose:       config.Verbose,
+
+		ValidateRequests: config.ValidateRequests,
+		RateLimiter:      config.RateLimiter,
+		AppCacheTTL:      config.AppCacheTTL,
+		AuditRetention:   config.AuditRetention,
 	}, logger)
 
 	return &App{
@@ -131,6 +179,8 @@ func NewApp(config *Config) *App {
 		http:    h,
 		db:      db,
 		indexer: i,
+		worker:  w,
+		broker:  b,
 
 		quitChan: quitChan,
 		errChan:  errChan,
@@ -146,6 +196,8 @@ type App struct {
 	db      *postgres.DB
 	http    *http.HTTP
 	indexer *indexer.Indexer
+	worker  *jobs.Worker
+	broker  *stream.Broker
 
 	quitChan chan struct{}
 	errChan  chan error
@@ -161,18 +213,14 @@ func (a *App) Start() error {
 		return err
 	}
 
-	stopChan := make(chan os.Signal)
+	stopChan := make(chan os.Signal, 1)
 	signal.Notify(stopChan, os.Interrupt)
 
-	go func() {
-		a.wg.Add(1)
-		a.http.Start()
-	}()
-
-	go func() {
-		a.wg.Add(1)
-		a.indexer.Start()
-	}()
+	a.wg.Add(4)
+	go a.http.Start()
+	go a.indexer.Start()
+	go a.worker.Start()
+	go a.broker.Start()
 
 	go a.recordMetrics()
 
@@ -118,6 +118,53 @@ func (c *Client) Patch(ctx context.Context, requestURL, accessToken string, body
 	return c.postOrPatch(ctx, http.MethodPatch, requestURL, accessToken, body)
 }
 
+// Delete attempts to send a DELETE request to the given URL, setting the
+// correct authorization header. Returns an error on any failure.
+func (c *Client) Delete(ctx context.Context, requestURL, accessToken string) error {
+	log := logger.FromContext(ctx)
+
+	if c.verbose {
+		log.Log(
+			"msg", "deleting url",
+			"url", requestURL,
+		)
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, requestURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		log.Log(
+			"msg", "error making request",
+			"error", err,
+		)
+		if err.(*url.Error).Timeout() {
+			return TimeoutError
+		}
+
+		return UnexpectedError
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized:
+		return UnauthorizedError
+	case http.StatusNotFound:
+		return NotFoundError
+	default:
+		log.Log("msg", "unexpected response code", "code", resp.StatusCode)
+		return fmt.Errorf("Unexpected response: %s", resp.Status)
+	}
+}
+
 func (c *Client) postOrPatch(ctx context.Context, method, requestURL, accessToken string, body io.Reader) ([]byte, error) {
 	log := logger.FromContext(ctx)
 
@@ -3,7 +3,10 @@ package client_test
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	kitlog "github.com/go-kit/kit/log"
@@ -76,3 +79,51 @@ func TestGetNotFoundError(t *testing.T) {
 	err = simular.AllStubsCalled()
 	assert.Nil(t, err)
 }
+
+func TestIsPublicIP(t *testing.T) {
+	testcases := []struct {
+		ip       string
+		expected bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"0.0.0.0", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.ip, func(t *testing.T) {
+			assert.Equal(t, tc.expected, client.IsPublicIP(net.ParseIP(tc.ip)))
+		})
+	}
+}
+
+func TestPublicClientRefusesNonPublicAddresses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	resp, err := client.NewClient(1, false).Client.Get(ts.URL)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+	}
+
+	_, err = client.NewPublicClient(1, false).Client.Get(ts.URL)
+	assert.NotNil(t, err)
+	assert.True(t, client.IsNonPublicAddress(err))
+
+	// hosts are checked once they have been resolved
+	_, err = client.NewPublicClient(1, false).Client.Get(strings.Replace(ts.URL, "127.0.0.1", "localhost", 1))
+	assert.NotNil(t, err)
+	assert.True(t, client.IsNonPublicAddress(err))
+}
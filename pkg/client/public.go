@@ -0,0 +1,131 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNonPublicAddress is returned when a public client is asked to connect to
+// an address that isn't publicly routable
+var ErrNonPublicAddress = errors.New("address is not publicly routable")
+
+// nonPublicNetworks are the ranges of addresses that a public client refuses to
+// connect to: loopback, private, link-local (which includes cloud metadata
+// services), shared, multicast and otherwise reserved addresses
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"100::/64",
+	"2001:db8::/32",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+// mustParseCIDRs parses the given CIDR ranges, panicking if any is invalid
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublicIP returns true if the address is publicly routable. IPv4 addresses
+// mapped to IPv6 are checked as IPv4 addresses.
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewPublicClient returns a new client like NewClient which may only connect
+// to publicly routable addresses, for making requests to URLs given to us by
+// apps. Addresses are checked once the host has been resolved, so hosts whose
+// names resolve to internal addresses are also refused, as are redirects to
+// them. Proxies configured in the environment are not used as they would
+// bypass the check.
+func NewPublicClient(timeout int, verbose bool) *Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnly,
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	c := NewClient(timeout, verbose)
+	c.Transport = InstrumentRoundTripperDuration(durationHist, transport)
+
+	return c
+}
+
+// publicOnly is the control function of the dialer of a public client, called
+// with the resolved address before each connection is made
+func publicOnly(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrap(err, "failed to split address")
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return ErrNonPublicAddress
+	}
+
+	return nil
+}
+
+// IsNonPublicAddress returns true if the error was returned because a public
+// client refused to connect to an address
+func IsNonPublicAddress(err error) bool {
+	err = errors.Cause(err)
+
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+
+	if opErr, ok := err.(*net.OpError); ok {
+		err = opErr.Err
+	}
+
+	return err == ErrNonPublicAddress
+}
@@ -0,0 +1,807 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/thingful"
+)
+
+const (
+	// observationWindow is the interval we read observations from Thingful over,
+	// matching the longest interval the time series endpoints allow
+	observationWindow = 10 * 24 * time.Hour
+)
+
+// Thingful is the interface we expect for reading observations back from
+// Thingful
+type Thingful interface {
+	// GetData returns the things with the given UIDs, including their
+	// observations recorded within the given interval
+	GetData(context.Context, []string, time.Time, time.Time, bool) ([]thingful.Thing, error)
+}
+
+// Exporter builds archives of everything we hold about a user, for answering
+// subject access requests
+type Exporter struct {
+	db       *postgres.DB
+	thingful Thingful
+}
+
+// NewExporter returns a new Exporter reading from the given DB and Thingful
+// client
+func NewExporter(db *postgres.DB, th Thingful) *Exporter {
+	return &Exporter{
+		db:       db,
+		thingful: th,
+	}
+}
+
+// Export returns an archive of everything we hold about the user with the given
+// UID, optionally including all observations of their things read back from
+// Thingful, which for users with a long history is slow. Clients can unwrap the
+// returned error to check for an sql.ErrNoRows error to determine if no such
+// user exists.
+func (e *Exporter) Export(ctx context.Context, userUID string, withObservations bool) (*Archive, error) {
+	log := logger.FromContext(ctx)
+
+	data, err := e.db.GetUserExport(ctx, userUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load user data")
+	}
+
+	archive := NewArchive(data, time.Now())
+
+	if withObservations {
+		archive.Observations, err = GetObservations(ctx, e.thingful, data.Things)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read observations")
+		}
+	}
+
+	log.Log(
+		"msg", "exported user",
+		"userUID", userUID,
+		"things", len(archive.Things),
+		"observations", len(archive.Observations),
+	)
+
+	return archive, nil
+}
+
+// Archive is everything we hold about a user in the form we hand it over
+type Archive struct {
+	ExportedAt      time.Time        `json:"exported_at"`
+	User            User             `json:"user"`
+	Identities      []Identity       `json:"identities"`
+	Things          []Thing          `json:"things"`
+	Channels        []Channel        `json:"channels"`
+	LocationChanges []LocationChange `json:"location_changes"`
+	HardwareChanges []HardwareChange `json:"hardware_changes"`
+	PlantStatuses   []PlantStatus    `json:"plant_statuses"`
+	AlertRules      []AlertRule      `json:"alert_rules"`
+	Alerts          []Alert          `json:"alerts"`
+	Deliveries      []Delivery       `json:"webhook_deliveries"`
+	AuditRecords    []AuditRecord    `json:"audit_records"`
+	Observations    []Observation    `json:"observations,omitempty"`
+}
+
+// User is the user record of an archive
+type User struct {
+	UID       string      `json:"uid"`
+	ParrotID  null.String `json:"parrot_id"`
+	CreatedAt null.Time   `json:"created_at"`
+}
+
+// Identity is an identity of the user. We never hand over tokens, only whether
+// we hold them.
+type Identity struct {
+	Provider        null.String `json:"provider"`
+	HasAccessToken  bool        `json:"has_access_token"`
+	HasRefreshToken bool        `json:"has_refresh_token"`
+	CreatedAt       null.Time   `json:"created_at"`
+	IndexedAt       null.Time   `json:"indexed_at"`
+	RevokedAt       null.Time   `json:"revoked_at"`
+}
+
+// Thing is one of the user's things along with its current hardware
+type Thing struct {
+	UID                string      `json:"uid"`
+	Provider           null.String `json:"provider"`
+	SerialNum          string      `json:"serial_num"`
+	Nickname           null.String `json:"nickname"`
+	LocationID         string      `json:"location_identifier"`
+	Longitude          float64     `json:"longitude"`
+	Latitude           float64     `json:"latitude"`
+	FirstSample        null.Time   `json:"first_sample"`
+	LastSample         null.Time   `json:"last_sample"`
+	LastUploadedSample null.Time   `json:"last_uploaded_sample"`
+	CreatedAt          null.Time   `json:"created_at"`
+	UpdatedAt          null.Time   `json:"updated_at"`
+	IndexedAt          null.Time   `json:"indexed_at"`
+	StaleAt            null.Time   `json:"stale_at"`
+	Hardware
+}
+
+// Hardware is the sensor hardware of a thing
+type Hardware struct {
+	FirmwareVersion  null.String `json:"firmware_version"`
+	HardwareRevision null.String `json:"hardware_revision"`
+	SensorType       null.String `json:"sensor_type"`
+	CalibrationData  null.String `json:"calibration_data"`
+	IsIndoor         null.Bool   `json:"is_indoor"`
+	InPot            null.Bool   `json:"in_pot"`
+	PlantIDs         []int64     `json:"plant_ids"`
+	AutowateringMode null.String `json:"autowatering_mode"`
+}
+
+// Channel is the metadata of a single channel of a thing
+type Channel struct {
+	ThingUID    string      `json:"thing_uid"`
+	Name        string      `json:"name"`
+	Unit        null.String `json:"unit"`
+	DataType    string      `json:"data_type"`
+	FirstSample null.Time   `json:"first_sample"`
+	LastSample  null.Time   `json:"last_sample"`
+}
+
+// LocationChange is an entry of a thing's location history
+type LocationChange struct {
+	ThingUID          string      `json:"thing_uid"`
+	ChangedAt         time.Time   `json:"changed_at"`
+	AppUID            null.String `json:"app_uid"`
+	PreviousLongitude null.Float  `json:"previous_longitude"`
+	PreviousLatitude  null.Float  `json:"previous_latitude"`
+	Longitude         float64     `json:"longitude"`
+	Latitude          float64     `json:"latitude"`
+}
+
+// HardwareChange is an entry of a thing's hardware history
+type HardwareChange struct {
+	ThingUID  string    `json:"thing_uid"`
+	ChangedAt time.Time `json:"changed_at"`
+	Hardware
+}
+
+// PlantStatus is Parrot's latest assessment of a single variable of a thing
+type PlantStatus struct {
+	ThingUID     string     `json:"thing_uid"`
+	Variable     string     `json:"variable"`
+	Status       string     `json:"status"`
+	Instruction  string     `json:"instruction"`
+	MinThreshold null.Float `json:"min_threshold"`
+	MaxThreshold null.Float `json:"max_threshold"`
+	CurrentValue null.Float `json:"current_value"`
+	StatusAt     time.Time  `json:"status_at"`
+}
+
+// AlertRule is an alert rule created by an app to watch the user, or one of
+// their things when ThingUID is set
+type AlertRule struct {
+	UID        string      `json:"uid"`
+	AppUID     string      `json:"app_uid"`
+	ThingUID   null.String `json:"thing_uid"`
+	Channel    string      `json:"channel"`
+	Comparator string      `json:"comparator"`
+	Threshold  float64     `json:"threshold"`
+	Duration   int         `json:"duration"`
+	Cooldown   int         `json:"cooldown"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// Alert is the state of an alert rule for one of the user's things
+type Alert struct {
+	RuleUID      string    `json:"rule_uid"`
+	ThingUID     string    `json:"thing_uid"`
+	State        string    `json:"state"`
+	Value        float64   `json:"value"`
+	PendingSince null.Time `json:"pending_since"`
+	FiredAt      null.Time `json:"fired_at"`
+	ResolvedAt   null.Time `json:"resolved_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Delivery is an event about the user or one of their things sent to the
+// webhook of an app, along with the payload that was sent
+type Delivery struct {
+	UID         string          `json:"uid"`
+	AppUID      string          `json:"app_uid"`
+	WebhookUID  string          `json:"webhook_uid"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	DeliveredAt null.Time       `json:"delivered_at"`
+	FailedAt    null.Time       `json:"failed_at"`
+}
+
+// AuditRecord is a request an app made that acted on the user, one of their
+// things or an alert rule watching them
+type AuditRecord struct {
+	AppUID    string    `json:"app_uid"`
+	RequestID string    `json:"request_id"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Path      string    `json:"path"`
+	Targets   []string  `json:"targets"`
+	Status    int       `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Observation is a single value recorded by one of a thing's channels
+type Observation struct {
+	ThingUID   string    `json:"thing_uid"`
+	Channel    string    `json:"channel"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Value      string    `json:"value"`
+}
+
+// NewArchive returns an archive of the data loaded from the DB, without any
+// observations
+func NewArchive(data *postgres.UserExport, exportedAt time.Time) *Archive {
+	archive := &Archive{
+		ExportedAt: exportedAt.UTC(),
+		User: User{
+			UID:       data.User.UID,
+			ParrotID:  data.User.ParrotID,
+			CreatedAt: data.User.CreatedAt,
+		},
+		Identities:      []Identity{},
+		Things:          []Thing{},
+		Channels:        []Channel{},
+		LocationChanges: []LocationChange{},
+		HardwareChanges: []HardwareChange{},
+		PlantStatuses:   []PlantStatus{},
+		AlertRules:      []AlertRule{},
+		Alerts:          []Alert{},
+		Deliveries:      []Delivery{},
+		AuditRecords:    []AuditRecord{},
+	}
+
+	for _, i := range data.Identities {
+		archive.Identities = append(archive.Identities, Identity{
+			Provider:        i.Provider,
+			HasAccessToken:  i.HasAccessToken,
+			HasRefreshToken: i.HasRefreshToken,
+			CreatedAt:       i.CreatedAt,
+			IndexedAt:       i.IndexedAt,
+			RevokedAt:       i.RevokedAt,
+		})
+	}
+
+	for _, t := range data.Things {
+		archive.Things = append(archive.Things, Thing{
+			UID:                t.UID.String,
+			Provider:           t.Provider,
+			SerialNum:          t.SerialNum,
+			Nickname:           t.Nickname,
+			LocationID:         t.LocationID,
+			Longitude:          t.Longitude,
+			Latitude:           t.Latitude,
+			FirstSample:        t.FirstSampleUTC,
+			LastSample:         t.LastSampleUTC,
+			LastUploadedSample: t.LastUploadedUTC,
+			CreatedAt:          t.CreatedAt,
+			UpdatedAt:          t.UpdatedAt,
+			IndexedAt:          t.IndexedAt,
+			StaleAt:            t.StaleAt,
+			Hardware:           buildHardware(&t.Hardware),
+		})
+	}
+
+	for _, c := range data.Channels {
+		archive.Channels = append(archive.Channels, Channel{
+			ThingUID:    c.ThingUID,
+			Name:        c.Name,
+			Unit:        c.Unit,
+			DataType:    c.DataType,
+			FirstSample: c.FirstSampleUTC,
+			LastSample:  c.LastSampleUTC,
+		})
+	}
+
+	for _, c := range data.LocationChanges {
+		archive.LocationChanges = append(archive.LocationChanges, LocationChange{
+			ThingUID:          c.ThingUID,
+			ChangedAt:         c.InsertedAt,
+			AppUID:            c.AppUID,
+			PreviousLongitude: c.PreviousLongitude,
+			PreviousLatitude:  c.PreviousLatitude,
+			Longitude:         c.NewLongitude,
+			Latitude:          c.NewLatitude,
+		})
+	}
+
+	for _, c := range data.HardwareChanges {
+		archive.HardwareChanges = append(archive.HardwareChanges, HardwareChange{
+			ThingUID:  c.ThingUID,
+			ChangedAt: c.InsertedAt,
+			Hardware:  buildHardware(&c.Hardware),
+		})
+	}
+
+	for _, s := range data.PlantStatuses {
+		archive.PlantStatuses = append(archive.PlantStatuses, PlantStatus{
+			ThingUID:     s.ThingUID,
+			Variable:     s.Variable,
+			Status:       s.StatusKey,
+			Instruction:  s.InstructionKey,
+			MinThreshold: s.MinThreshold,
+			MaxThreshold: s.MaxThreshold,
+			CurrentValue: s.CurrentValue,
+			StatusAt:     s.StatusAt,
+		})
+	}
+
+	for _, r := range data.AlertRules {
+		archive.AlertRules = append(archive.AlertRules, AlertRule{
+			UID:        r.UID,
+			AppUID:     r.AppUID,
+			ThingUID:   r.ThingUID,
+			Channel:    r.Channel,
+			Comparator: r.Comparator,
+			Threshold:  r.Threshold,
+			Duration:   r.Duration,
+			Cooldown:   r.Cooldown,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+
+	for _, a := range data.Alerts {
+		archive.Alerts = append(archive.Alerts, Alert{
+			RuleUID:      a.RuleUID,
+			ThingUID:     a.ThingUID,
+			State:        a.State,
+			Value:        a.Value,
+			PendingSince: a.PendingSince,
+			FiredAt:      a.FiredAt,
+			ResolvedAt:   a.ResolvedAt,
+			UpdatedAt:    a.UpdatedAt,
+		})
+	}
+
+	for _, d := range data.Deliveries {
+		archive.Deliveries = append(archive.Deliveries, Delivery{
+			UID:         d.UID,
+			AppUID:      d.AppUID,
+			WebhookUID:  d.WebhookUID,
+			Event:       d.Event,
+			Payload:     json.RawMessage(d.Payload),
+			CreatedAt:   d.CreatedAt,
+			DeliveredAt: d.DeliveredAt,
+			FailedAt:    d.FailedAt,
+		})
+	}
+
+	for _, r := range data.AuditRecords {
+		archive.AuditRecords = append(archive.AuditRecords, AuditRecord{
+			AppUID:    r.AppUID,
+			RequestID: r.RequestID,
+			Method:    r.Method,
+			Route:     r.Route,
+			Path:      r.Path,
+			Targets:   []string(r.Targets),
+			Status:    r.Status,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+
+	return archive
+}
+
+// buildHardware converts the hardware stored in the DB
+func buildHardware(h *postgres.Hardware) Hardware {
+	plantIDs := []int64(h.PlantIDs)
+	if plantIDs == nil {
+		plantIDs = []int64{}
+	}
+
+	return Hardware{
+		FirmwareVersion:  h.FirmwareVersion,
+		HardwareRevision: h.HardwareRevision,
+		SensorType:       h.SensorType,
+		CalibrationData:  h.CalibrationData,
+		IsIndoor:         h.IsIndoor,
+		InPot:            h.InPot,
+		PlantIDs:         plantIDs,
+		AutowateringMode: h.AutowateringMode,
+	}
+}
+
+// GetObservations reads every observation of the given things back from
+// Thingful, working through each thing's uploaded history in windows no longer
+// than the time series endpoints allow. Things that haven't uploaded anything
+// are skipped.
+func GetObservations(ctx context.Context, th Thingful, things []postgres.Thing) ([]Observation, error) {
+	observations := []Observation{}
+
+	for _, t := range things {
+		if !t.FirstSampleUTC.Valid || !t.LastUploadedUTC.Valid {
+			continue
+		}
+
+		// windows share their boundaries, so remember the last observation of each
+		// channel to avoid repeating it
+		latest := map[string]time.Time{}
+
+		for from := t.FirstSampleUTC.Time; !from.After(t.LastUploadedUTC.Time); from = from.Add(observationWindow) {
+			to := from.Add(observationWindow)
+			if to.After(t.LastUploadedUTC.Time) {
+				to = t.LastUploadedUTC.Time
+			}
+
+			data, err := th.GetData(ctx, []string{t.UID.String}, from, to, true)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read data from Thingful")
+			}
+
+			for _, d := range data {
+				for _, c := range d.Attributes.Channels {
+					for _, o := range c.Observations {
+						if last, ok := latest[c.ID]; ok && !o.RecordedAt.After(last) {
+							continue
+						}
+
+						latest[c.ID] = o.RecordedAt
+
+						observations = append(observations, Observation{
+							ThingUID:   t.UID.String,
+							Channel:    c.ID,
+							RecordedAt: o.RecordedAt.UTC(),
+							Value:      o.Value,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return observations, nil
+}
+
+// WriteZip writes the archive as a zip file containing the whole archive as
+// JSON, along with a CSV file per record type
+func (a *Archive) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	f, err := zw.Create("export.json")
+	if err != nil {
+		return errors.Wrap(err, "failed to create JSON file")
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+
+	err = enc.Encode(a)
+	if err != nil {
+		return errors.Wrap(err, "failed to write JSON file")
+	}
+
+	for _, t := range a.tables() {
+		f, err := zw.Create(t.name + ".csv")
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s CSV file", t.name)
+		}
+
+		cw := csv.NewWriter(f)
+		cw.Write(t.header)
+		cw.WriteAll(t.rows)
+
+		err = cw.Error()
+		if err != nil {
+			return errors.Wrapf(err, "failed to write %s CSV file", t.name)
+		}
+	}
+
+	return zw.Close()
+}
+
+// table is the contents of a single CSV file of the archive
+type table struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+var hardwareHeader = []string{
+	"firmware_version",
+	"hardware_revision",
+	"sensor_type",
+	"calibration_data",
+	"is_indoor",
+	"in_pot",
+	"plant_ids",
+	"autowatering_mode",
+}
+
+// tables returns the CSV files of the archive, with observations only included
+// if they were requested
+func (a *Archive) tables() []table {
+	user := table{
+		name:   "user",
+		header: []string{"uid", "parrot_id", "created_at"},
+		rows: [][]string{
+			{a.User.UID, a.User.ParrotID.String, formatNullTime(a.User.CreatedAt)},
+		},
+	}
+
+	identities := table{
+		name:   "identities",
+		header: []string{"provider", "has_access_token", "has_refresh_token", "created_at", "indexed_at", "revoked_at"},
+	}
+	for _, i := range a.Identities {
+		identities.rows = append(identities.rows, []string{
+			i.Provider.String,
+			strconv.FormatBool(i.HasAccessToken),
+			strconv.FormatBool(i.HasRefreshToken),
+			formatNullTime(i.CreatedAt),
+			formatNullTime(i.IndexedAt),
+			formatNullTime(i.RevokedAt),
+		})
+	}
+
+	things := table{
+		name: "things",
+		header: append([]string{
+			"uid",
+			"provider",
+			"serial_num",
+			"nickname",
+			"location_identifier",
+			"longitude",
+			"latitude",
+			"first_sample",
+			"last_sample",
+			"last_uploaded_sample",
+			"created_at",
+			"updated_at",
+			"indexed_at",
+			"stale_at",
+		}, hardwareHeader...),
+	}
+	for _, t := range a.Things {
+		things.rows = append(things.rows, append([]string{
+			t.UID,
+			t.Provider.String,
+			t.SerialNum,
+			t.Nickname.String,
+			t.LocationID,
+			formatFloat(t.Longitude),
+			formatFloat(t.Latitude),
+			formatNullTime(t.FirstSample),
+			formatNullTime(t.LastSample),
+			formatNullTime(t.LastUploadedSample),
+			formatNullTime(t.CreatedAt),
+			formatNullTime(t.UpdatedAt),
+			formatNullTime(t.IndexedAt),
+			formatNullTime(t.StaleAt),
+		}, t.Hardware.row()...))
+	}
+
+	channels := table{
+		name:   "channels",
+		header: []string{"thing_uid", "name", "unit", "data_type", "first_sample", "last_sample"},
+	}
+	for _, c := range a.Channels {
+		channels.rows = append(channels.rows, []string{
+			c.ThingUID,
+			c.Name,
+			c.Unit.String,
+			c.DataType,
+			formatNullTime(c.FirstSample),
+			formatNullTime(c.LastSample),
+		})
+	}
+
+	locationChanges := table{
+		name:   "location_changes",
+		header: []string{"thing_uid", "changed_at", "app_uid", "previous_longitude", "previous_latitude", "longitude", "latitude"},
+	}
+	for _, c := range a.LocationChanges {
+		locationChanges.rows = append(locationChanges.rows, []string{
+			c.ThingUID,
+			formatTime(c.ChangedAt),
+			c.AppUID.String,
+			formatNullFloat(c.PreviousLongitude),
+			formatNullFloat(c.PreviousLatitude),
+			formatFloat(c.Longitude),
+			formatFloat(c.Latitude),
+		})
+	}
+
+	hardwareChanges := table{
+		name:   "hardware_changes",
+		header: append([]string{"thing_uid", "changed_at"}, hardwareHeader...),
+	}
+	for _, c := range a.HardwareChanges {
+		hardwareChanges.rows = append(hardwareChanges.rows, append([]string{
+			c.ThingUID,
+			formatTime(c.ChangedAt),
+		}, c.Hardware.row()...))
+	}
+
+	plantStatuses := table{
+		name:   "plant_statuses",
+		header: []string{"thing_uid", "variable", "status", "instruction", "min_threshold", "max_threshold", "current_value", "status_at"},
+	}
+	for _, s := range a.PlantStatuses {
+		plantStatuses.rows = append(plantStatuses.rows, []string{
+			s.ThingUID,
+			s.Variable,
+			s.Status,
+			s.Instruction,
+			formatNullFloat(s.MinThreshold),
+			formatNullFloat(s.MaxThreshold),
+			formatNullFloat(s.CurrentValue),
+			formatTime(s.StatusAt),
+		})
+	}
+
+	alertRules := table{
+		name:   "alert_rules",
+		header: []string{"uid", "app_uid", "thing_uid", "channel", "comparator", "threshold", "duration", "cooldown", "created_at"},
+	}
+	for _, r := range a.AlertRules {
+		alertRules.rows = append(alertRules.rows, []string{
+			r.UID,
+			r.AppUID,
+			r.ThingUID.String,
+			r.Channel,
+			r.Comparator,
+			formatFloat(r.Threshold),
+			strconv.Itoa(r.Duration),
+			strconv.Itoa(r.Cooldown),
+			formatTime(r.CreatedAt),
+		})
+	}
+
+	alerts := table{
+		name:   "alerts",
+		header: []string{"rule_uid", "thing_uid", "state", "value", "pending_since", "fired_at", "resolved_at", "updated_at"},
+	}
+	for _, al := range a.Alerts {
+		alerts.rows = append(alerts.rows, []string{
+			al.RuleUID,
+			al.ThingUID,
+			al.State,
+			formatFloat(al.Value),
+			formatNullTime(al.PendingSince),
+			formatNullTime(al.FiredAt),
+			formatNullTime(al.ResolvedAt),
+			formatTime(al.UpdatedAt),
+		})
+	}
+
+	deliveries := table{
+		name:   "webhook_deliveries",
+		header: []string{"uid", "app_uid", "webhook_uid", "event", "payload", "created_at", "delivered_at", "failed_at"},
+	}
+	for _, d := range a.Deliveries {
+		deliveries.rows = append(deliveries.rows, []string{
+			d.UID,
+			d.AppUID,
+			d.WebhookUID,
+			d.Event,
+			string(d.Payload),
+			formatTime(d.CreatedAt),
+			formatNullTime(d.DeliveredAt),
+			formatNullTime(d.FailedAt),
+		})
+	}
+
+	auditRecords := table{
+		name:   "audit_records",
+		header: []string{"app_uid", "request_id", "method", "route", "path", "targets", "status", "created_at"},
+	}
+	for _, r := range a.AuditRecords {
+		auditRecords.rows = append(auditRecords.rows, []string{
+			r.AppUID,
+			r.RequestID,
+			r.Method,
+			r.Route,
+			r.Path,
+			strings.Join(r.Targets, " "),
+			strconv.Itoa(r.Status),
+			formatTime(r.CreatedAt),
+		})
+	}
+
+	tables := []table{
+		user,
+		identities,
+		things,
+		channels,
+		locationChanges,
+		hardwareChanges,
+		plantStatuses,
+		alertRules,
+		alerts,
+		deliveries,
+		auditRecords,
+	}
+
+	if a.Observations != nil {
+		observations := table{
+			name:   "observations",
+			header: []string{"thing_uid", "channel", "recorded_at", "value"},
+		}
+		for _, o := range a.Observations {
+			observations.rows = append(observations.rows, []string{
+				o.ThingUID,
+				o.Channel,
+				formatTime(o.RecordedAt),
+				o.Value,
+			})
+		}
+
+		tables = append(tables, observations)
+	}
+
+	return tables
+}
+
+// row returns the CSV columns of the hardware, in the order of hardwareHeader
+func (h *Hardware) row() []string {
+	plantIDs := make([]string, len(h.PlantIDs))
+	for i, id := range h.PlantIDs {
+		plantIDs[i] = strconv.FormatInt(id, 10)
+	}
+
+	return []string{
+		h.FirmwareVersion.String,
+		h.HardwareRevision.String,
+		h.SensorType.String,
+		h.CalibrationData.String,
+		formatNullBool(h.IsIndoor),
+		formatNullBool(h.InPot),
+		strings.Join(plantIDs, " "),
+		h.AutowateringMode.String,
+	}
+}
+
+// formatTime formats a time for CSV files
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatNullTime formats a time for CSV files, returning an empty string for a
+// null time
+func formatNullTime(t null.Time) string {
+	if !t.Valid {
+		return ""
+	}
+	return formatTime(t.Time)
+}
+
+// formatFloat formats a float for CSV files
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatNullFloat formats a float for CSV files, returning an empty string for
+// a null float
+func formatNullFloat(f null.Float) string {
+	if !f.Valid {
+		return ""
+	}
+	return formatFloat(f.Float64)
+}
+
+// formatNullBool formats a bool for CSV files, returning an empty string for a
+// null bool
+func formatNullBool(b null.Bool) string {
+	if !b.Valid {
+		return ""
+	}
+	return strconv.FormatBool(b.Bool)
+}
@@ -0,0 +1,201 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/export"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/thingful"
+)
+
+// fakeThingful returns one observation per day of each requested window
+type fakeThingful struct {
+	windows [][2]time.Time
+}
+
+func (f *fakeThingful) GetData(ctx context.Context, uids []string, from, to time.Time, ascending bool) ([]thingful.Thing, error) {
+	f.windows = append(f.windows, [2]time.Time{from, to})
+
+	observations := []thingful.Observation{}
+	for t := from; !t.After(to); t = t.Add(24 * time.Hour) {
+		observations = append(observations, thingful.Observation{RecordedAt: t, Value: "21.5"})
+	}
+
+	return []thingful.Thing{
+		{
+			ID: uids[0],
+			Attributes: thingful.ThingAttributes{
+				Channels: []thingful.Channel{
+					{ID: "air_temperature", Observations: observations},
+				},
+			},
+		},
+	}, nil
+}
+
+func TestArchive(t *testing.T) {
+	changedAt := time.Date(2019, 6, 13, 9, 0, 0, 0, time.UTC)
+
+	archive := export.NewArchive(&postgres.UserExport{
+		User: postgres.ExportedUser{
+			UID:      "user1",
+			ParrotID: null.StringFrom("parrot1"),
+		},
+		Identities: []postgres.ExportedIdentity{
+			{Provider: null.StringFrom("oauth"), HasAccessToken: true},
+		},
+		Things: []postgres.Thing{
+			{
+				UID:        null.StringFrom("abc123"),
+				SerialNum:  "PA123",
+				LocationID: "loc1",
+				Longitude:  -0.5,
+				Latitude:   55.1,
+				Hardware: postgres.Hardware{
+					SensorType: null.StringFrom("flower-power-pot"),
+					IsIndoor:   null.BoolFrom(true),
+					PlantIDs:   pq.Int64Array{12, 34},
+				},
+			},
+		},
+		LocationChanges: []postgres.LocationChange{
+			{ThingUID: "abc123", InsertedAt: changedAt, NewLongitude: -0.5, NewLatitude: 55.1},
+		},
+		AlertRules: []postgres.ExportedAlertRule{
+			{UID: "rule1", AppUID: "app1", Channel: "soil_moisture", Comparator: "lt", Threshold: 15, CreatedAt: changedAt},
+		},
+		Deliveries: []postgres.ExportedWebhookDelivery{
+			{
+				UID:        "delivery1",
+				AppUID:     "app1",
+				WebhookUID: "hook1",
+				Event:      "thing.created",
+				Payload:    []byte(`{"data":{"thingUid":"abc123","userUid":"user1","longitude":-0.5,"latitude":55.1}}`),
+				CreatedAt:  changedAt,
+			},
+		},
+		AuditRecords: []postgres.AuditRecord{
+			{AppUID: "app1", Method: "DELETE", Route: "/alerts/rules/:uid", Path: "/alerts/rules/rule1", Targets: pq.StringArray{"alert-rule:rule1"}, Status: 204, CreatedAt: changedAt},
+		},
+	}, changedAt)
+
+	assert.Equal(t, "user1", archive.User.UID)
+	assert.Len(t, archive.Things, 1)
+	assert.Equal(t, []int64{12, 34}, archive.Things[0].PlantIDs)
+	assert.Len(t, archive.Channels, 0)
+	assert.Nil(t, archive.Observations)
+
+	var buf bytes.Buffer
+	err := archive.WriteZip(&buf)
+	assert.Nil(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		assert.Nil(t, err)
+
+		files[f.Name], err = ioutil.ReadAll(r)
+		assert.Nil(t, err)
+		r.Close()
+	}
+
+	assert.Len(t, files, 12)
+	assert.NotContains(t, files, "observations.csv")
+
+	var doc map[string]interface{}
+	err = json.Unmarshal(files["export.json"], &doc)
+	assert.Nil(t, err)
+	assert.Equal(t, "user1", doc["user"].(map[string]interface{})["uid"])
+	assert.NotContains(t, doc, "observations")
+
+	delivery := doc["webhook_deliveries"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, 55.1, delivery["payload"].(map[string]interface{})["data"].(map[string]interface{})["latitude"])
+
+	identity := doc["identities"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, true, identity["has_access_token"])
+	assert.NotContains(t, identity, "access_token")
+
+	rows, err := csv.NewReader(bytes.NewReader(files["things.csv"])).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "uid", rows[0][0])
+	assert.Equal(t, "abc123", rows[1][0])
+	assert.Contains(t, rows[1], "-0.5")
+	assert.Contains(t, rows[1], "12 34")
+
+	rows, err = csv.NewReader(bytes.NewReader(files["location_changes.csv"])).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"abc123", "2019-06-13T09:00:00Z", "", "", "", "-0.5", "55.1"}, rows[1])
+
+	rows, err = csv.NewReader(bytes.NewReader(files["channels.csv"])).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 1)
+
+	rows, err = csv.NewReader(bytes.NewReader(files["alert_rules.csv"])).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"rule1", "app1", "", "soil_moisture", "lt", "15", "0", "0", "2019-06-13T09:00:00Z"}, rows[1])
+
+	rows, err = csv.NewReader(bytes.NewReader(files["webhook_deliveries.csv"])).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Contains(t, rows[1][4], `"userUid":"user1"`)
+
+	rows, err = csv.NewReader(bytes.NewReader(files["audit_records.csv"])).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"app1", "", "DELETE", "/alerts/rules/:uid", "/alerts/rules/rule1", "alert-rule:rule1", "204", "2019-06-13T09:00:00Z"}, rows[1])
+}
+
+func TestGetObservations(t *testing.T) {
+	first := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	th := &fakeThingful{}
+
+	observations, err := export.GetObservations(context.Background(), th, []postgres.Thing{
+		{
+			UID:             null.StringFrom("abc123"),
+			FirstSampleUTC:  null.TimeFrom(first),
+			LastUploadedUTC: null.TimeFrom(first.Add(24 * 24 * time.Hour)),
+		},
+		{
+			// never uploaded anything so skipped
+			UID:            null.StringFrom("def456"),
+			FirstSampleUTC: null.TimeFrom(first),
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Len(t, th.windows, 3)
+	assert.Equal(t, first.Add(20*24*time.Hour), th.windows[2][0])
+	assert.Equal(t, first.Add(24*24*time.Hour), th.windows[2][1])
+
+	// one observation per day without repeating those on window boundaries
+	assert.Len(t, observations, 25)
+	assert.Equal(t, "abc123", observations[0].ThingUID)
+	assert.Equal(t, "air_temperature", observations[0].Channel)
+	assert.Equal(t, first, observations[0].RecordedAt)
+	assert.Equal(t, first.Add(24*24*time.Hour), observations[24].RecordedAt)
+
+	archive := &export.Archive{Observations: observations}
+
+	var buf bytes.Buffer
+	err = archive.WriteZip(&buf)
+	assert.Nil(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	assert.Equal(t, "observations.csv", zr.File[len(zr.File)-1].Name)
+}
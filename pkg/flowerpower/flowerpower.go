@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/guregu/null"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/thingful/kudzu/pkg/client"
@@ -67,9 +68,84 @@ type statusData struct {
 
 // statusLocation is a specific location parsed from the Parrot status response
 type statusLocation struct {
-	LocationID     string    `json:"location_identifier"`
-	FirstSampleUTC time.Time `json:"first_sample_utc"`
-	LastSampleUTC  time.Time `json:"last_sample_utc"`
+	LocationID     string          `json:"location_identifier"`
+	FirstSampleUTC time.Time       `json:"first_sample_utc"`
+	LastSampleUTC  time.Time       `json:"last_sample_utc"`
+	StatusUTC      time.Time       `json:"status_creation_datetime_utc"`
+	AirTemperature *statusVariable `json:"air_temperature"`
+	Light          *statusVariable `json:"light"`
+	Fertilizer     *statusVariable `json:"fertilizer"`
+	Watering       *statusWatering `json:"watering"`
+	Battery        *statusVariable `json:"battery"`
+}
+
+// statusWatering contains the status of the soil moisture, and of the water
+// tank for pot sensors
+type statusWatering struct {
+	SoilMoisture      *statusVariable `json:"soil_moisture"`
+	AutomaticWatering *statusVariable `json:"automatic_watering"`
+}
+
+// statusVariable is Parrot's assessment of a single variable of a location
+type statusVariable struct {
+	StatusKey      string      `json:"status_key"`
+	InstructionKey string      `json:"instruction_key"`
+	GaugeValues    statusGauge `json:"gauge_values"`
+}
+
+// statusGauge contains the thresholds Parrot uses for a variable, any of which
+// may be null
+type statusGauge struct {
+	MinThreshold null.Float `json:"min_threshold"`
+	MaxThreshold null.Float `json:"max_threshold"`
+	CurrentValue null.Float `json:"current_value"`
+}
+
+// plantStatuses returns the status of each variable present in the status
+// response, named after our channels where we have a matching one
+func (s *statusLocation) plantStatuses() []PlantStatus {
+	variables := []struct {
+		name   string
+		status *statusVariable
+	}{
+		{"air_temperature", s.AirTemperature},
+		{"light", s.Light},
+		{"fertilizer_level", s.Fertilizer},
+		{"battery_level", s.Battery},
+	}
+
+	if s.Watering != nil {
+		variables = append(
+			variables,
+			struct {
+				name   string
+				status *statusVariable
+			}{"soil_moisture", s.Watering.SoilMoisture},
+			struct {
+				name   string
+				status *statusVariable
+			}{"automatic_watering", s.Watering.AutomaticWatering},
+		)
+	}
+
+	statuses := []PlantStatus{}
+
+	for _, v := range variables {
+		if v.status == nil {
+			continue
+		}
+
+		statuses = append(statuses, PlantStatus{
+			Variable:       v.name,
+			StatusKey:      v.status.StatusKey,
+			InstructionKey: v.status.InstructionKey,
+			MinThreshold:   v.status.GaugeValues.MinThreshold,
+			MaxThreshold:   v.status.GaugeValues.MaxThreshold,
+			CurrentValue:   v.status.GaugeValues.CurrentValue,
+		})
+	}
+
+	return statuses
 }
 
 // configurationData is a type used when parsing the response from Parrot
@@ -84,11 +160,45 @@ type configurationLocation struct {
 	Nickname   string              `json:"plant_nickname"`
 	Longitude  float64             `json:"longitude"`
 	Latitude   float64             `json:"latitude"`
+	IsIndoor   null.Bool           `json:"is_indoor"`
+	InPot      null.Bool           `json:"in_pot"`
+	PlantIDs   []int64             `json:"plant_ids"`
 }
 
-// configurationSensor captures the serial number in the response from parrot
+// configurationSensor captures the serial number and hardware details of the
+// sensor in the response from parrot
 type configurationSensor struct {
-	SerialNum string `json:"sensor_serial"`
+	SerialNum        string                    `json:"sensor_serial"`
+	FirmwareVersion  string                    `json:"firmware_version"`
+	HardwareRevision string                    `json:"hardware_revision"`
+	SensorType       string                    `json:"sensor_type"`
+	CalibrationData  string                    `json:"calibration_data"`
+	Autowatering     *configurationWateringCfg `json:"autowatering_cfg"`
+}
+
+// configurationWateringCfg is the automatic watering configuration of pot
+// sensors, the mode is off for sensors that can't water
+type configurationWateringCfg struct {
+	Mode string `json:"mode"`
+}
+
+// hardware returns the hardware details of the configuration location
+func (c *configurationLocation) hardware() Hardware {
+	hardware := Hardware{
+		FirmwareVersion:  c.Sensor.FirmwareVersion,
+		HardwareRevision: c.Sensor.HardwareRevision,
+		SensorType:       c.Sensor.SensorType,
+		CalibrationData:  c.Sensor.CalibrationData,
+		IsIndoor:         c.IsIndoor,
+		InPot:            c.InPot,
+		PlantIDs:         c.PlantIDs,
+	}
+
+	if c.Sensor.Autowatering != nil {
+		hardware.AutowateringMode = c.Sensor.Autowatering.Mode
+	}
+
+	return hardware
 }
 
 // userData is a type used when parsing the user profile response
@@ -170,6 +280,9 @@ func GetLocations(ctx context.Context, client *client.Client, accessToken string
 					LastSampleUTC:  l.LastSampleUTC,
 					Longitude:      cl.Longitude,
 					Latitude:       cl.Latitude,
+					StatusUTC:      l.StatusUTC,
+					PlantStatuses:  l.plantStatuses(),
+					Hardware:       cl.hardware(),
 				}
 
 				locations = append(locations, location)
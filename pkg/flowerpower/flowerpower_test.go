@@ -10,6 +10,7 @@ import (
 	"time"
 
 	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
 	"github.com/stretchr/testify/assert"
 	"github.com/thingful/simular"
 
@@ -122,6 +123,49 @@ func TestGetLocations(t *testing.T) {
 
 	assert.NotEqual(t, "", locations[0].SerialNum)
 
+	var location *flowerpower.Location
+	for i := range locations {
+		if locations[i].LocationID == "Gu80jTmwyq1539530459586" {
+			location = &locations[i]
+		}
+	}
+
+	assert.NotNil(t, location)
+	assert.Equal(t, time.Date(2018, 12, 21, 15, 12, 27, 0, time.UTC), location.StatusUTC)
+	assert.Len(t, location.PlantStatuses, 6)
+	assert.Equal(t, flowerpower.PlantStatus{
+		Variable:       "air_temperature",
+		StatusKey:      "status_warning",
+		InstructionKey: "air_temperature_too_low",
+		MinThreshold:   null.FloatFrom(7),
+		MaxThreshold:   null.FloatFrom(30),
+		CurrentValue:   null.FloatFrom(-0.330695897458571),
+	}, location.PlantStatuses[0])
+
+	statuses := map[string]flowerpower.PlantStatus{}
+	for _, status := range location.PlantStatuses {
+		statuses[status.Variable] = status
+	}
+
+	assert.Equal(t, "soil_moisture_prediction", statuses["soil_moisture"].InstructionKey)
+	assert.False(t, statuses["soil_moisture"].MaxThreshold.Valid)
+	assert.Equal(t, "", statuses["battery_level"].StatusKey)
+	assert.Equal(t, null.FloatFrom(71), statuses["battery_level"].CurrentValue)
+
+	assert.Equal(t, flowerpower.Hardware{
+		FirmwareVersion:  "2016-09-14_hawaii-2.0.3_hardware-config-MP",
+		HardwareRevision: "2013-07-26_hawaiiProduction-1.2_protoDV-bootloader",
+		SensorType:       "flower-power",
+		CalibrationData:  "0000f6001900ffffffffa500ef025405d005df002802",
+		IsIndoor:         null.BoolFrom(false),
+		PlantIDs:         []int64{2518},
+		AutowateringMode: "off",
+	}, location.Hardware)
+
+	for _, l := range locations {
+		assert.Equal(t, l.LocationID == "xVSKyqnIDy1538489204954", l.Hardware.IsIndoor.Bool)
+	}
+
 	err = simular.AllStubsCalled()
 	assert.Nil(t, err)
 }
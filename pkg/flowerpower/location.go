@@ -1,6 +1,10 @@
 package flowerpower
 
-import "time"
+import (
+	"time"
+
+	"github.com/guregu/null"
+)
 
 // Location is a type used to export sensor configuration as read from the
 // Parrot API.
@@ -12,4 +16,39 @@ type Location struct {
 	LastSampleUTC  time.Time
 	Longitude      float64
 	Latitude       float64
+
+	// StatusUTC is when Parrot last assessed the plant, and PlantStatuses
+	// contains their assessment of each variable
+	StatusUTC     time.Time
+	PlantStatuses []PlantStatus
+
+	// Hardware describes the sensor and how it is installed
+	Hardware Hardware
+}
+
+// Hardware is the sensor metadata read from the Parrot configuration. The
+// firmware version starts with its release date, e.g.
+// 2016-09-14_hawaii-2.0.3_hardware-config-MP, and the sensor type is either
+// flower-power or pot.
+type Hardware struct {
+	FirmwareVersion  string
+	HardwareRevision string
+	SensorType       string
+	CalibrationData  string
+	IsIndoor         null.Bool
+	InPot            null.Bool
+	PlantIDs         []int64
+	AutowateringMode string
+}
+
+// PlantStatus is Parrot's own assessment of a single variable of a location,
+// e.g. a status key of status_warning with an instruction key of
+// air_temperature_too_low, along with the thresholds used to make it
+type PlantStatus struct {
+	Variable       string
+	StatusKey      string
+	InstructionKey string
+	MinThreshold   null.Float
+	MaxThreshold   null.Float
+	CurrentValue   null.Float
 }
@@ -19,3 +19,81 @@ type Reading struct {
 type SampleData struct {
 	Readings []Reading `json:"samples"`
 }
+
+// SampleRange records the timestamps of the first and last meaningful
+// observation for a single channel
+type SampleRange struct {
+	First time.Time
+	Last  time.Time
+}
+
+// include widens the range to include the given timestamp
+func (s *SampleRange) include(t time.Time) {
+	if s.First.IsZero() || t.Before(s.First) {
+		s.First = t
+	}
+
+	if t.After(s.Last) {
+		s.Last = t
+	}
+}
+
+// SampleRanges returns the sample range of each channel present in the given
+// readings keyed by channel name. The water tank level is only reported by pot
+// sensors, so zero values are ignored when calculating its range.
+func SampleRanges(readings []Reading) map[string]SampleRange {
+	ranges := map[string]*SampleRange{}
+
+	add := func(name string, t time.Time) {
+		r, ok := ranges[name]
+		if !ok {
+			r = &SampleRange{}
+			ranges[name] = r
+		}
+		r.include(t)
+	}
+
+	for _, reading := range readings {
+		add("air_temperature", reading.Timestamp)
+		add("fertilizer_level", reading.Timestamp)
+		add("light", reading.Timestamp)
+		add("soil_moisture", reading.Timestamp)
+		add("calibrated_soil_moisture", reading.Timestamp)
+		add("battery_level", reading.Timestamp)
+
+		if reading.WaterTankLevel != 0 {
+			add("water_tank_level", reading.Timestamp)
+		}
+	}
+
+	result := map[string]SampleRange{}
+	for name, r := range ranges {
+		result[name] = *r
+	}
+
+	return result
+}
+
+// Value returns the value of the channel with the given name, and whether the
+// reading contains a value for it. The water tank level is only reported by pot
+// sensors, so a zero value is treated as missing.
+func (r *Reading) Value(channel string) (float64, bool) {
+	switch channel {
+	case "air_temperature":
+		return r.AirTemperature, true
+	case "fertilizer_level":
+		return r.FertilizerLevel, true
+	case "light":
+		return r.Light, true
+	case "soil_moisture":
+		return r.SoilMoisture, true
+	case "calibrated_soil_moisture":
+		return r.CalibratedSoilMoisture, true
+	case "battery_level":
+		return r.BatteryLevel, true
+	case "water_tank_level":
+		return r.WaterTankLevel, r.WaterTankLevel != 0
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,56 @@
+package flowerpower_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/flowerpower"
+)
+
+func TestSampleRanges(t *testing.T) {
+	t1 := time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(15 * time.Minute)
+	t3 := t2.Add(15 * time.Minute)
+
+	readings := []flowerpower.Reading{
+		{Timestamp: t2, AirTemperature: 12.5, WaterTankLevel: 40},
+		{Timestamp: t1, AirTemperature: 12.1},
+		{Timestamp: t3, AirTemperature: 13.2},
+	}
+
+	ranges := flowerpower.SampleRanges(readings)
+	assert.Len(t, ranges, 7)
+
+	assert.Equal(t, flowerpower.SampleRange{First: t1, Last: t3}, ranges["air_temperature"])
+	assert.Equal(t, flowerpower.SampleRange{First: t2, Last: t2}, ranges["water_tank_level"])
+}
+
+func TestSampleRangesWithoutWaterTank(t *testing.T) {
+	t1 := time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	ranges := flowerpower.SampleRanges([]flowerpower.Reading{{Timestamp: t1}})
+
+	_, ok := ranges["water_tank_level"]
+	assert.False(t, ok)
+	assert.Len(t, ranges, 6)
+}
+
+func TestReadingValue(t *testing.T) {
+	reading := flowerpower.Reading{SoilMoisture: 21.5, BatteryLevel: 80}
+
+	value, ok := reading.Value("soil_moisture")
+	assert.True(t, ok)
+	assert.Equal(t, 21.5, value)
+
+	value, ok = reading.Value("battery_level")
+	assert.True(t, ok)
+	assert.Equal(t, 80.0, value)
+
+	_, ok = reading.Value("water_tank_level")
+	assert.False(t, ok)
+
+	_, ok = reading.Value("unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// FieldCost is the cost of resolving a field once, used when checking the cost
+// of a query before executing it.
+//
+// If Cost is zero, fields of leaf types are free and fields of object types,
+// or lists of them, cost 1. Size is an estimate of the number of items returned
+// by a list field given the arguments of the field, which multiplies the cost
+// of the fields selected within it. If Size is nil, lists are assumed to
+// contain a single item.
+type FieldCost struct {
+	Cost int
+	Size func(args map[string]interface{}) int
+}
+
+// Costs maps fields, named as Type.field, to their costs. Fields which are not
+// listed have the default cost.
+type Costs map[string]FieldCost
+
+// leaf is implemented by the scalar and enum types, whose values are parsed
+// from either a variable or a literal within the query
+type leaf interface {
+	ParseValue(value interface{}) interface{}
+	ParseLiteral(value ast.Value) interface{}
+}
+
+// coster calculates the cost of the selections of an operation
+type coster struct {
+	costs     Costs
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selectionCost returns the cost of the fields selected on the object, each
+// of which is resolved count times. The selections have been validated, so we
+// need not check that fields exist or that fragments apply to the object.
+func (c *coster) selectionCost(obj *gql.Object, set *ast.SelectionSet, count int) int {
+	if set == nil {
+		return 0
+	}
+
+	total := 0
+
+	for _, selection := range set.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			field, ok := obj.Fields()[sel.Name.Value]
+			if !ok {
+				// introspection fields such as __typename are free
+				continue
+			}
+
+			fc := c.costs[obj.Name()+"."+field.Name]
+			child, isObject := gql.GetNamed(field.Type).(*gql.Object)
+
+			cost := fc.Cost
+			if cost == 0 && isObject {
+				cost = 1
+			}
+			total += cost * count
+
+			size := 1
+			if fc.Size != nil {
+				size = fc.Size(c.arguments(field, sel))
+			}
+
+			if isObject {
+				total += c.selectionCost(child, sel.SelectionSet, count*size)
+			}
+
+		case *ast.InlineFragment:
+			total += c.selectionCost(obj, sel.SelectionSet, count)
+
+		case *ast.FragmentSpread:
+			if frag, ok := c.fragments[sel.Name.Value]; ok {
+				total += c.selectionCost(obj, frag.SelectionSet, count)
+			}
+		}
+	}
+
+	return total
+}
+
+// arguments returns the values of the arguments given to a field, applying
+// any default values. Arguments which cannot be coerced to their type are
+// treated as if they were not given, as the query will fail to execute.
+func (c *coster) arguments(field *gql.FieldDefinition, sel *ast.Field) map[string]interface{} {
+	given := map[string]ast.Value{}
+	for _, arg := range sel.Arguments {
+		given[arg.Name.Value] = arg.Value
+	}
+
+	args := map[string]interface{}{}
+	for _, def := range field.Args {
+		value := c.value(def.Type, given[def.Name()])
+		if value == nil {
+			value = def.DefaultValue
+		}
+
+		if value != nil {
+			args[def.Name()] = value
+		}
+	}
+
+	return args
+}
+
+// value coerces the value given to an argument to the type of the argument,
+// returning nil if it cannot
+func (c *coster) value(t gql.Input, value ast.Value) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if v, ok := value.(*ast.Variable); ok {
+		return coerce(t, c.variables[v.Name.Value])
+	}
+
+	switch typ := t.(type) {
+	case *gql.NonNull:
+		return c.value(typ.OfType, value)
+
+	case *gql.List:
+		items := []ast.Value{value}
+		if list, ok := value.(*ast.ListValue); ok {
+			items = list.Values
+		}
+
+		values := []interface{}{}
+		for _, item := range items {
+			values = append(values, c.value(typ.OfType, item))
+		}
+		return values
+
+	case leaf:
+		return typ.ParseLiteral(value)
+	}
+
+	return nil
+}
+
+// coerce converts the value of a variable to the given type, returning nil if
+// it cannot
+func coerce(t gql.Input, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch typ := t.(type) {
+	case *gql.NonNull:
+		return coerce(typ.OfType, value)
+
+	case *gql.List:
+		items, ok := value.([]interface{})
+		if !ok {
+			// a single value is accepted in place of a list of one item
+			items = []interface{}{value}
+		}
+
+		values := []interface{}{}
+		for _, item := range items {
+			values = append(values, coerce(typ.OfType, item))
+		}
+		return values
+
+	case leaf:
+		return typ.ParseValue(value)
+	}
+
+	return nil
+}
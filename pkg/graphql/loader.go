@@ -0,0 +1,80 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc loads the values of many keys at once, returning a value for each
+// key in the same order as the keys
+type BatchFunc func(ctx context.Context, keys []string) ([]interface{}, error)
+
+// Loader batches and caches loads by key. Keys passed to Load are queued
+// until the first returned thunk is called, at which point every queued key is
+// loaded by a single call to the BatchFunc. As the executor calls every
+// resolver of a level of the query before calling any thunk, each level is
+// loaded with one call. A Loader should only be used for a single request.
+type Loader struct {
+	batch   BatchFunc
+	pending []string
+	results map[string]*loaderResult
+	sync.Mutex
+}
+
+// loaderResult is the outcome of loading a single key
+type loaderResult struct {
+	value interface{}
+	err   error
+	done  bool
+}
+
+// NewLoader returns a new Loader which loads values with the given BatchFunc
+func NewLoader(batch BatchFunc) *Loader {
+	return &Loader{
+		batch:   batch,
+		results: map[string]*loaderResult{},
+	}
+}
+
+// Load queues the key to be loaded, returning a thunk which returns its value.
+// Resolvers may return the thunk in place of a value, or a list of thunks in
+// place of a list.
+func (l *Loader) Load(ctx context.Context, key string) func() (interface{}, error) {
+	l.Lock()
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = &loaderResult{}
+		l.pending = append(l.pending, key)
+	}
+	l.Unlock()
+
+	return func() (interface{}, error) {
+		l.Lock()
+		defer l.Unlock()
+
+		result := l.results[key]
+		if !result.done {
+			l.dispatch(ctx)
+		}
+
+		return result.value, result.err
+	}
+}
+
+// dispatch loads every pending key. Must be called with the lock held.
+func (l *Loader) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.batch(ctx, keys)
+
+	for i, key := range keys {
+		result := l.results[key]
+		result.done = true
+
+		if err != nil {
+			result.err = err
+		} else if i < len(values) {
+			result.value = values[i]
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package graphql prepares and executes GraphQL queries using
+// github.com/graphql-go/graphql. It adds a check of the cost of a query before
+// it is executed, and a Loader which lets resolvers load the values of every
+// field of a level of the query in a single batch.
+package graphql
+
+import (
+	"context"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is the body of a GraphQL request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query is a request which has been parsed and validated against a schema,
+// ready to be executed
+type Query struct {
+	schema *gql.Schema
+	doc    *ast.Document
+	req    *Request
+}
+
+// Prepare parses the query of the request and validates it against the schema,
+// returning a Query ready to execute. If the query is invalid the errors found
+// are returned instead.
+func Prepare(schema *gql.Schema, req *Request) (*Query, []gqlerrors.FormattedError) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(req.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return nil, gqlerrors.FormatErrors(err)
+	}
+
+	result := gql.ValidateDocument(schema, doc, nil)
+	if !result.IsValid {
+		return nil, result.Errors
+	}
+
+	return &Query{
+		schema: schema,
+		doc:    doc,
+		req:    req,
+	}, nil
+}
+
+// Cost returns the cost of the query, calculated from the costs of each of the
+// selected fields multiplied by the estimated size of any enclosing lists
+func (q *Query) Cost(costs Costs) int {
+	op, fragments := operation(q.doc, q.req.OperationName)
+	if op == nil {
+		return 0
+	}
+
+	c := &coster{
+		costs:     costs,
+		fragments: fragments,
+		variables: q.req.Variables,
+	}
+
+	return c.selectionCost(q.schema.QueryType(), op.SelectionSet, 1)
+}
+
+// Execute executes the query, returning the result to be written to the
+// client. Errors raised by resolvers are added to the errors of the result,
+// with the field they were raised for set to null.
+func (q *Query) Execute(ctx context.Context) *gql.Result {
+	return gql.Execute(gql.ExecuteParams{
+		Schema:        *q.schema,
+		AST:           q.doc,
+		OperationName: q.req.OperationName,
+		Args:          q.req.Variables,
+		Context:       ctx,
+	})
+}
+
+// operation returns the named operation of the document, or its only operation
+// if the name is empty, along with the fragments of the document by name
+func operation(doc *ast.Document, name string) (*ast.OperationDefinition, map[string]*ast.FragmentDefinition) {
+	var op *ast.OperationDefinition
+	fragments := map[string]*ast.FragmentDefinition{}
+
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if name == "" || (def.Name != nil && def.Name.Value == name) {
+				op = def
+			}
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		}
+	}
+
+	return op, fragments
+}
@@ -0,0 +1,274 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/graphql"
+)
+
+type person struct {
+	Name    string
+	Age     int
+	Friends []string
+}
+
+var people = map[string]*person{
+	"alice": {Name: "alice", Age: 30, Friends: []string{"bob", "carol"}},
+	"bob":   {Name: "bob", Age: 25, Friends: []string{"alice", "carol"}},
+	"carol": {Name: "carol", Age: 35, Friends: []string{"alice"}},
+}
+
+// costs are the costs of the fields of the test schema
+var costs = graphql.Costs{
+	"Person.friends": {
+		Size: func(args map[string]interface{}) int {
+			return args["first"].(int)
+		},
+	},
+	"Person.secret": {Cost: 5},
+}
+
+// newTestSchema returns a schema of people and a pointer to the recorded
+// batches of keys loaded by its loader
+func newTestSchema(t *testing.T) (*gql.Schema, *[][]string) {
+	batches := [][]string{}
+	var mu sync.Mutex
+
+	loader := graphql.NewLoader(func(ctx context.Context, keys []string) ([]interface{}, error) {
+		mu.Lock()
+		batches = append(batches, keys)
+		mu.Unlock()
+
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			if p, ok := people[key]; ok {
+				values[i] = p
+			}
+		}
+		return values, nil
+	})
+
+	var personType *gql.Object
+	personType = gql.NewObject(gql.ObjectConfig{
+		Name: "Person",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"name": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return p.Source.(*person).Name, nil
+					},
+				},
+				"age": {
+					Type: gql.Int,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return p.Source.(*person).Age, nil
+					},
+				},
+				"secret": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("not permitted")
+					},
+				},
+				"friends": {
+					Type: gql.NewList(personType),
+					Args: gql.FieldConfigArgument{
+						"first": {Type: gql.Int, DefaultValue: 10},
+					},
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						friends := p.Source.(*person).Friends
+						if first := p.Args["first"].(int); first < len(friends) {
+							friends = friends[:first]
+						}
+
+						thunks := []interface{}{}
+						for _, name := range friends {
+							thunks = append(thunks, loader.Load(p.Context, name))
+						}
+						return thunks, nil
+					},
+				},
+			}
+		}),
+	})
+
+	schema, err := gql.NewSchema(gql.SchemaConfig{
+		Query: gql.NewObject(gql.ObjectConfig{
+			Name: "Query",
+			Fields: gql.Fields{
+				"person": {
+					Type: personType,
+					Args: gql.FieldConfigArgument{
+						"name": {Type: gql.NewNonNull(gql.String)},
+					},
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return loader.Load(p.Context, p.Args["name"].(string)), nil
+					},
+				},
+			},
+		}),
+	})
+	assert.Nil(t, err)
+
+	return &schema, &batches
+}
+
+func TestExecute(t *testing.T) {
+	schema, batches := newTestSchema(t)
+
+	query, errs := graphql.Prepare(schema, &graphql.Request{
+		Query: `
+			query Friends($name: String!) {
+				a: person(name: $name) { ...fields friends { name friends(first: 1) { name } } }
+				b: person(name: "bob") { name, __typename }
+				c: person(name: "nobody") { name }
+			}
+
+			fragment fields on Person { name age }
+		`,
+		Variables: map[string]interface{}{"name": "alice"},
+	})
+	assert.Nil(t, errs)
+
+	result := query.Execute(context.Background())
+	assert.Nil(t, result.Errors)
+
+	b, err := json.Marshal(result)
+	assert.Nil(t, err)
+
+	assert.JSONEq(t, `{
+		"data": {
+			"a": {
+				"name": "alice",
+				"age": 30,
+				"friends": [
+					{"name": "bob", "friends": [{"name": "alice"}]},
+					{"name": "carol", "friends": [{"name": "alice"}]}
+				]
+			},
+			"b": {"name": "bob", "__typename": "Person"},
+			"c": null
+		}
+	}`, string(b))
+
+	// each level of the query is loaded in a single batch, and loaded values
+	// are cached
+	if assert.Len(t, *batches, 2) {
+		assert.ElementsMatch(t, []string{"alice", "bob", "nobody"}, (*batches)[0])
+		assert.Equal(t, []string{"carol"}, (*batches)[1])
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	schema, _ := newTestSchema(t)
+
+	query, errs := graphql.Prepare(schema, &graphql.Request{
+		Query: `{ person(name: "alice") { name secret } }`,
+	})
+	assert.Nil(t, errs)
+
+	b, err := json.Marshal(query.Execute(context.Background()))
+	assert.Nil(t, err)
+
+	assert.JSONEq(t, `{
+		"data": {"person": {"name": "alice", "secret": null}},
+		"errors": [
+			{"message": "not permitted", "locations": [{"line": 1, "column": 32}], "path": ["person", "secret"]}
+		]
+	}`, string(b))
+}
+
+func TestPrepareInvalid(t *testing.T) {
+	schema, _ := newTestSchema(t)
+
+	testcases := []struct {
+		label    string
+		query    string
+		expected string
+	}{
+		{
+			label:    "syntax error",
+			query:    `{ person(name: "alice") { name }`,
+			expected: "Syntax Error GraphQL request (1:33) Expected Name, found EOF",
+		},
+		{
+			label:    "unknown field",
+			query:    `{ person(name: "alice") { height } }`,
+			expected: `Cannot query field "height" on type "Person".`,
+		},
+		{
+			label:    "missing argument",
+			query:    `{ person { name } }`,
+			expected: `Field "person" argument "name" of type "String!" is required but not provided.`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			_, errs := graphql.Prepare(schema, &graphql.Request{Query: tc.query})
+			if assert.Len(t, errs, 1) {
+				assert.Contains(t, errs[0].Message, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCost(t *testing.T) {
+	schema, _ := newTestSchema(t)
+
+	testcases := []struct {
+		label     string
+		query     string
+		variables map[string]interface{}
+		expected  int
+	}{
+		{
+			label:    "scalars are free",
+			query:    `{ person(name: "alice") { name age __typename } }`,
+			expected: 1,
+		},
+		{
+			label:    "fields may have a cost",
+			query:    `{ person(name: "alice") { secret } }`,
+			expected: 1 + 5,
+		},
+		{
+			label:    "lists multiply",
+			query:    `{ person(name: "alice") { friends { friends(first: 3) { name } } } }`,
+			expected: 1 + 1 + 10,
+		},
+		{
+			label:     "variables",
+			query:     `query ($first: Int) { person(name: "alice") { friends(first: $first) { secret } } }`,
+			variables: map[string]interface{}{"first": float64(2)},
+			expected:  1 + 1 + 2*5,
+		},
+		{
+			label:    "omitted variables use the default",
+			query:    `query ($first: Int) { person(name: "alice") { friends(first: $first) { secret } } }`,
+			expected: 1 + 1 + 10*5,
+		},
+		{
+			label:    "fragments",
+			query:    `{ person(name: "alice") { ...f ... on Person { secret } } } fragment f on Person { friends(first: 2) { name } }`,
+			expected: 1 + 1 + 5,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			query, errs := graphql.Prepare(schema, &graphql.Request{Query: tc.query, Variables: tc.variables})
+			if assert.Nil(t, errs) {
+				assert.Equal(t, tc.expected, query.Cost(costs))
+			}
+		})
+	}
+}
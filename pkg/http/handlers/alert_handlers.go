@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// RegisterAlertHandlers registers the endpoints apps use to manage their alert
+// rules and read the current state of their alerts. Alerts carry the reading
+// that breached the rule, so creating rules and reading alerts also requires
+// the timeseries scope.
+func RegisterAlertHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB) {
+	mux.Handle(perms.Require(perms.Audit(pat.Post("/alerts/rules/new")), postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope), Handler{env: &Env{db: db}, handler: createAlertRuleHandler})
+	mux.Handle(perms.Require(pat.Get("/alerts/rules"), postgres.GetMetadataScope), Handler{env: &Env{db: db}, handler: listAlertRulesHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Delete("/alerts/rules/:uid")), postgres.GetMetadataScope), Handler{env: &Env{db: db}, handler: deleteAlertRuleHandler})
+	mux.Handle(perms.Require(pat.Get("/alerts"), postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope), Handler{env: &Env{db: db}, handler: listAlertsHandler})
+}
+
+type alertRuleRequest struct {
+	Rule struct {
+		ThingUID   string   `json:"ThingUid"`
+		UserUID    string   `json:"UserUid"`
+		Channel    string   `json:"Channel"`
+		Comparator string   `json:"Comparator"`
+		Threshold  *float64 `json:"Threshold"`
+		Duration   int      `json:"Duration"`
+		Cooldown   int      `json:"Cooldown"`
+	} `json:"Rule"`
+}
+
+type alertRuleJSON struct {
+	UID        string      `json:"Uid"`
+	ThingUID   null.String `json:"ThingUid"`
+	UserUID    null.String `json:"UserUid"`
+	Channel    string      `json:"Channel"`
+	Comparator string      `json:"Comparator"`
+	Threshold  float64     `json:"Threshold"`
+	Duration   int         `json:"Duration"`
+	Cooldown   int         `json:"Cooldown"`
+	CreatedAt  time.Time   `json:"CreatedAt"`
+}
+
+type alertJSON struct {
+	RuleUID      string    `json:"RuleUid"`
+	ThingUID     string    `json:"ThingUid"`
+	State        string    `json:"State"`
+	Value        float64   `json:"Value"`
+	PendingSince null.Time `json:"PendingSince"`
+	FiredAt      null.Time `json:"FiredAt"`
+	ResolvedAt   null.Time `json:"ResolvedAt"`
+	UpdatedAt    time.Time `json:"UpdatedAt"`
+}
+
+func newAlertRuleJSON(rule *postgres.AlertRule) alertRuleJSON {
+	return alertRuleJSON{
+		UID:        rule.UID,
+		ThingUID:   rule.ThingUID,
+		UserUID:    rule.UserUID,
+		Channel:    rule.Channel,
+		Comparator: rule.Comparator,
+		Threshold:  rule.Threshold,
+		Duration:   rule.Duration,
+		Cooldown:   rule.Cooldown,
+		CreatedAt:  rule.CreatedAt,
+	}
+}
+
+func createAlertRuleHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req, err := parseCreateAlertRuleRequest(r)
+	if err != nil {
+		return err
+	}
+
+	if (req.Rule.ThingUID == "") == (req.Rule.UserUID == "") {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("a rule must have either a ThingUid or a UserUid"),
+		}
+	}
+
+	if req.Rule.Threshold == nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("a rule must have a Threshold"),
+		}
+	}
+
+	rule, err := env.db.CreateAlertRule(ctx, middleware.SubjectFromContext(ctx), &postgres.AlertRule{
+		ThingUID:   null.NewString(req.Rule.ThingUID, req.Rule.ThingUID != ""),
+		UserUID:    null.NewString(req.Rule.UserUID, req.Rule.UserUID != ""),
+		Channel:    req.Rule.Channel,
+		Comparator: req.Rule.Comparator,
+		Threshold:  *req.Rule.Threshold,
+		Duration:   req.Rule.Duration,
+		Cooldown:   req.Rule.Cooldown,
+	})
+	if err != nil {
+		switch errors.Cause(err) {
+		case postgres.ClientError:
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  err,
+			}
+		default:
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to save alert rule to the database"),
+			}
+		}
+	}
+
+	middleware.AddAuditTarget(ctx, "alert-rule", rule.UID)
+
+	log.Log(
+		"msg", "created alert rule",
+		"uid", rule.UID,
+	)
+
+	b, err := json.Marshal(struct {
+		Rule alertRuleJSON `json:"Rule"`
+	}{
+		Rule: newAlertRuleJSON(rule),
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(b)
+
+	return nil
+}
+
+func listAlertRulesHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	rules, err := env.db.ListAlertRules(ctx, middleware.SubjectFromContext(ctx))
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to list alert rules"),
+		}
+	}
+
+	resp := []alertRuleJSON{}
+	for i := range rules {
+		resp = append(resp, newAlertRuleJSON(&rules[i]))
+	}
+
+	b, err := json.Marshal(struct {
+		Rules []alertRuleJSON `json:"Rules"`
+	}{
+		Rules: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+func deleteAlertRuleHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	uid := pat.Param(r, "uid")
+	middleware.AddAuditTarget(ctx, "alert-rule", uid)
+
+	err := env.db.DeleteAlertRule(ctx, middleware.SubjectFromContext(ctx), uid)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("alert rule not found"),
+			}
+		}
+
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to delete alert rule"),
+		}
+	}
+
+	log.Log(
+		"msg", "deleted alert rule",
+		"uid", uid,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// listAlertsHandler returns the alerts of the app's rules in the requested
+// state, defaulting to those currently firing
+func listAlertsHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		state = postgres.AlertStateFiring
+	}
+
+	if !postgres.IsValidAlertState(state) {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Errorf("unknown state: %s, expected one of pending, firing or resolved", state),
+		}
+	}
+
+	alerts, err := env.db.ListAlerts(ctx, middleware.SubjectFromContext(ctx), state)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to list alerts"),
+		}
+	}
+
+	resp := []alertJSON{}
+	for _, alert := range alerts {
+		resp = append(resp, alertJSON{
+			RuleUID:      alert.RuleUID,
+			ThingUID:     alert.ThingUID,
+			State:        alert.State,
+			Value:        alert.Value,
+			PendingSince: alert.PendingSince,
+			FiredAt:      alert.FiredAt,
+			ResolvedAt:   alert.ResolvedAt,
+			UpdatedAt:    alert.UpdatedAt,
+		})
+	}
+
+	b, err := json.Marshal(struct {
+		Alerts []alertJSON `json:"Alerts"`
+	}{
+		Alerts: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+func parseCreateAlertRuleRequest(r *http.Request) (*alertRuleRequest, error) {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var data alertRuleRequest
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,169 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+	goji "goji.io"
+)
+
+type AlertHandlersSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+	app    *postgres.App
+	mux    *goji.Mux
+}
+
+func (s *AlertHandlersSuite) SetupTest() {
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.logger = kitlog.NewNopLogger()
+	s.db = helper.PrepareDB(s.T(), connStr, s.logger)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "Client", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+	s.app = app
+
+	_, err = s.db.DB.Exec(`INSERT INTO users (uid) VALUES ('user1')`)
+	assert.Nil(s.T(), err)
+
+	s.mux = goji.NewMux()
+	perms := middleware.NewPermissions("")
+	handlers.RegisterAlertHandlers(s.mux, perms, s.db)
+
+	authMiddleware := middleware.NewAuthMiddleware(s.db)
+	s.mux.Use(authMiddleware.Handler)
+	s.mux.Use(perms.Handler)
+}
+
+func (s *AlertHandlersSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+// request makes an authenticated request to the mux, returning the recorded
+// response
+func (s *AlertHandlersSuite) request(method, path string, body []byte) *httptest.ResponseRecorder {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	assert.Nil(s.T(), err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.app.Key))
+
+	recorder := httptest.NewRecorder()
+	s.mux.ServeHTTP(recorder, req.WithContext(ctx))
+
+	return recorder
+}
+
+func (s *AlertHandlersSuite) TestAlertRuleLifecycle() {
+	recorder := s.request(http.MethodPost, "/alerts/rules/new", []byte(`
+	{
+		"Rule": {
+			"UserUid": "user1",
+			"Channel": "soil_moisture",
+			"Comparator": "lt",
+			"Threshold": 15,
+			"Duration": 3600
+		}
+	}`))
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+
+	var created struct {
+		Rule struct {
+			UID       string  `json:"Uid"`
+			UserUID   string  `json:"UserUid"`
+			Threshold float64 `json:"Threshold"`
+			Duration  int     `json:"Duration"`
+		} `json:"Rule"`
+	}
+
+	err := json.Unmarshal(recorder.Body.Bytes(), &created)
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), "", created.Rule.UID)
+	assert.Equal(s.T(), "user1", created.Rule.UserUID)
+	assert.Equal(s.T(), 15.0, created.Rule.Threshold)
+	assert.Equal(s.T(), 3600, created.Rule.Duration)
+
+	recorder = s.request(http.MethodGet, "/alerts/rules", nil)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), created.Rule.UID)
+
+	recorder = s.request(http.MethodGet, "/alerts", nil)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{"Alerts":[]}`, recorder.Body.String())
+
+	recorder = s.request(http.MethodDelete, "/alerts/rules/"+created.Rule.UID, nil)
+	assert.Equal(s.T(), http.StatusNoContent, recorder.Code)
+
+	recorder = s.request(http.MethodDelete, "/alerts/rules/"+created.Rule.UID, nil)
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *AlertHandlersSuite) TestCreateAlertRuleInvalid() {
+	testcases := []struct {
+		label         string
+		input         string
+		expectedError string
+	}{
+		{
+			label:         "missing target",
+			input:         `{"Rule":{"Channel":"light","Comparator":"lt","Threshold":1}}`,
+			expectedError: "a rule must have either a ThingUid or a UserUid",
+		},
+		{
+			label:         "both targets",
+			input:         `{"Rule":{"ThingUid":"abc123","UserUid":"user1","Channel":"light","Comparator":"lt","Threshold":1}}`,
+			expectedError: "a rule must have either a ThingUid or a UserUid",
+		},
+		{
+			label:         "missing threshold",
+			input:         `{"Rule":{"UserUid":"user1","Channel":"light","Comparator":"lt"}}`,
+			expectedError: "a rule must have a Threshold",
+		},
+		{
+			label:         "unknown channel",
+			input:         `{"Rule":{"UserUid":"user1","Channel":"humidity","Comparator":"lt","Threshold":1}}`,
+			expectedError: "unknown channel: humidity",
+		},
+		{
+			label:         "unknown thing",
+			input:         `{"Rule":{"ThingUid":"abc123","Channel":"light","Comparator":"lt","Threshold":1}}`,
+			expectedError: "unknown thing: abc123",
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder := s.request(http.MethodPost, "/alerts/rules/new", []byte(tc.input))
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.expectedError)
+		})
+	}
+}
+
+func (s *AlertHandlersSuite) TestListAlertsInvalidState() {
+	recorder := s.request(http.MethodGet, "/alerts?state=silenced", nil)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "unknown state: silenced")
+}
+
+func TestAlertHandlersSuite(t *testing.T) {
+	suite.Run(t, new(AlertHandlersSuite))
+}
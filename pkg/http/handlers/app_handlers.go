@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"time"
 
+	"github.com/guregu/null"
 	"github.com/pkg/errors"
 	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/logger"
@@ -13,15 +16,31 @@ import (
 	"goji.io/pat"
 )
 
-// RegisterAppHandlers registers our new app creation endpoint with the mux
-func RegisterAppHandlers(mux *goji.Mux, db *postgres.DB) {
-	mux.Handle(pat.Post("/apps/new"), Handler{env: &Env{db: db}, handler: createAppHandler})
+const (
+	// defaultRotationOverlap is how long the previous key of a rotated app
+	// remains valid if the client doesn't say
+	defaultRotationOverlap = 24 * time.Hour
+
+	// maxRotationOverlap is the longest we allow two keys to be valid for
+	maxRotationOverlap = 30 * 24 * time.Hour
+)
+
+// RegisterAppHandlers registers our app creation and key management endpoints
+// with the mux
+func RegisterAppHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB) {
+	mux.Handle(perms.Require(perms.Audit(pat.Post("/apps/new")), postgres.ManageAppsScope), Handler{env: &Env{db: db}, handler: createAppHandler})
+	mux.Handle(perms.Require(pat.Get("/apps"), postgres.ManageAppsScope), Handler{env: &Env{db: db}, handler: listAppsHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Patch("/apps/:uid")), postgres.ManageAppsScope), Handler{env: &Env{db: db}, handler: updateAppHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Post("/apps/:uid/revoke")), postgres.ManageAppsScope), Handler{env: &Env{db: db}, handler: revokeAppHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Post("/apps/:uid/rotate")), postgres.ManageAppsScope), Handler{env: &Env{db: db}, handler: rotateAppHandler})
 }
 
 type appRequest struct {
 	App struct {
 		Name  string               `json:"Name"`
 		Scope postgres.ScopeClaims `json:"Scope"`
+		Rate  int                  `json:"Rate"`
+		Burst int                  `json:"Burst"`
 	} `json:"App"`
 }
 
@@ -31,18 +50,55 @@ type appResponse struct {
 	} `json:"App"`
 }
 
+// appJSON is used when rendering an app, its key is never included
+type appJSON struct {
+	UID                  string               `json:"Uid"`
+	Name                 string               `json:"Name"`
+	Scope                postgres.ScopeClaims `json:"Scope"`
+	Rate                 int                  `json:"Rate"`
+	Burst                int                  `json:"Burst"`
+	CreatedAt            null.Time            `json:"CreatedAt"`
+	RevokedAt            null.Time            `json:"RevokedAt"`
+	ExpiresAt            null.Time            `json:"ExpiresAt"`
+	LastUsedAt           null.Time            `json:"LastUsedAt"`
+	PreviousKeyExpiresAt null.Time            `json:"PreviousKeyExpiresAt"`
+}
+
+func newAppJSON(app *postgres.App) appJSON {
+	return appJSON{
+		UID:                  app.UID,
+		Name:                 app.Name,
+		Scope:                app.Roles,
+		Rate:                 app.Rate,
+		Burst:                app.BurstSize(),
+		CreatedAt:            app.CreatedAt,
+		RevokedAt:            app.RevokedAt,
+		ExpiresAt:            app.ExpiresAt,
+		LastUsedAt:           app.LastUsedAt,
+		PreviousKeyExpiresAt: app.PreviousKeyExpiresAt,
+	}
+}
+
+// updateAppRequest holds the changes to an app, where Burst and ExpiresAt are
+// kept raw so that we can tell a null value, which resets them, from one that
+// is absent
+type updateAppRequest struct {
+	App struct {
+		Rate      *int                 `json:"Rate"`
+		Burst     json.RawMessage      `json:"Burst"`
+		Scope     postgres.ScopeClaims `json:"Scope"`
+		ExpiresAt json.RawMessage      `json:"ExpiresAt"`
+	} `json:"App"`
+}
+
+type rotateAppRequest struct {
+	Overlap *int `json:"Overlap"`
+}
+
 func createAppHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
 
-	roles := middleware.RolesFromContext(ctx)
-	if !roles.Permits(postgres.CreateUserScope) {
-		return &HTTPError{
-			Code: http.StatusForbidden,
-			Err:  errors.New("you are not permitted to create new application keys"),
-		}
-	}
-
 	appReq, err := parseCreateAppRequest(r)
 	if err != nil {
 		return err
@@ -59,7 +115,14 @@ func createAppHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		appReq.App.Scope = postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}
 	}
 
-	app, err := env.db.CreateApp(ctx, appReq.App.Name, appReq.App.Scope)
+	if appReq.App.Rate < 0 || appReq.App.Burst < 0 {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("rate and burst must be greater than zero if supplied"),
+		}
+	}
+
+	app, err := env.db.CreateApp(ctx, appReq.App.Name, appReq.App.Scope, appReq.App.Rate, appReq.App.Burst)
 	if err != nil {
 		return &HTTPError{
 			Code: http.StatusInternalServerError,
@@ -67,6 +130,8 @@ func createAppHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
+	middleware.AddAuditTarget(ctx, "app", app.UID)
+
 	log.Log(
 		"msg", "created app",
 		"name", app.Name,
@@ -112,3 +177,233 @@ func parseCreateAppRequest(r *http.Request) (*appRequest, error) {
 
 	return &data, nil
 }
+
+// listAppsHandler returns every app along with the state of its key
+func listAppsHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	apps, err := env.db.ListApps(ctx)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to list apps"),
+		}
+	}
+
+	resp := []appJSON{}
+	for i := range apps {
+		resp = append(resp, newAppJSON(&apps[i]))
+	}
+
+	b, err := json.Marshal(struct {
+		Apps []appJSON `json:"Apps"`
+	}{
+		Apps: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+// updateAppHandler changes the rate limits, scopes or expiry of an app. A null
+// burst resets it to twice the rate, and a null expiry means the key never
+// expires. Changes take effect from the app's next request.
+func updateAppHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var data updateAppRequest
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	update := &postgres.AppUpdate{
+		Rate:  data.App.Rate,
+		Scope: data.App.Scope,
+	}
+
+	if len(data.App.Burst) > 0 {
+		var burst null.Int
+		err = json.Unmarshal(data.App.Burst, &burst)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.Wrap(err, "failed to parse burst"),
+			}
+		}
+		update.Burst = &burst
+	}
+
+	if len(data.App.ExpiresAt) > 0 {
+		var expiresAt null.Time
+		err = json.Unmarshal(data.App.ExpiresAt, &expiresAt)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.Wrap(err, "failed to parse expiry"),
+			}
+		}
+		update.ExpiresAt = &expiresAt
+	}
+
+	uid := pat.Param(r, "uid")
+	middleware.AddAuditTarget(ctx, "app", uid)
+
+	app, err := env.db.UpdateApp(ctx, uid, update)
+	if err != nil {
+		return appError(err, "failed to update app")
+	}
+
+	log.Log(
+		"msg", "updated app",
+		"uid", app.UID,
+		"rate", app.Rate,
+		"burst", app.BurstSize(),
+		"scope", app.Roles,
+		"expiresAt", app.ExpiresAt,
+	)
+
+	return writeApp(w, app, "")
+}
+
+// revokeAppHandler revokes the keys of an app, which can't be undone
+func revokeAppHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	uid := pat.Param(r, "uid")
+	middleware.AddAuditTarget(ctx, "app", uid)
+
+	app, err := env.db.RevokeApp(ctx, uid)
+	if err != nil {
+		return appError(err, "failed to revoke app")
+	}
+
+	log.Log(
+		"msg", "revoked app",
+		"uid", app.UID,
+	)
+
+	return writeApp(w, app, "")
+}
+
+// rotateAppHandler generates a new key for an app, keeping the current key
+// valid for the requested overlap in seconds so clients can switch over
+// without downtime
+func rotateAppHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	overlap := defaultRotationOverlap
+
+	// the body is optional
+	if len(b) > 0 {
+		var data rotateAppRequest
+		err = json.Unmarshal(b, &data)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.Wrap(err, "failed to parse incoming request body"),
+			}
+		}
+
+		if data.Overlap != nil {
+			overlap = time.Duration(*data.Overlap) * time.Second
+		}
+	}
+
+	if overlap < 0 || overlap > maxRotationOverlap {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Errorf("overlap must be between 0 and %d seconds", int(maxRotationOverlap.Seconds())),
+		}
+	}
+
+	uid := pat.Param(r, "uid")
+	middleware.AddAuditTarget(ctx, "app", uid)
+
+	app, err := env.db.RotateApp(ctx, uid, overlap)
+	if err != nil {
+		return appError(err, "failed to rotate app")
+	}
+
+	log.Log(
+		"msg", "rotated app",
+		"uid", app.UID,
+		"overlap", overlap,
+	)
+
+	return writeApp(w, app, app.Key)
+}
+
+// appError converts an error from managing an app into an HTTPError
+func appError(err error, msg string) error {
+	switch errors.Cause(err) {
+	case sql.ErrNoRows:
+		return &HTTPError{
+			Code: http.StatusNotFound,
+			Err:  errors.New("app not found"),
+		}
+	case postgres.ClientError:
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  err,
+		}
+	default:
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, msg),
+		}
+	}
+}
+
+// writeApp writes an app as the response, including the given key if it is
+// not empty
+func writeApp(w http.ResponseWriter, app *postgres.App, key string) error {
+	b, err := json.Marshal(struct {
+		App    appJSON `json:"App"`
+		APIKey string  `json:"ApiKey,omitempty"`
+	}{
+		App:    newAppJSON(app),
+		APIKey: key,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
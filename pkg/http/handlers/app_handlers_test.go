@@ -42,19 +42,21 @@ func (s *AppsSuite) TearDownTest() {
 func (s *AppsSuite) TestCreateApp() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
-	// create an app with create users permission, and capture so we have an api
+	// create an app with manage apps permission, and capture so we have an api
 	// key we can use
-	app, err := s.db.CreateApp(ctx, "Supervisor", postgres.ScopeClaims{postgres.CreateUserScope})
+	app, err := s.db.CreateApp(ctx, "Supervisor", postgres.ScopeClaims{postgres.ManageAppsScope}, 0, 0)
 	assert.Nil(s.T(), err)
 
 	// set up our mux with the create app handler for testing
 	mux := goji.NewMux()
-	handlers.RegisterAppHandlers(mux, s.db)
+	perms := middleware.NewPermissions("")
+	handlers.RegisterAppHandlers(mux, perms, s.db)
 
-	// wrap the mux with our authentication middleware so that it will set up the
-	// context correctly
+	// wrap the mux with our authentication and permissions middleware so that
+	// it will set up the context correctly
 	authMiddleware := middleware.NewAuthMiddleware(s.db)
 	mux.Use(authMiddleware.Handler)
+	mux.Use(perms.Handler)
 
 	// set up new test recorder that captures the response
 	recorder := httptest.NewRecorder()
@@ -89,17 +91,19 @@ func (s *AppsSuite) TestCreateAppInvalidPermissions() {
 
 	// create an app with create users permission, and capture so we have an api
 	// key we can use
-	app, err := s.db.CreateApp(ctx, "Supervisor", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope})
+	app, err := s.db.CreateApp(ctx, "Supervisor", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, 0, 0)
 	assert.Nil(s.T(), err)
 
 	// set up our mux with the create app handler for testing
 	mux := goji.NewMux()
-	handlers.RegisterAppHandlers(mux, s.db)
+	perms := middleware.NewPermissions("")
+	handlers.RegisterAppHandlers(mux, perms, s.db)
 
-	// wrap the mux with our authentication middleware so that it will set up the
-	// context correctly
+	// wrap the mux with our authentication and permissions middleware so that
+	// it will set up the context correctly
 	authMiddleware := middleware.NewAuthMiddleware(s.db)
 	mux.Use(authMiddleware.Handler)
+	mux.Use(perms.Handler)
 
 	// set up new test recorder that captures the response
 	recorder := httptest.NewRecorder()
@@ -125,19 +129,21 @@ func (s *AppsSuite) TestCreateAppInvalidPermissions() {
 func (s *AppsSuite) TestCreateAppInvalidBody() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
-	// create an app with create users permission, and capture so we have an api
+	// create an app with manage apps permission, and capture so we have an api
 	// key we can use
-	app, err := s.db.CreateApp(ctx, "Supervisor", postgres.ScopeClaims{postgres.CreateUserScope})
+	app, err := s.db.CreateApp(ctx, "Supervisor", postgres.ScopeClaims{postgres.ManageAppsScope}, 0, 0)
 	assert.Nil(s.T(), err)
 
 	// set up our mux with the create app handler for testing
 	mux := goji.NewMux()
-	handlers.RegisterAppHandlers(mux, s.db)
+	perms := middleware.NewPermissions("")
+	handlers.RegisterAppHandlers(mux, perms, s.db)
 
-	// wrap the mux with our authentication middleware so that it will set up the
-	// context correctly
+	// wrap the mux with our authentication and permissions middleware so that
+	// it will set up the context correctly
 	authMiddleware := middleware.NewAuthMiddleware(s.db)
 	mux.Use(authMiddleware.Handler)
+	mux.Use(perms.Handler)
 
 	testcases := []struct {
 		label        string
@@ -175,6 +181,79 @@ func (s *AppsSuite) TestCreateAppInvalidBody() {
 	}
 }
 
+func (s *AppsSuite) TestManageApps() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	admin, err := s.db.CreateApp(ctx, "Admin", postgres.ScopeClaims{postgres.ManageAppsScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	app, err := s.db.CreateApp(ctx, "Student App", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	mux := goji.NewMux()
+	perms := middleware.NewPermissions("")
+	handlers.RegisterAppHandlers(mux, perms, s.db)
+
+	authMiddleware := middleware.NewAuthMiddleware(s.db)
+	mux.Use(authMiddleware.Handler)
+	mux.Use(perms.Handler)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		assert.Nil(s.T(), err)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", admin.Key))
+
+		mux.ServeHTTP(recorder, req.WithContext(ctx))
+
+		return recorder
+	}
+
+	recorder := do(http.MethodGet, "/apps", "")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), `"Name":"Student App"`)
+
+	recorder = do(http.MethodPost, "/apps/"+app.UID+"/rotate", `{"Overlap":3600}`)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	var rotated struct {
+		App struct {
+			PreviousKeyExpiresAt *string
+		}
+		APIKey string `json:"ApiKey"`
+	}
+	err = json.Unmarshal(recorder.Body.Bytes(), &rotated)
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), "", rotated.APIKey)
+	assert.NotNil(s.T(), rotated.App.PreviousKeyExpiresAt)
+
+	recorder = do(http.MethodPost, "/apps/"+app.UID+"/rotate", `{"Overlap":-1}`)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+
+	recorder = do(http.MethodPatch, "/apps/"+app.UID, `{"App":{"ExpiresAt":"2019-07-01T00:00:00Z"}}`)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), `"ExpiresAt":"2019-07-01T00:00:00Z"`)
+
+	recorder = do(http.MethodPatch, "/apps/"+app.UID, `{"App":{"Rate":10,"Scope":["metadata"]}}`)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), `"Rate":10,"Burst":20`)
+	assert.Contains(s.T(), recorder.Body.String(), `"Scope":["metadata"]`)
+	assert.Contains(s.T(), recorder.Body.String(), `"ExpiresAt":"2019-07-01T00:00:00Z"`)
+
+	recorder = do(http.MethodPatch, "/apps/"+app.UID, `{"App":{"Rate":0}}`)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+
+	recorder = do(http.MethodPost, "/apps/"+app.UID+"/revoke", "")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	recorder = do(http.MethodPost, "/apps/"+app.UID+"/rotate", "")
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+
+	recorder = do(http.MethodPost, "/apps/unknown/revoke", "")
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
 func TestAppsSuite(t *testing.T) {
 	suite.Run(t, new(AppsSuite))
 }
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// RegisterAuditHandlers registers our endpoint for searching the audit log with
+// the mux
+func RegisterAuditHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB) {
+	mux.Handle(perms.Require(pat.Get("/audit"), postgres.ReadAuditLogScope), Handler{env: &Env{db: db}, handler: listAuditRecordsHandler})
+}
+
+type auditRecordJSON struct {
+	ID        int64     `json:"Id"`
+	AppUID    string    `json:"AppUid"`
+	RequestID string    `json:"RequestId"`
+	Method    string    `json:"Method"`
+	Route     string    `json:"Route"`
+	Path      string    `json:"Path"`
+	Targets   []string  `json:"Targets"`
+	Status    int       `json:"Status"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+// listAuditRecordsHandler searches the audit log, returning the most recent
+// matching records first. Older records can be fetched by passing the id of the
+// last record returned as the before parameter.
+func listAuditRecordsHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  err,
+		}
+	}
+
+	records, err := env.db.ListAuditRecords(ctx, filter)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to list audit records"),
+		}
+	}
+
+	resp := []auditRecordJSON{}
+	for _, record := range records {
+		resp = append(resp, auditRecordJSON{
+			ID:        record.ID,
+			AppUID:    record.AppUID,
+			RequestID: record.RequestID,
+			Method:    record.Method,
+			Route:     record.Route,
+			Path:      record.Path,
+			Targets:   []string(record.Targets),
+			Status:    record.Status,
+			CreatedAt: record.CreatedAt,
+		})
+	}
+
+	b, err := json.Marshal(struct {
+		Records []auditRecordJSON `json:"Records"`
+	}{
+		Records: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+// parseAuditFilter builds a filter for the audit log from the query parameters
+// of a request
+func parseAuditFilter(query url.Values) (*postgres.AuditFilter, error) {
+	filter := &postgres.AuditFilter{
+		AppUID: query.Get("app"),
+		Target: query.Get("target"),
+		Route:  query.Get("route"),
+	}
+
+	for param, t := range map[string]*null.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse %s", param)
+			}
+			*t = null.TimeFrom(parsed)
+		}
+	}
+
+	if value := query.Get("before"); value != "" {
+		before, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || before <= 0 {
+			return nil, errors.New("before must be a positive record id")
+		}
+		filter.Before = before
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > postgres.MaxAuditRecords {
+			return nil, errors.Errorf("limit must be between 1 and %d", postgres.MaxAuditRecords)
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
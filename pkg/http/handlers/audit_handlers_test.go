@@ -0,0 +1,99 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+	goji "goji.io"
+)
+
+type AuditSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *AuditSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.logger = logger
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+}
+
+func (s *AuditSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *AuditSuite) TestSearchAuditLog() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	admin, err := s.db.CreateApp(ctx, "Admin", postgres.ScopeClaims{postgres.ReadAuditLogScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	app, err := s.db.CreateApp(ctx, "Partner App", postgres.ScopeClaims{postgres.DeleteUserScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	mux := goji.NewMux()
+	perms := middleware.NewPermissions("")
+	handlers.RegisterUserHandlers(mux, perms, s.db, nil, nil, nil)
+	handlers.RegisterAuditHandlers(mux, perms, s.db)
+
+	authMiddleware := middleware.NewAuthMiddleware(s.db)
+	mux.Use(middleware.RequestIDMiddleware)
+	mux.Use(authMiddleware.Handler)
+	mux.Use(middleware.NewAuditMiddleware(s.db, perms, 0, clockwork.NewRealClock(), s.logger).Handler)
+	mux.Use(perms.Handler)
+
+	do := func(key, method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		assert.Nil(s.T(), err)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+
+		mux.ServeHTTP(recorder, req.WithContext(ctx))
+
+		return recorder
+	}
+
+	recorder := do(app.Key, http.MethodDelete, "/user/delete", `{"User":{"Identifier":"5b8c1ad5"}}`)
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+
+	recorder = do(admin.Key, http.MethodGet, "/audit?target=user:5b8c1ad5", "")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), fmt.Sprintf(`"AppUid":"%s"`, app.UID))
+	assert.Contains(s.T(), recorder.Body.String(), `"Method":"DELETE","Route":"/user/delete","Path":"/user/delete","Targets":["user:5b8c1ad5"],"Status":404`)
+
+	recorder = do(admin.Key, http.MethodGet, "/audit?app="+admin.UID, "")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), `{"Records":[]}`, recorder.Body.String())
+
+	recorder = do(admin.Key, http.MethodGet, "/audit?since=yesterday", "")
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+
+	recorder = do(admin.Key, http.MethodGet, "/audit?limit=1000", "")
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+
+	// only apps holding the audit-log scope can search the log
+	recorder = do(app.Key, http.MethodGet, "/audit", "")
+	assert.Equal(s.T(), http.StatusForbidden, recorder.Code)
+}
+
+func TestAuditSuite(t *testing.T) {
+	suite.Run(t, new(AuditSuite))
+}
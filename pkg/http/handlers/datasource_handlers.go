@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/postgres"
 	goji "goji.io"
 	"goji.io/pat"
@@ -18,8 +19,8 @@ const (
 )
 
 // RegisterDataSourceHandlers registers our data source related handlers
-func RegisterDataSourceHandlers(mux *goji.Mux, db *postgres.DB) {
-	mux.Handle(pat.Post("/entity/dataSourceVariables/get"), Handler{env: &Env{db: db}, handler: datasourcesHandler})
+func RegisterDataSourceHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB) {
+	mux.Handle(perms.Require(pat.Post("/entity/dataSourceVariables/get"), postgres.GetMetadataScope), Handler{env: &Env{db: db}, handler: datasourcesHandler})
 }
 
 // datasource is our type used for generating datasource output
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
 	"github.com/thingful/kudzu/pkg/postgres/helper"
@@ -89,7 +90,7 @@ func (s *DatasourceHandlersSuite) TestGetDatasources() {
 	}
 
 	mux := goji.NewMux()
-	handlers.RegisterDataSourceHandlers(mux, s.db)
+	handlers.RegisterDataSourceHandlers(mux, middleware.NewPermissions(""), s.db)
 
 	recorder := httptest.NewRecorder()
 	req, err := http.NewRequest(http.MethodPost, "/entity/dataSourceVariables/get", nil)
@@ -0,0 +1,775 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/pkg/errors"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/graphql"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/thingful"
+)
+
+const (
+	// graphQLCostPerRate is the maximum cost of a query for each request per
+	// second of the rate limit of the app, so apps permitted more requests may
+	// also make more expensive queries
+	graphQLCostPerRate = 250
+
+	// graphQLChannelsPerThing is the number of channels we expect a thing to
+	// have when estimating the cost of a query
+	graphQLChannelsPerThing = 8
+
+	// graphQLDefaultFirst is the number of things returned for a user if the
+	// query does not say how many
+	graphQLDefaultFirst = 50
+
+	// latestObservationWindow is the length of the window read from Thingful
+	// to find the latest observation of a channel
+	latestObservationWindow = 2 * time.Hour
+
+	graphQLLoadersKey = contextKey("graphQLLoaders")
+)
+
+// graphQLCosts are the costs of the fields of our schema which differ from the
+// default, used to estimate the cost of a query before executing it
+var graphQLCosts = graphql.Costs{
+	"Query.things": {
+		Size: func(args map[string]interface{}) int {
+			uids, _ := args["uids"].([]interface{})
+			return len(uids)
+		},
+	},
+	"User.things": {
+		Size: func(args map[string]interface{}) int {
+			first, _ := args["first"].(int)
+			return first
+		},
+	},
+	"Thing.channels": {
+		Size: func(args map[string]interface{}) int {
+			return graphQLChannelsPerThing
+		},
+	},
+	"Channel.latestObservation": {Cost: 2},
+	"Channel.observations":      {Cost: 5},
+}
+
+// contextKey is the type of the keys of values we store in the context
+type contextKey string
+
+// RegisterGraphQLHandler registers the GraphQL endpoint, which reads users,
+// things, channels and observations in a single request. The route requires
+// the metadata scope; observations additionally require the timeseries scope.
+func RegisterGraphQLHandler(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB, th Thingful) {
+	env := &Env{db: db, thingful: th}
+	env.schema = newGraphQLSchema(env)
+
+	mux.Handle(perms.Require(pat.Post("/graphql"), postgres.GetMetadataScope), Handler{env: env, handler: graphQLHandler})
+}
+
+// graphQLHandler prepares and executes a GraphQL query, rejecting it if its
+// cost exceeds the limit of the app making the request
+func graphQLHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	req, err := parseGraphQLRequest(r)
+	if err != nil {
+		return err
+	}
+
+	query, errs := graphql.Prepare(env.schema, req)
+	if len(errs) > 0 {
+		return writeGraphQLResponse(w, http.StatusUnprocessableEntity, &gql.Result{Errors: errs})
+	}
+
+	cost := query.Cost(graphQLCosts)
+	limit := middleware.RateFromContext(ctx) * graphQLCostPerRate
+
+	if cost > limit {
+		return writeGraphQLResponse(w, http.StatusUnprocessableEntity, &gql.Result{
+			Errors: []gqlerrors.FormattedError{
+				gqlerrors.NewFormattedError(fmt.Sprintf("query cost of %d exceeds the limit of %d for your API key, try requesting fewer items", cost, limit)),
+			},
+		})
+	}
+
+	ctx = context.WithValue(ctx, graphQLLoadersKey, newGraphQLLoaders(env))
+
+	return writeGraphQLResponse(w, http.StatusOK, query.Execute(ctx))
+}
+
+// parseGraphQLRequest reads the request body
+func parseGraphQLRequest(r *http.Request) (*graphql.Request, error) {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var req graphql.Request
+
+	err = json.Unmarshal(b, &req)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	if req.Query == "" {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("query must be supplied and be a non-empty string"),
+		}
+	}
+
+	return &req, nil
+}
+
+// writeGraphQLResponse writes the response with the given status code
+func writeGraphQLResponse(w http.ResponseWriter, status int, resp *gql.Result) error {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+
+	return nil
+}
+
+// graphQLUser is the source value of the User type. Users are only known by
+// their things, so an unknown user is simply a user with no things.
+type graphQLUser struct {
+	UID string
+}
+
+// graphQLChannel is the source value of the Channel type
+type graphQLChannel struct {
+	postgres.Metadata
+	DataSource *postgres.DataSource
+}
+
+// graphQLObservation is the source value of the Observation type
+type graphQLObservation struct {
+	RecordedAt time.Time
+	Value      float64
+}
+
+// graphQLLoaders are the loaders used by the resolvers of a single request
+type graphQLLoaders struct {
+	things       *graphql.Loader
+	channels     *graphql.Loader
+	observations *graphql.Loader
+}
+
+// newGraphQLLoaders returns loaders which read from the environment
+func newGraphQLLoaders(env *Env) *graphQLLoaders {
+	return &graphQLLoaders{
+		things:       graphql.NewLoader(env.loadThings),
+		channels:     graphql.NewLoader(env.loadChannels),
+		observations: graphql.NewLoader(env.loadObservations),
+	}
+}
+
+// loadersFromContext returns the loaders of the request, or an error if the
+// context has none
+func loadersFromContext(ctx context.Context) (*graphQLLoaders, error) {
+	if loaders, ok := ctx.Value(graphQLLoadersKey).(*graphQLLoaders); ok {
+		return loaders, nil
+	}
+
+	return nil, errors.New("no loaders found in context")
+}
+
+// loadThings loads the things with the given uids
+func (env *Env) loadThings(ctx context.Context, uids []string) ([]interface{}, error) {
+	locations, err := env.db.ListLocations(ctx, &postgres.LocationFilter{
+		ThingUIDs: uids,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read things")
+	}
+
+	byUID := map[string]*postgres.Location{}
+	for i := range locations {
+		byUID[locations[i].UID] = &locations[i]
+	}
+
+	values := make([]interface{}, len(uids))
+	for i, uid := range uids {
+		if loc, ok := byUID[uid]; ok {
+			values[i] = loc
+		}
+	}
+
+	return values, nil
+}
+
+// loadChannels loads the channels of each of the things with the given uids
+func (env *Env) loadChannels(ctx context.Context, uids []string) ([]interface{}, error) {
+	metadata, err := env.db.GetMetadata(ctx, &postgres.MetadataFilter{
+		ThingUIDs: uids,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read channels")
+	}
+
+	datasources, err := env.db.GetDataSources(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read data sources")
+	}
+
+	byID := map[int64]*postgres.DataSource{}
+	for i := range datasources {
+		byID[datasources[i].ID] = &datasources[i]
+	}
+
+	byUID := map[string][]*graphQLChannel{}
+	for _, m := range metadata {
+		byUID[m.ThingUID] = append(byUID[m.ThingUID], &graphQLChannel{
+			Metadata:   m,
+			DataSource: byID[m.DataSourceID],
+		})
+	}
+
+	values := make([]interface{}, len(uids))
+	for i, uid := range uids {
+		channels := byUID[uid]
+		if channels == nil {
+			channels = []*graphQLChannel{}
+		}
+		values[i] = channels
+	}
+
+	return values, nil
+}
+
+// observationsKey returns the key used to load the data of a thing within a
+// window from Thingful
+func observationsKey(uid string, start, end time.Time) string {
+	return strings.Join([]string{uid, start.Format(time.RFC3339), end.Format(time.RFC3339)}, " ")
+}
+
+// loadObservations loads the data of things from Thingful. Keys sharing the
+// same window are read with a single call to GetData.
+func (env *Env) loadObservations(ctx context.Context, keys []string) ([]interface{}, error) {
+	windows := map[string][]string{}
+	for _, key := range keys {
+		parts := strings.SplitN(key, " ", 2)
+		windows[parts[1]] = append(windows[parts[1]], parts[0])
+	}
+
+	things := map[string]*thingful.Thing{}
+
+	for window, uids := range windows {
+		parts := strings.Split(window, " ")
+
+		start, err := time.Parse(time.RFC3339, parts[0])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse start of window")
+		}
+
+		end, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse end of window")
+		}
+
+		data, err := env.thingful.GetData(ctx, uids, start, end, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get data from Thingful")
+		}
+
+		for i := range data {
+			things[observationsKey(path.Base(data[i].ID), start, end)] = &data[i]
+		}
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if t, ok := things[key]; ok {
+			values[i] = t
+		}
+	}
+
+	return values, nil
+}
+
+// channelObservations returns a thunk which returns the observations of the
+// channel within the window
+func channelObservations(ctx context.Context, c *graphQLChannel, start, end time.Time) (func() (interface{}, error), error) {
+	loaders, err := loadersFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	thunk := loaders.observations.Load(ctx, observationsKey(c.ThingUID, start, end))
+
+	return func() (interface{}, error) {
+		value, err := thunk()
+		if err != nil || value == nil || c.DataSource == nil {
+			return nil, err
+		}
+
+		observations := []*graphQLObservation{}
+
+		for _, ch := range value.(*thingful.Thing).Attributes.Channels {
+			if path.Base(ch.ID) != c.DataSource.Code {
+				continue
+			}
+
+			for _, o := range ch.Observations {
+				val, err := strconv.ParseFloat(o.Value, 64)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to parse value to a float")
+				}
+
+				observations = append(observations, &graphQLObservation{
+					RecordedAt: o.RecordedAt.UTC(),
+					Value:      val,
+				})
+			}
+		}
+
+		return observations, nil
+	}, nil
+}
+
+// requireTimeSeriesScope returns an error if the app making the request may
+// not read time series data
+func requireTimeSeriesScope(ctx context.Context) error {
+	if !middleware.RolesFromContext(ctx).Permits(postgres.GetTimeSeriesDataScope) {
+		return fmt.Errorf("you are not permitted to read observations, missing scopes: %s", postgres.GetTimeSeriesDataScope)
+	}
+	return nil
+}
+
+// formatTime returns the time formatted as RFC3339, or nil if the time is null
+func formatTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseWindow parses the start and end arguments of a field, applying the same
+// defaults and limits as the v2 observations route
+func parseWindow(args map[string]interface{}) (time.Time, time.Time, error) {
+	end := time.Now().UTC()
+	if str, ok := args["end"].(string); ok {
+		t, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("end must be an RFC3339 timestamp")
+		}
+		end = t
+	}
+
+	start := end.Add(-defaultObservationWindow)
+	if str, ok := args["start"].(string); ok {
+		t, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("start must be an RFC3339 timestamp")
+		}
+		start = t
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.New("start must be before end")
+	}
+
+	if end.Sub(start).Hours()/24 > maxTimeInterval {
+		return time.Time{}, time.Time{}, errors.Errorf("start must be within %v days of end", maxTimeInterval)
+	}
+
+	return start, end, nil
+}
+
+// sourceThing returns the thing a field of the Thing type is resolved for
+func sourceThing(p gql.ResolveParams) *postgres.Location {
+	return p.Source.(*postgres.Location)
+}
+
+// newGraphQLSchema returns the schema served by the GraphQL endpoint, whose
+// resolvers read from the given environment. Object types refer to each other,
+// so their fields are defined by thunks which are called once every type has
+// been created.
+func newGraphQLSchema(env *Env) *gql.Schema {
+	var userType, thingType, channelType, dataSourceType, observationType *gql.Object
+
+	userType = gql.NewObject(gql.ObjectConfig{
+		Name: "User",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"uid": {
+					Type: gql.ID,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return p.Source.(*graphQLUser).UID, nil
+					},
+				},
+				"things": {
+					Type: gql.NewList(thingType),
+					Args: gql.FieldConfigArgument{
+						"status":      {Type: gql.String},
+						"dataSources": {Type: gql.NewList(gql.String)},
+						"first":       {Type: gql.Int, DefaultValue: graphQLDefaultFirst},
+					},
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						filter := &postgres.LocationFilter{
+							OwnerUID: p.Source.(*graphQLUser).UID,
+						}
+
+						first := p.Args["first"].(int)
+						if first < 1 || first > postgres.MaxLocationsLimit {
+							return nil, errors.Errorf("first must be between 1 and %d", postgres.MaxLocationsLimit)
+						}
+						filter.Limit = uint64(first)
+
+						if status, ok := p.Args["status"].(string); ok {
+							if !postgres.IsValidStatus(status) {
+								return nil, errors.New("status must be one of: live, stale, dead")
+							}
+							filter.Status = status
+						}
+
+						if dataSources, ok := p.Args["dataSources"].([]interface{}); ok {
+							codes := []string{}
+							for _, ds := range dataSources {
+								if code, ok := ds.(string); ok {
+									codes = append(codes, code)
+								}
+							}
+							filter.DataSourceCodes = dataSourceNames(codes)
+						}
+
+						locations, err := env.db.ListLocations(p.Context, filter)
+						if err != nil {
+							return nil, errors.Wrap(err, "failed to read things")
+						}
+
+						things := make([]*postgres.Location, len(locations))
+						for i := range locations {
+							things[i] = &locations[i]
+						}
+
+						return things, nil
+					},
+				},
+			}
+		}),
+	})
+
+	thingType = gql.NewObject(gql.ObjectConfig{
+		Name: "Thing",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"uid": {
+					Type: gql.ID,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return sourceThing(p).UID, nil
+					},
+				},
+				"locationIdentifier": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return fmt.Sprintf("Grow.Thingful#%s", sourceThing(p).UID), nil
+					},
+				},
+				"nickname": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return sourceThing(p).Nickname, nil
+					},
+				},
+				"serialNumber": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return sourceThing(p).SerialNum, nil
+					},
+				},
+				"longitude": {
+					Type: gql.Float,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return sourceThing(p).Longitude, nil
+					},
+				},
+				"latitude": {
+					Type: gql.Float,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return sourceThing(p).Latitude, nil
+					},
+				},
+				"firstSample": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return formatTime(sourceThing(p).FirstSampleUTC.Ptr()), nil
+					},
+				},
+				"lastSample": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return formatTime(sourceThing(p).LastSampleUTC.Ptr()), nil
+					},
+				},
+				"owner": {
+					Type: userType,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return &graphQLUser{UID: sourceThing(p).UserUID}, nil
+					},
+				},
+				"channels": {
+					Type: gql.NewList(channelType),
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						loaders, err := loadersFromContext(p.Context)
+						if err != nil {
+							return nil, err
+						}
+						return loaders.channels.Load(p.Context, sourceThing(p).UID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	channelType = gql.NewObject(gql.ObjectConfig{
+		Name: "Channel",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id": {
+					Type: gql.ID,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						c := p.Source.(*graphQLChannel)
+						if c.DataSource == nil {
+							return nil, nil
+						}
+						return c.DataSource.Code, nil
+					},
+				},
+				"dataSource": {
+					Type: dataSourceType,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return p.Source.(*graphQLChannel).DataSource, nil
+					},
+				},
+				"firstSample": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return formatTime(p.Source.(*graphQLChannel).FirstSampleUTC.Ptr()), nil
+					},
+				},
+				"lastSample": {
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						return formatTime(p.Source.(*graphQLChannel).LastSampleUTC.Ptr()), nil
+					},
+				},
+				"latestObservation": {
+					Type: observationType,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						err := requireTimeSeriesScope(p.Context)
+						if err != nil {
+							return nil, err
+						}
+
+						c := p.Source.(*graphQLChannel)
+						if !c.LastSampleUTC.Valid {
+							return nil, nil
+						}
+
+						// round the window so that channels of the same thing share a window
+						end := c.LastSampleUTC.Time.UTC().Truncate(time.Hour).Add(time.Hour)
+						thunk, err := channelObservations(p.Context, c, end.Add(-latestObservationWindow), end)
+						if err != nil {
+							return nil, err
+						}
+
+						return func() (interface{}, error) {
+							value, err := thunk()
+							if err != nil || value == nil {
+								return nil, err
+							}
+
+							observations := value.([]*graphQLObservation)
+							if len(observations) == 0 {
+								return nil, nil
+							}
+							return observations[len(observations)-1], nil
+						}, nil
+					},
+				},
+				"observations": {
+					Type: gql.NewList(observationType),
+					Args: gql.FieldConfigArgument{
+						"start": {Type: gql.String},
+						"end":   {Type: gql.String},
+					},
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						err := requireTimeSeriesScope(p.Context)
+						if err != nil {
+							return nil, err
+						}
+
+						start, end, err := parseWindow(p.Args)
+						if err != nil {
+							return nil, err
+						}
+
+						return channelObservations(p.Context, p.Source.(*graphQLChannel), start, end)
+					},
+				},
+			}
+		}),
+	})
+
+	dataSourceType = gql.NewObject(gql.ObjectConfig{
+		Name: "DataSource",
+		Fields: gql.Fields{
+			"id": {
+				Type: gql.Int,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return p.Source.(*postgres.DataSource).ID, nil
+				},
+			},
+			"name": {
+				Type: gql.String,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return p.Source.(*postgres.DataSource).Code, nil
+				},
+			},
+			"variableCode": {
+				Type: gql.String,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return fmt.Sprintf("%s.%s", nodeName, p.Source.(*postgres.DataSource).Code), nil
+				},
+			},
+			"unit": {
+				Type: gql.String,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					ds := p.Source.(*postgres.DataSource)
+					if !ds.Unit.Valid {
+						return nil, nil
+					}
+					return ds.Unit.String, nil
+				},
+			},
+			"dataType": {
+				Type: gql.String,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return p.Source.(*postgres.DataSource).DataType, nil
+				},
+			},
+		},
+	})
+
+	observationType = gql.NewObject(gql.ObjectConfig{
+		Name: "Observation",
+		Fields: gql.Fields{
+			"recordedAt": {
+				Type: gql.String,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return formatTime(&p.Source.(*graphQLObservation).RecordedAt), nil
+				},
+			},
+			"value": {
+				Type: gql.Float,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return p.Source.(*graphQLObservation).Value, nil
+				},
+			},
+		},
+	})
+
+	nonNullString := gql.NewNonNull(gql.String)
+
+	queryType := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"user": {
+				Type: userType,
+				Args: gql.FieldConfigArgument{
+					"uid": {Type: nonNullString},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return &graphQLUser{UID: p.Args["uid"].(string)}, nil
+				},
+			},
+			"thing": {
+				Type: thingType,
+				Args: gql.FieldConfigArgument{
+					"uid": {Type: nonNullString},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					loaders, err := loadersFromContext(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					return loaders.things.Load(p.Context, p.Args["uid"].(string)), nil
+				},
+			},
+			"things": {
+				Type: gql.NewList(thingType),
+				Args: gql.FieldConfigArgument{
+					"uids": {Type: gql.NewNonNull(gql.NewList(nonNullString))},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					loaders, err := loadersFromContext(p.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					thunks := []interface{}{}
+					for _, uid := range p.Args["uids"].([]interface{}) {
+						thunks = append(thunks, loaders.things.Load(p.Context, uid.(string)))
+					}
+					return thunks, nil
+				},
+			},
+			"dataSources": {
+				Type: gql.NewList(dataSourceType),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					datasources, err := env.db.GetDataSources(p.Context)
+					if err != nil {
+						return nil, errors.Wrap(err, "failed to read data sources")
+					}
+
+					values := make([]*postgres.DataSource, len(datasources))
+					for i := range datasources {
+						values[i] = &datasources[i]
+					}
+
+					return values, nil
+				},
+			},
+		},
+	})
+
+	schema, err := gql.NewSchema(gql.SchemaConfig{
+		Query: queryType,
+	})
+	if err != nil {
+		// the schema is fixed, so an error here is a bug in its definition
+		panic(errors.Wrap(err, "failed to create GraphQL schema"))
+	}
+
+	return &schema
+}
@@ -0,0 +1,269 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/thingful/kudzu/pkg/client"
+	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+	"github.com/thingful/kudzu/pkg/thingful"
+	"github.com/thingful/simular"
+	goji "goji.io"
+)
+
+type GraphQLHandlerSuite struct {
+	suite.Suite
+	db       *postgres.DB
+	logger   kitlog.Logger
+	client   *client.Client
+	thingful *thingful.Thingful
+	mux      *goji.Mux
+}
+
+func (s *GraphQLHandlerSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+	s.client = client.NewClient(1, true)
+	s.thingful = thingful.NewClient(s.client, "http://thingful.net", "api-key", true, 2)
+
+	perms := middleware.NewPermissions("")
+
+	s.mux = goji.NewMux()
+	handlers.RegisterGraphQLHandler(s.mux, perms, s.db, s.thingful)
+	s.mux.Use(middleware.NewAuthMiddleware(s.db).Handler)
+	s.mux.Use(perms.Handler)
+
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "alice")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, nickname, long, lat, location_identifier, last_sample)
+		VALUES
+			('1234', $1, 'PA1', 'Tomatoes', 12.2, 13.3, 'LOC1', NOW()),
+			('1235', $1, 'PA2', 'Beans', 12.2, 13.3, 'LOC2', NOW() - interval '31 days')`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO data_sources (id, name, unit, data_type)
+		VALUES (1, 'air_temperature', 'Cel', 'xsd:double'), (2, 'soil_moisture', NULL, 'xsd:double')`,
+	)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO channels (thing_uid, data_source_id, first_sample, last_sample)
+		VALUES
+			('1234', 1, '2019-03-25T00:00:00Z', '2019-03-26T00:19:29Z'),
+			('1234', 2, '2019-03-25T00:00:00Z', '2019-03-26T00:04:29Z'),
+			('1235', 1, NULL, NULL)`,
+	)
+	assert.Nil(s.T(), err)
+}
+
+func (s *GraphQLHandlerSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+// query executes the query with an API key holding the given scopes
+func (s *GraphQLHandlerSuite) query(scopes postgres.ScopeClaims, query string) (*httptest.ResponseRecorder, string) {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "Front End", scopes, 0, 0)
+	assert.Nil(s.T(), err)
+
+	body, err := json.Marshal(map[string]string{"query": query})
+	assert.Nil(s.T(), err)
+
+	req, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	assert.Nil(s.T(), err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", app.Key))
+
+	recorder := httptest.NewRecorder()
+	s.mux.ServeHTTP(recorder, req.WithContext(ctx))
+
+	return recorder, recorder.Body.String()
+}
+
+func (s *GraphQLHandlerSuite) TestQueryUser() {
+	recorder, body := s.query(postgres.ScopeClaims{postgres.GetMetadataScope}, `{
+		user(uid: "alice") {
+			uid
+			things(status: "live") {
+				locationIdentifier
+				nickname
+				owner { uid }
+				channels { id lastSample dataSource { variableCode unit } }
+			}
+		}
+	}`)
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{
+		"data": {
+			"user": {
+				"uid": "alice",
+				"things": [
+					{
+						"locationIdentifier": "Grow.Thingful#1234",
+						"nickname": "Tomatoes",
+						"owner": {"uid": "alice"},
+						"channels": [
+							{
+								"id": "air_temperature",
+								"lastSample": "2019-03-26T00:19:29Z",
+								"dataSource": {"variableCode": "Thingful.Connectors.GROWSensors.air_temperature", "unit": "Cel"}
+							},
+							{
+								"id": "soil_moisture",
+								"lastSample": "2019-03-26T00:04:29Z",
+								"dataSource": {"variableCode": "Thingful.Connectors.GROWSensors.soil_moisture", "unit": null}
+							}
+						]
+					}
+				]
+			}
+		}
+	}`, body)
+}
+
+func (s *GraphQLHandlerSuite) TestQueryObservations() {
+	simular.ActivateNonDefault(s.client.Client)
+	defer simular.DeactivateAndReset()
+
+	// both channels share a single request for their latest observation
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			http.MethodGet,
+			"http://thingful.net/things/1234?from=2019-03-25T23:00:00Z&to=2019-03-26T01:00:00Z",
+			simular.NewStringResponder(200, `{
+				"data": {
+					"id": "https://api.thingful.net/things/1234",
+					"attributes": {
+						"channels": [
+							{
+								"id": "https://api.thingful.net/things/1234/channels/air_temperature",
+								"observations": [
+									{"recordedAt": "2019-03-26T00:04:29Z", "value": "12.1"},
+									{"recordedAt": "2019-03-26T00:19:29Z", "value": "12.5"}
+								]
+							},
+							{
+								"id": "https://api.thingful.net/things/1234/channels/soil_moisture",
+								"observations": [
+									{"recordedAt": "2019-03-26T00:04:29Z", "value": "29.89"}
+								]
+							}
+						]
+					}
+				}
+			}`),
+			simular.WithHeader(
+				&http.Header{
+					"Authorization": []string{"Bearer api-key"},
+				},
+			),
+		),
+	)
+
+	recorder, body := s.query(postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}, `{
+		thing(uid: "1234") {
+			channels { id latestObservation { recordedAt value } }
+		}
+	}`)
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{
+		"data": {
+			"thing": {
+				"channels": [
+					{"id": "air_temperature", "latestObservation": {"recordedAt": "2019-03-26T00:19:29Z", "value": 12.5}},
+					{"id": "soil_moisture", "latestObservation": {"recordedAt": "2019-03-26T00:04:29Z", "value": 29.89}}
+				]
+			}
+		}
+	}`, body)
+
+	assert.Nil(s.T(), simular.AllStubsCalled())
+}
+
+func (s *GraphQLHandlerSuite) TestQueryObservationsWithoutScope() {
+	recorder, body := s.query(postgres.ScopeClaims{postgres.GetMetadataScope}, `{
+		thing(uid: "1235") { uid channels { observations { value } } }
+	}`)
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{
+		"data": {"thing": {"uid": "1235", "channels": [{"observations": null}]}},
+		"errors": [
+			{
+				"message": "you are not permitted to read observations, missing scopes: timeseries",
+				"locations": [{"line": 2, "column": 39}],
+				"path": ["thing", "channels", 0, "observations"]
+			}
+		]
+	}`, body)
+}
+
+func (s *GraphQLHandlerSuite) TestInvalidQueries() {
+	testcases := []struct {
+		label           string
+		query           string
+		expectedMessage string
+	}{
+		{
+			label:           "syntax error",
+			query:           `{ user(uid: "alice") {`,
+			expectedMessage: "Syntax Error GraphQL request (1:23) Expected Name, found EOF",
+		},
+		{
+			label:           "unknown field",
+			query:           `{ user(uid: "alice") { email } }`,
+			expectedMessage: `Cannot query field "email" on type "User".`,
+		},
+		{
+			label:           "too expensive",
+			query:           `{ user(uid: "alice") { things(first: 1000) { channels { id } } } }`,
+			expectedMessage: "query cost of 1002 exceeds the limit of 1000 for your API key, try requesting fewer items",
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder, body := s.query(postgres.ScopeClaims{postgres.GetMetadataScope}, tc.query)
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+			var resp struct {
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			err := json.Unmarshal([]byte(body), &resp)
+			assert.Nil(t, err)
+
+			if assert.Len(t, resp.Errors, 1) {
+				assert.Contains(t, resp.Errors[0].Message, tc.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestGraphQLHandlerSuite(t *testing.T) {
+	suite.Run(t, new(GraphQLHandlerSuite))
+}
@@ -6,11 +6,14 @@ import (
 	"net/http"
 	"time"
 
+	gql "github.com/graphql-go/graphql"
+
 	"github.com/thingful/kudzu/pkg/client"
 	"github.com/thingful/kudzu/pkg/flowerpower"
 	"github.com/thingful/kudzu/pkg/indexer"
 	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/stream"
 	"github.com/thingful/kudzu/pkg/thingful"
 )
 
@@ -36,6 +39,8 @@ type Env struct {
 	client   *client.Client
 	indexer  *indexer.Indexer
 	thingful Thingful
+	schema   *gql.Schema
+	broker   *stream.Broker
 }
 
 // Handler is a custom handler type that provides some error handling niceties.
@@ -1,31 +1,85 @@
 package handlers
 
 import (
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/guregu/null"
 	"github.com/pkg/errors"
 	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
 	goji "goji.io"
 	"goji.io/pat"
 )
 
 // RegisterLocationHandlers registers handlers for working with locations
-func RegisterLocationHandlers(mux *goji.Mux, db *postgres.DB, th Thingful) {
-	mux.Handle(pat.Post("/entity/locations/get"), Handler{env: &Env{db: db}, handler: listLocationsHandler})
-	mux.Handle(pat.Patch("/entity/locations/update"), Handler{env: &Env{db: db, thingful: th}, handler: updateLocationHandler})
+func RegisterLocationHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB, th Thingful) {
+	mux.Handle(perms.Require(pat.Post("/entity/locations/get"), postgres.GetMetadataScope), Handler{env: &Env{db: db}, handler: listLocationsHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Patch("/entity/locations/update")), postgres.UpdateLocationScope), Handler{env: &Env{db: db, thingful: th}, handler: updateLocationHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Patch("/entity/locations/bulkupdate")), postgres.UpdateLocationScope), Handler{env: &Env{db: db}, handler: bulkUpdateLocationsHandler})
+	mux.Handle(perms.Require(pat.Post("/entity/locations/history"), postgres.GetMetadataScope), Handler{env: &Env{db: db}, handler: locationHistoryHandler})
+	mux.Handle(perms.Require(pat.Post("/entity/locations/hardware/history"), postgres.GetMetadataScope), Handler{env: &Env{db: db}, handler: hardwareHistoryHandler})
 }
 
+// maxBulkLocationUpdates is the maximum number of entries permitted in a single
+// bulk update request
+const maxBulkLocationUpdates = 1000
+
+// defaultLocationsLimit is the page size used if the client asks for a paged
+// response without specifying a limit
+const defaultLocationsLimit = 100
+
 // listLocationsRequest is used to parse incoming requests for locations
 type listLocationsRequest struct {
-	UserUID         string   `json:"UserId"`
-	DataSourceCodes []string `json:"DataSourceCodes"`
-	InvalidLocation bool     `json:"InvalidLocation"`
-	StaleData       bool     `json:"StaleData"`
+	UserUID            string      `json:"UserId"`
+	DataSourceCodes    []string    `json:"DataSourceCodes"`
+	MatchAnyDataSource bool        `json:"MatchAnyDataSource"`
+	Status             string      `json:"Status"`
+	InvalidLocation    bool        `json:"InvalidLocation"`
+	StaleData          bool        `json:"StaleData"`
+	BoundingBox        []float64   `json:"BoundingBox"`
+	Near               *near       `json:"Near"`
+	Polygon            [][]float64 `json:"Polygon"`
+	Indoor             *bool       `json:"Indoor"`
+	SensorType         string      `json:"SensorType"`
+	FirmwareBefore     string      `json:"FirmwareBefore"`
+	Limit              uint64      `json:"Limit"`
+	Cursor             string      `json:"Cursor"`
+	SortBy             string      `json:"SortBy"`
+	Descending         bool        `json:"Descending"`
+}
+
+// isPaged returns true if the client has asked for any of the paging or
+// sorting options, in which case we return the paged form of the response.
+func (l *listLocationsRequest) isPaged() bool {
+	return l.Limit > 0 || l.Cursor != "" || l.SortBy != ""
+}
+
+// locationCursor is the opaque cursor we hand back to clients to fetch the
+// next page. It records the sort options so a cursor can't be reused with a
+// different ordering.
+type locationCursor struct {
+	SortBy     string `json:"s"`
+	Descending bool   `json:"d"`
+	SortKey    string `json:"k"`
+	UID        string `json:"u"`
+}
+
+// near is used to parse a radius query from the incoming request. X and Y are
+// the longitude and latitude of the centre point, and Radius is the distance
+// in metres.
+type near struct {
+	X      float64 `json:"X"`
+	Y      float64 `json:"Y"`
+	Radius float64 `json:"Radius"`
 }
 
 // updateLocationRequest is used to parse incoming requests to set the location
@@ -36,6 +90,39 @@ type updateLocationRequest struct {
 	Y    float64 `json:"Y"`
 }
 
+// bulkUpdateLocationsRequest is used to parse incoming requests to set the
+// location of many devices at once
+type bulkUpdateLocationsRequest struct {
+	Locations []updateLocationRequest `json:"Locations"`
+}
+
+// bulkUpdateResult reports the outcome of a single entry of a bulk update.
+// Status is an HTTP status code describing the result for that entry.
+type bulkUpdateResult struct {
+	Code     string    `json:"Code"`
+	Status   int       `json:"Status"`
+	Error    string    `json:"Error,omitempty"`
+	Location *location `json:"Location,omitempty"`
+}
+
+// locationHistoryRequest is used to parse incoming requests for the coordinate
+// history of a device
+type locationHistoryRequest struct {
+	Code string `json:"Code"`
+}
+
+// locationChange is used when rendering the coordinate history of a device.
+// The previous coordinates are null for the entry recorded when the device was
+// first indexed, and AppUid is null for changes not made via the API.
+type locationChange struct {
+	ChangedAt string   `json:"ChangedAt"`
+	AppUID    *string  `json:"AppUid"`
+	PreviousX *float64 `json:"PreviousX"`
+	PreviousY *float64 `json:"PreviousY"`
+	X         float64  `json:"X"`
+	Y         float64  `json:"Y"`
+}
+
 // location is used when rendering the response to the client. The structure is
 // defined by hydronet
 type location struct {
@@ -52,6 +139,46 @@ type location struct {
 	LastFetchedSampleTimestamp string  `json:"LastFetchedSampleTimestamp"`
 	UserUID                    string  `json:"UserUid"`
 	SerialNumber               string  `json:"SerialNumber"`
+
+	// PlantStatus and Hardware are only returned when listing locations, and
+	// only once Parrot has reported them
+	PlantStatus *plantStatus `json:"PlantStatus,omitempty"`
+	Hardware    *hardware    `json:"Hardware,omitempty"`
+}
+
+// hardware describes the sensor of a location and how it is installed, as
+// reported by Parrot
+type hardware struct {
+	FirmwareVersion  null.String `json:"FirmwareVersion"`
+	HardwareRevision null.String `json:"HardwareRevision"`
+	SensorType       null.String `json:"SensorType"`
+	CalibrationData  null.String `json:"CalibrationData"`
+	IsIndoor         null.Bool   `json:"IsIndoor"`
+	InPot            null.Bool   `json:"InPot"`
+	PlantIDs         []int64     `json:"PlantIds"`
+	AutowateringMode null.String `json:"AutowateringMode"`
+}
+
+// hardwareChange is used when rendering the hardware history of a device,
+// each entry is the hardware from the time it changed
+type hardwareChange struct {
+	ChangedAt string `json:"ChangedAt"`
+	*hardware
+}
+
+// plantStatus is Parrot's latest assessment of a location's plant, keyed by
+// variable
+type plantStatus struct {
+	UpdatedAt string                         `json:"UpdatedAt"`
+	Variables map[string]plantStatusVariable `json:"Variables"`
+}
+
+type plantStatusVariable struct {
+	Status       string     `json:"Status"`
+	Instruction  string     `json:"Instruction"`
+	MinThreshold null.Float `json:"MinThreshold"`
+	MaxThreshold null.Float `json:"MaxThreshold"`
+	CurrentValue null.Float `json:"CurrentValue"`
 }
 
 // listLocationsHandler is our handler that returns location information to
@@ -64,7 +191,22 @@ func listLocationsHandler(env *Env, w http.ResponseWriter, r *http.Request) erro
 		return err
 	}
 
-	locations, err := env.db.ListLocations(ctx, req.UserUID, req.InvalidLocation, req.StaleData)
+	filter, err := buildLocationFilter(req)
+	if err != nil {
+		return err
+	}
+
+	if req.isPaged() {
+		err = applyLocationPaging(req, filter)
+		if err != nil {
+			return err
+		}
+
+		// request one extra row so we know whether there is a next page
+		filter.Limit = filter.Limit + 1
+	}
+
+	locations, err := env.db.ListLocations(ctx, filter)
 	if err != nil {
 		return &HTTPError{
 			Code: http.StatusInternalServerError,
@@ -72,18 +214,84 @@ func listLocationsHandler(env *Env, w http.ResponseWriter, r *http.Request) erro
 		}
 	}
 
+	var nextCursor string
+
+	if req.isPaged() && uint64(len(locations)) == filter.Limit {
+		locations = locations[:len(locations)-1]
+		last := locations[len(locations)-1]
+
+		nextCursor, err = encodeLocationCursor(&locationCursor{
+			SortBy:     filter.SortBy,
+			Descending: filter.Descending,
+			SortKey:    last.SortKey,
+			UID:        last.UID,
+		})
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to encode cursor"),
+			}
+		}
+	}
+
+	uids := []string{}
+	for _, loc := range locations {
+		uids = append(uids, loc.UID)
+	}
+
+	statuses, err := env.db.GetPlantStatuses(ctx, uids)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to load plant statuses"),
+		}
+	}
+
 	locationMap := map[string]*location{}
+	order := []string{}
 
 	for _, loc := range locations {
-		l := buildLocation(&loc)
-		locationMap[fmt.Sprintf("Grow.Thingful#%s", loc.UID)] = l
+		code := fmt.Sprintf("Grow.Thingful#%s", loc.UID)
+		locationMap[code] = buildLocation(&loc)
+		locationMap[code].PlantStatus = buildPlantStatus(statuses[loc.UID])
+		locationMap[code].Hardware = buildHardware(&loc.Hardware)
+		order = append(order, code)
 	}
 
-	b, err := json.Marshal(struct {
-		Locations map[string]*location `json:"Locations"`
-	}{
-		Locations: locationMap,
-	})
+	var b []byte
+
+	if req.isPaged() {
+		// the total count ignores the cursor so clients can show progress
+		filter.After = nil
+
+		var count int64
+
+		count, err = env.db.CountLocations(ctx, filter)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to count locations"),
+			}
+		}
+
+		b, err = json.Marshal(struct {
+			Locations  map[string]*location `json:"Locations"`
+			Order      []string             `json:"Order"`
+			TotalCount int64                `json:"TotalCount"`
+			NextCursor string               `json:"NextCursor,omitempty"`
+		}{
+			Locations:  locationMap,
+			Order:      order,
+			TotalCount: count,
+			NextCursor: nextCursor,
+		})
+	} else {
+		b, err = json.Marshal(struct {
+			Locations map[string]*location `json:"Locations"`
+		}{
+			Locations: locationMap,
+		})
+	}
 	if err != nil {
 		return &HTTPError{
 			Code: http.StatusInternalServerError,
@@ -118,6 +326,217 @@ func parseListRequest(r *http.Request) (*listLocationsRequest, error) {
 	return &data, nil
 }
 
+// buildLocationFilter converts the incoming request into a filter we can pass
+// to the database, validating any spatial parameters as we go.
+func buildLocationFilter(req *listLocationsRequest) (*postgres.LocationFilter, error) {
+	filter := &postgres.LocationFilter{
+		OwnerUID:           req.UserUID,
+		InvalidLocation:    req.InvalidLocation,
+		StaleData:          req.StaleData,
+		DataSourceCodes:    dataSourceNames(req.DataSourceCodes),
+		MatchAnyDataSource: req.MatchAnyDataSource,
+		Indoor:             req.Indoor,
+		SensorType:         req.SensorType,
+		FirmwareBefore:     req.FirmwareBefore,
+	}
+
+	if req.Status != "" {
+		if !postgres.IsValidStatus(req.Status) {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("Status must be one of: live, stale, dead"),
+			}
+		}
+
+		filter.Status = req.Status
+	}
+
+	if req.BoundingBox != nil {
+		if len(req.BoundingBox) != 4 {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("BoundingBox must contain exactly four values: [minX, minY, maxX, maxY]"),
+			}
+		}
+
+		bbox := &postgres.BoundingBox{
+			MinLongitude: req.BoundingBox[0],
+			MinLatitude:  req.BoundingBox[1],
+			MaxLongitude: req.BoundingBox[2],
+			MaxLatitude:  req.BoundingBox[3],
+		}
+
+		if !isValidCoordinate(bbox.MinLongitude, bbox.MinLatitude) || !isValidCoordinate(bbox.MaxLongitude, bbox.MaxLatitude) {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("BoundingBox coordinates must be valid WGS84 longitude and latitude values"),
+			}
+		}
+
+		if bbox.MinLongitude > bbox.MaxLongitude || bbox.MinLatitude > bbox.MaxLatitude {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("BoundingBox minimum values must be less than maximum values"),
+			}
+		}
+
+		filter.BoundingBox = bbox
+	}
+
+	if req.Near != nil {
+		if !isValidCoordinate(req.Near.X, req.Near.Y) {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("Near coordinates must be valid WGS84 longitude and latitude values"),
+			}
+		}
+
+		if req.Near.Radius <= 0 {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("Near radius must be a positive distance in metres"),
+			}
+		}
+
+		filter.Radius = &postgres.Radius{
+			Center: postgres.Point{
+				Longitude: req.Near.X,
+				Latitude:  req.Near.Y,
+			},
+			Distance: req.Near.Radius,
+		}
+	}
+
+	if req.Polygon != nil {
+		if len(req.Polygon) < 3 {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("Polygon must contain at least three points"),
+			}
+		}
+
+		for _, p := range req.Polygon {
+			if len(p) != 2 || !isValidCoordinate(p[0], p[1]) {
+				return nil, &HTTPError{
+					Code: http.StatusUnprocessableEntity,
+					Err:  errors.New("Polygon points must be [X, Y] pairs of valid WGS84 longitude and latitude values"),
+				}
+			}
+
+			filter.Polygon = append(filter.Polygon, postgres.Point{
+				Longitude: p[0],
+				Latitude:  p[1],
+			})
+		}
+	}
+
+	return filter, nil
+}
+
+// dataSourceNames converts the data source codes sent by the client into the
+// names we store in the database. Clients may send either full variable codes
+// (e.g. Thingful.Connectors.GROWSensors.air_temperature) or bare names. The
+// node code on its own matches every data source so is dropped.
+func dataSourceNames(codes []string) []string {
+	names := []string{}
+
+	for _, code := range codes {
+		if code == nodeName {
+			continue
+		}
+
+		names = append(names, strings.TrimPrefix(code, nodeName+"."))
+	}
+
+	return names
+}
+
+// applyLocationPaging validates the sorting and paging parameters of the
+// request and sets them on the filter
+func applyLocationPaging(req *listLocationsRequest, filter *postgres.LocationFilter) error {
+	filter.SortBy = req.SortBy
+	if filter.SortBy == "" {
+		filter.SortBy = "uid"
+	}
+
+	if !postgres.IsValidLocationSort(filter.SortBy) {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("SortBy must be one of: uid, last_sample, created_at, nickname"),
+		}
+	}
+
+	filter.Descending = req.Descending
+
+	filter.Limit = req.Limit
+	if filter.Limit == 0 {
+		filter.Limit = defaultLocationsLimit
+	}
+
+	if filter.Limit > postgres.MaxLocationsLimit {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Errorf("Limit must not be greater than %d", postgres.MaxLocationsLimit),
+		}
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeLocationCursor(req.Cursor)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.Wrap(err, "invalid Cursor"),
+			}
+		}
+
+		if cursor.SortBy != filter.SortBy || cursor.Descending != filter.Descending {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("Cursor was created with different sort options"),
+			}
+		}
+
+		filter.After = &postgres.LocationCursor{
+			SortKey: cursor.SortKey,
+			UID:     cursor.UID,
+		}
+	}
+
+	return nil
+}
+
+// encodeLocationCursor returns an opaque string representation of the cursor
+func encodeLocationCursor(cursor *locationCursor) (string, error) {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeLocationCursor parses a cursor previously returned to the client
+func decodeLocationCursor(str string) (*locationCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode cursor")
+	}
+
+	var cursor locationCursor
+	err = json.Unmarshal(b, &cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal cursor")
+	}
+
+	return &cursor, nil
+}
+
+// isValidCoordinate returns true if the given longitude and latitude are
+// within the valid WGS84 ranges
+func isValidCoordinate(x, y float64) bool {
+	return x >= -180 && x <= 180 && y >= -90 && y <= 90
+}
+
 func updateLocationHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 
@@ -126,8 +545,26 @@ func updateLocationHandler(env *Env, w http.ResponseWriter, r *http.Request) err
 		return err
 	}
 
-	loc, err := env.db.UpdateGeolocation(ctx, req.Code, req.X, req.Y)
+	if !isValidCoordinate(req.X, req.Y) {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("X and Y must be valid WGS84 longitude and latitude values"),
+		}
+	}
+
+	uid := strings.TrimPrefix(req.Code, "Grow.Thingful#")
+
+	middleware.AddAuditTarget(ctx, "location", uid)
+
+	loc, err := env.db.UpdateGeolocation(ctx, uid, middleware.SubjectFromContext(ctx), req.X, req.Y)
 	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("location not found"),
+			}
+		}
+
 		return &HTTPError{
 			Code: http.StatusInternalServerError,
 			Err:  errors.Wrap(err, "failed to update geolocation"),
@@ -142,9 +579,17 @@ func updateLocationHandler(env *Env, w http.ResponseWriter, r *http.Request) err
 		Latitude:   loc.Latitude,
 	}, []flowerpower.Reading{})
 	if err != nil {
-		return &HTTPError{
-			Code: http.StatusInternalServerError,
-			Err:  err,
+		// the database has already been updated, so rather than failing the
+		// request we queue a job to retry the sync until Thingful agrees
+		log := logger.FromContext(ctx)
+		log.Log("msg", "failed to sync location to Thingful, queuing retry", "uid", loc.UID, "err", err)
+
+		err = env.db.EnqueueJob(ctx, postgres.SyncThingQueue, &postgres.SyncThingPayload{ThingUID: loc.UID})
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to queue Thingful sync"),
+			}
 		}
 	}
 
@@ -164,6 +609,262 @@ func updateLocationHandler(env *Env, w http.ResponseWriter, r *http.Request) err
 	return nil
 }
 
+// bulkUpdateLocationsHandler sets the location of many devices in a single
+// transaction. Each entry is validated individually and invalid entries are
+// reported back without preventing the valid entries from being applied. The
+// updated devices are synced to Thingful by a background job.
+func bulkUpdateLocationsHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var req bulkUpdateLocationsRequest
+	err = json.Unmarshal(b, &req)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	if len(req.Locations) == 0 {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("Locations must contain at least one entry"),
+		}
+	}
+
+	if len(req.Locations) > maxBulkLocationUpdates {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Errorf("Locations must not contain more than %d entries", maxBulkLocationUpdates),
+		}
+	}
+
+	results := make([]bulkUpdateResult, len(req.Locations))
+	updates := []postgres.GeolocationUpdate{}
+	seen := map[string]bool{}
+
+	for i, l := range req.Locations {
+		uid := strings.TrimPrefix(l.Code, "Grow.Thingful#")
+		results[i].Code = l.Code
+
+		switch {
+		case uid == "":
+			results[i].Status = http.StatusUnprocessableEntity
+			results[i].Error = "Code must be supplied and be a non-empty string"
+		case !isValidCoordinate(l.X, l.Y):
+			results[i].Status = http.StatusUnprocessableEntity
+			results[i].Error = "X and Y must be valid WGS84 longitude and latitude values"
+		case seen[uid]:
+			results[i].Status = http.StatusUnprocessableEntity
+			results[i].Error = "Code appears more than once in the request"
+		default:
+			seen[uid] = true
+			middleware.AddAuditTarget(ctx, "location", uid)
+			updates = append(updates, postgres.GeolocationUpdate{
+				ThingUID:  uid,
+				Longitude: l.X,
+				Latitude:  l.Y,
+			})
+		}
+	}
+
+	locations, err := env.db.BulkUpdateGeolocations(ctx, middleware.SubjectFromContext(ctx), updates)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to update geolocations"),
+		}
+	}
+
+	for i, l := range req.Locations {
+		if results[i].Status != 0 {
+			continue
+		}
+
+		loc, ok := locations[strings.TrimPrefix(l.Code, "Grow.Thingful#")]
+		if !ok {
+			results[i].Status = http.StatusNotFound
+			results[i].Error = "location not found"
+			continue
+		}
+
+		results[i].Status = http.StatusOK
+		results[i].Location = buildLocation(loc)
+	}
+
+	b, err = json.Marshal(struct {
+		Results []bulkUpdateResult `json:"Results"`
+	}{
+		Results: results,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+// locationHistoryHandler returns the coordinate history of a single device
+func locationHistoryHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var req locationHistoryRequest
+	err = json.Unmarshal(b, &req)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	if req.Code == "" {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("Code must be supplied and be a non-empty string"),
+		}
+	}
+
+	changes, err := env.db.GetLocationHistory(ctx, strings.TrimPrefix(req.Code, "Grow.Thingful#"))
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("location not found"),
+			}
+		}
+
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read location history"),
+		}
+	}
+
+	history := []locationChange{}
+	for _, c := range changes {
+		history = append(history, locationChange{
+			ChangedAt: c.InsertedAt.UTC().Format(timeFormat),
+			AppUID:    c.AppUID.Ptr(),
+			PreviousX: c.PreviousLongitude.Ptr(),
+			PreviousY: c.PreviousLatitude.Ptr(),
+			X:         c.NewLongitude,
+			Y:         c.NewLatitude,
+		})
+	}
+
+	b, err = json.Marshal(struct {
+		Code    string           `json:"Code"`
+		History []locationChange `json:"History"`
+	}{
+		Code:    req.Code,
+		History: history,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+// hardwareHistoryHandler returns the hardware history of a single device,
+// recording e.g. firmware updates and the sensor being moved indoors
+func hardwareHistoryHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var req locationHistoryRequest
+	err = json.Unmarshal(b, &req)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	if req.Code == "" {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("Code must be supplied and be a non-empty string"),
+		}
+	}
+
+	changes, err := env.db.GetHardwareHistory(ctx, strings.TrimPrefix(req.Code, "Grow.Thingful#"))
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("location not found"),
+			}
+		}
+
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read hardware history"),
+		}
+	}
+
+	history := []hardwareChange{}
+	for i := range changes {
+		history = append(history, hardwareChange{
+			ChangedAt: changes[i].InsertedAt.UTC().Format(timeFormat),
+			hardware:  buildHardware(&changes[i].Hardware),
+		})
+	}
+
+	b, err = json.Marshal(struct {
+		Code    string           `json:"Code"`
+		History []hardwareChange `json:"History"`
+	}{
+		Code:    req.Code,
+		History: history,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
 // parseUpdateRequest builds a updateLocationRequest object or returns an error
 func parseUpdateRequest(r *http.Request) (*updateLocationRequest, error) {
 	b, err := ioutil.ReadAll(r.Body)
@@ -186,6 +887,61 @@ func parseUpdateRequest(r *http.Request) (*updateLocationRequest, error) {
 	return &data, nil
 }
 
+// buildHardware builds our output hardware type from the hardware returned
+// from Postgres, returning nil if Parrot hasn't reported any
+func buildHardware(h *postgres.Hardware) *hardware {
+	if !h.FirmwareVersion.Valid && !h.SensorType.Valid {
+		return nil
+	}
+
+	plantIDs := []int64{}
+	if h.PlantIDs != nil {
+		plantIDs = h.PlantIDs
+	}
+
+	return &hardware{
+		FirmwareVersion:  h.FirmwareVersion,
+		HardwareRevision: h.HardwareRevision,
+		SensorType:       h.SensorType,
+		CalibrationData:  h.CalibrationData,
+		IsIndoor:         h.IsIndoor,
+		InPot:            h.InPot,
+		PlantIDs:         plantIDs,
+		AutowateringMode: h.AutowateringMode,
+	}
+}
+
+// buildPlantStatus builds our output plant status from the statuses returned
+// from Postgres, returning nil if there are none. Statuses are replaced
+// together so share a timestamp, but we report the most recent to be safe.
+func buildPlantStatus(statuses []postgres.PlantStatus) *plantStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	var updatedAt time.Time
+	variables := map[string]plantStatusVariable{}
+
+	for _, status := range statuses {
+		if status.StatusAt.After(updatedAt) {
+			updatedAt = status.StatusAt
+		}
+
+		variables[status.Variable] = plantStatusVariable{
+			Status:       status.StatusKey,
+			Instruction:  status.InstructionKey,
+			MinThreshold: status.MinThreshold,
+			MaxThreshold: status.MaxThreshold,
+			CurrentValue: status.CurrentValue,
+		}
+	}
+
+	return &plantStatus{
+		UpdatedAt: updatedAt.UTC().Format("20060102150405"),
+		Variables: variables,
+	}
+}
+
 // buildLocation builds our output location type from the location returned from
 // Postgres
 func buildLocation(loc *postgres.Location) *location {
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/thingful/kudzu/pkg/client"
 	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
 	"github.com/thingful/kudzu/pkg/postgres/helper"
@@ -102,7 +103,7 @@ func (s *LocationHandlersSuite) TestListLocations() {
 	assert.Nil(s.T(), err)
 
 	mux := goji.NewMux()
-	handlers.RegisterLocationHandlers(mux, s.db, s.thingful)
+	handlers.RegisterLocationHandlers(mux, middleware.NewPermissions(""), s.db, s.thingful)
 
 	testcases := []struct {
 		label           string
@@ -134,6 +135,36 @@ func (s *LocationHandlersSuite) TestListLocations() {
 			expectedLength:  2,
 			expectedFirstID: "Grow.Thingful#1236",
 		},
+		{
+			label:           "live",
+			requestBody:     []byte(`{"DataSourceCodes":["Thingful.Connectors.GROWSensors"],"Status":"live"}`),
+			expectedLength:  2,
+			expectedFirstID: "Grow.Thingful#1234",
+		},
+		{
+			label:           "dead",
+			requestBody:     []byte(`{"DataSourceCodes":["Thingful.Connectors.GROWSensors"],"Status":"dead"}`),
+			expectedLength:  2,
+			expectedFirstID: "Grow.Thingful#1238",
+		},
+		{
+			label:           "bounding box",
+			requestBody:     []byte(`{"DataSourceCodes":["Thingful.Connectors.GROWSensors"],"BoundingBox":[12,13,13,14]}`),
+			expectedLength:  3,
+			expectedFirstID: "Grow.Thingful#1234",
+		},
+		{
+			label:           "radius",
+			requestBody:     []byte(`{"DataSourceCodes":["Thingful.Connectors.GROWSensors"],"Near":{"X":12.2,"Y":13.3,"Radius":1000}}`),
+			expectedLength:  3,
+			expectedFirstID: "Grow.Thingful#1234",
+		},
+		{
+			label:           "polygon",
+			requestBody:     []byte(`{"DataSourceCodes":["Thingful.Connectors.GROWSensors"],"Polygon":[[-1,-1],[1,-1],[1,1],[-1,1]]}`),
+			expectedLength:  3,
+			expectedFirstID: "Grow.Thingful#1235",
+		},
 	}
 
 	for _, tc := range testcases {
@@ -156,6 +187,133 @@ func (s *LocationHandlersSuite) TestListLocations() {
 	}
 }
 
+func (s *LocationHandlersSuite) TestListLocationsPaged() {
+	var userID int64
+
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "alice")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, last_sample)
+		VALUES
+			('1234', $1, 'PA1', 12.2, 13.3, 'LOC1', NOW()),
+			('1235', $1, 'PA2', 12.2, 13.3, 'LOC2', NOW()),
+			('1236', $1, 'PA3', 12.2, 13.3, 'LOC3', NOW())`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	mux := goji.NewMux()
+	handlers.RegisterLocationHandlers(mux, middleware.NewPermissions(""), s.db, s.thingful)
+
+	var page struct {
+		Locations  map[string]interface{} `json:"Locations"`
+		Order      []string               `json:"Order"`
+		TotalCount int64                  `json:"TotalCount"`
+		NextCursor string                 `json:"NextCursor"`
+	}
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/entity/locations/get", bytes.NewReader([]byte(`{"Limit":2}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	err = json.Unmarshal(recorder.Body.Bytes(), &page)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), page.Locations, 2)
+	assert.Equal(s.T(), []string{"Grow.Thingful#1234", "Grow.Thingful#1235"}, page.Order)
+	assert.Equal(s.T(), int64(3), page.TotalCount)
+	assert.NotEqual(s.T(), "", page.NextCursor)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPost, "/entity/locations/get", bytes.NewReader([]byte(`{"Limit":2,"Cursor":"`+page.NextCursor+`"}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	page.NextCursor = ""
+	err = json.Unmarshal(recorder.Body.Bytes(), &page)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"Grow.Thingful#1236"}, page.Order)
+	assert.Equal(s.T(), "", page.NextCursor)
+
+	// a cursor can't be used with a different sort order
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPost, "/entity/locations/get", bytes.NewReader([]byte(`{"SortBy":"nickname","Cursor":"eyJzIjoidWlkIiwiZCI6ZmFsc2UsImsiOiIxMjM1IiwidSI6IjEyMzUifQ"}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+}
+
+func (s *LocationHandlersSuite) TestListLocationsInvalidRequests() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	mux := goji.NewMux()
+	handlers.RegisterLocationHandlers(mux, middleware.NewPermissions(""), s.db, s.thingful)
+
+	testcases := []struct {
+		label       string
+		requestBody []byte
+	}{
+		{
+			label:       "short bounding box",
+			requestBody: []byte(`{"BoundingBox":[12,13,13]}`),
+		},
+		{
+			label:       "inverted bounding box",
+			requestBody: []byte(`{"BoundingBox":[13,14,12,13]}`),
+		},
+		{
+			label:       "negative radius",
+			requestBody: []byte(`{"Near":{"X":12.2,"Y":13.3,"Radius":-1}}`),
+		},
+		{
+			label:       "invalid radius centre",
+			requestBody: []byte(`{"Near":{"X":200,"Y":13.3,"Radius":100}}`),
+		},
+		{
+			label:       "too few polygon points",
+			requestBody: []byte(`{"Polygon":[[-1,-1],[1,-1]]}`),
+		},
+		{
+			label:       "invalid status",
+			requestBody: []byte(`{"Status":"zombie"}`),
+		},
+		{
+			label:       "invalid sort",
+			requestBody: []byte(`{"SortBy":"foo"}`),
+		},
+		{
+			label:       "limit too large",
+			requestBody: []byte(`{"Limit":5000}`),
+		},
+		{
+			label:       "invalid cursor",
+			requestBody: []byte(`{"Cursor":"!!!"}`),
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, "/entity/locations/get", bytes.NewReader(tc.requestBody))
+			assert.Nil(t, err)
+			req = req.WithContext(ctx)
+
+			mux.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		})
+	}
+}
+
 func (s *LocationHandlersSuite) TestUpdatelocation() {
 	var userID int64
 	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "alice")
@@ -188,13 +346,13 @@ func (s *LocationHandlersSuite) TestUpdatelocation() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
 	mux := goji.NewMux()
-	handlers.RegisterLocationHandlers(mux, s.db, s.thingful)
+	handlers.RegisterLocationHandlers(mux, middleware.NewPermissions(""), s.db, s.thingful)
 
 	recorder := httptest.NewRecorder()
 
 	input := []byte(`
 	{
-		"Code": "1234",
+		"Code": "Grow.Thingful#1234",
 		"X": 31.2,
 		"Y": 13.2
 	}`)
@@ -210,6 +368,181 @@ func (s *LocationHandlersSuite) TestUpdatelocation() {
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), 31.2, thing.Longitude)
 	assert.Equal(s.T(), 13.2, thing.Latitude)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPost, "/entity/locations/history", bytes.NewReader([]byte(`{"Code":"Grow.Thingful#1234"}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	var history struct {
+		Code    string
+		History []struct {
+			ChangedAt string
+			AppUid    *string
+			PreviousX *float64
+			PreviousY *float64
+			X         float64
+			Y         float64
+		}
+	}
+
+	err = json.Unmarshal(recorder.Body.Bytes(), &history)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), history.History, 2)
+	assert.Nil(s.T(), history.History[0].PreviousX)
+	assert.Equal(s.T(), 12.2, *history.History[1].PreviousX)
+	assert.Equal(s.T(), 31.2, history.History[1].X)
+	assert.Equal(s.T(), 13.2, history.History[1].Y)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPost, "/entity/locations/history", bytes.NewReader([]byte(`{"Code":"unknown"}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *LocationHandlersSuite) TestUpdateLocationInvalid() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	mux := goji.NewMux()
+	handlers.RegisterLocationHandlers(mux, middleware.NewPermissions(""), s.db, s.thingful)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPatch, "/entity/locations/update", bytes.NewReader([]byte(`{"Code":"1234","X":200,"Y":13.2}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPatch, "/entity/locations/update", bytes.NewReader([]byte(`{"Code":"unknown","X":20,"Y":13.2}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *LocationHandlersSuite) TestUpdateLocationQueuesRetry() {
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "alice")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, last_sample)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())`, "1234", userID, "PA1", 12.2, 13.3, "LOC1",
+	)
+	assert.Nil(s.T(), err)
+
+	simular.ActivateNonDefault(s.client.Client)
+	defer simular.DeactivateAndReset()
+
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			"PATCH",
+			"http://thingful.net/things/1234",
+			simular.NewStringResponder(503, "{}"),
+		),
+	)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	mux := goji.NewMux()
+	handlers.RegisterLocationHandlers(mux, middleware.NewPermissions(""), s.db, s.thingful)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPatch, "/entity/locations/update", bytes.NewReader([]byte(`{"Code":"1234","X":31.2,"Y":13.2}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	var jobCount int
+	err = s.db.DB.Get(&jobCount, `SELECT COUNT(*) FROM jobs WHERE queue = 'sync_thing'`)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, jobCount)
+}
+
+func (s *LocationHandlersSuite) TestBulkUpdateLocations() {
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "alice")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, last_sample)
+		VALUES
+			('1234', $1, 'PA1', 12.2, 13.3, 'LOC1', NOW()),
+			('1235', $1, 'PA2', 12.2, 13.3, 'LOC2', NOW())`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	mux := goji.NewMux()
+	handlers.RegisterLocationHandlers(mux, middleware.NewPermissions(""), s.db, s.thingful)
+
+	input := []byte(`
+	{
+		"Locations": [
+			{"Code": "Grow.Thingful#1234", "X": 1.5, "Y": 2.5},
+			{"Code": "1235", "X": 181, "Y": 2.5},
+			{"Code": "unknown", "X": 1.5, "Y": 2.5},
+			{"Code": "1234", "X": 3.5, "Y": 4.5}
+		]
+	}`)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPatch, "/entity/locations/bulkupdate", bytes.NewReader(input))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	var resp struct {
+		Results []struct {
+			Code   string
+			Status int
+			Error  string
+		}
+	}
+
+	err = json.Unmarshal(recorder.Body.Bytes(), &resp)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), resp.Results, 4)
+	assert.Equal(s.T(), http.StatusOK, resp.Results[0].Status)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, resp.Results[1].Status)
+	assert.Equal(s.T(), http.StatusNotFound, resp.Results[2].Status)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, resp.Results[3].Status)
+
+	thing, err := s.db.GetThingByUID(ctx, "1234")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1.5, thing.Longitude)
+	assert.Equal(s.T(), 2.5, thing.Latitude)
+
+	thing, err = s.db.GetThingByUID(ctx, "1235")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 12.2, thing.Longitude)
+
+	var jobCount int
+	err = s.db.DB.Get(&jobCount, `SELECT COUNT(*) FROM jobs WHERE queue = 'sync_thing'`)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, jobCount)
+
+	recorder = httptest.NewRecorder()
+	req, err = http.NewRequest(http.MethodPatch, "/entity/locations/bulkupdate", bytes.NewReader([]byte(`{"Locations":[]}`)))
+	assert.Nil(s.T(), err)
+	req = req.WithContext(ctx)
+
+	mux.ServeHTTP(recorder, req)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
 }
 
 func TestLocationHandlersSuite(t *testing.T) {
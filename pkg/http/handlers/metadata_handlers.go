@@ -1,22 +1,32 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/postgres"
 	goji "goji.io"
 	"goji.io/pat"
 )
 
 // RegisterMetadataHandlers registers any handlers for metadata operations
-func RegisterMetadataHandlers(mux *goji.Mux, db *postgres.DB) {
-	mux.Handle(pat.Post("/entity/timeSeriesInformations/get"), Handler{env: &Env{db: db}, handler: metadataHandler})
+func RegisterMetadataHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB) {
+	mux.Handle(perms.Require(pat.Post("/entity/timeSeriesInformations/get"), postgres.GetMetadataScope), Handler{env: &Env{db: db}, handler: metadataHandler})
 }
 
+// defaultMetadataLimit is the page size used if the client asks for a paged
+// response without specifying a limit
+const defaultMetadataLimit = 100
+
 // timeseries is a struct used when rendering output for the metadata endpoint
 type timeseries struct {
 	ID           int64  `json:"TimeSeriesInformationId"`
@@ -26,10 +36,42 @@ type timeseries struct {
 	EndDate      string `json:"EndDate"`
 }
 
+// metadataRequest is used to parse the optional filters clients may send when
+// requesting metadata. An empty request returns every channel.
+type metadataRequest struct {
+	LocationCodes []string `json:"LocationCodes"`
+	UserUID       string   `json:"UserId"`
+	DataSourceIDs []int64  `json:"DataSourceVariableIds"`
+	StartDate     string   `json:"StartDate"`
+	EndDate       string   `json:"EndDate"`
+	Limit         uint64   `json:"Limit"`
+	Cursor        string   `json:"Cursor"`
+}
+
+// isPaged returns true if the client asked for a paged response
+func (m *metadataRequest) isPaged() bool {
+	return m.Limit > 0 || m.Cursor != ""
+}
+
 func metadataHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 
-	metadata, err := env.db.GetMetadata(ctx)
+	req, err := parseMetadataRequest(r)
+	if err != nil {
+		return err
+	}
+
+	filter, err := buildMetadataFilter(req)
+	if err != nil {
+		return err
+	}
+
+	if req.isPaged() {
+		// request one extra row so we know whether there is a next page
+		filter.Limit = filter.Limit + 1
+	}
+
+	metadata, err := env.db.GetMetadata(ctx, filter)
 	if err != nil {
 		return &HTTPError{
 			Code: http.StatusInternalServerError,
@@ -37,6 +79,13 @@ func metadataHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
+	var nextCursor string
+
+	if req.isPaged() && uint64(len(metadata)) == filter.Limit {
+		metadata = metadata[:len(metadata)-1]
+		nextCursor = encodeIDCursor(metadata[len(metadata)-1].ID)
+	}
+
 	ts := map[string]timeseries{}
 
 	for _, m := range metadata {
@@ -51,11 +100,35 @@ func metadataHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		ts[strconv.FormatInt(m.ID, 10)] = t
 	}
 
-	b, err := json.Marshal(struct {
-		TimeSeriesInformation map[string]timeseries `json:"TimeSeriesInformations"`
-	}{
-		TimeSeriesInformation: ts,
-	})
+	var b []byte
+
+	if req.isPaged() {
+		var count int64
+
+		count, err = env.db.CountMetadata(ctx, filter)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to count metadata"),
+			}
+		}
+
+		b, err = json.Marshal(struct {
+			TimeSeriesInformation map[string]timeseries `json:"TimeSeriesInformations"`
+			TotalCount            int64                 `json:"TotalCount"`
+			NextCursor            string                `json:"NextCursor,omitempty"`
+		}{
+			TimeSeriesInformation: ts,
+			TotalCount:            count,
+			NextCursor:            nextCursor,
+		})
+	} else {
+		b, err = json.Marshal(struct {
+			TimeSeriesInformation map[string]timeseries `json:"TimeSeriesInformations"`
+		}{
+			TimeSeriesInformation: ts,
+		})
+	}
 	if err != nil {
 		return &HTTPError{
 			Code: http.StatusInternalServerError,
@@ -68,3 +141,117 @@ func metadataHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 
 	return nil
 }
+
+// parseMetadataRequest reads the optional filters from the request body. Older
+// clients send no body at all, so we treat that as an empty request.
+func parseMetadataRequest(r *http.Request) (*metadataRequest, error) {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var data metadataRequest
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return &data, nil
+	}
+
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	return &data, nil
+}
+
+// buildMetadataFilter validates the incoming request and converts it into a
+// filter we can pass to the database
+func buildMetadataFilter(req *metadataRequest) (*postgres.MetadataFilter, error) {
+	filter := &postgres.MetadataFilter{
+		OwnerUID:      req.UserUID,
+		DataSourceIDs: req.DataSourceIDs,
+		Limit:         req.Limit,
+	}
+
+	for _, code := range req.LocationCodes {
+		filter.ThingUIDs = append(filter.ThingUIDs, strings.TrimPrefix(code, "Grow.Thingful#"))
+	}
+
+	var err error
+
+	if req.StartDate != "" {
+		filter.StartDate, err = time.Parse(timeFormat, req.StartDate)
+		if err != nil {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.Wrapf(err, "value for StartDate must be a date time string of the format: 20170329000000, received: %s", req.StartDate),
+			}
+		}
+	}
+
+	if req.EndDate != "" {
+		filter.EndDate, err = time.Parse(timeFormat, req.EndDate)
+		if err != nil {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.Wrapf(err, "value for EndDate must be a date time string of the format: 20170329000000, received: %s", req.EndDate),
+			}
+		}
+	}
+
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() && filter.StartDate.After(filter.EndDate) {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("start date must be before end date"),
+		}
+	}
+
+	if req.isPaged() && filter.Limit == 0 {
+		filter.Limit = defaultMetadataLimit
+	}
+
+	if filter.Limit > postgres.MaxMetadataLimit {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Errorf("Limit must not be greater than %d", postgres.MaxMetadataLimit),
+		}
+	}
+
+	if req.Cursor != "" {
+		filter.AfterID, err = decodeIDCursor(req.Cursor)
+		if err != nil {
+			return nil, &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.Wrap(err, "invalid Cursor"),
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// encodeIDCursor returns an opaque cursor pointing after the given id
+func encodeIDCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodeIDCursor returns the id encoded in a cursor returned to the client
+func decodeIDCursor(cursor string) (int64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to decode cursor")
+	}
+
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse cursor")
+	}
+
+	return id, nil
+}
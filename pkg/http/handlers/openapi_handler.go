@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/openapi"
+)
+
+// RegisterOpenAPIHandler registers a handler that serves the OpenAPI document
+// describing our API at the given path. The document is public so that clients
+// can read it before they have been issued a key.
+func RegisterOpenAPIHandler(mux *goji.Mux, path string) {
+	spec := openapi.Spec()
+
+	mux.HandleFunc(pat.Get(path), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	})
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// RegisterQuotaHandlers registers our endpoints for viewing the usage of apps
+// and managing their quotas with the mux. Any app may view its own usage.
+func RegisterQuotaHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB) {
+	mux.Handle(perms.Require(pat.Get("/apps/usage")), Handler{env: &Env{db: db}, handler: ownUsageHandler})
+	mux.Handle(perms.Require(pat.Get("/apps/:uid/usage"), postgres.ManageAppsScope), Handler{env: &Env{db: db}, handler: appUsageHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Patch("/apps/:uid/quotas")), postgres.ManageAppsScope), Handler{env: &Env{db: db}, handler: updateQuotasHandler})
+}
+
+type quotaJSON struct {
+	Scope  postgres.ScopeClaim  `json:"Scope"`
+	Period postgres.QuotaPeriod `json:"Period"`
+	Limit  int                  `json:"Limit"`
+}
+
+type usageJSON struct {
+	Scope        postgres.ScopeClaim `json:"Scope"`
+	Today        int64               `json:"Today"`
+	ThisMonth    int64               `json:"ThisMonth"`
+	DailyQuota   null.Int            `json:"DailyQuota"`
+	MonthlyQuota null.Int            `json:"MonthlyQuota"`
+}
+
+type updateQuotasRequest struct {
+	Quotas []quotaJSON `json:"Quotas"`
+}
+
+// ownUsageHandler returns the usage of the app making the request
+func ownUsageHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	return writeUsage(env, w, r, middleware.SubjectFromContext(r.Context()))
+}
+
+// appUsageHandler returns the usage of any app
+func appUsageHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	return writeUsage(env, w, r, pat.Param(r, "uid"))
+}
+
+// updateQuotasHandler sets the quotas of an app, where a limit of zero removes
+// a quota. Quotas not included in the request are left unchanged.
+func updateQuotasHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var data updateQuotasRequest
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	quotas := []postgres.AppQuota{}
+	for _, q := range data.Quotas {
+		quotas = append(quotas, postgres.AppQuota{
+			Scope:  q.Scope,
+			Period: q.Period,
+			Limit:  q.Limit,
+		})
+	}
+
+	uid := pat.Param(r, "uid")
+	middleware.AddAuditTarget(ctx, "app", uid)
+
+	quotas, err = env.db.SetAppQuotas(ctx, uid, quotas)
+	if err != nil {
+		return appError(err, "failed to set app quotas")
+	}
+
+	log.Log(
+		"msg", "updated app quotas",
+		"uid", uid,
+	)
+
+	return writeQuotas(w, quotas)
+}
+
+// writeQuotas writes the quotas of an app as the response
+func writeQuotas(w http.ResponseWriter, quotas []postgres.AppQuota) error {
+	resp := []quotaJSON{}
+	for _, q := range quotas {
+		resp = append(resp, quotaJSON{
+			Scope:  q.Scope,
+			Period: q.Period,
+			Limit:  q.Limit,
+		})
+	}
+
+	b, err := json.Marshal(struct {
+		Quotas []quotaJSON `json:"Quotas"`
+	}{
+		Quotas: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+// writeUsage writes the usage of the app with the given UID today and this
+// month as the response, along with when each period ends
+func writeUsage(env *Env, w http.ResponseWriter, r *http.Request, uid string) error {
+	now := time.Now().UTC()
+
+	usage, err := env.db.GetAppUsage(r.Context(), uid, now)
+	if err != nil {
+		return appError(err, "failed to get app usage")
+	}
+
+	resp := []usageJSON{}
+	for _, u := range usage {
+		resp = append(resp, usageJSON{
+			Scope:        u.Scope,
+			Today:        u.Today,
+			ThisMonth:    u.ThisMonth,
+			DailyQuota:   u.DailyQuota,
+			MonthlyQuota: u.MonthlyQuota,
+		})
+	}
+
+	b, err := json.Marshal(struct {
+		UID           string      `json:"Uid"`
+		DayResetsAt   time.Time   `json:"DayResetsAt"`
+		MonthResetsAt time.Time   `json:"MonthResetsAt"`
+		Usage         []usageJSON `json:"Usage"`
+	}{
+		UID:           uid,
+		DayResetsAt:   postgres.DailyQuota.End(now),
+		MonthResetsAt: postgres.MonthlyQuota.End(now),
+		Usage:         resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+	goji "goji.io"
+)
+
+type QuotasSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *QuotasSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.logger = logger
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+}
+
+func (s *QuotasSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *QuotasSuite) TestQuotasAndUsage() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	admin, err := s.db.CreateApp(ctx, "Admin", postgres.ScopeClaims{postgres.ManageAppsScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	app, err := s.db.CreateApp(ctx, "Student App", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.CountRequest(ctx, app.UID, postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, time.Now())
+	assert.Nil(s.T(), err)
+
+	mux := goji.NewMux()
+	perms := middleware.NewPermissions("")
+	handlers.RegisterQuotaHandlers(mux, perms, s.db)
+
+	authMiddleware := middleware.NewAuthMiddleware(s.db)
+	mux.Use(authMiddleware.Handler)
+	mux.Use(perms.Handler)
+
+	do := func(key, method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+
+		req, err := http.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		assert.Nil(s.T(), err)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+
+		mux.ServeHTTP(recorder, req.WithContext(ctx))
+
+		return recorder
+	}
+
+	recorder := do(admin.Key, http.MethodPatch, "/apps/"+app.UID+"/quotas", `{"Quotas":[{"Scope":"timeseries","Period":"day","Limit":10000}]}`)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), `{"Quotas":[{"Scope":"timeseries","Period":"day","Limit":10000}]}`, recorder.Body.String())
+
+	recorder = do(admin.Key, http.MethodPatch, "/apps/"+app.UID+"/quotas", `{"Quotas":[{"Scope":"timeseries","Period":"week","Limit":10000}]}`)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+
+	recorder = do(admin.Key, http.MethodPatch, "/apps/unknown/quotas", `{"Quotas":[]}`)
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+
+	// apps can see their own usage
+	recorder = do(app.Key, http.MethodGet, "/apps/usage", "")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), `{"Scope":"timeseries","Today":1,"ThisMonth":1,"DailyQuota":10000,"MonthlyQuota":null}`)
+
+	// but only admins can see that of others
+	recorder = do(app.Key, http.MethodGet, "/apps/"+admin.UID+"/usage", "")
+	assert.Equal(s.T(), http.StatusForbidden, recorder.Code)
+
+	recorder = do(admin.Key, http.MethodGet, "/apps/"+app.UID+"/usage", "")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), fmt.Sprintf(`"Uid":"%s"`, app.UID))
+}
+
+func TestQuotasSuite(t *testing.T) {
+	suite.Run(t, new(QuotasSuite))
+}
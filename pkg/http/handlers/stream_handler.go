@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/stream"
+)
+
+const (
+	// keepAliveInterval is how often we write a comment to idle streams, so
+	// that proxies don't close the connection
+	keepAliveInterval = 15 * time.Second
+
+	// streamRetry is how long in milliseconds we ask clients to wait before
+	// reconnecting to a closed stream
+	streamRetry = 2000
+
+	// readingsEvent is the type of the events sent when new readings are
+	// indexed
+	readingsEvent = "readings"
+)
+
+// RegisterStreamHandler registers our handler that streams events to clients
+// as Server-Sent Events
+func RegisterStreamHandler(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB, broker *stream.Broker) {
+	mux.Handle(perms.Require(pat.Get("/stream"), postgres.GetTimeSeriesDataScope), Handler{env: &Env{db: db, broker: broker}, handler: streamHandler})
+}
+
+// streamHandler pushes an event to the client each time new readings are
+// indexed for any of the requested locations. Clients reconnecting with a
+// Last-Event-ID header are first sent any events they missed.
+func streamHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	uids, err := parseStreamLocations(r)
+	if err != nil {
+		return err
+	}
+
+	var lastID int64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		lastID, err = strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("invalid Last-Event-ID header, expected the id of an event"),
+			}
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.New("streaming is not supported"),
+		}
+	}
+
+	// streams are closed at the write timeout of the server, after which clients
+	// reconnect with the id of the last event they saw and are sent any they
+	// missed
+
+	// subscribe before replaying so that no events are lost in between
+	sub := env.broker.Subscribe(uids)
+	defer env.broker.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+
+	if lastID > 0 {
+		lastID, err = replayEvents(env, w, r, lastID, uids)
+		if err != nil {
+			log.Log("msg", "failed to replay missed events", "err", err)
+			return nil
+		}
+	}
+
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				// we fell behind or the server is stopping, clients reconnect
+				// with the id of the last event they saw
+				return nil
+			}
+
+			// already sent while replaying
+			if event.ID <= lastID {
+				continue
+			}
+
+			err = writeStreamEvent(w, event)
+			if err != nil {
+				return nil
+			}
+		case <-ticker.C:
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+			if err != nil {
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+
+		flusher.Flush()
+	}
+}
+
+// parseStreamLocations returns the thing uids listed in the locations query
+// parameter, which may be given as a comma separated list or repeated
+func parseStreamLocations(r *http.Request) ([]string, error) {
+	seen := map[string]bool{}
+	uids := []string{}
+
+	for _, param := range r.URL.Query()["locations"] {
+		for _, uid := range strings.Split(param, ",") {
+			uid = strings.TrimSpace(uid)
+			if uid == "" || seen[uid] {
+				continue
+			}
+
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+
+	if len(uids) == 0 {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("missing location identifier"),
+		}
+	}
+
+	if len(uids) > maxLocations {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  fmt.Errorf("too many location identifiers, max permitted is %d", maxLocations),
+		}
+	}
+
+	return uids, nil
+}
+
+// replayEvents writes the retained events for the given things published after
+// the event with the given id, returning the id of the last event written
+func replayEvents(env *Env, w http.ResponseWriter, r *http.Request, lastID int64, uids []string) (int64, error) {
+	for {
+		events, err := env.db.ListStreamEvents(r.Context(), lastID, uids)
+		if err != nil {
+			return lastID, errors.Wrap(err, "failed to read missed events")
+		}
+
+		if len(events) == 0 {
+			return lastID, nil
+		}
+
+		for _, event := range events {
+			err = writeStreamEvent(w, event)
+			if err != nil {
+				return lastID, err
+			}
+
+			lastID = event.ID
+		}
+	}
+}
+
+// writeStreamEvent writes the event in the text/event-stream format
+func writeStreamEvent(w http.ResponseWriter, event *postgres.StreamEvent) error {
+	var data bytes.Buffer
+
+	// data must not contain newlines
+	err := json.Compact(&data, event.Payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to compact event payload")
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, readingsEvent, data.Bytes())
+	return err
+}
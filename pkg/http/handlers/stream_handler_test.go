@@ -0,0 +1,154 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+	"github.com/thingful/kudzu/pkg/stream"
+	goji "goji.io"
+)
+
+type StreamHandlerSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+	broker *stream.Broker
+	mux    *goji.Mux
+}
+
+func (s *StreamHandlerSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+	s.broker = stream.NewBroker(&stream.Config{DB: s.db}, logger)
+
+	s.mux = goji.NewMux()
+	handlers.RegisterStreamHandler(s.mux, middleware.NewPermissions(""), s.db, s.broker)
+}
+
+func (s *StreamHandlerSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+// stream makes a request to the stream endpoint which is ended after a short
+// time, calling fn once the request has started
+func (s *StreamHandlerSuite) stream(path string, lastEventID string, fn func()) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithTimeout(logger.ToContext(context.Background(), s.logger), 500*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	assert.Nil(s.T(), err)
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	recorder := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		s.mux.ServeHTTP(recorder, req.WithContext(ctx))
+		close(done)
+	}()
+
+	if fn != nil {
+		fn()
+	}
+
+	<-done
+
+	return recorder
+}
+
+func (s *StreamHandlerSuite) TestStreamReplaysMissedEvents() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	lastID, err := s.db.LastStreamEventID(ctx)
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishStreamEvent(ctx, "abc123", map[string]string{"thingUid": "abc123"})
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishStreamEvent(ctx, "def456", map[string]string{"thingUid": "def456"})
+	assert.Nil(s.T(), err)
+
+	events, err := s.db.ListStreamEvents(ctx, lastID, []string{"abc123"})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), events, 1)
+
+	recorder := s.stream("/stream?locations=abc123", fmt.Sprintf("%d", lastID), nil)
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), "text/event-stream", recorder.Header().Get("Content-Type"))
+	assert.Equal(
+		s.T(),
+		fmt.Sprintf("retry: 2000\n\nid: %d\nevent: readings\ndata: {\"thingUid\":\"abc123\"}\n\n", events[0].ID),
+		recorder.Body.String(),
+	)
+}
+
+func (s *StreamHandlerSuite) TestStreamLiveEvents() {
+	recorder := s.stream("/stream?locations=abc123,def456", "", func() {
+		// wait for the handler to subscribe
+		time.Sleep(100 * time.Millisecond)
+
+		s.broker.Broadcast(&postgres.StreamEvent{ID: 7, ThingUID: "def456", Payload: []byte(`{"a": 1}`)})
+		s.broker.Broadcast(&postgres.StreamEvent{ID: 8, ThingUID: "ghi789", Payload: []byte(`{"a": 2}`)})
+	})
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), "retry: 2000\n\nid: 7\nevent: readings\ndata: {\"a\":1}\n\n", recorder.Body.String())
+}
+
+func (s *StreamHandlerSuite) TestStreamInvalid() {
+	testcases := []struct {
+		label         string
+		path          string
+		lastEventID   string
+		expectedError string
+	}{
+		{
+			label:         "missing locations",
+			path:          "/stream",
+			expectedError: "missing location identifier",
+		},
+		{
+			label:         "too many locations",
+			path:          "/stream?locations=1,2,3,4,5,6,7,8,9,10&locations=11",
+			expectedError: "too many location identifiers, max permitted is 10",
+		},
+		{
+			label:         "invalid last event id",
+			path:          "/stream?locations=1",
+			lastEventID:   "foo",
+			expectedError: "invalid Last-Event-ID header, expected the id of an event",
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder := s.stream(tc.path, tc.lastEventID, nil)
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.expectedError)
+		})
+	}
+}
+
+func TestStreamHandlerSuite(t *testing.T) {
+	suite.Run(t, new(StreamHandlerSuite))
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/guregu/null"
 	"github.com/pkg/errors"
+	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/postgres"
 	"github.com/thingful/kudzu/pkg/thingful"
 )
@@ -28,8 +29,8 @@ const (
 )
 
 // RegisterTimeseriesHandler registers our handler that requests data from Thingful
-func RegisterTimeseriesHandler(mux *goji.Mux, db *postgres.DB, th *thingful.Thingful) {
-	mux.Handle(pat.Post("/timeSeries/get"), Handler{env: &Env{db: db, thingful: th}, handler: timeseriesHandler})
+func RegisterTimeseriesHandler(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB, th *thingful.Thingful) {
+	mux.Handle(perms.Require(pat.Post("/timeSeries/get"), postgres.GetTimeSeriesDataScope), Handler{env: &Env{db: db, thingful: th}, handler: timeseriesHandler})
 }
 
 // setting is a type used to parse an incoming timeseries request. We build the
@@ -44,6 +45,7 @@ type setting struct {
 	Ascending       bool      `json:"Order"`
 	StructureType   string    `json:"StructureType"`
 	CalculationType string    `json:"CalculationType"`
+	IncludeLocation bool      `json:"IncludeLocation"`
 }
 
 // UnmarshalJSON is a custom unmarshaller to convert strings into times and
@@ -93,6 +95,11 @@ type timeseriesRequest struct {
 type observation struct {
 	Value    float64 `json:"Value"`
 	DateTime time.Time
+
+	// X and Y are the coordinates of the device when the observation was
+	// recorded, and are only included if the client asks for them
+	X *float64 `json:"X,omitempty"`
+	Y *float64 `json:"Y,omitempty"`
 }
 
 // MarshalJSON is an implementation of the marshaller interface to add extra
@@ -283,7 +290,19 @@ func timeseriesHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
-	resp, err := buildResponse(things, rd.VariableCodes, datasources, rd.Ascending)
+	var histories map[string][]postgres.LocationChange
+
+	if rd.IncludeLocation {
+		histories, err = env.db.GetLocationHistories(ctx, rd.LocationCodes)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to read location history from the DB"),
+			}
+		}
+	}
+
+	resp, err := buildResponse(things, rd.VariableCodes, datasources, rd.Ascending, histories)
 	if err != nil {
 		return &HTTPError{
 			Code: http.StatusInternalServerError,
@@ -378,7 +397,10 @@ func parseTimeSeriesRequest(r *http.Request) (*setting, error) {
 	return &reader.Setting, nil
 }
 
-func buildResponse(things []thingful.Thing, variableCodes []string, datasources []postgres.DataSource, ascending bool) (*timeseriesResponse, error) {
+// buildResponse builds the HydroNet response from the data returned by
+// Thingful. If histories is non-nil each observation is annotated with the
+// coordinates of the device at the time it was recorded.
+func buildResponse(things []thingful.Thing, variableCodes []string, datasources []postgres.DataSource, ascending bool, histories map[string][]postgres.LocationChange) (*timeseriesResponse, error) {
 	allSeries := []series{}
 	locations := map[string]hydronetLocation{}
 	units := map[string]hydronetUnit{}
@@ -443,6 +465,10 @@ func buildResponse(things []thingful.Thing, variableCodes []string, datasources
 				return nil, err
 			}
 
+			if histories != nil {
+				locateObservations(observations, histories[uid], t.Attributes.Location.Longitude, t.Attributes.Location.Latitude)
+			}
+
 			var (
 				startDate, endDate time.Time
 			)
@@ -542,6 +568,43 @@ func buildObservations(input []thingful.Observation, ascending bool) ([]observat
 	return observations, nil
 }
 
+// locateObservations sets the coordinates of each observation to the location
+// of the device at the time the observation was recorded. Observations recorded
+// before the first entry in the history take the earliest known coordinates,
+// and if there is no history at all we use the current coordinates.
+func locateObservations(observations []observation, history []postgres.LocationChange, longitude, latitude float64) {
+	for i := range observations {
+		x, y := locationAt(history, observations[i].DateTime, longitude, latitude)
+		observations[i].X = &x
+		observations[i].Y = &y
+	}
+}
+
+// locationAt returns the coordinates in effect at the given time according to
+// the history, falling back to the given coordinates if the history is empty
+func locationAt(history []postgres.LocationChange, at time.Time, longitude, latitude float64) (float64, float64) {
+	if len(history) == 0 {
+		return longitude, latitude
+	}
+
+	first := history[0]
+
+	if first.PreviousLongitude.Valid && first.PreviousLatitude.Valid {
+		longitude, latitude = first.PreviousLongitude.Float64, first.PreviousLatitude.Float64
+	} else {
+		longitude, latitude = first.NewLongitude, first.NewLatitude
+	}
+
+	for _, c := range history {
+		if c.InsertedAt.After(at) {
+			break
+		}
+		longitude, latitude = c.NewLongitude, c.NewLatitude
+	}
+
+	return longitude, latitude
+}
+
 func getUnitKey(channelID, unit string) string {
 	return unitToHN4(path.Base(channelID), null.NewString(unit, unit != ""))
 }
@@ -1,25 +1,38 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"io/ioutil"
+	"math"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/guregu/null"
 	"github.com/pkg/errors"
 	goji "goji.io"
 	"goji.io/pat"
 
 	"github.com/thingful/kudzu/pkg/client"
+	"github.com/thingful/kudzu/pkg/export"
 	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/indexer"
 	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/thingful"
 )
 
 // RegisterUserHandlers registers our user related handlers into the mux
-func RegisterUserHandlers(mux *goji.Mux, db *postgres.DB, cl *client.Client, in *indexer.Indexer) {
-	mux.Handle(pat.Post("/user/new"), Handler{env: &Env{db: db, client: cl, indexer: in}, handler: newUserHandler})
-	mux.Handle(pat.Delete("/user/delete"), Handler{env: &Env{db: db}, handler: deleteUserHandler})
+func RegisterUserHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB, cl *client.Client, in *indexer.Indexer, th *thingful.Thingful) {
+	mux.Handle(perms.Require(perms.Audit(pat.Post("/user/new")), postgres.CreateUserScope), Handler{env: &Env{db: db, client: cl, indexer: in}, handler: newUserHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Delete("/user/delete")), postgres.DeleteUserScope), Handler{env: &Env{db: db}, handler: deleteUserHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Patch("/user/credentials")), postgres.CreateUserScope), Handler{env: &Env{db: db, client: cl}, handler: updateCredentialsHandler})
+	mux.Handle(perms.Require(pat.Get("/user/:uid/status"), postgres.CreateUserScope), Handler{env: &Env{db: db}, handler: userStatusHandler})
+	mux.Handle(perms.Require(pat.Get("/user/:uid/deletion"), postgres.DeleteUserScope), Handler{env: &Env{db: db}, handler: userDeletionHandler})
+	mux.Handle(perms.Require(pat.Get("/user/:uid/export"), postgres.ExportUserScope), Handler{env: &Env{db: db, thingful: th}, handler: exportUserHandler})
 }
 
 // newUserRequest is a local type used for parsing incoming requests
@@ -50,6 +63,8 @@ func newUserHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
+	middleware.AddAuditTarget(ctx, "user", userData.Info.UID)
+
 	// get user profile from parrot
 	parrotUser, err := flowerpower.GetUser(ctx, env.client, userData.Info.AccessToken)
 	if err != nil {
@@ -143,7 +158,10 @@ func parseNewUserRequest(r *http.Request) (*newUserRequest, error) {
 	return &data, nil
 }
 
-func deleteUserHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+// updateCredentialsHandler replaces the Parrot tokens of an existing user, e.g.
+// after they log in again on the hub, without losing their data. The user is
+// reindexed as soon as the indexer is free.
+func updateCredentialsHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
 
@@ -152,25 +170,139 @@ func deleteUserHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	if userData.Info.UID == "" {
+	if userData.Info.UID == "" || userData.Info.AccessToken == "" {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("User identifier and access token must be supplied"),
+		}
+	}
+
+	middleware.AddAuditTarget(ctx, "user", userData.Info.UID)
+
+	// check the new token works, and which account it belongs to
+	parrotUser, err := flowerpower.GetUser(ctx, env.client, userData.Info.AccessToken)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusBadGateway,
+			Err:  errors.New("failed to read profile information from flowerpower API"),
+		}
+	}
+
+	err = env.db.UpdateCredentials(ctx, &postgres.User{
+		UID:          userData.Info.UID,
+		ParrotID:     parrotUser.ParrotID,
+		AccessToken:  userData.Info.AccessToken,
+		RefreshToken: userData.Info.RefreshToken,
+		Provider:     userData.Info.Provider,
+	})
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("user not found"),
+			}
+		case postgres.ClientError:
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  err,
+			}
+		default:
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to update credentials"),
+			}
+		}
+	}
+
+	log.Log(
+		"msg", "updated user credentials",
+		"uid", userData.Info.UID,
+	)
+
+	b, err := json.Marshal(struct {
+		UserUID string `json:"User"`
+		State   string `json:"State"`
+	}{
+		UserUID: userData.Info.UID,
+		State:   postgres.IdentityPending,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(b)
+
+	return nil
+}
+
+// deleteUserRequest is a local type used for parsing incoming deletion
+// requests, the mode defaults to purge
+type deleteUserRequest struct {
+	newUserRequest
+	Mode string `json:"Mode"`
+}
+
+// deleteUserHandler requests the deletion of a user. The deletion is carried
+// out by a background job, and its progress can be followed with
+// userDeletionHandler.
+func deleteUserHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var data deleteUserRequest
+
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	if data.Info.UID == "" {
 		return &HTTPError{
 			Code: http.StatusUnprocessableEntity,
 			Err:  errors.New("User identifier must be supplied"),
 		}
 	}
 
-	err = env.db.DeleteUser(ctx, userData.Info.UID)
+	middleware.AddAuditTarget(ctx, "user", data.Info.UID)
+
+	if data.Mode == "" {
+		data.Mode = postgres.DeletionPurge
+	}
+
+	deletion, err := env.db.RequestUserDeletion(ctx, data.Info.UID, data.Mode)
 	if err != nil {
 		log.Log(
 			"msg", "error deleting user",
 			"error", err,
 		)
 		switch errors.Cause(err) {
-		case postgres.ClientError:
+		case sql.ErrNoRows:
 			return &HTTPError{
 				Code: http.StatusNotFound,
 				Err:  errors.New("Unable to delete the specified user"),
 			}
+		case postgres.ClientError:
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  err,
+			}
 		default:
 			return &HTTPError{
 				Code: http.StatusInternalServerError,
@@ -179,7 +311,206 @@ func deleteUserHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return writeUserDeletion(w, http.StatusAccepted, deletion)
+}
+
+// userDeletionHandler returns the progress of the latest deletion of a user
+func userDeletionHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	deletion, err := env.db.GetLatestUserDeletion(ctx, pat.Param(r, "uid"))
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("no deletion of the user has been requested"),
+			}
+		}
+
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read user deletion"),
+		}
+	}
+
+	return writeUserDeletion(w, http.StatusOK, deletion)
+}
+
+// writeUserDeletion writes the progress of a deletion as the response
+func writeUserDeletion(w http.ResponseWriter, code int, deletion *postgres.UserDeletion) error {
+	b, err := json.Marshal(struct {
+		UserUID     string  `json:"User"`
+		Mode        string  `json:"Mode"`
+		State       string  `json:"State"`
+		TotalThings int     `json:"TotalThings"`
+		RequestedAt string  `json:"RequestedAt"`
+		CompletedAt *string `json:"CompletedAt"`
+		Attempts    int64   `json:"Attempts"`
+		LastError   *string `json:"LastError"`
+	}{
+		UserUID:     deletion.UserUID,
+		Mode:        deletion.Mode,
+		State:       deletion.State(),
+		TotalThings: len(deletion.ThingUIDs),
+		RequestedAt: deletion.CreatedAt.UTC().Format(timeFormat),
+		CompletedAt: formatNullTime(deletion.CompletedAt),
+		Attempts:    deletion.Attempts.Int64,
+		LastError:   deletion.LastError.Ptr(),
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(b)
 
 	return nil
 }
+
+// thingStatus is used when rendering the backfill progress of a single thing,
+// timestamps are null until the thing has been indexed
+type thingStatus struct {
+	Code                        string  `json:"Code"`
+	FirstSampleTimestamp        *string `json:"FirstSampleTimestamp"`
+	LastSampleTimestamp         *string `json:"LastSampleTimestamp"`
+	LastUploadedSampleTimestamp *string `json:"LastUploadedSampleTimestamp"`
+	Backfilled                  float64 `json:"Backfilled"`
+}
+
+// userStatusHandler returns the progress of indexing a user's data, so the
+// client that registered them can show how much of their history has been
+// backfilled
+func userStatusHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	status, err := env.db.GetIndexingStatus(ctx, pat.Param(r, "uid"))
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("user not found"),
+			}
+		}
+
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read indexing status"),
+		}
+	}
+
+	things := []thingStatus{}
+	for i := range status.Things {
+		t := &status.Things[i]
+
+		things = append(things, thingStatus{
+			Code:                        t.UID,
+			FirstSampleTimestamp:        formatNullTime(t.FirstSampleUTC),
+			LastSampleTimestamp:         formatNullTime(t.LastSampleUTC),
+			LastUploadedSampleTimestamp: formatNullTime(t.LastUploadedUTC),
+			Backfilled:                  roundPercentage(t.Backfilled()),
+		})
+	}
+
+	b, err := json.Marshal(struct {
+		UserUID       string        `json:"User"`
+		State         string        `json:"State"`
+		LastIndexedAt *string       `json:"LastIndexedAt"`
+		TotalThings   int           `json:"TotalThings"`
+		Backfilled    float64       `json:"Backfilled"`
+		Things        []thingStatus `json:"Things"`
+	}{
+		UserUID:       status.UserUID,
+		State:         status.State(time.Now()),
+		LastIndexedAt: formatNullTime(status.IndexedAt),
+		TotalThings:   len(things),
+		Backfilled:    roundPercentage(status.Backfilled()),
+		Things:        things,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+// exportUserHandler streams a zip archive of everything we hold about a user,
+// for answering subject access requests. Observations can only be exported
+// with the users export command, as reading them back from Thingful is too
+// slow for a request and would have to be held in memory until written.
+func exportUserHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	uid := pat.Param(r, "uid")
+
+	if value := r.URL.Query().Get("observations"); value != "" {
+		withObservations, err := strconv.ParseBool(value)
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("observations must be true or false"),
+			}
+		}
+
+		if withObservations {
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  errors.New("observations can only be exported with the kudzu users export command"),
+			}
+		}
+	}
+
+	archive, err := export.NewExporter(env.db, env.thingful).Export(ctx, uid, false)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("user not found"),
+			}
+		}
+
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to export user"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": "kudzu-export-" + uid + ".zip",
+	}))
+
+	// the archive is written straight to the client, so once started the status
+	// can no longer be changed and a failure leaves a truncated zip
+	err = archive.WriteZip(w)
+	if err != nil {
+		log.Log("msg", "failed to write export archive", "uid", uid, "err", err)
+	}
+
+	return nil
+}
+
+// formatNullTime formats the time in our usual timestamp format, returning nil
+// for a null time
+func formatNullTime(t null.Time) *string {
+	if !t.Valid {
+		return nil
+	}
+
+	formatted := t.Time.UTC().Format(timeFormat)
+	return &formatted
+}
+
+// roundPercentage rounds a percentage to one decimal place
+func roundPercentage(p float64) float64 {
+	return math.Round(p*10) / 10
+}
@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"io/ioutil"
@@ -10,6 +11,8 @@ import (
 	"testing"
 
 	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/thingful/simular"
@@ -18,18 +21,21 @@ import (
 	"github.com/thingful/kudzu/pkg/client"
 	"github.com/thingful/kudzu/pkg/flowerpower"
 	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/indexer"
 	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
 	"github.com/thingful/kudzu/pkg/postgres/helper"
+	"github.com/thingful/kudzu/pkg/thingful"
 )
 
 type UsersSuite struct {
 	suite.Suite
-	db      *postgres.DB
-	logger  kitlog.Logger
-	client  *client.Client
-	indexer *indexer.Indexer
+	db       *postgres.DB
+	logger   kitlog.Logger
+	client   *client.Client
+	indexer  *indexer.Indexer
+	thingful *thingful.Thingful
 }
 
 func (s *UsersSuite) SetupTest() {
@@ -40,6 +46,7 @@ func (s *UsersSuite) SetupTest() {
 	s.db = helper.PrepareDB(s.T(), connStr, logger)
 
 	s.client = client.NewClient(1, true)
+	s.thingful = thingful.NewClient(s.client, "http://thingful.net", "api-key", true, 2)
 	s.indexer = indexer.NewIndexer(
 		&indexer.Config{
 			DB:     s.db,
@@ -102,7 +109,7 @@ func (s *UsersSuite) TestCreateUser() {
 
 	// create mux and register the handler we want to test
 	mux := goji.NewMux()
-	handlers.RegisterUserHandlers(mux, s.db, s.client, s.indexer)
+	handlers.RegisterUserHandlers(mux, middleware.NewPermissions(""), s.db, s.client, s.indexer, s.thingful)
 
 	recorder := httptest.NewRecorder()
 
@@ -159,7 +166,7 @@ func (s *UsersSuite) TestCreateUserWhenAlreadyRegistered() {
 
 	// create mux and register the handler we want to test
 	mux := goji.NewMux()
-	handlers.RegisterUserHandlers(mux, s.db, s.client, s.indexer)
+	handlers.RegisterUserHandlers(mux, middleware.NewPermissions(""), s.db, s.client, s.indexer, s.thingful)
 
 	recorder := httptest.NewRecorder()
 
@@ -195,22 +202,319 @@ func (s *UsersSuite) TestDeleteUser() {
 	assert.Nil(s.T(), err)
 
 	mux := goji.NewMux()
-	handlers.RegisterUserHandlers(mux, s.db, s.client, s.indexer)
+	handlers.RegisterUserHandlers(mux, middleware.NewPermissions(""), s.db, s.client, s.indexer, s.thingful)
+
+	testcases := []struct {
+		label        string
+		input        string
+		expectedCode int
+	}{
+		{
+			label:        "invalid mode",
+			input:        `{"User":{"Identifier":"barnabas"},"Mode":"shred"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			label:        "unknown user",
+			input:        `{"User":{"Identifier":"luca"}}`,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			label:        "default mode",
+			input:        `{"User":{"Identifier":"barnabas"}}`,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			label:        "already in progress",
+			input:        `{"User":{"Identifier":"barnabas"},"Mode":"anonymise"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodDelete, "/user/delete", bytes.NewReader([]byte(tc.input)))
+			assert.Nil(t, err)
+
+			mux.ServeHTTP(recorder, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
 
 	recorder := httptest.NewRecorder()
+
+	req, err := http.NewRequest(http.MethodGet, "/user/barnabas/deletion", nil)
+	assert.Nil(s.T(), err)
+
+	mux.ServeHTTP(recorder, req.WithContext(ctx))
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), `"Mode":"purge","State":"pending","TotalThings":0`)
+
+	recorder = httptest.NewRecorder()
+
+	req, err = http.NewRequest(http.MethodGet, "/user/luca/deletion", nil)
+	assert.Nil(s.T(), err)
+
+	mux.ServeHTTP(recorder, req.WithContext(ctx))
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *UsersSuite) TestUpdateCredentials() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	profileBytes, err := ioutil.ReadFile("../../flowerpower/testdata/barnabas_profile.json")
+	assert.Nil(s.T(), err)
+
+	simular.ActivateNonDefault(s.client.Client)
+	defer simular.DeactivateAndReset()
+
+	var userID int64
+	err = s.db.DB.Get(&userID, `INSERT INTO users (uid, parrot_id) VALUES ($1, $2) RETURNING id`, "barnabas", "barnabas@example.com")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`INSERT INTO identities (owner_id, auth_provider, access_token, refresh_token, indexed_at, revoked_at)
+		VALUES ($1, 'parrot', 'old', 'old', NOW(), NOW())`, userID)
+	assert.Nil(s.T(), err)
+
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			"GET",
+			flowerpower.ProfileURL,
+			simular.NewBytesResponder(200, profileBytes),
+			simular.WithHeader(
+				&http.Header{
+					"Authorization": []string{"Bearer access"},
+				},
+			),
+		),
+	)
+
+	mux := goji.NewMux()
+	handlers.RegisterUserHandlers(mux, middleware.NewPermissions(""), s.db, s.client, s.indexer, s.thingful)
+
+	recorder := httptest.NewRecorder()
+
 	input := []byte(`
 	{
 		"User": {
-			"Identifier": "barnabas"
+			"Identifier": "barnabas",
+			"Provider": "parrot",
+			"AccessToken": "access",
+			"RefreshToken": "refresh"
 		}
 	}`)
 
-	req, err := http.NewRequest(http.MethodDelete, "/user/delete", bytes.NewReader(input))
+	req, err := http.NewRequest(http.MethodPatch, "/user/credentials", bytes.NewReader(input))
 	assert.Nil(s.T(), err)
-
 	req = req.WithContext(ctx)
+
 	mux.ServeHTTP(recorder, req)
-	assert.Equal(s.T(), http.StatusNoContent, recorder.Code)
+	assert.Equal(s.T(), http.StatusAccepted, recorder.Code)
+	assert.JSONEq(s.T(), `{"User":"barnabas","State":"pending"}`, recorder.Body.String())
+
+	var identity struct {
+		AccessToken  string    `db:"access_token"`
+		RefreshToken string    `db:"refresh_token"`
+		IndexedAt    null.Time `db:"indexed_at"`
+		RevokedAt    null.Time `db:"revoked_at"`
+	}
+
+	err = s.db.DB.Get(&identity, `SELECT access_token, refresh_token, indexed_at, revoked_at FROM identities WHERE owner_id = $1`, userID)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "access", identity.AccessToken)
+	assert.Equal(s.T(), "refresh", identity.RefreshToken)
+	assert.False(s.T(), identity.IndexedAt.Valid)
+	assert.False(s.T(), identity.RevokedAt.Valid)
+
+	err = simular.AllStubsCalled()
+	assert.Nil(s.T(), err)
+}
+
+func (s *UsersSuite) TestUpdateCredentialsForOtherAccount() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	profileBytes, err := ioutil.ReadFile("../../flowerpower/testdata/barnabas_profile.json")
+	assert.Nil(s.T(), err)
+
+	simular.ActivateNonDefault(s.client.Client)
+	defer simular.DeactivateAndReset()
+
+	_, err = s.db.DB.Exec(`INSERT INTO users (uid, parrot_id) VALUES ($1, $2)`, "luca", "luca@example.com")
+	assert.Nil(s.T(), err)
+
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			"GET",
+			flowerpower.ProfileURL,
+			simular.NewBytesResponder(200, profileBytes),
+		),
+	)
+
+	mux := goji.NewMux()
+	handlers.RegisterUserHandlers(mux, middleware.NewPermissions(""), s.db, s.client, s.indexer, s.thingful)
+
+	testcases := []struct {
+		label        string
+		identifier   string
+		expectedCode int
+	}{
+		{
+			label:        "different account",
+			identifier:   "luca",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			label:        "unknown user",
+			identifier:   "barnabas",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			input := []byte(`{"User":{"Identifier":"` + tc.identifier + `","AccessToken":"access"}}`)
+
+			req, err := http.NewRequest(http.MethodPatch, "/user/credentials", bytes.NewReader(input))
+			assert.Nil(t, err)
+
+			mux.ServeHTTP(recorder, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func (s *UsersSuite) TestExportUser() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid, parrot_id) VALUES ($1, $2) RETURNING id`, "barnabas", "barnabas@example.com")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(
+		`INSERT INTO identities (owner_id, auth_provider, access_token, refresh_token) VALUES ($1, 'oauth', 'secret-access', 'secret-refresh')`,
+		userID,
+	)
+	assert.Nil(s.T(), err)
+
+	err = s.db.CreateThing(ctx, &postgres.Thing{
+		UID:        null.StringFrom("abc123"),
+		OwnerID:    null.IntFrom(userID),
+		Provider:   null.StringFrom("parrot"),
+		SerialNum:  "PA123",
+		LocationID: "loc1",
+		Longitude:  -0.5,
+		Latitude:   55.1,
+	})
+	assert.Nil(s.T(), err)
+
+	app, err := s.db.CreateApp(ctx, "Client", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	rule, err := s.db.CreateAlertRule(ctx, app.UID, &postgres.AlertRule{
+		ThingUID:   null.StringFrom("abc123"),
+		Channel:    "soil_moisture",
+		Comparator: "lt",
+		Threshold:  15,
+	})
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.UserIndexedEvent})
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishWebhookEvent(ctx, postgres.UserIndexedEvent, &postgres.UserEventData{UserUID: "barnabas"})
+	assert.Nil(s.T(), err)
+
+	err = s.db.RecordAudit(ctx, &postgres.AuditRecord{
+		AppUID:    app.UID,
+		RequestID: "req1",
+		Method:    http.MethodPost,
+		Route:     "/alerts/rules/new",
+		Path:      "/alerts/rules/new",
+		Targets:   pq.StringArray{"alert-rule:" + rule.UID},
+		Status:    http.StatusCreated,
+	})
+	assert.Nil(s.T(), err)
+
+	mux := goji.NewMux()
+	handlers.RegisterUserHandlers(mux, middleware.NewPermissions(""), s.db, s.client, s.indexer, s.thingful)
+
+	testcases := []struct {
+		label        string
+		path         string
+		expectedCode int
+	}{
+		{
+			label:        "existing user",
+			path:         "/user/barnabas/export",
+			expectedCode: http.StatusOK,
+		},
+		{
+			label:        "unknown user",
+			path:         "/user/luca/export",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			label:        "invalid observations flag",
+			path:         "/user/barnabas/export?observations=maybe",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			label:        "observations are only exported from the command line",
+			path:         "/user/barnabas/export?observations=true",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			assert.Nil(t, err)
+
+			mux.ServeHTTP(recorder, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+
+	req, err := http.NewRequest(http.MethodGet, "/user/barnabas/export", nil)
+	assert.Nil(s.T(), err)
+
+	mux.ServeHTTP(recorder, req.WithContext(ctx))
+	assert.Equal(s.T(), "application/zip", recorder.Header().Get("Content-Type"))
+	assert.Equal(s.T(), `attachment; filename=kudzu-export-barnabas.zip`, recorder.Header().Get("Content-Disposition"))
+
+	zr, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+	assert.Nil(s.T(), err)
+
+	for _, f := range zr.File {
+		r, err := f.Open()
+		assert.Nil(s.T(), err)
+
+		b, err := ioutil.ReadAll(r)
+		assert.Nil(s.T(), err)
+		r.Close()
+
+		assert.NotContains(s.T(), string(b), "secret-access", f.Name)
+		assert.NotContains(s.T(), string(b), "secret-refresh", f.Name)
+
+		switch f.Name {
+		case "things.csv":
+			assert.Contains(s.T(), string(b), "abc123,parrot,PA123")
+		case "alert_rules.csv":
+			assert.Contains(s.T(), string(b), rule.UID+","+app.UID+",abc123,soil_moisture")
+		case "webhook_deliveries.csv":
+			assert.Contains(s.T(), string(b), postgres.UserIndexedEvent)
+		case "audit_records.csv":
+			assert.Contains(s.T(), string(b), "alert-rule:"+rule.UID)
+		}
+	}
 }
 
 func TestUsersSuite(t *testing.T) {
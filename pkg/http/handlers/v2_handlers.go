@@ -0,0 +1,619 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+const (
+	// jsonAPIContentType is the media type of every v2 response
+	jsonAPIContentType = "application/vnd.api+json"
+
+	// defaultV2PageSize is the number of things returned per page if the client
+	// does not ask for a particular size
+	defaultV2PageSize = 50
+
+	// defaultObservationWindow is how far back we read observations if the
+	// client does not give a start time
+	defaultObservationWindow = 24 * time.Hour
+)
+
+// v2SortKeys maps the sort keys of the v2 API to the sort columns understood by
+// postgres.LocationFilter
+var v2SortKeys = map[string]string{
+	"uid":        "uid",
+	"lastSample": "last_sample",
+	"createdAt":  "created_at",
+	"nickname":   "nickname",
+}
+
+// RegisterV2Handlers registers the resource oriented v2 API. Unlike the
+// HydroNet routes, all v2 routes are read via GET with filtering in the query
+// string, and respond with JSON:API style documents.
+func RegisterV2Handlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB, th Thingful) {
+	mux.Handle(perms.Require(pat.Get("/v2/users/:uid/things"), postgres.GetMetadataScope), v2Handler{env: &Env{db: db}, handler: listUserThingsHandler})
+	mux.Handle(perms.Require(pat.Get("/v2/things/:uid"), postgres.GetMetadataScope), v2Handler{env: &Env{db: db}, handler: getThingHandler})
+	mux.Handle(perms.Require(pat.Get("/v2/things/:uid/channels/:id/observations"), postgres.GetTimeSeriesDataScope), v2Handler{env: &Env{db: db, thingful: th}, handler: listObservationsHandler})
+}
+
+// v2Handler is the v2 equivalent of Handler, which writes any error returned by
+// the handler as a JSON:API error document
+type v2Handler struct {
+	env     *Env
+	handler func(env *Env, w http.ResponseWriter, r *http.Request) error
+}
+
+// parameterError is returned from v2 handlers when a query parameter is invalid,
+// so that the error document can identify the parameter
+type parameterError struct {
+	param string
+	err   error
+}
+
+// Error returns the message
+func (p *parameterError) Error() string {
+	return p.err.Error()
+}
+
+// Status returns the status code associated with the error response
+func (p *parameterError) Status() int {
+	return http.StatusBadRequest
+}
+
+// errorObject is a single error of a JSON:API error document
+type errorObject struct {
+	Status string       `json:"status"`
+	Title  string       `json:"title"`
+	Detail string       `json:"detail,omitempty"`
+	Source *errorSource `json:"source,omitempty"`
+}
+
+// errorSource identifies the part of the request that caused an error
+type errorSource struct {
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// ServeHTTP is our implementation of the Handler interface
+func (h v2Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.handler(h.env, w, r)
+	if err == nil {
+		return
+	}
+
+	obj := errorObject{}
+	status := http.StatusInternalServerError
+
+	switch e := err.(type) {
+	case *parameterError:
+		status = e.Status()
+		obj.Detail = e.Error()
+		obj.Source = &errorSource{Parameter: e.param}
+	case Error:
+		status = e.Status()
+		obj.Detail = e.Error()
+	}
+
+	// internal errors are logged rather than being returned to the client
+	if status == http.StatusInternalServerError {
+		log := logger.FromContext(r.Context())
+		log.Log("msg", "internal server error", "error", err.Error())
+		obj.Detail = ""
+	}
+
+	obj.Status = strconv.Itoa(status)
+	obj.Title = http.StatusText(status)
+
+	b, innerErr := json.Marshal(struct {
+		Errors []errorObject `json:"errors"`
+	}{
+		Errors: []errorObject{obj},
+	})
+	if innerErr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", jsonAPIContentType)
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+// resource is a single JSON:API resource object
+type resource struct {
+	Type          string                  `json:"type"`
+	ID            string                  `json:"id"`
+	Attributes    interface{}             `json:"attributes,omitempty"`
+	Relationships map[string]relationship `json:"relationships,omitempty"`
+}
+
+// resourceIdentifier identifies a related resource
+type resourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// relationship links a resource to either a single resourceIdentifier or a
+// slice of them
+type relationship struct {
+	Data interface{} `json:"data"`
+}
+
+// links holds the links of a document
+type links struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+}
+
+// document is a JSON:API top level document
+type document struct {
+	Data     interface{}            `json:"data"`
+	Included []resource             `json:"included,omitempty"`
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+	Links    *links                 `json:"links,omitempty"`
+}
+
+// thingAttributes are the attributes of a things resource
+type thingAttributes struct {
+	Nickname           string     `json:"nickname"`
+	SerialNumber       string     `json:"serialNumber"`
+	LocationIdentifier string     `json:"locationIdentifier"`
+	Longitude          float64    `json:"longitude"`
+	Latitude           float64    `json:"latitude"`
+	FirstSample        *time.Time `json:"firstSample"`
+	LastSample         *time.Time `json:"lastSample"`
+}
+
+// channelAttributes are the attributes of a channels resource
+type channelAttributes struct {
+	DataSourceVariableID int64      `json:"dataSourceVariableId"`
+	VariableCode         string     `json:"variableCode"`
+	Unit                 *string    `json:"unit"`
+	DataType             string     `json:"dataType"`
+	FirstSample          *time.Time `json:"firstSample"`
+	LastSample           *time.Time `json:"lastSample"`
+}
+
+// observationAttributes are the attributes of an observations resource
+type observationAttributes struct {
+	RecordedAt time.Time `json:"recordedAt"`
+	Value      float64   `json:"value"`
+}
+
+// listUserThingsHandler returns a page of the things owned by a user
+func listUserThingsHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := &postgres.LocationFilter{
+		OwnerUID: pat.Param(r, "uid"),
+		SortBy:   "uid",
+		Limit:    defaultV2PageSize,
+	}
+
+	if status := query.Get("filter[status]"); status != "" {
+		if !postgres.IsValidStatus(status) {
+			return &parameterError{
+				param: "filter[status]",
+				err:   errors.New("must be one of: live, stale, dead"),
+			}
+		}
+		filter.Status = status
+	}
+
+	if dataSources := query.Get("filter[dataSource]"); dataSources != "" {
+		filter.DataSourceCodes = dataSourceNames(strings.Split(dataSources, ","))
+	}
+
+	if sortBy := query.Get("sort"); sortBy != "" {
+		filter.Descending = strings.HasPrefix(sortBy, "-")
+
+		column, ok := v2SortKeys[strings.TrimPrefix(sortBy, "-")]
+		if !ok {
+			return &parameterError{
+				param: "sort",
+				err:   errors.New("must be one of: uid, lastSample, createdAt, nickname, optionally prefixed with -"),
+			}
+		}
+		filter.SortBy = column
+	}
+
+	if size := query.Get("page[size]"); size != "" {
+		limit, err := strconv.ParseUint(size, 10, 64)
+		if err != nil || limit == 0 || limit > postgres.MaxLocationsLimit {
+			return &parameterError{
+				param: "page[size]",
+				err:   errors.Errorf("must be an integer between 1 and %d", postgres.MaxLocationsLimit),
+			}
+		}
+		filter.Limit = limit
+	}
+
+	if after := query.Get("page[after]"); after != "" {
+		cursor, err := decodeLocationCursor(after)
+		if err != nil || cursor.SortBy != filter.SortBy || cursor.Descending != filter.Descending {
+			return &parameterError{
+				param: "page[after]",
+				err:   errors.New("must be the cursor of a previous page with the same sort order"),
+			}
+		}
+
+		filter.After = &postgres.LocationCursor{
+			SortKey: cursor.SortKey,
+			UID:     cursor.UID,
+		}
+	}
+
+	// request one extra row so we know whether there is a next page
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	locations, err := env.db.ListLocations(ctx, filter)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to list things"),
+		}
+	}
+
+	l := &links{
+		Self: r.URL.RequestURI(),
+	}
+
+	if uint64(len(locations)) > limit {
+		locations = locations[:limit]
+		last := locations[len(locations)-1]
+
+		next, err := encodeLocationCursor(&locationCursor{
+			SortBy:     filter.SortBy,
+			Descending: filter.Descending,
+			SortKey:    last.SortKey,
+			UID:        last.UID,
+		})
+		if err != nil {
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to encode cursor"),
+			}
+		}
+
+		l.Next = pageLink(r.URL, next)
+	}
+
+	// the total count ignores the cursor so clients can show progress
+	filter.After = nil
+
+	count, err := env.db.CountLocations(ctx, filter)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to count things"),
+		}
+	}
+
+	things := []resource{}
+	for i := range locations {
+		things = append(things, buildThingResource(&locations[i]))
+	}
+
+	return writeDocument(w, &document{
+		Data: things,
+		Meta: map[string]interface{}{
+			"totalCount": count,
+		},
+		Links: l,
+	})
+}
+
+// getThingHandler returns a single thing, including its channels
+func getThingHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	uid := pat.Param(r, "uid")
+
+	loc, err := getLocation(env, r, uid)
+	if err != nil {
+		return err
+	}
+
+	channels, err := getChannels(env, r, uid)
+	if err != nil {
+		return err
+	}
+
+	thing := buildThingResource(loc)
+
+	identifiers := []resourceIdentifier{}
+	for _, c := range channels {
+		identifiers = append(identifiers, resourceIdentifier{Type: c.Type, ID: c.ID})
+	}
+	thing.Relationships["channels"] = relationship{Data: identifiers}
+
+	return writeDocument(w, &document{
+		Data:     thing,
+		Included: channels,
+		Links: &links{
+			Self: r.URL.RequestURI(),
+		},
+	})
+}
+
+// listObservationsHandler returns the observations recorded by a single
+// channel of a thing within a time window, read from Thingful
+func listObservationsHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	query := r.URL.Query()
+	uid := pat.Param(r, "uid")
+	channelID := pat.Param(r, "id")
+
+	end := time.Now().UTC()
+	if str := query.Get("filter[end]"); str != "" {
+		t, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return &parameterError{
+				param: "filter[end]",
+				err:   errors.New("must be an RFC3339 timestamp"),
+			}
+		}
+		end = t
+	}
+
+	start := end.Add(-defaultObservationWindow)
+	if str := query.Get("filter[start]"); str != "" {
+		t, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return &parameterError{
+				param: "filter[start]",
+				err:   errors.New("must be an RFC3339 timestamp"),
+			}
+		}
+		start = t
+	}
+
+	if start.After(end) {
+		return &parameterError{
+			param: "filter[start]",
+			err:   errors.New("must be before filter[end]"),
+		}
+	}
+
+	if end.Sub(start).Hours()/24 > maxTimeInterval {
+		return &parameterError{
+			param: "filter[start]",
+			err:   errors.Errorf("must be within %v days of filter[end]", maxTimeInterval),
+		}
+	}
+
+	ascending := true
+	switch query.Get("sort") {
+	case "", "recordedAt":
+	case "-recordedAt":
+		ascending = false
+	default:
+		return &parameterError{
+			param: "sort",
+			err:   errors.New("must be one of: recordedAt, -recordedAt"),
+		}
+	}
+
+	_, err := getLocation(env, r, uid)
+	if err != nil {
+		return err
+	}
+
+	channels, err := getChannels(env, r, uid)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, c := range channels {
+		if c.ID == channelID {
+			found = true
+		}
+	}
+
+	if !found {
+		return &HTTPError{
+			Code: http.StatusNotFound,
+			Err:  errors.New("channel not found"),
+		}
+	}
+
+	things, err := env.thingful.GetData(ctx, []string{uid}, start, end, ascending)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusBadGateway,
+			Err:  errors.Wrap(err, "failed to get data from Thingful"),
+		}
+	}
+
+	observations := []resource{}
+
+	for _, t := range things {
+		for _, c := range t.Attributes.Channels {
+			if path.Base(c.ID) != channelID {
+				continue
+			}
+
+			for _, o := range c.Observations {
+				val, err := strconv.ParseFloat(o.Value, 64)
+				if err != nil {
+					return &HTTPError{
+						Code: http.StatusInternalServerError,
+						Err:  errors.Wrap(err, "failed to parse value to a float"),
+					}
+				}
+
+				recordedAt := o.RecordedAt.UTC()
+
+				observations = append(observations, resource{
+					Type: "observations",
+					ID:   recordedAt.Format(time.RFC3339),
+					Attributes: observationAttributes{
+						RecordedAt: recordedAt,
+						Value:      val,
+					},
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(observations, func(i, j int) bool {
+		ti := observations[i].Attributes.(observationAttributes).RecordedAt
+		tj := observations[j].Attributes.(observationAttributes).RecordedAt
+		if ascending {
+			return ti.Before(tj)
+		}
+		return ti.After(tj)
+	})
+
+	return writeDocument(w, &document{
+		Data: observations,
+		Meta: map[string]interface{}{
+			"start": start.UTC(),
+			"end":   end.UTC(),
+		},
+		Links: &links{
+			Self: r.URL.RequestURI(),
+		},
+	})
+}
+
+// getLocation returns the thing with the given uid, or a 404 error if there is
+// no such thing
+func getLocation(env *Env, r *http.Request, uid string) (*postgres.Location, error) {
+	locations, err := env.db.ListLocations(r.Context(), &postgres.LocationFilter{
+		ThingUIDs: []string{uid},
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read thing"),
+		}
+	}
+
+	if len(locations) == 0 {
+		return nil, &HTTPError{
+			Code: http.StatusNotFound,
+			Err:  errors.New("thing not found"),
+		}
+	}
+
+	return &locations[0], nil
+}
+
+// getChannels returns channels resources for each of the channels of the thing
+func getChannels(env *Env, r *http.Request, uid string) ([]resource, error) {
+	ctx := r.Context()
+
+	metadata, err := env.db.GetMetadata(ctx, &postgres.MetadataFilter{
+		ThingUIDs: []string{uid},
+	})
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read channels"),
+		}
+	}
+
+	datasources, err := env.db.GetDataSources(ctx)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read data sources"),
+		}
+	}
+
+	byID := map[int64]postgres.DataSource{}
+	for _, ds := range datasources {
+		byID[ds.ID] = ds
+	}
+
+	channels := []resource{}
+
+	for _, m := range metadata {
+		ds, ok := byID[m.DataSourceID]
+		if !ok {
+			continue
+		}
+
+		channels = append(channels, resource{
+			Type: "channels",
+			ID:   ds.Code,
+			Attributes: channelAttributes{
+				DataSourceVariableID: ds.ID,
+				VariableCode:         fmt.Sprintf("%s.%s", nodeName, ds.Code),
+				Unit:                 ds.Unit.Ptr(),
+				DataType:             ds.DataType,
+				FirstSample:          m.FirstSampleUTC.Ptr(),
+				LastSample:           m.LastSampleUTC.Ptr(),
+			},
+		})
+	}
+
+	return channels, nil
+}
+
+// buildThingResource builds a things resource from a location read from
+// Postgres
+func buildThingResource(loc *postgres.Location) resource {
+	return resource{
+		Type: "things",
+		ID:   loc.UID,
+		Attributes: thingAttributes{
+			Nickname:           loc.Nickname,
+			SerialNumber:       loc.SerialNum,
+			LocationIdentifier: fmt.Sprintf("Grow.Thingful#%s", loc.UID),
+			Longitude:          loc.Longitude,
+			Latitude:           loc.Latitude,
+			FirstSample:        loc.FirstSampleUTC.Ptr(),
+			LastSample:         loc.LastSampleUTC.Ptr(),
+		},
+		Relationships: map[string]relationship{
+			"owner": {
+				Data: resourceIdentifier{Type: "users", ID: loc.UserUID},
+			},
+		},
+	}
+}
+
+// pageLink returns the given url with the page[after] parameter replaced
+func pageLink(u *url.URL, after string) string {
+	query := u.Query()
+	query.Set("page[after]", after)
+
+	next := *u
+	next.RawQuery = query.Encode()
+
+	return next.RequestURI()
+}
+
+// writeDocument writes the document to the response
+func writeDocument(w http.ResponseWriter, doc *document) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", jsonAPIContentType)
+	w.Write(b)
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/thingful/kudzu/pkg/client"
+	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+	"github.com/thingful/kudzu/pkg/thingful"
+	"github.com/thingful/simular"
+	goji "goji.io"
+)
+
+type V2HandlersSuite struct {
+	suite.Suite
+	db       *postgres.DB
+	logger   kitlog.Logger
+	client   *client.Client
+	thingful *thingful.Thingful
+	mux      *goji.Mux
+}
+
+func (s *V2HandlersSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+	s.client = client.NewClient(1, true)
+	s.thingful = thingful.NewClient(s.client, "http://thingful.net", "api-key", true, 2)
+
+	s.mux = goji.NewMux()
+	handlers.RegisterV2Handlers(s.mux, middleware.NewPermissions(""), s.db, s.thingful)
+
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "alice")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, nickname, long, lat, location_identifier, last_sample)
+		VALUES
+			('1234', $1, 'PA1', 'Tomatoes', 12.2, 13.3, 'LOC1', NOW()),
+			('1235', $1, 'PA2', 'Beans', 12.2, 13.3, 'LOC2', NOW() - interval '31 days'),
+			('1236', $1, 'PA3', 'Kale', 12.2, 13.3, 'LOC3', NOW() - interval '91 days')`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO data_sources (id, name, unit, data_type)
+		VALUES (1, 'air_temperature', 'Cel', 'xsd:double'), (2, 'soil_moisture', NULL, 'xsd:double')`,
+	)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO channels (thing_uid, data_source_id)
+		VALUES ('1234', 1), ('1234', 2), ('1235', 1), ('1236', 2)`,
+	)
+	assert.Nil(s.T(), err)
+}
+
+func (s *V2HandlersSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *V2HandlersSuite) get(path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	assert.Nil(s.T(), err)
+
+	recorder := httptest.NewRecorder()
+	s.mux.ServeHTTP(recorder, req.WithContext(ctx))
+
+	assert.Equal(s.T(), "application/vnd.api+json", recorder.Header().Get("Content-Type"))
+
+	var doc map[string]interface{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &doc)
+	assert.Nil(s.T(), err)
+
+	return recorder, doc
+}
+
+func (s *V2HandlersSuite) TestListUserThings() {
+	testcases := []struct {
+		label         string
+		path          string
+		expectedIDs   []interface{}
+		expectedCount float64
+	}{
+		{
+			label:         "all things",
+			path:          "/v2/users/alice/things",
+			expectedIDs:   []interface{}{"1234", "1235", "1236"},
+			expectedCount: 3,
+		},
+		{
+			label:         "by status",
+			path:          "/v2/users/alice/things?filter[status]=stale",
+			expectedIDs:   []interface{}{"1235"},
+			expectedCount: 1,
+		},
+		{
+			label:         "by data source",
+			path:          "/v2/users/alice/things?filter[dataSource]=air_temperature",
+			expectedIDs:   []interface{}{"1234", "1235"},
+			expectedCount: 2,
+		},
+		{
+			label:         "sorted",
+			path:          "/v2/users/alice/things?sort=-lastSample",
+			expectedIDs:   []interface{}{"1234", "1235", "1236"},
+			expectedCount: 3,
+		},
+		{
+			label:         "sorted by nickname",
+			path:          "/v2/users/alice/things?sort=nickname",
+			expectedIDs:   []interface{}{"1235", "1236", "1234"},
+			expectedCount: 3,
+		},
+		{
+			label:         "unknown user",
+			path:          "/v2/users/bob/things",
+			expectedIDs:   []interface{}{},
+			expectedCount: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder, doc := s.get(tc.path)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+
+			ids := []interface{}{}
+			for _, d := range doc["data"].([]interface{}) {
+				ids = append(ids, d.(map[string]interface{})["id"])
+			}
+
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedCount, doc["meta"].(map[string]interface{})["totalCount"])
+		})
+	}
+}
+
+func (s *V2HandlersSuite) TestListUserThingsPaged() {
+	ids := []interface{}{}
+	path := "/v2/users/alice/things?page[size]=2"
+
+	for path != "" {
+		recorder, doc := s.get(path)
+		assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+		for _, d := range doc["data"].([]interface{}) {
+			ids = append(ids, d.(map[string]interface{})["id"])
+		}
+
+		next, _ := doc["links"].(map[string]interface{})["next"].(string)
+		path = next
+	}
+
+	assert.Equal(s.T(), []interface{}{"1234", "1235", "1236"}, ids)
+}
+
+func (s *V2HandlersSuite) TestGetThing() {
+	recorder, doc := s.get("/v2/things/1234")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	data := doc["data"].(map[string]interface{})
+	assert.Equal(s.T(), "things", data["type"])
+	assert.Equal(s.T(), "1234", data["id"])
+
+	attributes := data["attributes"].(map[string]interface{})
+	assert.Equal(s.T(), "Tomatoes", attributes["nickname"])
+	assert.Equal(s.T(), "Grow.Thingful#1234", attributes["locationIdentifier"])
+
+	relationships := data["relationships"].(map[string]interface{})
+	owner := relationships["owner"].(map[string]interface{})["data"].(map[string]interface{})
+	assert.Equal(s.T(), "alice", owner["id"])
+
+	channels := relationships["channels"].(map[string]interface{})["data"].([]interface{})
+	assert.Len(s.T(), channels, 2)
+
+	included := doc["included"].([]interface{})
+	assert.Len(s.T(), included, 2)
+}
+
+func (s *V2HandlersSuite) TestListObservations() {
+	simular.ActivateNonDefault(s.client.Client)
+	defer simular.DeactivateAndReset()
+
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			http.MethodGet,
+			"http://thingful.net/things/1234?from=2019-03-26T00:00:00Z&to=2019-03-27T00:00:00Z",
+			simular.NewStringResponder(200, `{
+				"data": {
+					"id": "https://api.thingful.net/things/1234",
+					"attributes": {
+						"channels": [
+							{
+								"id": "https://api.thingful.net/things/1234/channels/air_temperature",
+								"observations": [
+									{"recordedAt": "2019-03-26T00:19:29Z", "value": "12.5"},
+									{"recordedAt": "2019-03-26T00:04:29Z", "value": "12.1"}
+								]
+							},
+							{
+								"id": "https://api.thingful.net/things/1234/channels/soil_moisture",
+								"observations": [
+									{"recordedAt": "2019-03-26T00:04:29Z", "value": "29.89"}
+								]
+							}
+						]
+					}
+				}
+			}`),
+			simular.WithHeader(
+				&http.Header{
+					"Authorization": []string{"Bearer api-key"},
+				},
+			),
+		),
+	)
+
+	recorder, doc := s.get("/v2/things/1234/channels/air_temperature/observations?filter[start]=2019-03-26T00:00:00Z&filter[end]=2019-03-27T00:00:00Z")
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	data := doc["data"].([]interface{})
+	assert.Len(s.T(), data, 2)
+
+	first := data[0].(map[string]interface{})
+	assert.Equal(s.T(), "observations", first["type"])
+	assert.Equal(s.T(), "2019-03-26T00:04:29Z", first["id"])
+	assert.Equal(s.T(), 12.1, first["attributes"].(map[string]interface{})["value"])
+
+	assert.Nil(s.T(), simular.AllStubsCalled())
+}
+
+func (s *V2HandlersSuite) TestErrors() {
+	testcases := []struct {
+		label             string
+		path              string
+		expectedStatus    int
+		expectedParameter interface{}
+	}{
+		{
+			label:             "invalid status",
+			path:              "/v2/users/alice/things?filter[status]=foo",
+			expectedStatus:    http.StatusBadRequest,
+			expectedParameter: "filter[status]",
+		},
+		{
+			label:             "invalid sort",
+			path:              "/v2/users/alice/things?sort=serial",
+			expectedStatus:    http.StatusBadRequest,
+			expectedParameter: "sort",
+		},
+		{
+			label:             "invalid page size",
+			path:              "/v2/users/alice/things?page[size]=0",
+			expectedStatus:    http.StatusBadRequest,
+			expectedParameter: "page[size]",
+		},
+		{
+			label:             "invalid cursor",
+			path:              "/v2/users/alice/things?page[after]=foo",
+			expectedStatus:    http.StatusBadRequest,
+			expectedParameter: "page[after]",
+		},
+		{
+			label:          "unknown thing",
+			path:           "/v2/things/9999",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			label:             "invalid start",
+			path:              "/v2/things/1234/channels/air_temperature/observations?filter[start]=yesterday",
+			expectedStatus:    http.StatusBadRequest,
+			expectedParameter: "filter[start]",
+		},
+		{
+			label:             "window too long",
+			path:              "/v2/things/1234/channels/air_temperature/observations?filter[start]=2019-01-01T00:00:00Z&filter[end]=2019-03-01T00:00:00Z",
+			expectedStatus:    http.StatusBadRequest,
+			expectedParameter: "filter[start]",
+		},
+		{
+			label:          "unknown channel",
+			path:           "/v2/things/1235/channels/soil_moisture/observations",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder, doc := s.get(tc.path)
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+
+			errs := doc["errors"].([]interface{})
+			assert.Len(t, errs, 1)
+
+			e := errs[0].(map[string]interface{})
+			assert.Equal(t, http.StatusText(tc.expectedStatus), e["title"])
+
+			if tc.expectedParameter != nil {
+				assert.Equal(t, tc.expectedParameter, e["source"].(map[string]interface{})["parameter"])
+			}
+		})
+	}
+}
+
+func TestV2HandlersSuite(t *testing.T) {
+	suite.Run(t, new(V2HandlersSuite))
+}
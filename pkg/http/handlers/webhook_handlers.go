@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/client"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// RegisterWebhookHandlers registers the endpoints apps use to manage their
+// webhooks and read the delivery log
+func RegisterWebhookHandlers(mux *goji.Mux, perms *middleware.Permissions, db *postgres.DB) {
+	mux.Handle(perms.Require(perms.Audit(pat.Post("/webhooks/new")), postgres.ManageWebhooksScope), Handler{env: &Env{db: db}, handler: createWebhookHandler})
+	mux.Handle(perms.Require(pat.Get("/webhooks"), postgres.ManageWebhooksScope), Handler{env: &Env{db: db}, handler: listWebhooksHandler})
+	mux.Handle(perms.Require(pat.Get("/webhooks/deliveries"), postgres.ManageWebhooksScope), Handler{env: &Env{db: db}, handler: listWebhookDeliveriesHandler})
+	mux.Handle(perms.Require(perms.Audit(pat.Delete("/webhooks/:uid")), postgres.ManageWebhooksScope), Handler{env: &Env{db: db}, handler: deleteWebhookHandler})
+}
+
+type webhookRequest struct {
+	Webhook struct {
+		URL    string   `json:"Url"`
+		Events []string `json:"Events"`
+	} `json:"Webhook"`
+}
+
+// webhookJSON is how we render a webhook. The secret is only returned when the
+// webhook is created.
+type webhookJSON struct {
+	UID       string    `json:"Uid"`
+	URL       string    `json:"Url"`
+	Events    []string  `json:"Events"`
+	Secret    string    `json:"Secret,omitempty"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+type webhookDeliveryJSON struct {
+	UID         string          `json:"Uid"`
+	WebhookUID  string          `json:"WebhookUid"`
+	Event       string          `json:"Event"`
+	Status      string          `json:"Status"`
+	Attempts    int             `json:"Attempts"`
+	StatusCode  null.Int        `json:"StatusCode"`
+	LastError   null.String     `json:"LastError"`
+	Payload     json.RawMessage `json:"Payload"`
+	CreatedAt   time.Time       `json:"CreatedAt"`
+	AttemptedAt null.Time       `json:"AttemptedAt"`
+	DeliveredAt null.Time       `json:"DeliveredAt"`
+	FailedAt    null.Time       `json:"FailedAt"`
+}
+
+func newWebhookJSON(webhook *postgres.Webhook) webhookJSON {
+	return webhookJSON{
+		UID:       webhook.UID,
+		URL:       webhook.URL,
+		Events:    []string(webhook.Events),
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func createWebhookHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	req, err := parseCreateWebhookRequest(r)
+	if err != nil {
+		return err
+	}
+
+	if !isValidWebhookURL(req.Webhook.URL) {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("webhook url must be an absolute http or https URL"),
+		}
+	}
+
+	if !isPublicWebhookURL(req.Webhook.URL) {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("webhook url must not point to a local or private address"),
+		}
+	}
+
+	if len(req.Webhook.Events) == 0 {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.New("webhook must subscribe to at least one event"),
+		}
+	}
+
+	webhook, err := env.db.CreateWebhook(ctx, middleware.SubjectFromContext(ctx), req.Webhook.URL, req.Webhook.Events)
+	if err != nil {
+		switch errors.Cause(err) {
+		case postgres.ClientError:
+			return &HTTPError{
+				Code: http.StatusUnprocessableEntity,
+				Err:  err,
+			}
+		default:
+			return &HTTPError{
+				Code: http.StatusInternalServerError,
+				Err:  errors.Wrap(err, "failed to save webhook to the database"),
+			}
+		}
+	}
+
+	middleware.AddAuditTarget(ctx, "webhook", webhook.UID)
+
+	log.Log(
+		"msg", "created webhook",
+		"uid", webhook.UID,
+	)
+
+	resp := newWebhookJSON(webhook)
+	resp.Secret = webhook.Secret
+
+	b, err := json.Marshal(struct {
+		Webhook webhookJSON `json:"Webhook"`
+	}{
+		Webhook: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(b)
+
+	return nil
+}
+
+func listWebhooksHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	webhooks, err := env.db.ListWebhooks(ctx, middleware.SubjectFromContext(ctx))
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to list webhooks"),
+		}
+	}
+
+	resp := []webhookJSON{}
+	for i := range webhooks {
+		resp = append(resp, newWebhookJSON(&webhooks[i]))
+	}
+
+	b, err := json.Marshal(struct {
+		Webhooks []webhookJSON `json:"Webhooks"`
+	}{
+		Webhooks: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+func deleteWebhookHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	uid := pat.Param(r, "uid")
+	middleware.AddAuditTarget(ctx, "webhook", uid)
+
+	err := env.db.DeleteWebhook(ctx, middleware.SubjectFromContext(ctx), uid)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return &HTTPError{
+				Code: http.StatusNotFound,
+				Err:  errors.New("webhook not found"),
+			}
+		}
+
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to delete webhook"),
+		}
+	}
+
+	log.Log(
+		"msg", "deleted webhook",
+		"uid", uid,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// listWebhookDeliveriesHandler returns the most recent deliveries to the app's
+// webhooks, optionally filtered by webhook, event and status
+func listWebhookDeliveriesHandler(env *Env, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+
+	filter := &postgres.WebhookDeliveryFilter{
+		WebhookUID: query.Get("webhook"),
+		Event:      query.Get("event"),
+		Status:     query.Get("status"),
+	}
+
+	if filter.Event != "" && !postgres.IsValidWebhookEvent(filter.Event) {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Errorf("unknown event: %s", filter.Event),
+		}
+	}
+
+	if filter.Status != "" && !postgres.IsValidDeliveryStatus(filter.Status) {
+		return &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Errorf("unknown status: %s, expected one of pending, delivered or failed", filter.Status),
+		}
+	}
+
+	deliveries, err := env.db.ListWebhookDeliveries(ctx, middleware.SubjectFromContext(ctx), filter)
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to list webhook deliveries"),
+		}
+	}
+
+	resp := []webhookDeliveryJSON{}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		resp = append(resp, webhookDeliveryJSON{
+			UID:         delivery.UID,
+			WebhookUID:  delivery.WebhookUID,
+			Event:       delivery.Event,
+			Status:      delivery.Status(),
+			Attempts:    delivery.Attempts,
+			StatusCode:  delivery.StatusCode,
+			LastError:   delivery.LastError,
+			Payload:     json.RawMessage(delivery.Payload),
+			CreatedAt:   delivery.CreatedAt,
+			AttemptedAt: delivery.AttemptedAt,
+			DeliveredAt: delivery.DeliveredAt,
+			FailedAt:    delivery.FailedAt,
+		})
+	}
+
+	b, err := json.Marshal(struct {
+		Deliveries []webhookDeliveryJSON `json:"Deliveries"`
+	}{
+		Deliveries: resp,
+	})
+	if err != nil {
+		return &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to marshal response JSON"),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+
+	return nil
+}
+
+func parseCreateWebhookRequest(r *http.Request) (*webhookRequest, error) {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusInternalServerError,
+			Err:  errors.Wrap(err, "failed to read incoming request body"),
+		}
+	}
+
+	var data webhookRequest
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return nil, &HTTPError{
+			Code: http.StatusUnprocessableEntity,
+			Err:  errors.Wrap(err, "failed to parse incoming request body"),
+		}
+	}
+
+	return &data, nil
+}
+
+// isValidWebhookURL returns true if the given string is an absolute http or
+// https URL
+func isValidWebhookURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isPublicWebhookURL returns false if the host of the given URL is a name for
+// the local machine, or an address that isn't publicly routable. Other names
+// are checked once resolved when each delivery is made, as the addresses they
+// resolve to may change.
+func isPublicWebhookURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return client.IsPublicIP(ip)
+	}
+
+	return true
+}
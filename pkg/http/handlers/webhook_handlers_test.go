@@ -0,0 +1,206 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/thingful/kudzu/pkg/http/handlers"
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+	goji "goji.io"
+)
+
+type WebhookHandlersSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+	app    *postgres.App
+	mux    *goji.Mux
+}
+
+func (s *WebhookHandlersSuite) SetupTest() {
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.logger = kitlog.NewNopLogger()
+	s.db = helper.PrepareDB(s.T(), connStr, s.logger)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "Client", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+	s.app = app
+
+	s.mux = goji.NewMux()
+	perms := middleware.NewPermissions("")
+	handlers.RegisterWebhookHandlers(s.mux, perms, s.db)
+
+	authMiddleware := middleware.NewAuthMiddleware(s.db)
+	s.mux.Use(authMiddleware.Handler)
+	s.mux.Use(perms.Handler)
+}
+
+func (s *WebhookHandlersSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+// request makes an authenticated request to the mux, returning the recorded
+// response
+func (s *WebhookHandlersSuite) request(method, path string, body []byte) *httptest.ResponseRecorder {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	assert.Nil(s.T(), err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.app.Key))
+
+	recorder := httptest.NewRecorder()
+	s.mux.ServeHTTP(recorder, req.WithContext(ctx))
+
+	return recorder
+}
+
+func (s *WebhookHandlersSuite) TestWebhookLifecycle() {
+	recorder := s.request(http.MethodPost, "/webhooks/new", []byte(`
+	{
+		"Webhook": {
+			"Url": "https://example.com/hook",
+			"Events": ["thing.created", "thing.went_stale"]
+		}
+	}`))
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+
+	var created struct {
+		Webhook struct {
+			UID    string   `json:"Uid"`
+			URL    string   `json:"Url"`
+			Events []string `json:"Events"`
+			Secret string   `json:"Secret"`
+		} `json:"Webhook"`
+	}
+
+	err := json.Unmarshal(recorder.Body.Bytes(), &created)
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), "", created.Webhook.UID)
+	assert.NotEqual(s.T(), "", created.Webhook.Secret)
+	assert.Equal(s.T(), []string{"thing.created", "thing.went_stale"}, created.Webhook.Events)
+
+	recorder = s.request(http.MethodGet, "/webhooks", nil)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), created.Webhook.UID)
+	assert.NotContains(s.T(), recorder.Body.String(), created.Webhook.Secret)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+	err = s.db.PublishWebhookEvent(ctx, postgres.ThingCreatedEvent, &postgres.ThingEventData{ThingUID: "abc123"})
+	assert.Nil(s.T(), err)
+
+	recorder = s.request(http.MethodGet, "/webhooks/deliveries?status=pending&event=thing.created", nil)
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	var deliveries struct {
+		Deliveries []struct {
+			WebhookUID string                 `json:"WebhookUid"`
+			Event      string                 `json:"Event"`
+			Status     string                 `json:"Status"`
+			Payload    map[string]interface{} `json:"Payload"`
+		} `json:"Deliveries"`
+	}
+
+	err = json.Unmarshal(recorder.Body.Bytes(), &deliveries)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), deliveries.Deliveries, 1)
+	assert.Equal(s.T(), created.Webhook.UID, deliveries.Deliveries[0].WebhookUID)
+	assert.Equal(s.T(), "pending", deliveries.Deliveries[0].Status)
+	assert.Equal(s.T(), "thing.created", deliveries.Deliveries[0].Payload["event"])
+
+	recorder = s.request(http.MethodDelete, "/webhooks/"+created.Webhook.UID, nil)
+	assert.Equal(s.T(), http.StatusNoContent, recorder.Code)
+
+	recorder = s.request(http.MethodDelete, "/webhooks/"+created.Webhook.UID, nil)
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *WebhookHandlersSuite) TestCreateWebhookInvalid() {
+	testcases := []struct {
+		label         string
+		input         string
+		expectedError string
+	}{
+		{
+			label:         "missing url",
+			input:         `{"Webhook":{"Events":["user.indexed"]}}`,
+			expectedError: "webhook url must be an absolute http or https URL",
+		},
+		{
+			label:         "invalid scheme",
+			input:         `{"Webhook":{"Url":"ftp://example.com","Events":["user.indexed"]}}`,
+			expectedError: "webhook url must be an absolute http or https URL",
+		},
+		{
+			label:         "missing events",
+			input:         `{"Webhook":{"Url":"https://example.com/hook"}}`,
+			expectedError: "webhook must subscribe to at least one event",
+		},
+		{
+			label:         "unknown event",
+			input:         `{"Webhook":{"Url":"https://example.com/hook","Events":["thing.exploded"]}}`,
+			expectedError: "unknown event: thing.exploded",
+		},
+		{
+			label:         "loopback address",
+			input:         `{"Webhook":{"Url":"http://127.0.0.1:8080/hook","Events":["user.indexed"]}}`,
+			expectedError: "webhook url must not point to a local or private address",
+		},
+		{
+			label:         "loopback ipv6 address",
+			input:         `{"Webhook":{"Url":"http://[::1]/hook","Events":["user.indexed"]}}`,
+			expectedError: "webhook url must not point to a local or private address",
+		},
+		{
+			label:         "localhost",
+			input:         `{"Webhook":{"Url":"http://localhost/hook","Events":["user.indexed"]}}`,
+			expectedError: "webhook url must not point to a local or private address",
+		},
+		{
+			label:         "private address",
+			input:         `{"Webhook":{"Url":"http://10.0.0.1/hook","Events":["user.indexed"]}}`,
+			expectedError: "webhook url must not point to a local or private address",
+		},
+		{
+			label:         "link-local address",
+			input:         `{"Webhook":{"Url":"http://169.254.169.254/latest/meta-data","Events":["user.indexed"]}}`,
+			expectedError: "webhook url must not point to a local or private address",
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			recorder := s.request(http.MethodPost, "/webhooks/new", []byte(tc.input))
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.expectedError)
+		})
+	}
+}
+
+func (s *WebhookHandlersSuite) TestListDeliveriesInvalid() {
+	recorder := s.request(http.MethodGet, "/webhooks/deliveries?status=lost", nil)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "unknown status: lost")
+
+	recorder = s.request(http.MethodGet, "/webhooks/deliveries?event=thing.exploded", nil)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "unknown event: thing.exploded")
+}
+
+func TestWebhookHandlersSuite(t *testing.T) {
+	suite.Run(t, new(WebhookHandlersSuite))
+}
@@ -8,6 +8,7 @@ import (
 
 	kitlog "github.com/go-kit/kit/log"
 	"github.com/jonboulle/clockwork"
+	"github.com/pkg/errors"
 	goji "goji.io"
 	"goji.io/pat"
 
@@ -15,10 +16,29 @@ import (
 	"github.com/thingful/kudzu/pkg/http/handlers"
 	"github.com/thingful/kudzu/pkg/http/middleware"
 	"github.com/thingful/kudzu/pkg/indexer"
+	"github.com/thingful/kudzu/pkg/openapi"
 	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/stream"
 	"github.com/thingful/kudzu/pkg/thingful"
 )
 
+const (
+	// apiPrefix is the path at which all API routes are mounted
+	apiPrefix = "/api"
+
+	// MemoryRateLimiter is the rate limiter backend that keeps its state in
+	// memory, so limits apply to each replica separately
+	MemoryRateLimiter = "memory"
+
+	// PostgresRateLimiter is the rate limiter backend that keeps its state in
+	// the database, so limits hold across every replica
+	PostgresRateLimiter = "postgres"
+
+	// appListenerPingInterval is how often we check the health of the
+	// connection on which we listen for changes to apps
+	appListenerPingInterval = 90 * time.Second
+)
+
 // HTTP is our struct that exposes an HTTP server for handling incoming
 // requests.
 type HTTP struct {
@@ -32,6 +52,7 @@ type Config struct {
 	Addr          string
 	DB            *postgres.DB
 	Indexer       *indexer.Indexer
+	Broker        *stream.Broker
 	Client        *client.Client
 	Thingful      *thingful.Thingful
 	QuitChan      <-chan struct{}
@@ -39,6 +60,22 @@ type Config struct {
 	WaitGroup     *sync.WaitGroup
 	ServerTimeout int
 	Verbose       bool
+
+	// ValidateRequests enables validation of incoming request bodies against
+	// our OpenAPI document
+	ValidateRequests bool
+
+	// RateLimiter is the rate limiter backend to use, one of memory or
+	// postgres, defaulting to memory
+	RateLimiter string
+
+	// AppCacheTTL is how long verified api keys are cached for, or zero to
+	// verify keys against the database on every request
+	AppCacheTTL time.Duration
+
+	// AuditRetention is how long records are kept in the audit log, or zero to
+	// keep them forever
+	AuditRetention time.Duration
 }
 
 // NewHTTP returns a new HTTP instance configured and ready to use, but not yet
@@ -69,15 +106,14 @@ func (h *HTTP) Start() {
 	handlers.RegisterHealthCheck(mux, h.DB)
 	handlers.RegisterMetricsHandler(mux)
 
+	// registered before the API mux so that it is served without auth
+	handlers.RegisterOpenAPIHandler(mux, apiPrefix+"/openapi.json")
+
 	apiMux := goji.SubMux()
-	mux.Handle(pat.New("/api/*"), apiMux)
+	mux.Handle(pat.New(apiPrefix+"/*"), apiMux)
 
-	handlers.RegisterUserHandlers(apiMux, h.DB, h.Client, h.Indexer)
-	handlers.RegisterDataSourceHandlers(apiMux, h.DB)
-	handlers.RegisterLocationHandlers(apiMux, h.DB, h.Thingful)
-	handlers.RegisterMetadataHandlers(apiMux, h.DB)
-	handlers.RegisterTimeseriesHandler(apiMux, h.DB, h.Thingful)
-	handlers.RegisterAppHandlers(apiMux, h.DB)
+	perms := middleware.NewPermissions(apiPrefix)
+	h.registerAPIHandlers(apiMux, perms)
 
 	// add middleware
 	apiMux.Use(middleware.RequestIDMiddleware)
@@ -87,12 +123,39 @@ func (h *HTTP) Start() {
 
 	apiMux.Use(middleware.MetricsMiddleware)
 
-	authMiddleware := middleware.NewAuthMiddleware(h.DB)
+	authMiddleware := middleware.NewAuthMiddleware(h.newAppLoader())
 	apiMux.Use(authMiddleware.Handler)
 
-	rateLimitMiddleware := middleware.NewRateLimiterMiddleware(clockwork.NewRealClock())
+	auditMiddleware := middleware.NewAuditMiddleware(h.DB, perms, h.AuditRetention, clockwork.NewRealClock(), h.logger)
+	defer auditMiddleware.Stop()
+
+	apiMux.Use(auditMiddleware.Handler)
+
+	apiMux.Use(perms.Handler)
+
+	limiter := h.newLimiter()
+	defer limiter.Stop()
+
+	rateLimitMiddleware := middleware.NewRateLimiterMiddleware(limiter)
 	apiMux.Use(rateLimitMiddleware.Handler)
 
+	if h.ValidateRequests {
+		doc, err := openapi.Load()
+		if err != nil {
+			h.ErrChan <- errors.Wrap(err, "failed to load OpenAPI document")
+			h.WaitGroup.Done()
+			return
+		}
+
+		validationMiddleware := middleware.NewValidationMiddleware(doc, apiPrefix)
+		apiMux.Use(validationMiddleware.Handler)
+	}
+
+	// installed last so that requests rejected by earlier middleware aren't
+	// counted against an app's quota
+	quotaMiddleware := middleware.NewQuotaMiddleware(h.DB, perms, clockwork.NewRealClock())
+	apiMux.Use(quotaMiddleware.Handler)
+
 	h.srv.Handler = mux
 
 	go func() {
@@ -111,3 +174,78 @@ func (h *HTTP) Start() {
 	h.srv.Shutdown(ctx)
 	h.WaitGroup.Done()
 }
+
+// newAppLoader returns the DB, wrapped in a cache of verified keys if enabled.
+// The cache is invalidated whenever an app is changed by any replica or via the
+// CLI.
+func (h *HTTP) newAppLoader() middleware.AppLoader {
+	if h.AppCacheTTL <= 0 {
+		return h.DB
+	}
+
+	cache := middleware.NewAppCache(h.DB, clockwork.NewRealClock(), h.AppCacheTTL)
+	go h.invalidateApps(cache)
+
+	return cache
+}
+
+// invalidateApps listens for changes to apps, removing them from the cache
+// until the quit channel is closed. If we can't listen cached apps still
+// expire after the TTL.
+func (h *HTTP) invalidateApps(cache *middleware.AppCache) {
+	listener, err := h.DB.Listen(postgres.AppChangesChannel)
+	if err != nil {
+		h.logger.Log("msg", "error listening for app changes, cached keys will only expire", "err", err)
+		return
+	}
+
+	ticker := time.NewTicker(appListenerPingInterval)
+
+	for {
+		select {
+		case n := <-listener.NotificationChannel():
+			if n == nil {
+				// the connection was re-established, so we may have missed
+				// notifications in the meantime
+				h.logger.Log("msg", "app listener reconnected")
+				cache.Purge()
+				continue
+			}
+
+			cache.Invalidate(n.Extra)
+		case <-ticker.C:
+			go listener.Ping()
+		case <-h.QuitChan:
+			ticker.Stop()
+			listener.Close()
+			return
+		}
+	}
+}
+
+// newLimiter returns the configured rate limiter backend
+func (h *HTTP) newLimiter() middleware.Limiter {
+	if h.RateLimiter == PostgresRateLimiter {
+		return middleware.NewPostgresLimiter(h.DB, clockwork.NewRealClock(), h.logger)
+	}
+
+	return middleware.NewMemoryLimiter(clockwork.NewRealClock())
+}
+
+// registerAPIHandlers registers every API route with the mux, declaring the
+// scopes each route requires with the given permissions as it does so
+func (h *HTTP) registerAPIHandlers(mux *goji.Mux, perms *middleware.Permissions) {
+	handlers.RegisterUserHandlers(mux, perms, h.DB, h.Client, h.Indexer, h.Thingful)
+	handlers.RegisterDataSourceHandlers(mux, perms, h.DB)
+	handlers.RegisterLocationHandlers(mux, perms, h.DB, h.Thingful)
+	handlers.RegisterMetadataHandlers(mux, perms, h.DB)
+	handlers.RegisterTimeseriesHandler(mux, perms, h.DB, h.Thingful)
+	handlers.RegisterAppHandlers(mux, perms, h.DB)
+	handlers.RegisterQuotaHandlers(mux, perms, h.DB)
+	handlers.RegisterV2Handlers(mux, perms, h.DB, h.Thingful)
+	handlers.RegisterGraphQLHandler(mux, perms, h.DB, h.Thingful)
+	handlers.RegisterStreamHandler(mux, perms, h.DB, h.Broker)
+	handlers.RegisterWebhookHandlers(mux, perms, h.DB)
+	handlers.RegisterAlertHandlers(mux, perms, h.DB)
+	handlers.RegisterAuditHandlers(mux, perms, h.DB)
+}
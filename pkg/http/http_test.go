@@ -0,0 +1,247 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/openapi"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// stubAppLoader returns an app holding the configured scopes for any key
+type stubAppLoader struct {
+	roles postgres.ScopeClaims
+}
+
+func (s *stubAppLoader) LoadApp(ctx context.Context, key string) (*postgres.App, error) {
+	return &postgres.App{UID: "uid", Roles: s.roles}, nil
+}
+
+type okHandler struct{}
+
+func (okHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+// allScopes is every scope an app may hold
+var allScopes = postgres.ScopeClaims{
+	postgres.CreateUserScope,
+	postgres.DeleteUserScope,
+	postgres.GetMetadataScope,
+	postgres.GetTimeSeriesDataScope,
+	postgres.UpdateLocationScope,
+	postgres.ExportUserScope,
+	postgres.ManageAppsScope,
+	postgres.ReadAuditLogScope,
+	postgres.ManageWebhooksScope,
+}
+
+func TestRoutePermissions(t *testing.T) {
+	testcases := []struct {
+		method string
+		path   string
+		scopes postgres.ScopeClaims
+	}{
+		{http.MethodPost, "/user/new", postgres.ScopeClaims{postgres.CreateUserScope}},
+		{http.MethodDelete, "/user/delete", postgres.ScopeClaims{postgres.DeleteUserScope}},
+		{http.MethodGet, "/user/:uid/status", postgres.ScopeClaims{postgres.CreateUserScope}},
+		{http.MethodPatch, "/user/credentials", postgres.ScopeClaims{postgres.CreateUserScope}},
+		{http.MethodGet, "/user/:uid/export", postgres.ScopeClaims{postgres.ExportUserScope}},
+		{http.MethodGet, "/user/:uid/deletion", postgres.ScopeClaims{postgres.DeleteUserScope}},
+		{http.MethodPost, "/entity/dataSourceVariables/get", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodPost, "/entity/locations/get", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodPatch, "/entity/locations/update", postgres.ScopeClaims{postgres.UpdateLocationScope}},
+		{http.MethodPatch, "/entity/locations/bulkupdate", postgres.ScopeClaims{postgres.UpdateLocationScope}},
+		{http.MethodPost, "/entity/locations/history", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodPost, "/entity/locations/hardware/history", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodPost, "/entity/timeSeriesInformations/get", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodPost, "/timeSeries/get", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}},
+		{http.MethodPost, "/apps/new", postgres.ScopeClaims{postgres.ManageAppsScope}},
+		{http.MethodGet, "/apps", postgres.ScopeClaims{postgres.ManageAppsScope}},
+		{http.MethodPatch, "/apps/:uid", postgres.ScopeClaims{postgres.ManageAppsScope}},
+		{http.MethodPost, "/apps/:uid/revoke", postgres.ScopeClaims{postgres.ManageAppsScope}},
+		{http.MethodPost, "/apps/:uid/rotate", postgres.ScopeClaims{postgres.ManageAppsScope}},
+		{http.MethodGet, "/apps/usage", nil},
+		{http.MethodGet, "/apps/:uid/usage", postgres.ScopeClaims{postgres.ManageAppsScope}},
+		{http.MethodPatch, "/apps/:uid/quotas", postgres.ScopeClaims{postgres.ManageAppsScope}},
+		{http.MethodGet, "/v2/users/:uid/things", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodGet, "/v2/things/:uid", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodGet, "/v2/things/:uid/channels/:id/observations", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}},
+		{http.MethodPost, "/graphql", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodGet, "/stream", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}},
+		{http.MethodPost, "/webhooks/new", postgres.ScopeClaims{postgres.ManageWebhooksScope}},
+		{http.MethodGet, "/webhooks", postgres.ScopeClaims{postgres.ManageWebhooksScope}},
+		{http.MethodGet, "/webhooks/deliveries", postgres.ScopeClaims{postgres.ManageWebhooksScope}},
+		{http.MethodDelete, "/webhooks/:uid", postgres.ScopeClaims{postgres.ManageWebhooksScope}},
+		{http.MethodPost, "/alerts/rules/new", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}},
+		{http.MethodGet, "/alerts/rules", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodDelete, "/alerts/rules/:uid", postgres.ScopeClaims{postgres.GetMetadataScope}},
+		{http.MethodGet, "/alerts", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}},
+		{http.MethodGet, "/audit", postgres.ScopeClaims{postgres.ReadAuditLogScope}},
+	}
+
+	h := NewHTTP(&Config{}, kitlog.NewNopLogger())
+	perms := middleware.NewPermissions(apiPrefix)
+	h.registerAPIHandlers(goji.SubMux(), perms)
+
+	// every registered route must appear in the table above
+	expected := map[string]postgres.ScopeClaims{}
+	for _, tc := range testcases {
+		expected[tc.method+" "+tc.path] = tc.scopes
+	}
+	assert.Equal(t, expected, perms.Routes())
+
+	// serve requests through muxes mounted as in Start, but with every real
+	// handler replaced by a no-op so that we only exercise the permissions
+	// middleware
+	newMux := func(roles postgres.ScopeClaims) *goji.Mux {
+		apiMux := goji.SubMux()
+		for _, tc := range testcases {
+			apiMux.Handle(pat.New(tc.path), okHandler{})
+		}
+		apiMux.Use(middleware.NewAuthMiddleware(&stubAppLoader{roles: roles}).Handler)
+		apiMux.Use(perms.Handler)
+
+		mux := goji.NewMux()
+		mux.Handle(pat.New(apiPrefix+"/*"), apiMux)
+		return mux
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			others := postgres.ScopeClaims{}
+			for _, scope := range allScopes {
+				if !tc.scopes.Permits(scope) {
+					others = append(others, scope)
+				}
+			}
+
+			path := strings.NewReplacer(":uid", "abc123", ":id", "air_temperature").Replace(tc.path)
+
+			req, err := http.NewRequest(tc.method, apiPrefix+path, nil)
+			assert.Nil(t, err)
+			req.Header.Set("Authorization", "Bearer key")
+
+			recorder := httptest.NewRecorder()
+			newMux(tc.scopes).ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+
+			// routes requiring no scopes are open to every app
+			if len(tc.scopes) == 0 {
+				return
+			}
+
+			recorder = httptest.NewRecorder()
+			newMux(others).ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusForbidden, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			assert.Contains(t, recorder.Body.String(), `"Name":403`)
+		})
+	}
+}
+
+func TestUndeclaredRoutesDenied(t *testing.T) {
+	perms := middleware.NewPermissions(apiPrefix)
+
+	// a handler registered without declaring its scopes must not be reachable
+	apiMux := goji.SubMux()
+	apiMux.Handle(perms.Require(pat.Get("/declared")), okHandler{})
+	apiMux.Handle(pat.Get("/undeclared"), okHandler{})
+	apiMux.Use(middleware.NewAuthMiddleware(&stubAppLoader{roles: allScopes}).Handler)
+	apiMux.Use(perms.Handler)
+
+	mux := goji.NewMux()
+	mux.Handle(pat.New(apiPrefix+"/*"), apiMux)
+
+	testcases := []struct {
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{http.MethodGet, "/declared", http.StatusOK},
+		{http.MethodGet, "/undeclared", http.StatusNotFound},
+		{http.MethodPost, "/declared", http.StatusNotFound},
+		{http.MethodGet, "/unknown", http.StatusNotFound},
+	}
+
+	for _, tc := range testcases {
+		req, err := http.NewRequest(tc.method, apiPrefix+tc.path, nil)
+		assert.Nil(t, err)
+		req.Header.Set("Authorization", "Bearer key")
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		assert.Equal(t, tc.expectedCode, recorder.Code, tc.method+" "+tc.path)
+	}
+}
+
+func TestAuditedRoutes(t *testing.T) {
+	audited := map[string]bool{
+		"POST /user/new":                     true,
+		"DELETE /user/delete":                true,
+		"PATCH /user/credentials":            true,
+		"PATCH /entity/locations/update":     true,
+		"PATCH /entity/locations/bulkupdate": true,
+		"POST /apps/new":                     true,
+		"PATCH /apps/:uid":                   true,
+		"POST /apps/:uid/revoke":             true,
+		"POST /apps/:uid/rotate":             true,
+		"PATCH /apps/:uid/quotas":            true,
+		"POST /webhooks/new":                 true,
+		"DELETE /webhooks/:uid":              true,
+		"POST /alerts/rules/new":             true,
+		"DELETE /alerts/rules/:uid":          true,
+	}
+
+	h := NewHTTP(&Config{}, kitlog.NewNopLogger())
+	perms := middleware.NewPermissions(apiPrefix)
+	h.registerAPIHandlers(goji.SubMux(), perms)
+
+	// only the routes above are recorded in the audit log, with the route
+	// pattern rather than the requested path
+	for route := range perms.Routes() {
+		parts := strings.SplitN(route, " ", 2)
+		path := strings.NewReplacer(":uid", "abc123", ":id", "air_temperature").Replace(parts[1])
+
+		req, err := http.NewRequest(parts[0], apiPrefix+path, nil)
+		assert.Nil(t, err)
+
+		pattern, ok := perms.Audited(req)
+		assert.Equal(t, audited[route], ok, route)
+		if ok {
+			assert.Equal(t, parts[1], pattern)
+		}
+	}
+}
+
+func TestOpenAPIDocumentCoversRoutes(t *testing.T) {
+	doc, err := openapi.Load()
+	assert.Nil(t, err)
+
+	h := NewHTTP(&Config{}, kitlog.NewNopLogger())
+	perms := middleware.NewPermissions(apiPrefix)
+	h.registerAPIHandlers(goji.SubMux(), perms)
+
+	routes := []string{}
+	for route := range perms.Routes() {
+		routes = append(routes, route)
+	}
+
+	// the document uses {param} for path parameters where goji uses :param
+	params := regexp.MustCompile(`\{([a-zA-Z]+)\}`)
+
+	documented := []string{}
+	for _, route := range doc.Routes() {
+		documented = append(documented, params.ReplaceAllString(route, ":$1"))
+	}
+
+	assert.ElementsMatch(t, routes, documented)
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	registry "github.com/thingful/retryable-registry-prometheus"
+
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+const (
+	// negativeCacheTTL is how long we remember that a key was rejected, so
+	// that repeated attempts with a bad key don't each cost a bcrypt comparison
+	negativeCacheTTL = 10 * time.Second
+
+	// maxCachedKeys is the most keys we hold at once, so that attempts with
+	// many distinct bad keys can't exhaust our memory
+	maxCachedKeys = 10000
+)
+
+var (
+	appCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "grow",
+			Name:      "app_cache_lookups",
+			Help:      "A counter of api key lookups in the app cache by result, one of hit, negative_hit or miss",
+		}, []string{"result"},
+	)
+
+	appCacheInvalidations = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "grow",
+			Name:      "app_cache_invalidations",
+			Help:      "A counter of apps invalidated in the app cache after their key, scopes or limits changed",
+		},
+	)
+
+	appCacheEntries = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "grow",
+			Name:      "app_cache_entries_gauge",
+			Help:      "The number of api keys currently held in the app cache",
+		},
+	)
+)
+
+func init() {
+	registry.MustRegister(appCacheLookups)
+	registry.MustRegister(appCacheInvalidations)
+	registry.MustRegister(appCacheEntries)
+}
+
+// AppCache is an AppLoader that remembers the outcome of loading the app for a
+// key, so that most requests avoid a database round trip and a bcrypt
+// comparison. Keys are held as a SHA-256 hash rather than in the clear. Valid
+// keys are remembered for the configured TTL, or until the key or the previous
+// key of the app expires if sooner, and keys that were rejected for a shorter
+// time. Callers should call Invalidate whenever the key, scopes or limits of an
+// app change.
+type AppCache struct {
+	loader AppLoader
+	clock  clockwork.Clock
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*appCacheEntry
+
+	// generation is incremented by every invalidation, so that loads which
+	// were in flight at the time don't store what may be a stale app
+	generation uint64
+}
+
+// appCacheEntry is the outcome of loading the app for a key, either the app or
+// the error it was rejected with
+type appCacheEntry struct {
+	app       *postgres.App
+	err       error
+	expiresAt time.Time
+}
+
+// NewAppCache returns a new AppCache wrapping the given loader, which holds
+// valid keys for the given TTL
+func NewAppCache(loader AppLoader, clock clockwork.Clock, ttl time.Duration) *AppCache {
+	return &AppCache{
+		loader:  loader,
+		clock:   clock,
+		ttl:     ttl,
+		entries: make(map[[sha256.Size]byte]*appCacheEntry),
+	}
+}
+
+// LoadApp is our implementation of the AppLoader interface. Errors other than
+// the key being rejected, e.g. the database being unavailable, aren't cached.
+func (c *AppCache) LoadApp(ctx context.Context, key string) (*postgres.App, error) {
+	hash := sha256.Sum256([]byte(key))
+	now := c.clock.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[hash]
+	generation := c.generation
+	c.mu.Unlock()
+
+	if ok && entry.expiresAt.After(now) {
+		if entry.err != nil {
+			appCacheLookups.With(prometheus.Labels{"result": "negative_hit"}).Inc()
+			return nil, entry.err
+		}
+
+		appCacheLookups.With(prometheus.Labels{"result": "hit"}).Inc()
+
+		// the app is shared by every request using the key, so we hand out
+		// copies in case a caller modifies it
+		app := *entry.app
+		return &app, nil
+	}
+
+	appCacheLookups.With(prometheus.Labels{"result": "miss"}).Inc()
+
+	app, err := c.loader.LoadApp(ctx, key)
+	if err != nil {
+		if isRejectedKey(err) {
+			c.store(hash, &appCacheEntry{err: err, expiresAt: now.Add(negativeCacheTTL)}, generation, now)
+		}
+		return nil, err
+	}
+
+	expiresAt := now.Add(c.ttl)
+	if app.ExpiresAt.Valid && app.ExpiresAt.Time.Before(expiresAt) {
+		expiresAt = app.ExpiresAt.Time
+	}
+
+	// we can't tell which of its keys matched, so if the previous key is still
+	// valid we hold either only until it expires
+	if app.PreviousKeyExpiresAt.Valid && app.PreviousKeyExpiresAt.Time.After(now) && app.PreviousKeyExpiresAt.Time.Before(expiresAt) {
+		expiresAt = app.PreviousKeyExpiresAt.Time
+	}
+
+	cached := *app
+	c.store(hash, &appCacheEntry{app: &cached, expiresAt: expiresAt}, generation, now)
+
+	return app, nil
+}
+
+// Invalidate removes every key of the app with the given UID from the cache,
+// along with every rejected key as we don't know which app those were for
+func (c *AppCache) Invalidate(uid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for hash, entry := range c.entries {
+		if entry.app == nil || entry.app.UID == uid {
+			delete(c.entries, hash)
+		}
+	}
+
+	c.generation++
+
+	appCacheInvalidations.Inc()
+	appCacheEntries.Set(float64(len(c.entries)))
+}
+
+// Purge removes every key from the cache, for when we may have missed changes
+// to apps
+func (c *AppCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[[sha256.Size]byte]*appCacheEntry)
+	c.generation++
+	appCacheEntries.Set(0)
+}
+
+// store adds an entry loaded during the given generation to the cache, unless
+// there has been an invalidation since. If the cache is full we first remove
+// expired entries, and if it is still full the entry isn't stored.
+func (c *AppCache) store(hash [sha256.Size]byte, entry *appCacheEntry, generation uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if len(c.entries) >= maxCachedKeys {
+		for h, e := range c.entries {
+			if !e.expiresAt.After(now) {
+				delete(c.entries, h)
+			}
+		}
+	}
+
+	if len(c.entries) < maxCachedKeys {
+		c.entries[hash] = entry
+	}
+
+	appCacheEntries.Set(float64(len(c.entries)))
+}
+
+// isRejectedKey returns true if the error from loading an app means the key
+// itself was rejected, rather than that we failed to check it
+func isRejectedKey(err error) bool {
+	switch errors.Cause(err) {
+	case sql.ErrNoRows, postgres.InvalidKeyError, postgres.RevokedKeyError, postgres.ExpiredKeyError:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,132 @@
+package middleware_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+func TestAppCache(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	app := &postgres.App{UID: "uid", Rate: 1}
+
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "uid-secret").Return(app, nil).Twice()
+
+	cache := middleware.NewAppCache(al, clock, 30*time.Second)
+
+	for i := 0; i < 3; i++ {
+		loaded, err := cache.LoadApp(context.Background(), "uid-secret")
+		assert.Nil(t, err)
+		assert.Equal(t, app, loaded)
+	}
+
+	// loaded again once the TTL passes
+	clock.Advance(30 * time.Second)
+
+	_, err := cache.LoadApp(context.Background(), "uid-secret")
+	assert.Nil(t, err)
+
+	al.AssertExpectations(t)
+}
+
+func TestAppCacheInvalidate(t *testing.T) {
+	app := &postgres.App{UID: "uid", Rate: 1}
+
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "uid-secret").Return(app, nil).Once()
+	al.On("LoadApp", mock.Anything, "uid-secret").Return((*postgres.App)(nil), postgres.RevokedKeyError).Once()
+
+	cache := middleware.NewAppCache(al, clockwork.NewFakeClock(), time.Minute)
+
+	_, err := cache.LoadApp(context.Background(), "uid-secret")
+	assert.Nil(t, err)
+
+	cache.Invalidate("other")
+
+	_, err = cache.LoadApp(context.Background(), "uid-secret")
+	assert.Nil(t, err)
+
+	cache.Invalidate("uid")
+
+	_, err = cache.LoadApp(context.Background(), "uid-secret")
+	assert.Equal(t, postgres.RevokedKeyError, err)
+
+	al.AssertExpectations(t)
+}
+
+func TestAppCacheExpiringKeys(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	expiring := &postgres.App{UID: "uid1", ExpiresAt: null.TimeFrom(clock.Now().Add(5 * time.Second))}
+	rotated := &postgres.App{UID: "uid2", PreviousKeyExpiresAt: null.TimeFrom(clock.Now().Add(10 * time.Second))}
+
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "uid1-secret").Return(expiring, nil).Once()
+	al.On("LoadApp", mock.Anything, "uid1-secret").Return((*postgres.App)(nil), postgres.ExpiredKeyError).Once()
+	al.On("LoadApp", mock.Anything, "uid2-secret").Return(rotated, nil).Twice()
+
+	cache := middleware.NewAppCache(al, clock, time.Minute)
+
+	_, err := cache.LoadApp(context.Background(), "uid1-secret")
+	assert.Nil(t, err)
+
+	_, err = cache.LoadApp(context.Background(), "uid2-secret")
+	assert.Nil(t, err)
+
+	// keys are only held until they, or the previous key of the app, expire
+	clock.Advance(5 * time.Second)
+
+	_, err = cache.LoadApp(context.Background(), "uid1-secret")
+	assert.Equal(t, postgres.ExpiredKeyError, err)
+
+	_, err = cache.LoadApp(context.Background(), "uid2-secret")
+	assert.Nil(t, err)
+
+	clock.Advance(5 * time.Second)
+
+	_, err = cache.LoadApp(context.Background(), "uid2-secret")
+	assert.Nil(t, err)
+
+	al.AssertExpectations(t)
+}
+
+func TestAppCacheRejectedKeys(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "uid-wrong").Return((*postgres.App)(nil), sql.ErrNoRows).Twice()
+	al.On("LoadApp", mock.Anything, "uid-secret").Return((*postgres.App)(nil), errors.New("database unavailable")).Twice()
+
+	cache := middleware.NewAppCache(al, clock, time.Minute)
+
+	// rejected keys are remembered for a short time
+	for i := 0; i < 3; i++ {
+		_, err := cache.LoadApp(context.Background(), "uid-wrong")
+		assert.Equal(t, sql.ErrNoRows, err)
+	}
+
+	clock.Advance(10 * time.Second)
+
+	_, err := cache.LoadApp(context.Background(), "uid-wrong")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	// but failures to check a key are not
+	for i := 0; i < 2; i++ {
+		_, err := cache.LoadApp(context.Background(), "uid-secret")
+		assert.NotNil(t, err)
+	}
+
+	al.AssertExpectations(t)
+}
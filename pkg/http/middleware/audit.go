@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/jonboulle/clockwork"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+const (
+	auditKey = contextKey("audit")
+
+	// auditCleanupInterval is how often we delete audit records older than
+	// the retention period
+	auditCleanupInterval = time.Hour
+)
+
+// AuditStore is the interface we expect for a type that can keep the audit log,
+// such as our postgres.DB
+type AuditStore interface {
+	RecordAudit(ctx context.Context, record *postgres.AuditRecord) error
+	DeleteAuditRecords(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AuditMiddleware is middleware that records every request made to a route
+// declared via Permissions.Audit in the audit log, along with the app that
+// made it, its request ID and the status of the response. Handlers identify
+// what a request acted on by calling AddAuditTarget. It must be installed after
+// the auth middleware so that the app making the request is in the context,
+// and before the permissions middleware so that forbidden attempts are also
+// recorded.
+type AuditMiddleware struct {
+	store AuditStore
+	perms *Permissions
+
+	ticker clockwork.Ticker
+	done   chan struct{}
+}
+
+// NewAuditMiddleware returns a new AuditMiddleware instance. If retention is
+// greater than zero records older than it are deleted every hour until Stop is
+// called, logging any errors to the given logger.
+func NewAuditMiddleware(store AuditStore, perms *Permissions, retention time.Duration, clock clockwork.Clock, log kitlog.Logger) *AuditMiddleware {
+	a := &AuditMiddleware{
+		store: store,
+		perms: perms,
+		done:  make(chan struct{}),
+	}
+
+	if retention > 0 {
+		a.ticker = clock.NewTicker(auditCleanupInterval)
+		go func() {
+			for {
+				select {
+				case <-a.ticker.Chan():
+					deleted, err := store.DeleteAuditRecords(logger.ToContext(context.Background(), log), clock.Now().Add(-retention))
+					if err != nil {
+						log.Log("msg", "failed to delete audit records", "err", err)
+						continue
+					}
+
+					if deleted > 0 {
+						log.Log("msg", "deleted audit records", "count", deleted)
+					}
+				case <-a.done:
+					return
+				}
+			}
+		}()
+	}
+
+	return a
+}
+
+// Stop stops the deletion of records older than the retention period
+func (a *AuditMiddleware) Stop() {
+	if a.ticker != nil {
+		a.ticker.Stop()
+	}
+	close(a.done)
+}
+
+// Handler is the middleware handler function. Requests are recorded once they
+// have been handled, and a failure to record one doesn't fail the request.
+func (a *AuditMiddleware) Handler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		route, ok := a.perms.Audited(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		record := &postgres.AuditRecord{
+			AppUID:    SubjectFromContext(ctx),
+			RequestID: RequestIDFromContext(ctx),
+			Method:    r.Method,
+			Route:     route,
+			Path:      r.URL.Path,
+		}
+
+		lrw := newLoggingResponseWriter(w)
+		next.ServeHTTP(lrw, r.WithContext(context.WithValue(ctx, auditKey, record)))
+
+		record.Status = lrw.statusCode
+
+		err := a.store.RecordAudit(ctx, record)
+		if err != nil {
+			logger.FromContext(ctx).Log(
+				"msg", "failed to record audit",
+				"err", err,
+			)
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// AddAuditTarget records that the current request acted on the thing of the
+// given kind with the given identifier, e.g. a user or a location. It does
+// nothing if the request isn't being audited.
+func AddAuditTarget(ctx context.Context, kind, id string) {
+	if record, ok := ctx.Value(auditKey).(*postgres.AuditRecord); ok {
+		record.Targets = append(record.Targets, kind+":"+id)
+	}
+}
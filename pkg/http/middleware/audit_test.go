@@ -0,0 +1,140 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+type mockAuditStore struct {
+	mock.Mock
+}
+
+func (m *mockAuditStore) RecordAudit(ctx context.Context, record *postgres.AuditRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *mockAuditStore) DeleteAuditRecords(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// targetHandler records the user in the request path as the target of the
+// request
+type targetHandler struct{}
+
+func (targetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	middleware.AddAuditTarget(r.Context(), "user", pat.Param(r, "uid"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "my-api-token").Return(&postgres.App{UID: "uid", Roles: postgres.ScopeClaims{postgres.DeleteUserScope}}, nil)
+
+	store := &mockAuditStore{}
+	store.On("RecordAudit", mock.Anything, &postgres.AuditRecord{
+		AppUID:    "uid",
+		RequestID: "abc123",
+		Method:    http.MethodDelete,
+		Route:     "/users/:uid",
+		Path:      "/users/5b8c1ad5",
+		Targets:   []string{"user:5b8c1ad5"},
+		Status:    http.StatusNoContent,
+	}).Return(nil).Once()
+	store.On("RecordAudit", mock.Anything, &postgres.AuditRecord{
+		AppUID:    "uid",
+		RequestID: "abc123",
+		Method:    http.MethodPost,
+		Route:     "/apps/new",
+		Path:      "/apps/new",
+		Status:    http.StatusForbidden,
+	}).Return(errors.New("database unavailable")).Once()
+
+	perms := middleware.NewPermissions("")
+
+	mux := goji.NewMux()
+	mux.Handle(perms.Require(perms.Audit(pat.Delete("/users/:uid")), postgres.DeleteUserScope), targetHandler{})
+	mux.Handle(perms.Require(perms.Audit(pat.Post("/apps/new")), postgres.CreateUserScope), testHandler{})
+	mux.Handle(perms.Require(pat.Get("/users/:uid"), postgres.DeleteUserScope), targetHandler{})
+	mux.Use(middleware.RequestIDMiddleware)
+	mux.Use(middleware.NewAuthMiddleware(al).Handler)
+	mux.Use(middleware.NewAuditMiddleware(store, perms, 0, clockwork.NewFakeClock(), kitlog.NewNopLogger()).Handler)
+	mux.Use(perms.Handler)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, nil)
+		assert.Nil(t, err)
+		req.Header.Add("Authorization", "Bearer my-api-token")
+		req.Header.Add("X-Correlation-ID", "abc123")
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/users/5b8c1ad5").Code)
+
+	// forbidden attempts are recorded, and failing to record them doesn't fail
+	// the request
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "/apps/new").Code)
+
+	// requests to routes that aren't audited aren't recorded
+	assert.Equal(t, http.StatusNoContent, do(http.MethodGet, "/users/5b8c1ad5").Code)
+
+	store.AssertExpectations(t)
+}
+
+func TestAuditMiddlewareRetention(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	deleted := make(chan time.Time)
+
+	store := &mockAuditStore{}
+	store.On("DeleteAuditRecords", mock.Anything, mock.Anything).Return(int64(2), nil).Run(func(args mock.Arguments) {
+		deleted <- args.Get(1).(time.Time)
+	})
+
+	middleware.NewAuditMiddleware(store, middleware.NewPermissions(""), 24*time.Hour, clock, kitlog.NewNopLogger())
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	assert.Equal(t, clock.Now().Add(-24*time.Hour), <-deleted)
+}
+
+func TestAuditMiddlewareStop(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	deleted := make(chan time.Time, 1)
+
+	store := &mockAuditStore{}
+	store.On("DeleteAuditRecords", mock.Anything, mock.Anything).Return(int64(2), nil).Run(func(args mock.Arguments) {
+		deleted <- args.Get(1).(time.Time)
+	})
+
+	audit := middleware.NewAuditMiddleware(store, middleware.NewPermissions(""), 24*time.Hour, clock, kitlog.NewNopLogger())
+
+	clock.BlockUntil(1)
+	audit.Stop()
+	clock.Advance(time.Hour)
+
+	select {
+	case <-deleted:
+		t.Fatal("audit records were deleted after the middleware was stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
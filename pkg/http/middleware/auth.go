@@ -16,6 +16,7 @@ const (
 	subjectKey = contextKey("subject")
 	rolesKey   = contextKey("roles")
 	rateKey    = contextKey("rate")
+	burstKey   = contextKey("burst")
 )
 
 // AppLoader is an interface we define here for a type that can load an App from
@@ -59,6 +60,7 @@ func (a *AuthMiddleware) Handler(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, subjectKey, app.UID)
 		ctx = context.WithValue(ctx, rolesKey, app.Roles)
 		ctx = context.WithValue(ctx, rateKey, app.Rate)
+		ctx = context.WithValue(ctx, burstKey, app.BurstSize())
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
@@ -91,3 +93,13 @@ func RolesFromContext(ctx context.Context) postgres.ScopeClaims {
 
 	return postgres.ScopeClaims{}
 }
+
+// SubjectFromContext returns the UID of the authenticated app from the context,
+// or an empty string if the request has not been authenticated.
+func SubjectFromContext(ctx context.Context) string {
+	if subject, ok := ctx.Value(subjectKey).(string); ok {
+		return subject
+	}
+
+	return ""
+}
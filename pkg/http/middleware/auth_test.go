@@ -125,3 +125,25 @@ func TestAuthMiddlewareUnknownToken(t *testing.T) {
 	assert.JSONEq(t, `{"Message": "Invalid API Key", "Name": 403}`, recorder.Body.String())
 
 }
+
+func TestAuthMiddlewareRevokedToken(t *testing.T) {
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "my-api-token").Return((*postgres.App)(nil), postgres.RevokedKeyError)
+
+	mux := goji.NewMux()
+	auth := middleware.NewAuthMiddleware(al)
+
+	mux.Handle(pat.Get("/"), testHandler{})
+	mux.Use(auth.Handler)
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", "my-api-token"))
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.JSONEq(t, `{"Message": "api key has been revoked", "Name": 403}`, recorder.Body.String())
+}
@@ -3,6 +3,8 @@ package middleware
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/thingful/kudzu/pkg/openapi"
 )
 
 type httpError struct {
@@ -43,6 +45,22 @@ func authForbiddenError(w http.ResponseWriter, err error) {
 	w.Write(b)
 }
 
+func notFoundError(w http.ResponseWriter, err error) {
+	httpErr := &httpError{
+		Name:    http.StatusNotFound,
+		Message: err.Error(),
+	}
+
+	b, err := json.Marshal(httpErr)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write(b)
+}
+
 func tooManyRequestsError(w http.ResponseWriter, err error) {
 	httpErr := &httpError{
 		Name:    http.StatusTooManyRequests,
@@ -58,3 +76,25 @@ func tooManyRequestsError(w http.ResponseWriter, err error) {
 	w.WriteHeader(http.StatusTooManyRequests)
 	w.Write(b)
 }
+
+func validationError(w http.ResponseWriter, fieldErrors []openapi.FieldError) {
+	httpErr := &struct {
+		httpError
+		Errors []openapi.FieldError `json:"Errors"`
+	}{
+		httpError: httpError{
+			Name:    http.StatusUnprocessableEntity,
+			Message: "request body does not match the API specification",
+		},
+		Errors: fieldErrors,
+	}
+
+	b, err := json.Marshal(httpErr)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	w.Write(b)
+}
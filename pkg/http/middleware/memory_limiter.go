@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// MemoryLimiter is a Limiter that keeps its state in memory, using the same
+// generic cell rate algorithm as the PostgresLimiter. Limits are only applied
+// per process, so with several replicas an app can make its rate of requests
+// to each of them.
+type MemoryLimiter struct {
+	clock clockwork.Clock
+
+	ticker clockwork.Ticker
+	done   chan struct{}
+
+	visitors map[string]*visitor
+	sync.Mutex
+}
+
+// visitor holds the theoretical arrival time of a key along with the limits it
+// was computed with
+type visitor struct {
+	tat   time.Time
+	rate  int
+	burst int
+}
+
+// NewMemoryLimiter returns a new MemoryLimiter. The state of keys that have
+// been idle for long enough to have a full burst again is removed every minute
+// until Stop is called.
+func NewMemoryLimiter(clock clockwork.Clock) *MemoryLimiter {
+	ml := &MemoryLimiter{
+		clock:    clock,
+		ticker:   clock.NewTicker(time.Minute),
+		done:     make(chan struct{}),
+		visitors: make(map[string]*visitor),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ml.ticker.Chan():
+				ml.cleanup()
+			case <-ml.done:
+				return
+			}
+		}
+	}()
+
+	return ml
+}
+
+// Stop is our implementation of the Limiter interface, stopping the removal of
+// idle keys
+func (ml *MemoryLimiter) Stop() {
+	ml.ticker.Stop()
+	close(ml.done)
+}
+
+// Allow is our implementation of the Limiter interface. If the limits of a key
+// have changed since its last request it starts again with a full burst.
+func (ml *MemoryLimiter) Allow(ctx context.Context, key string, rate, burst int) (*LimitResult, error) {
+	if rate <= 0 || burst <= 0 {
+		return &LimitResult{}, nil
+	}
+
+	now := ml.clock.Now()
+	emission := emissionInterval(rate)
+
+	ml.Lock()
+	defer ml.Unlock()
+
+	v, ok := ml.visitors[key]
+	if !ok || v.rate != rate || v.burst != burst {
+		v = &visitor{tat: now, rate: rate, burst: burst}
+		ml.visitors[key] = v
+	}
+
+	tat := v.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(emission)
+
+	if newTAT.Add(-emission * time.Duration(burst)).After(now) {
+		return newGCRAResult(false, now, tat, emission, burst), nil
+	}
+
+	v.tat = newTAT
+
+	return newGCRAResult(true, now, newTAT, emission, burst), nil
+}
+
+// cleanup removes the state of keys whose tat has passed, which is the same as
+// having no state
+func (ml *MemoryLimiter) cleanup() {
+	now := ml.clock.Now()
+
+	ml.Lock()
+	defer ml.Unlock()
+
+	for key, v := range ml.visitors {
+		if v.tat.Before(now) {
+			delete(ml.visitors, key)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+
+	limiter := middleware.NewMemoryLimiter(clock)
+
+	// a burst of 2 is allowed at once
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, "app1", 2, 2)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Limit)
+		assert.Equal(t, 1-i, result.Remaining)
+		assert.Equal(t, time.Duration(i+1)*500*time.Millisecond, result.Reset)
+	}
+
+	result, err := limiter.Allow(ctx, "app1", 2, 2)
+	assert.Nil(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.Reset)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	// other keys are limited separately
+	result, err = limiter.Allow(ctx, "app2", 2, 2)
+	assert.Nil(t, err)
+	assert.True(t, result.Allowed)
+
+	// denied requests don't count against the limit
+	clock.Advance(500 * time.Millisecond)
+
+	result, err = limiter.Allow(ctx, "app1", 2, 2)
+	assert.Nil(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = limiter.Allow(ctx, "app1", 2, 2)
+	assert.Nil(t, err)
+	assert.False(t, result.Allowed)
+}
+
+func TestMemoryLimiterChangedLimits(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+
+	limiter := middleware.NewMemoryLimiter(clock)
+
+	result, err := limiter.Allow(ctx, "app1", 1, 1)
+	assert.Nil(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = limiter.Allow(ctx, "app1", 1, 1)
+	assert.Nil(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// new limits start again with a full burst
+	result, err = limiter.Allow(ctx, "app1", 10, 5)
+	assert.Nil(t, err)
+	assert.True(t, result.Allowed)
+}
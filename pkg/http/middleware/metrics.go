@@ -2,9 +2,11 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	registry "github.com/thingful/retryable-registry-prometheus"
 )
 
@@ -26,7 +28,23 @@ func init() {
 // MetricsMiddleware returns a handler that records some basic prometheus
 // metrics on the wrapped Handler. Currently we just record the duration of
 // requests to get a measure of the latency of responses, partitioned by method
-// and status code.
+// and status code. We capture the status code with our own writer rather than
+// promhttp's, as that hides the underlying writer from handlers that stream
+// responses.
 func MetricsMiddleware(next http.Handler) http.Handler {
-	return promhttp.InstrumentHandlerDuration(duration, next)
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		begin := time.Now()
+		lrw := newLoggingResponseWriter(w)
+
+		next.ServeHTTP(lrw, r)
+
+		duration.With(
+			prometheus.Labels{
+				"code":   strconv.Itoa(lrw.statusCode),
+				"method": strings.ToLower(r.Method),
+			},
+		).Observe(time.Since(begin).Seconds())
+	}
+
+	return http.HandlerFunc(fn)
 }
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"goji.io/pat"
+	"goji.io/pattern"
+
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// route is a single route along with the scopes an app must hold to use it
+type route struct {
+	pattern *pat.Pattern
+	scopes  postgres.ScopeClaims
+}
+
+// Permissions is middleware that enforces the scopes required by each route.
+// Routes declare their scopes via Require as they are registered with the mux,
+// and the middleware then rejects any request from an app that does not hold
+// all of the scopes declared for the matched route. It must be installed after
+// the auth middleware so that the roles of the app are in the context. Routes
+// that change something also declare so via Audit, so that the audit
+// middleware can record requests to them.
+type Permissions struct {
+	prefix  string
+	routes  []route
+	audited map[*pat.Pattern]bool
+}
+
+// NewPermissions returns a new Permissions instance with no routes declared.
+// The prefix is the path at which the mux the routes are registered with is
+// mounted, e.g. "/api", as by the time middleware runs goji no longer exposes
+// the path relative to the mux.
+func NewPermissions(prefix string) *Permissions {
+	return &Permissions{
+		prefix:  prefix,
+		routes:  []route{},
+		audited: map[*pat.Pattern]bool{},
+	}
+}
+
+// Require records that requests matching the given pattern may only be made by
+// apps holding all of the given scopes. It returns the pattern unchanged so it
+// can be used inline when registering a handler with the mux.
+func (p *Permissions) Require(pt *pat.Pattern, scopes ...postgres.ScopeClaim) *pat.Pattern {
+	p.routes = append(p.routes, route{
+		pattern: pt,
+		scopes:  postgres.ScopeClaims(scopes),
+	})
+
+	return pt
+}
+
+// Audit records that requests matching the given pattern change something and
+// should be recorded in the audit log. It returns the pattern unchanged so it
+// can be used inline, within Require, when registering a handler with the mux.
+func (p *Permissions) Audit(pt *pat.Pattern) *pat.Pattern {
+	p.audited[pt] = true
+
+	return pt
+}
+
+// Scopes returns the scopes required for the given request, and a boolean
+// which is false if the request does not match any declared route.
+func (p *Permissions) Scopes(r *http.Request) (postgres.ScopeClaims, bool) {
+	rt := p.match(r)
+	if rt == nil {
+		return nil, false
+	}
+
+	return rt.scopes, true
+}
+
+// Audited returns the string form of the route pattern matching the given
+// request, e.g. "/apps/:uid/revoke", and a boolean which is false unless the
+// route was declared via Audit.
+func (p *Permissions) Audited(r *http.Request) (string, bool) {
+	rt := p.match(r)
+	if rt == nil || !p.audited[rt.pattern] {
+		return "", false
+	}
+
+	return rt.pattern.String(), true
+}
+
+// match returns the declared route matching the given request, or nil
+func (p *Permissions) match(r *http.Request) *route {
+	path := r.URL.EscapedPath()
+	if !strings.HasPrefix(path, p.prefix) {
+		return nil
+	}
+
+	r = r.WithContext(pattern.SetPath(r.Context(), path[len(p.prefix):]))
+
+	for i := range p.routes {
+		if p.routes[i].pattern.Match(r) != nil {
+			return &p.routes[i]
+		}
+	}
+
+	return nil
+}
+
+// Routes returns the method and string form of every declared route pattern,
+// e.g. "POST /user/new", along with the scopes required for each. The HEAD
+// route implied by each GET pattern is not listed.
+func (p *Permissions) Routes() map[string]postgres.ScopeClaims {
+	routes := make(map[string]postgres.ScopeClaims, len(p.routes))
+	for _, rt := range p.routes {
+		for method := range rt.pattern.HTTPMethods() {
+			if method == http.MethodHead {
+				continue
+			}
+			routes[method+" "+rt.pattern.String()] = rt.scopes
+		}
+	}
+
+	return routes
+}
+
+// Handler is the middleware handler function. Requests that do not match a
+// declared route are rejected with a 404, so that a handler registered without
+// declaring its scopes is never open to every app.
+func (p *Permissions) Handler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		scopes, ok := p.Scopes(r)
+		if !ok {
+			notFoundError(w, fmt.Errorf("no route matches %s %s", r.Method, r.URL.Path))
+			return
+		}
+
+		roles := RolesFromContext(r.Context())
+
+		missing := []string{}
+		for _, scope := range scopes {
+			if !roles.Permits(scope) {
+				missing = append(missing, string(scope))
+			}
+		}
+
+		if len(missing) > 0 {
+			authForbiddenError(w, fmt.Errorf("you are not permitted to access this resource, missing scopes: %s", strings.Join(missing, ", ")))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/jonboulle/clockwork"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// RateLimitStore is the interface we expect for a type that can apply a rate
+// limit shared by every replica, such as our postgres.DB
+type RateLimitStore interface {
+	ApplyRateLimit(ctx context.Context, key string, now time.Time, emission time.Duration, burst int) (*postgres.RateLimit, error)
+	DeleteRateLimits(ctx context.Context, before time.Time) error
+}
+
+// PostgresLimiter is a Limiter that keeps its state in the database using the
+// generic cell rate algorithm, so that limits hold across every replica.
+type PostgresLimiter struct {
+	store RateLimitStore
+	clock clockwork.Clock
+
+	ticker clockwork.Ticker
+	done   chan struct{}
+}
+
+// NewPostgresLimiter returns a new PostgresLimiter. The state of keys that have
+// been idle for long enough to have a full burst again is removed every minute
+// until Stop is called, logging any errors to the given logger.
+func NewPostgresLimiter(store RateLimitStore, clock clockwork.Clock, log kitlog.Logger) *PostgresLimiter {
+	pl := &PostgresLimiter{
+		store:  store,
+		clock:  clock,
+		ticker: clock.NewTicker(time.Minute),
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-pl.ticker.Chan():
+				err := store.DeleteRateLimits(logger.ToContext(context.Background(), log), clock.Now())
+				if err != nil {
+					log.Log("msg", "failed to delete rate limits", "err", err)
+				}
+			case <-pl.done:
+				return
+			}
+		}
+	}()
+
+	return pl
+}
+
+// Stop is our implementation of the Limiter interface, stopping the removal of
+// idle keys
+func (pl *PostgresLimiter) Stop() {
+	pl.ticker.Stop()
+	close(pl.done)
+}
+
+// Allow is our implementation of the Limiter interface
+func (pl *PostgresLimiter) Allow(ctx context.Context, key string, rate, burst int) (*LimitResult, error) {
+	if rate <= 0 || burst <= 0 {
+		return &LimitResult{}, nil
+	}
+
+	now := pl.clock.Now()
+	emission := emissionInterval(rate)
+
+	limit, err := pl.store.ApplyRateLimit(ctx, key, now, emission, burst)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGCRAResult(limit.Allowed, now, limit.TAT, emission, burst), nil
+}
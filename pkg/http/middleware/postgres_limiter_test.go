@@ -0,0 +1,104 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+type mockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *mockRateLimitStore) ApplyRateLimit(ctx context.Context, key string, now time.Time, emission time.Duration, burst int) (*postgres.RateLimit, error) {
+	args := m.Called(ctx, key, now, emission, burst)
+	return args.Get(0).(*postgres.RateLimit), args.Error(1)
+}
+
+func (m *mockRateLimitStore) DeleteRateLimits(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+func TestPostgresLimiter(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	now := clock.Now()
+
+	store := &mockRateLimitStore{}
+	store.On("ApplyRateLimit", ctx, "app1", now, 250*time.Millisecond, 4).Return(&postgres.RateLimit{Allowed: true, TAT: now.Add(250 * time.Millisecond)}, nil).Once()
+
+	// the key already used its burst, so its tat is 4 intervals away and the
+	// next request is allowed once it's 3 intervals away
+	store.On("ApplyRateLimit", ctx, "app1", now, 250*time.Millisecond, 4).Return(&postgres.RateLimit{TAT: now.Add(time.Second)}, nil).Once()
+
+	limiter := middleware.NewPostgresLimiter(store, clock, kitlog.NewNopLogger())
+
+	result, err := limiter.Allow(ctx, "app1", 4, 4)
+	assert.Nil(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 4, result.Limit)
+	assert.Equal(t, 3, result.Remaining)
+	assert.Equal(t, 250*time.Millisecond, result.Reset)
+
+	result, err = limiter.Allow(ctx, "app1", 4, 4)
+	assert.Nil(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 250*time.Millisecond, result.RetryAfter)
+
+	store.AssertExpectations(t)
+}
+
+func TestPostgresLimiterDeletesIdleKeys(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	deleted := make(chan time.Time, 1)
+
+	store := &mockRateLimitStore{}
+	store.On("DeleteRateLimits", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		deleted <- args.Get(1).(time.Time)
+	})
+
+	middleware.NewPostgresLimiter(store, clock, kitlog.NewNopLogger())
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	select {
+	case before := <-deleted:
+		assert.Equal(t, clock.Now(), before)
+	case <-time.After(time.Second):
+		t.Fatal("idle keys were not deleted")
+	}
+}
+
+func TestPostgresLimiterStop(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	deleted := make(chan time.Time, 1)
+
+	store := &mockRateLimitStore{}
+	store.On("DeleteRateLimits", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		deleted <- args.Get(1).(time.Time)
+	})
+
+	limiter := middleware.NewPostgresLimiter(store, clock, kitlog.NewNopLogger())
+
+	clock.BlockUntil(1)
+	limiter.Stop()
+	clock.Advance(time.Minute)
+
+	select {
+	case <-deleted:
+		t.Fatal("idle keys were deleted after the limiter was stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	registry "github.com/thingful/retryable-registry-prometheus"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+var (
+	overQuota = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "grow",
+			Name:      "over_quota_requests",
+			Help:      "A counter of requests rejected for exceeding an app's quota",
+		}, []string{"scope", "period"},
+	)
+)
+
+func init() {
+	registry.MustRegister(overQuota)
+}
+
+// RequestCounter is the interface we expect for a type that can count the
+// requests apps make against their quotas, such as our postgres.DB
+type RequestCounter interface {
+	CountRequest(ctx context.Context, uid string, scopes postgres.ScopeClaims, now time.Time) (*postgres.AppQuota, error)
+}
+
+// QuotaMiddleware is middleware that counts requests made by each app to routes
+// requiring a scope, and rejects requests once the app has used up its quota
+// for a scope for the day or month. Requests to routes requiring no scopes are
+// neither counted nor limited. It must be installed after the auth middleware
+// so that the app making the request is in the context.
+type QuotaMiddleware struct {
+	counter RequestCounter
+	perms   *Permissions
+	clock   clockwork.Clock
+}
+
+// NewQuotaMiddleware returns a new QuotaMiddleware instance, using the given
+// permissions to find the scopes required by each request
+func NewQuotaMiddleware(counter RequestCounter, perms *Permissions, clock clockwork.Clock) *QuotaMiddleware {
+	return &QuotaMiddleware{
+		counter: counter,
+		perms:   perms,
+		clock:   clock,
+	}
+}
+
+// Handler is the middleware handler function. As with rate limiting, if
+// counting fails the request is allowed.
+func (q *QuotaMiddleware) Handler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		scopes, ok := q.perms.Scopes(r)
+		if !ok || len(scopes) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		uid, err := uidFromContext(ctx)
+		if err != nil {
+			invalidTokenError(w, err)
+			return
+		}
+
+		now := q.clock.Now()
+
+		quota, err := q.counter.CountRequest(ctx, uid, scopes, now)
+		if err != nil {
+			logger.FromContext(ctx).Log(
+				"msg", "failed to count request against quota",
+				"err", err,
+			)
+		} else if quota != nil {
+			overQuota.With(
+				prometheus.Labels{
+					"scope":  string(quota.Scope),
+					"period": string(quota.Period),
+				},
+			).Inc()
+
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(quota.Period.End(now).Sub(now))))
+			tooManyRequestsError(w, fmt.Errorf("API quota exceeded, your current quota is no more than %d %s requests per %s", quota.Limit, quota.Scope, quota.Period))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
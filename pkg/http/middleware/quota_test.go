@@ -0,0 +1,101 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+type mockRequestCounter struct {
+	mock.Mock
+}
+
+func (m *mockRequestCounter) CountRequest(ctx context.Context, uid string, scopes postgres.ScopeClaims, now time.Time) (*postgres.AppQuota, error) {
+	args := m.Called(ctx, uid, scopes, now)
+	quota, _ := args.Get(0).(*postgres.AppQuota)
+	return quota, args.Error(1)
+}
+
+func TestQuotaMiddleware(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(2019, 6, 17, 23, 0, 0, 0, time.UTC))
+
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "my-api-token").Return(&postgres.App{UID: "uid", Roles: postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}}, nil)
+
+	counter := &mockRequestCounter{}
+	counter.On("CountRequest", mock.Anything, "uid", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, clock.Now()).Return(nil, nil).Once()
+	counter.On("CountRequest", mock.Anything, "uid", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, clock.Now()).Return(
+		&postgres.AppQuota{
+			Scope:  postgres.GetTimeSeriesDataScope,
+			Period: postgres.DailyQuota,
+			Limit:  10,
+		}, nil,
+	).Once()
+
+	perms := middleware.NewPermissions("")
+
+	mux := goji.NewMux()
+	mux.Handle(perms.Require(pat.Get("/timeseries"), postgres.GetTimeSeriesDataScope), testHandler{})
+	mux.Handle(perms.Require(pat.Get("/usage")), testHandler{})
+	mux.Use(middleware.NewAuthMiddleware(al).Handler)
+	mux.Use(middleware.NewQuotaMiddleware(counter, perms, clock).Handler)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		assert.Nil(t, err)
+		req.Header.Add("Authorization", "Bearer my-api-token")
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, get("/timeseries").Code)
+
+	recorder := get("/timeseries")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "3600", recorder.Header().Get("Retry-After"))
+	assert.Contains(t, recorder.Body.String(), "no more than 10 timeseries requests per day")
+
+	// routes requiring no scopes aren't counted
+	assert.Equal(t, http.StatusOK, get("/usage").Code)
+
+	counter.AssertExpectations(t)
+}
+
+func TestQuotaMiddlewareAllowsRequestsWhenCounterFails(t *testing.T) {
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "my-api-token").Return(&postgres.App{UID: "uid"}, nil)
+
+	counter := &mockRequestCounter{}
+	counter.On("CountRequest", mock.Anything, "uid", mock.Anything, mock.Anything).Return(nil, errors.New("database unavailable"))
+
+	perms := middleware.NewPermissions("")
+
+	mux := goji.NewMux()
+	mux.Handle(perms.Require(pat.Get("/"), postgres.GetMetadataScope), testHandler{})
+	mux.Use(middleware.NewAuthMiddleware(al).Handler)
+	mux.Use(middleware.NewQuotaMiddleware(counter, perms, clockwork.NewFakeClock()).Handler)
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req.Header.Add("Authorization", "Bearer my-api-token")
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
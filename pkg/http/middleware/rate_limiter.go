@@ -4,15 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	registry "github.com/thingful/retryable-registry-prometheus"
 
-	"github.com/jonboulle/clockwork"
-	"golang.org/x/time/rate"
+	"github.com/thingful/kudzu/pkg/logger"
 )
 
 const (
@@ -33,57 +33,88 @@ func init() {
 	registry.MustRegister(limited)
 }
 
-// visitor is a type used to hold visit status for a user of the website. We use
-// a struct for this as it allows us to keep track of the time of the last
-// visit, so allowing old rate limits to be cleaned up.
-type visitor struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
+// Limiter is the interface we expect for a backend that decides whether a
+// request is allowed under a rate limit of the given number of requests per
+// second, with the given burst. Limits are tracked separately for each key.
+// Stop releases any background work the backend started, and is called when
+// the server shuts down.
+type Limiter interface {
+	Allow(ctx context.Context, key string, rate, burst int) (*LimitResult, error)
+	Stop()
 }
 
-// expired is a helper function that checks whether a visitor entry should be
-// consided expired and so flagged for deletion from our state. Must be passed a
-// valid clockwork.Clock as well as an expiry duration.
-func (v *visitor) expired(clock clockwork.Clock, expiry time.Duration) bool {
-	if clock.Now().Sub(v.lastSeen) > expiry {
-		return true
+// LimitResult is the outcome of asking a Limiter whether a request is allowed
+type LimitResult struct {
+	Allowed bool
+
+	// Limit is the number of requests that may be made at once, i.e. the burst
+	Limit int
+
+	// Remaining is the number of requests that may be made at once after this
+	// one
+	Remaining int
+
+	// Reset is how long until the full limit is available again
+	Reset time.Duration
+
+	// RetryAfter is how long until a request would be allowed, zero for
+	// requests that were allowed
+	RetryAfter time.Duration
+}
+
+// newGCRAResult returns the result of a limiter implementing the generic cell
+// rate algorithm, where tat is the theoretical arrival time of the key after
+// the request, emission the interval between requests at the limited rate, and
+// a request is allowed if it doesn't push the tat more than burst intervals
+// past now.
+func newGCRAResult(allowed bool, now, tat time.Time, emission time.Duration, burst int) *LimitResult {
+	result := &LimitResult{
+		Allowed: allowed,
+		Limit:   burst,
+	}
+
+	untilTAT := tat.Sub(now)
+	if untilTAT > 0 {
+		result.Reset = untilTAT
+	}
+
+	remaining := int((emission*time.Duration(burst) - untilTAT) / emission)
+	if remaining > 0 {
+		result.Remaining = remaining
+	}
+
+	if !allowed {
+		// a request is allowed once the tat is no more than burst intervals
+		// away, and this request would have added one more
+		result.RetryAfter = untilTAT - emission*time.Duration(burst-1)
 	}
-	return false
+
+	return result
+}
+
+// emissionInterval returns the interval between requests at the given rate in
+// requests per second
+func emissionInterval(rate int) time.Duration {
+	return time.Second / time.Duration(rate)
 }
 
-// RateLimiterMiddleware is a middleware that implements simple rate limiting of
-// requests using the golang.org/x/time/rate package.
+// RateLimiterMiddleware is a middleware that limits the rate of requests made
+// by each app, using a pluggable Limiter to keep track of them.
 type RateLimiterMiddleware struct {
-	expiry   time.Duration
-	clock    clockwork.Clock
-	visitors map[string]*visitor
-	sync.RWMutex
+	limiter Limiter
 }
 
 // NewRateLimiterMiddleware returns a new middleware instance that has been
 // configured to start limiting requests to the API. We limit by the submitted
 // API key that is saved to the context.
-func NewRateLimiterMiddleware(clock clockwork.Clock) *RateLimiterMiddleware {
-	expiry := time.Duration(60) * time.Second
-
-	rm := &RateLimiterMiddleware{
-		expiry:   expiry,
-		clock:    clock,
-		visitors: make(map[string]*visitor),
+func NewRateLimiterMiddleware(limiter Limiter) *RateLimiterMiddleware {
+	return &RateLimiterMiddleware{
+		limiter: limiter,
 	}
-
-	// set up a ticker to remove stale entries every `expiry` seconds
-	ticker := clock.NewTicker(expiry)
-	go func() {
-		for range ticker.Chan() {
-			rm.cleanupVisitors()
-		}
-	}()
-
-	return rm
 }
 
-// Handler is the middleware handler function
+// Handler is the middleware handler function. If the limiter fails the request
+// is allowed, as we'd rather not limit than fail every request.
 func (rm *RateLimiterMiddleware) Handler(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -93,10 +124,19 @@ func (rm *RateLimiterMiddleware) Handler(next http.Handler) http.Handler {
 			return
 		}
 
-		rate := rateFromContext(ctx)
+		rate := RateFromContext(ctx)
 
-		limiter := rm.getVisitor(uid, rate)
-		if !limiter.Allow() {
+		result, err := rm.limiter.Allow(ctx, uid, rate, BurstFromContext(ctx))
+		if err != nil {
+			logger.FromContext(ctx).Log(
+				"msg", "failed to apply rate limit",
+				"err", err,
+			)
+		} else {
+			setRateLimitHeaders(w, result)
+		}
+
+		if result != nil && !result.Allowed {
 			limited.With(
 				prometheus.Labels{
 					"path": r.URL.Path,
@@ -113,53 +153,6 @@ func (rm *RateLimiterMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
-// getVisitor attempts to return a rate limiter for the given uid. If an entry
-// for the visitor is already present in the map we simply return it, else we
-// hand over to `addVisitor` to create a new one.
-func (rm *RateLimiterMiddleware) getVisitor(uid string, rate int) *rate.Limiter {
-	rm.RLock()
-	if v, ok := rm.visitors[uid]; ok {
-		v.lastSeen = rm.clock.Now()
-		rm.RUnlock()
-		return v.limiter
-	}
-	rm.RUnlock()
-
-	return rm.addVisitor(uid, rate)
-}
-
-// addVisitor is an unexported function that attempts to adds a new visitor into
-// our map, initializes its limiter and adds a timestamp at which the visitor
-// was received. We later use this timestamp to remove old entries from the map.
-func (rm *RateLimiterMiddleware) addVisitor(uid string, r int) *rate.Limiter {
-	// create new limiter
-	limiter := rate.NewLimiter(rate.Limit(r), r*2)
-
-	// add to our map
-	rm.Lock()
-	defer rm.Unlock()
-
-	rm.visitors[uid] = &visitor{
-		limiter:  limiter,
-		lastSeen: rm.clock.Now(),
-	}
-
-	return rm.visitors[uid].limiter
-}
-
-// cleanupVisitors must be called repeatedly in a goroutine, and is responsible
-// for removing stale entries from our map, i.e. visitors that haven't been seen
-// for the length of our `expiry` duration.
-func (rm *RateLimiterMiddleware) cleanupVisitors() {
-	rm.Lock()
-	defer rm.Unlock()
-	for uid, v := range rm.visitors {
-		if v.expired(rm.clock, rm.expiry) {
-			delete(rm.visitors, uid)
-		}
-	}
-}
-
 // uidFromContext returns the subject key (i.e. uid) from the context returning
 // an error if it isn't there
 func uidFromContext(ctx context.Context) (string, error) {
@@ -170,11 +163,40 @@ func uidFromContext(ctx context.Context) (string, error) {
 	return "", errors.New("Unable to find subject uid in request context")
 }
 
-// rateFromContext returns a rate value read from the context, or our default rate
-func rateFromContext(ctx context.Context) int {
+// RateFromContext returns the rate limit of the app making the request, in
+// requests per second, or our default rate
+func RateFromContext(ctx context.Context) int {
 	if rate, ok := ctx.Value(rateKey).(int); ok {
 		return rate
 	}
 
 	return defaultRate
 }
+
+// BurstFromContext returns the number of requests the app making the request
+// may make at once, or twice our default rate
+func BurstFromContext(ctx context.Context) int {
+	if burst, ok := ctx.Value(burstKey).(int); ok {
+		return burst
+	}
+
+	return defaultRate * 2
+}
+
+// setRateLimitHeaders sets the RateLimit headers of the IETF draft on rate
+// limit headers, along with Retry-After for requests that weren't allowed.
+// Durations are rounded up to whole seconds.
+func setRateLimitHeaders(w http.ResponseWriter, result *LimitResult) {
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if !result.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	}
+}
+
+// ceilSeconds returns the duration in seconds rounded up
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+func TestRateLimiterPicksUpChangedLimits(t *testing.T) {
+	app := &postgres.App{
+		UID:   "uid",
+		Name:  "name",
+		Rate:  1,
+		Burst: null.IntFrom(1),
+	}
+
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "my-api-token").Return(app, nil)
+
+	mux := goji.NewMux()
+	mux.Handle(pat.Get("/"), testHandler{})
+	mux.Use(middleware.NewAuthMiddleware(al).Handler)
+	mux.Use(middleware.NewRateLimiterMiddleware(middleware.NewMemoryLimiter(clockwork.NewFakeClock())).Handler)
+
+	get := func() int {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		assert.Nil(t, err)
+		req.Header.Add("Authorization", "Bearer my-api-token")
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get())
+	assert.Equal(t, http.StatusTooManyRequests, get())
+
+	// the app is loaded on every request, so raising its limits takes effect
+	// straight away, with a burst of twice the rate once reset
+	app.Rate = 2
+	app.Burst = null.Int{}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, http.StatusOK, get())
+	}
+	assert.Equal(t, http.StatusTooManyRequests, get())
+}
+
+func TestRateLimiterSetsHeaders(t *testing.T) {
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "my-api-token").Return(&postgres.App{UID: "uid", Rate: 2, Burst: null.IntFrom(2)}, nil)
+
+	mux := goji.NewMux()
+	mux.Handle(pat.Get("/"), testHandler{})
+	mux.Use(middleware.NewAuthMiddleware(al).Handler)
+	mux.Use(middleware.NewRateLimiterMiddleware(middleware.NewMemoryLimiter(clockwork.NewFakeClock())).Handler)
+
+	get := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		assert.Nil(t, err)
+		req.Header.Add("Authorization", "Bearer my-api-token")
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	recorder := get()
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "", recorder.Header().Get("Retry-After"))
+
+	get()
+
+	recorder = get()
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+}
+
+type failingLimiter struct{}
+
+func (f failingLimiter) Allow(ctx context.Context, key string, rate, burst int) (*middleware.LimitResult, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func (f failingLimiter) Stop() {}
+
+func TestRateLimiterAllowsRequestsWhenLimiterFails(t *testing.T) {
+	al := &mockAppLoader{}
+	al.On("LoadApp", mock.Anything, "my-api-token").Return(&postgres.App{UID: "uid", Rate: 1}, nil)
+
+	mux := goji.NewMux()
+	mux.Handle(pat.Get("/"), testHandler{})
+	mux.Use(middleware.NewAuthMiddleware(al).Handler)
+	mux.Use(middleware.NewRateLimiterMiddleware(failingLimiter{}).Handler)
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, err)
+	req.Header.Add("Authorization", "Bearer my-api-token")
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/openapi"
+)
+
+// ValidationMiddleware is middleware that validates the body of incoming
+// requests against our OpenAPI document, rejecting any request that does not
+// match with a 422 response listing every invalid field.
+type ValidationMiddleware struct {
+	doc    *openapi.Document
+	prefix string
+}
+
+// NewValidationMiddleware returns a new ValidationMiddleware instance. The
+// prefix is the path at which the API is mounted, which is stripped from
+// incoming requests before they are compared with the paths of the document.
+func NewValidationMiddleware(doc *openapi.Document, prefix string) *ValidationMiddleware {
+	return &ValidationMiddleware{
+		doc:    doc,
+		prefix: prefix,
+	}
+}
+
+// Handler is the middleware handler function. The body is replaced after being
+// read so that handlers can read it again as normal.
+func (v *ValidationMiddleware) Handler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.EscapedPath()
+		if !strings.HasPrefix(path, v.prefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body []byte
+
+		if r.Body != nil {
+			b, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, errors.Wrap(err, "failed to read incoming request body").Error(), http.StatusInternalServerError)
+				return
+			}
+			r.Body.Close()
+
+			body = b
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		fieldErrors := v.doc.ValidateRequest(r.Method, path[len(v.prefix):], body)
+		if len(fieldErrors) > 0 {
+			validationError(w, fieldErrors)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
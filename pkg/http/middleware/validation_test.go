@@ -0,0 +1,101 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	goji "goji.io"
+	"goji.io/pat"
+
+	"github.com/thingful/kudzu/pkg/http/middleware"
+	"github.com/thingful/kudzu/pkg/openapi"
+)
+
+func TestValidationMiddleware(t *testing.T) {
+	doc, err := openapi.Parse([]byte(`{
+		"paths": {
+			"/apps/new": {
+				"post": {
+					"requestBody": {
+						"required": true,
+						"content": {
+							"application/json": {
+								"schema": {
+									"type": "object",
+									"required": ["Name"],
+									"properties": {
+										"Name": {"type": "string", "minLength": 1}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	var received []byte
+
+	apiMux := goji.SubMux()
+	apiMux.HandleFunc(pat.Post("/apps/new"), func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+	})
+	apiMux.Use(middleware.NewValidationMiddleware(doc, "/api").Handler)
+
+	mux := goji.NewMux()
+	mux.Handle(pat.New("/api/*"), apiMux)
+
+	testcases := []struct {
+		label        string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			label:        "valid",
+			body:         `{"Name":"app"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: ``,
+		},
+		{
+			label:        "invalid",
+			body:         `{"Name":""}`,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"Name":422,"Message":"request body does not match the API specification","Errors":[{"Field":"Name","Message":"must be at least 1 characters long"}]}`,
+		},
+		{
+			label:        "missing",
+			body:         `{}`,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"Name":422,"Message":"request body does not match the API specification","Errors":[{"Field":"Name","Message":"is required"}]}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			received = nil
+
+			req, err := http.NewRequest(http.MethodPost, "/api/apps/new", bytes.NewReader([]byte(tc.body)))
+			assert.Nil(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				// the handler must still be able to read the body
+				assert.Equal(t, tc.body, string(received))
+			} else {
+				assert.JSONEq(t, tc.expectedBody, recorder.Body.String())
+				assert.Nil(t, received)
+			}
+		})
+	}
+}
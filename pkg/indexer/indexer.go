@@ -11,12 +11,15 @@ import (
 
 	kitlog "github.com/go-kit/kit/log"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
+	"github.com/thingful/kudzu/pkg/alerts"
 	"github.com/thingful/kudzu/pkg/client"
 	"github.com/thingful/kudzu/pkg/flowerpower"
 	"github.com/thingful/kudzu/pkg/logger"
 	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/stream"
 	"github.com/thingful/kudzu/pkg/thingful"
 )
 
@@ -41,6 +44,10 @@ type Config struct {
 	Delay     time.Duration
 	Verbose   bool
 	NoIndexer bool
+
+	// Notifier is sent notifications when alerts fire or are resolved, and
+	// defaults to sending them to the webhooks of the app that created the rule
+	Notifier alerts.Notifier
 }
 
 // Indexer is a struct that controls the scheduled work where we pull data from
@@ -48,15 +55,22 @@ type Config struct {
 type Indexer struct {
 	*Config
 	logger kitlog.Logger
+	alerts *alerts.Evaluator
 }
 
 // NewIndexer returns a new Indexer instance ready to start work.
 func NewIndexer(config *Config, logger kitlog.Logger) *Indexer {
 	logger = kitlog.With(logger, "module", "indexer")
 
+	notifier := config.Notifier
+	if notifier == nil {
+		notifier = alerts.NewWebhookNotifier(config.DB)
+	}
+
 	return &Indexer{
 		Config: config,
 		logger: logger,
+		alerts: alerts.NewEvaluator(config.DB, notifier),
 	}
 }
 
@@ -92,6 +106,12 @@ func (i *Indexer) Index() {
 	log := kitlog.With(i.logger, "uid", uid)
 	ctx := logger.ToContext(context.Background(), log)
 
+	// announce any things that have stopped reporting
+	_, err := i.DB.MarkStaleThings(ctx)
+	if err != nil {
+		log.Log("msg", "error marking stale things", "err", err)
+	}
+
 	// next identity to index
 	identity, err := i.DB.NextIdentity(ctx)
 	if err != nil {
@@ -111,6 +131,12 @@ func (i *Indexer) Index() {
 	err = i.indexLocations(ctx, identity)
 	if err != nil {
 		i.logger.Log("msg", "error indexing locations", "err", err)
+		return
+	}
+
+	err = i.DB.PublishWebhookEvent(ctx, postgres.UserIndexedEvent, &postgres.UserEventData{UserUID: identity.UserUID})
+	if err != nil {
+		log.Log("msg", "error publishing user indexed event", "err", err)
 	}
 }
 
@@ -130,6 +156,14 @@ func (i *Indexer) indexLocations(ctx context.Context, identity *postgres.Identit
 	locations, err := flowerpower.GetLocations(ctx, i.Client, identity.AccessToken)
 	if err != nil {
 		log.Log("msg", "failed to get locations for indexing", "ownerID", identity.OwnerID)
+
+		if errors.Cause(err) == client.UnauthorizedError {
+			revokeErr := i.DB.RevokeIdentity(ctx, identity.OwnerID)
+			if revokeErr != nil {
+				log.Log("msg", "failed to revoke identity", "ownerID", identity.OwnerID, "err", revokeErr)
+			}
+		}
+
 		return errors.Wrap(err, "failed to get locations for indexing")
 	}
 
@@ -156,7 +190,10 @@ func (i *Indexer) indexLocations(ctx context.Context, identity *postgres.Identit
 				err = i.indexNewLocation(ctx, identity, &l)
 				if err != nil {
 					log.Log("msg", "error indexing location", "locationID", l.LocationID, "err", err)
+					continue
 				}
+
+				i.savePlantStatuses(ctx, &l)
 				continue
 			}
 			return err
@@ -166,6 +203,8 @@ func (i *Indexer) indexLocations(ctx context.Context, identity *postgres.Identit
 		if err != nil {
 			log.Log("msg", "error indexing existing location", "locationID", l.LocationID, "err", err)
 		}
+
+		i.savePlantStatuses(ctx, &l)
 	}
 
 	return nil
@@ -177,7 +216,7 @@ func (i *Indexer) indexNewLocation(ctx context.Context, identity *postgres.Ident
 	now := time.Now()
 
 	thing := &postgres.Thing{
-		OwnerID:        identity.OwnerID,
+		OwnerID:        null.IntFrom(identity.OwnerID),
 		Provider:       null.StringFrom("https://api.thingful.net/providers/flowerpower"),
 		SerialNum:      l.SerialNum,
 		Longitude:      l.Longitude,
@@ -189,6 +228,7 @@ func (i *Indexer) indexNewLocation(ctx context.Context, identity *postgres.Ident
 		IndexedAt:      null.TimeFrom(now),
 		Nickname:       null.StringFrom(l.Nickname),
 		LocationID:     l.LocationID,
+		Hardware:       buildHardware(&l.Hardware),
 	}
 
 	fromUTC := thing.FirstSampleUTC.Time
@@ -220,6 +260,25 @@ func (i *Indexer) indexNewLocation(ctx context.Context, identity *postgres.Ident
 		return errors.Wrap(err, "failed to insert thing record into DB")
 	}
 
+	err = i.DB.PublishWebhookEvent(ctx, postgres.ThingCreatedEvent, &postgres.ThingEventData{
+		ThingUID:   thing.UID.String,
+		UserUID:    identity.UserUID,
+		Longitude:  thing.Longitude,
+		Latitude:   thing.Latitude,
+		LastSample: thing.LastSampleUTC,
+	})
+	if err != nil {
+		log.Log("msg", "failed to publish thing created event", "err", err)
+	}
+
+	err = i.updateChannelRanges(ctx, thing, readings)
+	if err != nil {
+		return err
+	}
+
+	i.publishReadings(ctx, thing, readings)
+	i.evaluateAlerts(ctx, thing, readings)
+
 	for {
 		// we sleep to avoid hammering Parrot too hard
 		time.Sleep(i.Delay)
@@ -259,6 +318,14 @@ func (i *Indexer) indexNewLocation(ctx context.Context, identity *postgres.Ident
 		if err != nil {
 			return errors.Wrap(err, "failed to update thing")
 		}
+
+		err = i.updateChannelRanges(ctx, thing, readings)
+		if err != nil {
+			return err
+		}
+
+		i.publishReadings(ctx, thing, readings)
+		i.evaluateAlerts(ctx, thing, readings)
 	}
 
 	return nil
@@ -279,6 +346,15 @@ func (i *Indexer) indexExistingLocation(ctx context.Context, identity *postgres.
 	thing.FirstSampleUTC = null.TimeFrom(location.FirstSampleUTC)
 	thing.LastSampleUTC = null.TimeFrom(location.LastSampleUTC)
 
+	// keep the hardware current, e.g. after a firmware update or the sensor
+	// being moved indoors
+	thing.Hardware = buildHardware(&location.Hardware)
+
+	err := i.DB.UpdateHardware(ctx, location.LocationID, &thing.Hardware)
+	if err != nil {
+		return errors.Wrap(err, "failed to update thing hardware")
+	}
+
 	for {
 		// we sleep to avoid hammering Parrot too hard
 		time.Sleep(i.Delay)
@@ -330,11 +406,112 @@ func (i *Indexer) indexExistingLocation(ctx context.Context, identity *postgres.
 		if err != nil {
 			return errors.Wrap(err, "failed to update thing")
 		}
+
+		err = i.updateChannelRanges(ctx, thing, readings)
+		if err != nil {
+			return err
+		}
+
+		i.publishReadings(ctx, thing, readings)
+		i.evaluateAlerts(ctx, thing, readings)
+	}
+
+	return nil
+}
+
+// updateChannelRanges records the first and last observation of each channel
+// present in the given readings, so that metadata can report accurate ranges
+// for channels that don't always have data
+func (i *Indexer) updateChannelRanges(ctx context.Context, thing *postgres.Thing, readings []flowerpower.Reading) error {
+	ranges := []postgres.ChannelRange{}
+
+	for name, r := range flowerpower.SampleRanges(readings) {
+		ranges = append(ranges, postgres.ChannelRange{
+			Name:           name,
+			FirstSampleUTC: r.First,
+			LastSampleUTC:  r.Last,
+		})
+	}
+
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	err := i.DB.UpdateChannelRanges(ctx, thing.UID.String, ranges)
+	if err != nil {
+		return errors.Wrap(err, "failed to update channel ranges")
 	}
 
 	return nil
 }
 
+// publishReadings pushes the newly uploaded readings to any clients streaming
+// events for the thing. Failing to do so is logged but doesn't stop indexing,
+// as the readings are already saved.
+func (i *Indexer) publishReadings(ctx context.Context, thing *postgres.Thing, readings []flowerpower.Reading) {
+	if len(readings) == 0 {
+		return
+	}
+
+	err := stream.PublishReadings(ctx, i.DB, thing.UID.String, readings)
+	if err != nil {
+		log := logger.FromContext(ctx)
+		log.Log("msg", "failed to publish stream event", "err", err)
+	}
+}
+
+// evaluateAlerts applies the alert rules for the thing to the newly uploaded
+// readings. As with publishing, failures are logged but don't stop indexing.
+func (i *Indexer) evaluateAlerts(ctx context.Context, thing *postgres.Thing, readings []flowerpower.Reading) {
+	err := i.alerts.Evaluate(ctx, thing, readings)
+	if err != nil {
+		log := logger.FromContext(ctx)
+		log.Log("msg", "failed to evaluate alert rules", "err", err)
+	}
+}
+
+// savePlantStatuses stores Parrot's latest assessment of the location's plant.
+// Locations Parrot hasn't assessed yet are skipped, and failures are logged
+// but don't stop indexing.
+func (i *Indexer) savePlantStatuses(ctx context.Context, l *flowerpower.Location) {
+	if l.StatusUTC.IsZero() {
+		return
+	}
+
+	statuses := []postgres.PlantStatus{}
+	for _, status := range l.PlantStatuses {
+		statuses = append(statuses, postgres.PlantStatus{
+			Variable:       status.Variable,
+			StatusKey:      status.StatusKey,
+			InstructionKey: status.InstructionKey,
+			MinThreshold:   status.MinThreshold,
+			MaxThreshold:   status.MaxThreshold,
+			CurrentValue:   status.CurrentValue,
+		})
+	}
+
+	err := i.DB.SavePlantStatuses(ctx, l.LocationID, l.StatusUTC, statuses)
+	if err != nil {
+		log := logger.FromContext(ctx)
+		log.Log("msg", "failed to save plant statuses", "locationID", l.LocationID, "err", err)
+	}
+}
+
+// buildHardware converts the hardware read from Parrot into the form we store,
+// with missing values stored as null
+func buildHardware(h *flowerpower.Hardware) postgres.Hardware {
+	return postgres.Hardware{
+		FirmwareVersion:  null.NewString(h.FirmwareVersion, h.FirmwareVersion != ""),
+		HardwareRevision: null.NewString(h.HardwareRevision, h.HardwareRevision != ""),
+		SensorType:       null.NewString(h.SensorType, h.SensorType != ""),
+		CalibrationData:  null.NewString(h.CalibrationData, h.CalibrationData != ""),
+		IsIndoor:         h.IsIndoor,
+		InPot:            h.InPot,
+		PlantIDs:         pq.Int64Array(h.PlantIDs),
+		AutowateringMode: null.NewString(h.AutowateringMode, h.AutowateringMode != ""),
+	}
+}
+
 // hasMoreReadingsToIndex simply checks the value of the last uploaded sample
 // and compares it to the last sample sent by parrot. If the last uploaded is
 // before the last value, then return true, else return false
@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// ThingRemover is the interface we expect for a type that can delete things
+// from Thingful, or push their anonymised state and history to it
+type ThingRemover interface {
+	DeleteThing(context.Context, string) error
+	UpdateThing(context.Context, *postgres.Thing, []flowerpower.Reading) error
+	RepublishObservations(context.Context, *postgres.Thing) error
+}
+
+// NewDeleteUserHandler returns a handler for jobs on the UserDeletionQueue. In
+// purge mode each thing is deleted from Thingful before its rows are removed.
+// In anonymise mode the things are anonymised in the database first, and their
+// new state and every observation they have published then sent to Thingful
+// again at the anonymised coordinates. Every step is safe to repeat, so a failed
+// job can simply be retried from the start.
+func NewDeleteUserHandler(db *postgres.DB, th ThingRemover) HandlerFunc {
+	return func(ctx context.Context, job *postgres.Job) error {
+		log := logger.FromContext(ctx)
+
+		var payload postgres.UserDeletionPayload
+
+		err := json.Unmarshal(job.Payload, &payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal user deletion payload")
+		}
+
+		deletion, err := db.GetUserDeletion(ctx, payload.DeletionID)
+		if err != nil {
+			return err
+		}
+
+		if deletion.CompletedAt.Valid {
+			return nil
+		}
+
+		err = db.CollectUserDeletionThings(ctx, deletion)
+		if err != nil {
+			return err
+		}
+
+		switch deletion.Mode {
+		case postgres.DeletionPurge:
+			err = purgeUser(ctx, db, th, deletion)
+		case postgres.DeletionAnonymise:
+			err = anonymiseUser(ctx, db, th, deletion)
+		default:
+			err = errors.Errorf("unknown deletion mode: %s", deletion.Mode)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		log.Log(
+			"msg", "deleted user",
+			"userUID", deletion.UserUID,
+			"mode", deletion.Mode,
+			"things", len(deletion.ThingUIDs),
+		)
+
+		return db.CompleteUserDeletion(ctx, deletion.ID)
+	}
+}
+
+// purgeUser deletes each of the user's things from Thingful, and then removes
+// everything we store about them
+func purgeUser(ctx context.Context, db *postgres.DB, th ThingRemover, deletion *postgres.UserDeletion) error {
+	for _, uid := range deletion.ThingUIDs {
+		err := th.DeleteThing(ctx, uid)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete thing %s from Thingful", uid)
+		}
+	}
+
+	return db.PurgeUser(ctx, deletion)
+}
+
+// anonymiseUser anonymises the user's things and removes the user, then sends
+// the anonymised things to Thingful. Observations already published carry the
+// exact coordinates of the thing, so each is republished at the anonymised
+// coordinates.
+func anonymiseUser(ctx context.Context, db *postgres.DB, th ThingRemover, deletion *postgres.UserDeletion) error {
+	err := db.AnonymiseUser(ctx, deletion)
+	if err != nil {
+		return err
+	}
+
+	for _, uid := range deletion.ThingUIDs {
+		thing, err := db.GetThingByUID(ctx, uid)
+		if err != nil {
+			if errors.Cause(err) == sql.ErrNoRows {
+				continue
+			}
+			return err
+		}
+
+		err = th.UpdateThing(ctx, thing, []flowerpower.Reading{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update thing %s on Thingful", uid)
+		}
+
+		err = th.RepublishObservations(ctx, thing)
+		if err != nil {
+			return errors.Wrapf(err, "failed to republish observations of thing %s to Thingful", uid)
+		}
+	}
+
+	return nil
+}
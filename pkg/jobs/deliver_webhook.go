@@ -0,0 +1,139 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/client"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/version"
+)
+
+const (
+	// EventHeader is the header containing the type of event delivered to a
+	// webhook
+	EventHeader = "X-Kudzu-Event"
+
+	// DeliveryHeader is the header containing the unique id of a delivery, which
+	// is the same for every attempt
+	DeliveryHeader = "X-Kudzu-Delivery"
+
+	// SignatureHeader is the header containing the timestamp and signature of a
+	// delivery
+	SignatureHeader = "X-Kudzu-Signature"
+)
+
+// SignWebhook returns the value of the signature header for a body posted to a
+// webhook at the given unix timestamp. The signature is the hex encoded
+// HMAC-SHA256 of the timestamp and body joined by a period, keyed with the
+// secret of the webhook.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// NewDeliverWebhookHandler returns a handler for jobs on the
+// WebhookDeliveryQueue. It posts the payload of the delivery to the webhook,
+// recording the outcome of each attempt in the delivery log. Any response
+// other than a 2xx is returned as an error so the job is retried.
+func NewDeliverWebhookHandler(db *postgres.DB, client *http.Client) HandlerFunc {
+	userAgent := fmt.Sprintf("grow(%s)/%s", version.BinaryName, version.Version)
+
+	return func(ctx context.Context, job *postgres.Job) error {
+		var payload postgres.WebhookDeliveryPayload
+
+		err := json.Unmarshal(job.Payload, &payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal webhook delivery payload")
+		}
+
+		delivery, webhook, err := db.GetWebhookDelivery(ctx, payload.DeliveryID)
+		if err != nil {
+			if errors.Cause(err) == sql.ErrNoRows {
+				// the webhook, or the user the event was about, has been deleted
+				// so there is nothing to deliver
+				return nil
+			}
+			return err
+		}
+
+		statusCode, deliveryErr := postWebhook(ctx, client, userAgent, webhook, delivery)
+
+		err = db.RecordWebhookAttempt(ctx, delivery.ID, statusCode, deliveryFailure(statusCode, deliveryErr), job.Attempts >= job.MaxAttempts)
+		if err != nil {
+			return err
+		}
+
+		return deliveryErr
+	}
+}
+
+// postWebhook makes a single attempt to post a delivery to a webhook,
+// returning the status code of the response if one was received
+func postWebhook(ctx context.Context, client *http.Client, userAgent string, webhook *postgres.Webhook, delivery *postgres.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create http request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.UID)
+	req.Header.Set(SignatureHeader, SignWebhook(webhook.Secret, time.Now().Unix(), delivery.Payload))
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to post to webhook")
+	}
+	defer resp.Body.Close()
+
+	// drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// deliveryFailure returns the error recorded in the delivery log for a failed
+// attempt. The log can be read by the app that owns the webhook, so errors
+// from the transport, which may describe hosts and addresses on our network,
+// are replaced with a summary of the failure.
+func deliveryFailure(statusCode int, err error) error {
+	if err == nil || statusCode != 0 {
+		return err
+	}
+
+	if client.IsNonPublicAddress(err) {
+		return errors.New("webhook url resolves to an address that is not publicly routable")
+	}
+
+	if netErr, ok := errors.Cause(err).(net.Error); ok && netErr.Timeout() {
+		return errors.New("request to webhook timed out")
+	}
+
+	return errors.New("failed to post to webhook")
+}
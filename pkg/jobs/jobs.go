@@ -0,0 +1,175 @@
+package jobs
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	registry "github.com/thingful/retryable-registry-prometheus"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+const (
+	// maxJobsPerTick is the maximum number of jobs we work through each time
+	// the worker wakes up, so that we notice the quit signal in good time
+	maxJobsPerTick = 100
+
+	// baseRetryDelay is the delay before the first retry of a failed job, this
+	// doubles for each subsequent attempt up to maxRetryDelay
+	baseRetryDelay = 30 * time.Second
+
+	// maxRetryDelay is the longest we will wait before retrying a failed job
+	maxRetryDelay = time.Hour
+)
+
+var (
+	jobsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "grow",
+			Name:      "jobs",
+			Help:      "A counter of background jobs run partitioned by queue and outcome",
+		}, []string{"queue", "outcome"},
+	)
+)
+
+func init() {
+	registry.MustRegister(jobsCounter)
+}
+
+// HandlerFunc is a function that performs the work for a single job. Returning
+// an error causes the job to be retried later.
+type HandlerFunc func(ctx context.Context, job *postgres.Job) error
+
+// Config is a state holder we pass in to the worker to configure it.
+type Config struct {
+	DB        *postgres.DB
+	QuitChan  <-chan struct{}
+	WaitGroup *sync.WaitGroup
+	Delay     time.Duration
+	Verbose   bool
+}
+
+// Worker is a struct that polls the jobs table for work and dispatches each job
+// to the handler registered for its queue.
+type Worker struct {
+	*Config
+	logger   kitlog.Logger
+	handlers map[string]HandlerFunc
+}
+
+// NewWorker returns a new Worker instance with no handlers registered.
+func NewWorker(config *Config, logger kitlog.Logger) *Worker {
+	logger = kitlog.With(logger, "module", "jobs")
+
+	return &Worker{
+		Config:   config,
+		logger:   logger,
+		handlers: map[string]HandlerFunc{},
+	}
+}
+
+// Register adds a handler for jobs on the named queue. Must be called before
+// the worker is started.
+func (w *Worker) Register(queue string, fn HandlerFunc) {
+	w.handlers[queue] = fn
+}
+
+// Start starts our worker running until the quit channel is closed
+func (w *Worker) Start() {
+	w.logger.Log("msg", "starting job worker")
+
+	ticker := time.NewTicker(w.Delay)
+
+	for {
+		select {
+		case <-ticker.C:
+			for i := 0; i < maxJobsPerTick; i++ {
+				if !w.Work() {
+					break
+				}
+			}
+		case <-w.QuitChan:
+			w.logger.Log("msg", "stopping job worker")
+			ticker.Stop()
+			w.WaitGroup.Done()
+			return
+		}
+	}
+}
+
+// Work attempts to run the next pending job, returning true if a job was
+// found. Failed jobs are rescheduled with an exponential backoff.
+func (w *Worker) Work() bool {
+	uid := uuid.New().String()
+	log := kitlog.With(w.logger, "uid", uid)
+	ctx := logger.ToContext(context.Background(), log)
+
+	job, err := w.DB.NextJob(ctx, w.queues())
+	if err != nil {
+		log.Log("msg", "error getting next job", "err", err)
+		return false
+	}
+
+	if job == nil {
+		return false
+	}
+
+	log = kitlog.With(log, "jobID", job.ID, "queue", job.Queue, "attempt", job.Attempts)
+	ctx = logger.ToContext(ctx, log)
+
+	if w.Verbose {
+		log.Log("msg", "running job")
+	}
+
+	err = w.handlers[job.Queue](ctx, job)
+	if err != nil {
+		log.Log("msg", "job failed", "err", err)
+		jobsCounter.With(prometheus.Labels{"queue": job.Queue, "outcome": "failed"}).Inc()
+
+		err = w.DB.FailJob(ctx, job, err, RetryDelay(job.Attempts))
+		if err != nil {
+			log.Log("msg", "error recording job failure", "err", err)
+		}
+
+		return true
+	}
+
+	jobsCounter.With(prometheus.Labels{"queue": job.Queue, "outcome": "completed"}).Inc()
+
+	err = w.DB.CompleteJob(ctx, job)
+	if err != nil {
+		log.Log("msg", "error completing job", "err", err)
+	}
+
+	return true
+}
+
+// queues returns the names of all queues we have handlers for
+func (w *Worker) queues() []string {
+	queues := []string{}
+	for queue := range w.handlers {
+		queues = append(queues, queue)
+	}
+	return queues
+}
+
+// RetryDelay returns how long to wait before retrying a job that has failed the
+// given number of times.
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := float64(baseRetryDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(maxRetryDelay) {
+		return maxRetryDelay
+	}
+
+	return time.Duration(delay)
+}
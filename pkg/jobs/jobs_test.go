@@ -0,0 +1,33 @@
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/jobs"
+)
+
+func TestRetryDelay(t *testing.T) {
+	testcases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, time.Hour},
+		{50, time.Hour},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, jobs.RetryDelay(tc.attempts))
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	signature := jobs.SignWebhook("secret", 1560000000, []byte(`{"event":"user.indexed"}`))
+	assert.Equal(t, "t=1560000000,v1=3e08e074488a215cc1a5f5f6125ac4716ef65363fe6658d7de4d60b2ed079e11", signature)
+}
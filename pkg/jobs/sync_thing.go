@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// ThingUpdater is the interface we expect for a type that can push the current
+// state of a thing to Thingful
+type ThingUpdater interface {
+	UpdateThing(context.Context, *postgres.Thing, []flowerpower.Reading) error
+}
+
+// NewSyncThingHandler returns a handler for jobs on the SyncThingQueue. It
+// loads the current state of the thing from the database and sends it to
+// Thingful, so a retried job always sends the latest coordinates.
+func NewSyncThingHandler(db *postgres.DB, th ThingUpdater) HandlerFunc {
+	return func(ctx context.Context, job *postgres.Job) error {
+		var payload postgres.SyncThingPayload
+
+		err := json.Unmarshal(job.Payload, &payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal sync thing payload")
+		}
+
+		thing, err := db.GetThingByUID(ctx, payload.ThingUID)
+		if err != nil {
+			if errors.Cause(err) == sql.ErrNoRows {
+				// the thing has been deleted so there is nothing to sync
+				return nil
+			}
+			return err
+		}
+
+		return th.UpdateThing(ctx, thing, []flowerpower.Reading{})
+	}
+}
@@ -26,6 +26,40 @@
 // sql/20190311125125_add_location_audit_table.up.sql (1.004kB)
 // sql/20190528213142_add_app_rate.down.sql (42B)
 // sql/20190528213142_add_app_rate.up.sql (68B)
+// sql/20190603101512_add_spatial_index_to_things.down.sql (85B)
+// sql/20190603101512_add_spatial_index_to_things.up.sql (555B)
+// sql/20190604093021_add_sample_range_to_channels.down.sql (142B)
+// sql/20190604093021_add_sample_range_to_channels.up.sql (570B)
+// sql/20190605141207_add_app_uid_to_location_changes.down.sql (642B)
+// sql/20190605141207_add_app_uid_to_location_changes.up.sql (946B)
+// sql/20190606112233_add_jobs_table.down.sql (27B)
+// sql/20190606112233_add_jobs_table.up.sql (663B)
+// sql/20190607103045_add_admin_scopes_to_applications.down.sql (103B)
+// sql/20190607103045_add_admin_scopes_to_applications.up.sql (287B)
+// sql/20190608093000_add_stream_events_table.down.sql (36B)
+// sql/20190608093000_add_stream_events_table.up.sql (377B)
+// sql/20190609091500_add_webhooks.down.sql (180B)
+// sql/20190609091500_add_webhooks.up.sql (1.397kB)
+// sql/20190610090000_add_alerts.down.sql (63B)
+// sql/20190610090000_add_alerts.up.sql (1.622kB)
+// sql/20190611090000_add_plant_statuses.down.sql (37B)
+// sql/20190611090000_add_plant_statuses.up.sql (476B)
+// sql/20190612090000_add_thing_hardware.down.sql (522B)
+// sql/20190612090000_add_thing_hardware.up.sql (2.505kB)
+// sql/20190613090000_add_user_deletions.down.sql (144B)
+// sql/20190613090000_add_user_deletions.up.sql (595B)
+// sql/20190614090000_add_app_key_lifecycle.down.sql (296B)
+// sql/20190614090000_add_app_key_lifecycle.up.sql (423B)
+// sql/20190615090000_add_app_burst.down.sql (109B)
+// sql/20190615090000_add_app_burst.up.sql (130B)
+// sql/20190616090000_add_rate_limits.down.sql (125B)
+// sql/20190616090000_add_rate_limits.up.sql (1.287kB)
+// sql/20190617090000_add_app_quotas.down.sql (65B)
+// sql/20190617090000_add_app_quotas.up.sql (577B)
+// sql/20190618090000_add_audit_log.down.sql (207B)
+// sql/20190618090000_add_audit_log.up.sql (1.095kB)
+// sql/20190619090000_add_webhooks_scope.down.sql (65B)
+// sql/20190619090000_add_webhooks_scope.up.sql (139B)
 
 package migrations
 
@@ -614,6 +648,686 @@ func _20190528213142_add_app_rateUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __20190603101512_add_spatial_index_to_thingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x55\x00\xaa\xff\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x74\x68\x69\x6e\x67\x73\x5f\x67\x65\x6f\x67\x72\x61\x70\x68\x79\x5f\x69\x64\x78\x3b\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x74\x68\x69\x6e\x67\x73\x5f\x6c\x61\x74\x5f\x6c\x6f\x6e\x67\x5f\x69\x64\x78\x3b\x0a\x03\x00\x44\xfe\x3c\x00\x55\x00\x00\x00")
+
+func _20190603101512_add_spatial_index_to_thingsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190603101512_add_spatial_index_to_thingsDownSql,
+		"20190603101512_add_spatial_index_to_things.down.sql",
+	)
+}
+
+func _20190603101512_add_spatial_index_to_thingsDownSql() (*asset, error) {
+	bytes, err := _20190603101512_add_spatial_index_to_thingsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190603101512_add_spatial_index_to_things.down.sql", size: 85, mode: os.FileMode(0644), modTime: time.Unix(1792361412, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb0, 0x32, 0xe8, 0xc3, 0x77, 0x87, 0x27, 0xcf, 0xff, 0xe9, 0xd2, 0xd, 0xf3, 0x95, 0x6b, 0xfa, 0xe7, 0x23, 0xb9, 0x1b, 0xce, 0x87, 0x1f, 0x78, 0x91, 0xfe, 0x8e, 0xd2, 0xbf, 0x7a, 0x85, 0xc0}}
+	return a, nil
+}
+
+var __20190603101512_add_spatial_index_to_thingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\x4d\x6b\xdb\x40\x10\x86\xef\xfa\x15\xef\x21\x60\x0b\x9c\x40\x3f\xe8\x21\xa2\x07\xd7\x5a\x2b\x82\x44\x0e\x96\x4c\x7d\x13\xe3\xec\x20\x2d\x5d\xef\x2a\xda\x11\x96\xfe\x7d\x91\x1b\x0c\x3d\xe5\xb6\x3b\xc3\xfb\x0c\xf3\xcc\x66\xaf\xd6\x95\x42\x5e\xa4\xea\x88\x7c\x8b\x62\x57\x41\x1d\xf3\xb2\x2a\x21\xad\x71\x4d\xa8\x2d\x49\x6d\xbd\x6b\x6a\xa3\x47\xec\x8a\x8f\x32\x96\x96\x64\x85\xb9\x11\x27\x51\x74\x7f\x8f\x4b\xcb\x0e\xaf\x3e\x48\x96\x97\x30\x01\xc6\x05\x21\x6b\x59\xe3\xc2\x20\x1b\x3c\x48\x6b\x10\x1a\xf6\x4d\x4f\x5d\x3b\xc1\x38\xcd\x23\x86\xc0\x1a\xa7\x09\xd2\x32\x7a\xd2\x66\x08\x33\x8d\x9c\x46\xe7\xed\xd4\x78\x87\xf7\x81\x7b\xc3\xe1\x01\x55\xcb\x08\x42\xc2\x67\x76\x32\xcf\xe0\x91\xdf\x06\x61\x0d\x3d\x39\x3a\x9b\x37\xb2\x76\x42\xf0\x57\xd6\xd9\x34\x3d\x89\xf1\x6e\xc6\x05\x31\xd6\xa2\xa3\x3e\x70\x80\x77\xd0\x24\x74\xa2\xf9\x73\x31\xd2\xfa\x41\xae\x11\x1e\x85\x5d\x30\xde\x3d\x44\xe9\x0e\x77\x27\xaf\xa7\xbb\xe8\x97\xca\xf2\x22\xc2\x2c\xe7\x43\xcc\xb2\x54\xcf\x6a\x53\xe1\x0b\xb6\xfb\xdd\x0b\xba\xa6\xbe\x05\xf1\xfb\x49\xed\x15\x78\x14\x47\x67\xc6\x4f\x2c\x3a\x1f\xa4\x31\x61\x11\xa3\x7a\x52\x33\x07\x50\x47\xb5\x39\x54\x0a\x8b\xcf\xe5\xdf\x64\xfd\x6f\xff\x8a\x01\x0e\x65\x5e\x64\xc8\xf2\xb2\xc2\x72\x59\x56\x75\xc9\x52\xee\xf3\x74\x7e\xbe\xd0\x1f\x7e\xf5\xc6\xc9\x72\x3e\xd1\x0a\x96\x24\x5e\xe1\xfb\xb7\xaf\x3f\xe2\xc7\xc7\x1b\x35\x8e\x17\x49\x04\xa8\x22\x45\xbe\x4d\x22\x55\xa4\x49\xf4\x6f\x6d\x3c\xaf\x8b\xec\xb0\xce\x14\x3a\xdb\x35\xe1\xdd\x26\xd1\xdf\x01\x00\x71\x7b\x57\xc4\x2b\x02\x00\x00")
+
+func _20190603101512_add_spatial_index_to_thingsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190603101512_add_spatial_index_to_thingsUpSql,
+		"20190603101512_add_spatial_index_to_things.up.sql",
+	)
+}
+
+func _20190603101512_add_spatial_index_to_thingsUpSql() (*asset, error) {
+	bytes, err := _20190603101512_add_spatial_index_to_thingsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190603101512_add_spatial_index_to_things.up.sql", size: 555, mode: os.FileMode(0644), modTime: time.Unix(1792361412, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2, 0xe4, 0x66, 0x9b, 0x4, 0xa4, 0xb1, 0x9b, 0x3d, 0x26, 0x78, 0xb5, 0x96, 0x78, 0xfd, 0xf9, 0xc4, 0x23, 0x88, 0x4f, 0xb3, 0x72, 0x9e, 0xfe, 0x32, 0x95, 0x41, 0xb5, 0xc6, 0x3e, 0xdf, 0x4a}}
+	return a, nil
+}
+
+var __20190604093021_add_sample_range_to_channelsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xce\x48\xcc\xcb\x4b\xcd\x29\x8e\x2f\xc9\xc8\xcc\x4b\x8f\x2f\xcd\x4c\x89\xcf\x4c\xa9\xb0\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x85\x2b\xe2\x52\x50\x00\x1b\xe2\xec\xef\x13\xea\xeb\x87\x64\x4a\x5a\x66\x51\x71\x49\x7c\x71\x62\x6e\x41\x4e\xaa\x0e\x4e\x55\x39\x89\xc5\x25\xf1\xc5\x89\xb9\x05\x39\xa9\xd6\x5c\x80\x01\x00\x9d\xfe\xe3\x15\x8e\x00\x00\x00")
+
+func _20190604093021_add_sample_range_to_channelsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190604093021_add_sample_range_to_channelsDownSql,
+		"20190604093021_add_sample_range_to_channels.down.sql",
+	)
+}
+
+func _20190604093021_add_sample_range_to_channelsDownSql() (*asset, error) {
+	bytes, err := _20190604093021_add_sample_range_to_channelsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190604093021_add_sample_range_to_channels.down.sql", size: 142, mode: os.FileMode(0644), modTime: time.Unix(1792361783, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4a, 0x52, 0x10, 0xba, 0x61, 0x20, 0xa1, 0x1c, 0xa4, 0x2, 0x11, 0x9e, 0xe9, 0x9b, 0x2a, 0x38, 0x36, 0x83, 0x80, 0x3f, 0x79, 0x4a, 0x9c, 0x58, 0x6a, 0x53, 0x43, 0xd2, 0xf5, 0xb3, 0xe8, 0x82}}
+	return a, nil
+}
+
+var __20190604093021_add_sample_range_to_channelsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x90\xc1\x6e\xdb\x30\x10\x44\xef\xfc\x8a\xb9\xb9\x05\x12\xff\x80\x9b\x02\x6a\xc4\x20\x02\x6c\x29\xb0\x19\x24\xe8\x85\x60\xa5\x95\x45\x84\xa2\x0c\x2e\x95\xb8\x7f\x5f\x90\x28\x1c\xf9\x94\xe3\xee\xec\xec\x60\x5e\xb1\x55\x72\x0f\x55\xfc\xda\x4a\xb4\x83\xf1\x9e\x1c\x0b\xa0\x28\x4b\xdc\x37\xdb\xe7\x5d\x8d\xde\x06\x8e\x9a\xcd\x78\x72\x04\x55\xed\xe4\x41\x15\xbb\x27\xbc\x54\xea\x31\x8f\xf8\xdd\xd4\xf2\xe6\xda\xe3\xcc\xd7\x96\x8d\x10\xb7\xb7\xf8\x63\xda\xb7\xde\x3a\x87\x3e\x4c\x23\xe2\x40\x88\x83\xf5\xc7\x15\x23\x18\x7f\x24\xd0\xb9\xa5\x53\x44\x3f\x85\x2c\x7e\x98\x48\x01\xd1\xf8\x37\x38\x7a\x27\x87\x8f\xc1\xb6\x03\x2c\x63\xf2\xee\x6f\x7a\x38\x92\xf1\xd6\x1f\xfb\xd9\x65\xd3\x69\x8a\x60\xf2\x3c\x05\xde\xe4\x0f\xd6\x77\x74\xa6\x80\x14\xca\x29\x8c\x61\x3d\x0c\x23\x90\x71\xe8\x4c\x34\x30\x21\xd8\x77\x62\xf1\xfc\x54\x16\xea\x13\x0b\x5a\x71\x90\xea\x9a\xc7\x1d\xe2\x7a\xb9\x48\x1c\x70\x55\x3f\x5d\x2c\x66\xf1\xb0\x6f\x76\x29\xd6\x1f\x19\xf1\x26\x07\x6a\x9e\xe6\xd0\x12\xa3\x63\xf1\xf2\x28\xf7\x12\x71\x3d\xdb\x0e\x77\x68\xd7\xf9\x52\xcf\xb6\x4b\x80\xeb\x12\x1d\xaf\xff\x2b\x0b\xa7\x5e\xca\xde\x8c\x84\x1f\x3f\xb1\xca\xac\x74\x62\xa5\x33\xab\xd5\x46\x88\xfb\xbd\x4c\x95\xaa\xba\x94\xaf\xa8\x1e\x50\x37\x0a\xf2\xb5\x3a\xa8\xc3\xa5\xa6\xbe\x44\x6a\xdb\x9d\xd1\xd4\x9f\x00\xbe\x5d\xa4\xef\x1b\xf1\x6f\x00\x11\x4a\x84\xcf\x3a\x02\x00\x00")
+
+func _20190604093021_add_sample_range_to_channelsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190604093021_add_sample_range_to_channelsUpSql,
+		"20190604093021_add_sample_range_to_channels.up.sql",
+	)
+}
+
+func _20190604093021_add_sample_range_to_channelsUpSql() (*asset, error) {
+	bytes, err := _20190604093021_add_sample_range_to_channelsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190604093021_add_sample_range_to_channels.up.sql", size: 570, mode: os.FileMode(0644), modTime: time.Unix(1792361783, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa1, 0xd3, 0xd9, 0xbb, 0xab, 0x26, 0xf3, 0x57, 0x6c, 0x92, 0x57, 0x5a, 0x8c, 0xe5, 0x9c, 0x19, 0xfe, 0x55, 0x58, 0xe8, 0x5d, 0x5a, 0x89, 0xf2, 0x3b, 0xb1, 0x8e, 0x8d, 0x26, 0x87, 0xa7, 0xb9}}
+	return a, nil
+}
+
+var __20190605141207_add_app_uid_to_location_changesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x91\xcd\xee\x9b\x30\x10\xc4\xef\x7e\x8a\x39\xfc\xa5\x04\x29\xea\x0b\xa0\x1e\x1c\x58\xa8\x25\xc7\x46\xb6\x69\x73\x43\x6e\x88\x08\x12\x02\xda\x38\xfd\x78\xfb\x8a\x8f\xb4\x51\xd3\x1c\x7a\x1b\x7b\x77\x67\x7f\xab\x49\x0c\x71\x47\xd0\x06\x86\x0a\xc9\x13\x42\x56\xaa\xc4\x09\xad\x10\x2e\x6d\xdf\x54\xdd\x70\xf2\xa1\x1d\xfa\xca\xdf\xea\x36\x6c\x23\x18\x72\xa5\x51\x16\xce\x88\x3c\x27\x03\x6e\xf1\xf6\x79\xa8\x7f\xbe\xb1\x3d\xe5\x42\x31\x40\x64\xd8\xba\xbc\xd2\x05\xde\x63\x53\x16\x29\x77\xb4\x89\xe0\x3e\xd0\x54\x5c\xca\x8a\x3e\xbd\xeb\x86\xbe\x81\xb0\x48\x85\x75\x42\x25\x0e\x99\xd1\x07\x68\x99\x2e\x15\x6d\x30\x77\xf9\xf0\xa2\xc9\x87\x07\x53\x40\x28\x4b\xc6\x41\x28\xa7\xf1\x1b\xfa\x74\xf1\x7d\x73\xbe\x62\xbb\x1c\xd3\xd6\x3b\x8c\x5f\xcf\xdf\xda\xe1\x76\xad\xa6\x2d\x8f\x4f\x1f\x76\xe8\xcf\xdf\xd7\xff\x59\xf9\x10\xad\xee\x1f\xb9\x2c\xc9\x2e\xe0\x93\xcb\x1d\x73\x55\xd3\xec\xfd\xa6\x55\xf9\x10\xc5\xf3\x30\xa9\x14\x22\x9b\x34\x49\x4b\xec\x7f\x58\x5f\xe0\xfc\x0d\xf3\xef\xcd\x7f\xf6\x2e\x91\x41\x95\x52\xc6\x8c\x54\x1a\xb3\x25\x31\x48\xae\xf2\x92\xe7\x84\xb1\x1b\x9b\xeb\x97\x2e\x66\x2c\x35\xba\x80\x50\x29\x1d\xa7\xa0\xe8\x28\xac\xb3\x4f\x8c\xd5\x1d\xb1\x6a\xeb\x1f\x31\x63\x5c\x3a\x32\x70\x7c\x2f\xe9\xf9\x9e\xd9\x31\xd1\xb2\x3c\xa8\x07\x4b\x3f\x8e\xd5\xad\xad\x63\xf6\x6b\x00\x6e\xbe\xd5\x3a\x82\x02\x00\x00")
+
+func _20190605141207_add_app_uid_to_location_changesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190605141207_add_app_uid_to_location_changesDownSql,
+		"20190605141207_add_app_uid_to_location_changes.down.sql",
+	)
+}
+
+func _20190605141207_add_app_uid_to_location_changesDownSql() (*asset, error) {
+	bytes, err := _20190605141207_add_app_uid_to_location_changesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190605141207_add_app_uid_to_location_changes.down.sql", size: 642, mode: os.FileMode(0644), modTime: time.Unix(1792361888, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc9, 0x91, 0xef, 0xc5, 0xe4, 0x64, 0x94, 0x62, 0xd4, 0xa5, 0x57, 0x46, 0x1e, 0x9d, 0x79, 0x6c, 0x81, 0xdc, 0x9d, 0x79, 0xa9, 0x7a, 0x2c, 0xd7, 0xdc, 0xcd, 0xfd, 0x5b, 0x21, 0xf0, 0xf2, 0x51}}
+	return a, nil
+}
+
+var __20190605141207_add_app_uid_to_location_changesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x52\x5f\x6f\x9b\x3e\x14\x7d\xe7\x53\x9c\x87\x4a\x09\x52\x52\xfd\x7e\xaf\x8b\xfa\xe0\x82\x43\x2d\x51\x53\x19\xd3\xf5\x0d\xb9\xc1\x23\x56\x53\xc3\xc0\xb4\xcd\x3e\xfd\x64\x20\x6b\xb4\x6a\xab\xf6\x76\x7d\xff\x9d\x73\x7c\x2e\x49\x25\x15\x90\xe4\x3a\xa5\x38\x34\x3b\xe5\x4c\x63\xcb\xdd\x5e\xd9\x5a\xf7\x20\x71\x8c\x28\x4b\x8b\x5b\x0e\xd5\xb6\xe5\x60\x2a\xdc\x13\x11\xdd\x10\xb1\xfc\xff\xbf\x70\x13\x04\x91\xa0\x44\x52\x30\x1e\xd3\x07\xb0\x2d\x78\x26\x41\x1f\x58\x2e\xf3\x0f\xcb\x4a\xb7\x37\xb6\x2e\x4d\x55\x9a\xea\x0d\x19\xff\x88\xb6\x3c\x75\xac\x60\x6c\xaf\x3b\xa7\xab\x52\x39\x0f\xb3\x5e\xc3\xed\xb5\xe7\x80\x67\xf5\x64\x6c\x0d\x85\x69\x0a\xa6\x47\xab\xfa\x5e\x57\x30\x16\x2f\x46\x41\xc1\x75\xca\xf6\x6a\xe7\x95\x8c\x20\x07\xf4\xda\x39\x63\xeb\x15\x5e\xf7\x66\xb7\x0f\xd6\x6b\x3f\xa6\x9f\x5b\x77\xc4\xb7\xa6\x9b\x57\xf5\x78\x56\x95\xc6\xe3\x71\xc4\x32\xb6\xd2\x6f\xba\x3b\x29\xcc\x04\x04\xbd\x4b\x49\x44\xb1\x2d\x78\x24\x59\xc6\x31\xd1\xfd\x25\x43\x0d\x95\x71\xcb\x10\x82\xca\x42\xf0\x1c\x52\xb0\x24\xa1\x02\x24\xc7\xc5\x63\x53\x1d\x2f\x82\x98\x46\x29\x11\x34\xc0\x8c\x58\x95\x8f\xc7\xf3\x0f\xc5\x97\x2b\xf0\x22\x4d\xd9\x76\xb9\x1b\xba\x4e\x5b\x57\xce\xcc\x97\x8b\xa7\xa1\xfa\x31\x5c\xce\x2e\x2c\x56\x70\xdd\xa0\xc3\x15\x16\x8b\x70\x13\x5c\xd3\x84\xf1\x00\xde\x80\xa5\x4c\xca\xec\x0e\x57\x58\x14\x77\x31\x91\x74\x11\x42\xde\x50\x5f\x9c\xca\x9c\x7e\xbd\x3c\x34\xb6\x06\xcb\x11\xb3\x5c\x32\x1e\x49\x6c\x45\x76\x8b\x2c\x8d\xa7\x4a\x26\x30\x76\x29\xf7\x87\x26\xe5\xce\x96\x02\x8c\xe7\x54\x48\x30\x2e\xb3\xbf\x7a\xda\x76\xfa\xc5\x34\x43\x5f\x7a\x94\xf3\xa7\x72\x2b\x58\xfd\x3a\xe7\xc7\xc8\xa7\x66\xad\xe1\x0c\x73\x4f\xd2\x82\xe6\x93\x02\x7f\x22\x27\xbe\x73\xe4\x27\x4e\xe2\xe6\xc8\xa7\xde\x3f\x3a\xdc\x8c\x8b\x28\x8f\xc1\xb6\x3e\xa6\x69\x4e\x83\x7f\x11\xf0\x19\xc7\xdf\x19\x7e\x4e\xe7\x9d\xcc\x74\x34\xa3\xfb\x9b\x80\xf2\x78\x13\x4c\x37\x83\x94\xf0\xa4\x20\x09\x45\x7b\x68\xeb\xfe\xfb\x61\x13\xfc\x1c\x00\xd4\x46\x15\x86\xb2\x03\x00\x00")
+
+func _20190605141207_add_app_uid_to_location_changesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190605141207_add_app_uid_to_location_changesUpSql,
+		"20190605141207_add_app_uid_to_location_changes.up.sql",
+	)
+}
+
+func _20190605141207_add_app_uid_to_location_changesUpSql() (*asset, error) {
+	bytes, err := _20190605141207_add_app_uid_to_location_changesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190605141207_add_app_uid_to_location_changes.up.sql", size: 946, mode: os.FileMode(0644), modTime: time.Unix(1792361891, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd8, 0xc4, 0xe2, 0x18, 0xad, 0x42, 0x28, 0x6d, 0x2f, 0xe, 0xb3, 0xc0, 0x59, 0xfd, 0xa1, 0x9a, 0x1c, 0x68, 0xfc, 0xc1, 0x1a, 0x69, 0xcc, 0xe1, 0x6e, 0x73, 0xac, 0x63, 0x21, 0xc2, 0x7, 0x8e}}
+	return a, nil
+}
+
+var __20190606112233_add_jobs_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1b\x00\xe4\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6a\x6f\x62\x73\x3b\x0a\x03\x00\xbc\x60\xb5\xf7\x1b\x00\x00\x00")
+
+func _20190606112233_add_jobs_tableDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190606112233_add_jobs_tableDownSql,
+		"20190606112233_add_jobs_table.down.sql",
+	)
+}
+
+func _20190606112233_add_jobs_tableDownSql() (*asset, error) {
+	bytes, err := _20190606112233_add_jobs_tableDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190606112233_add_jobs_table.down.sql", size: 27, mode: os.FileMode(0644), modTime: time.Unix(1792362001, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7c, 0xda, 0x6a, 0x78, 0x97, 0xc6, 0xf1, 0x4f, 0xbf, 0xba, 0x8b, 0x6a, 0xbc, 0xf, 0x2e, 0x47, 0x24, 0x1a, 0x4f, 0x1e, 0xad, 0xe5, 0x81, 0x23, 0xfa, 0xbe, 0xeb, 0xf2, 0x44, 0xf0, 0xbd, 0x52}}
+	return a, nil
+}
+
+var __20190606112233_add_jobs_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\x5f\x6f\xaa\x30\x18\x87\xef\xf9\x14\xef\x1d\x90\x78\xa1\xb7\x7a\x72\x92\x8a\xaf\xd2\x23\x16\x53\xea\x51\x77\x43\xaa\x74\x0b\x0e\x81\x01\x26\x2e\xcb\xbe\xfb\x42\xad\x73\x8b\x73\x4b\xc6\x1d\xcd\xf3\xbc\x7f\xda\x9f\xc7\x91\x08\x04\x41\x86\x01\x02\x1d\x03\x0b\x05\xe0\x8a\x46\x22\x82\x5d\xb1\xa9\xc1\xb1\x00\xd2\x04\x2e\xdf\x90\x4e\x22\xe4\x94\x04\x30\xe7\x74\x46\xf8\x1a\xa6\xb8\xee\x58\x00\x4f\x07\x75\x50\x67\xea\x3f\xe1\x9e\x4f\xb8\xd3\xeb\x76\x5d\x5d\x93\x2d\x82\x00\x3c\x1f\xbd\x29\x38\x27\xf2\xcf\x5f\xb0\x6d\xb7\x35\x4b\xf9\x9c\x15\xd2\x34\xf9\x17\x85\x6c\x78\x51\x46\x38\x26\x8b\x40\x80\xfd\xf2\x6a\xf7\xfb\xbb\xba\xc8\x37\xad\x22\x9b\x46\xed\xcb\xa6\xd6\x0a\x65\x02\x27\xc8\xaf\xa5\x6e\x8b\xee\xe5\x31\x7e\xc7\x6f\xa2\x3d\xcd\x56\x87\x3c\x96\x8d\xd9\x41\xd0\x19\x46\x82\xcc\xe6\xb0\xa4\xc2\xd7\xbf\x70\x17\x32\xbc\x96\x59\xb8\x74\xf4\x26\x59\xb1\x7d\x54\x89\x29\x71\xcb\xd7\xa0\xac\x9b\x58\x55\x55\x51\xb5\x20\xae\x44\x7b\xb8\xad\x94\x6c\xce\xfa\x2f\xba\x6f\x8b\x7d\x99\x29\x53\xe1\xbb\xee\xf7\x32\xcd\x7e\x1c\xd3\x72\x07\x96\x65\xe2\x41\xd9\x08\x57\x5f\xc4\x23\x2e\x55\x9e\xa4\xf9\x43\x9c\x26\x47\x08\x99\x89\x8c\x7e\xdf\x8e\xb9\x4c\xd7\x02\x58\xfa\xc8\xf1\xf3\x78\x34\x3a\x2d\x41\xd8\xe8\xc3\x38\x34\x02\xb6\x08\x82\x81\xf5\x36\x00\x87\xb9\xba\x01\x97\x02\x00\x00")
+
+func _20190606112233_add_jobs_tableUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190606112233_add_jobs_tableUpSql,
+		"20190606112233_add_jobs_table.up.sql",
+	)
+}
+
+func _20190606112233_add_jobs_tableUpSql() (*asset, error) {
+	bytes, err := _20190606112233_add_jobs_tableUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190606112233_add_jobs_table.up.sql", size: 663, mode: os.FileMode(0644), modTime: time.Unix(1792362001, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x66, 0x2a, 0x6e, 0x32, 0xff, 0xf2, 0x6c, 0x2e, 0x1f, 0x88, 0x66, 0x52, 0x53, 0x0, 0x2e, 0x89, 0x2a, 0x64, 0xfe, 0x18, 0x7a, 0x66, 0xfe, 0x70, 0x2b, 0xc6, 0x9b, 0xa5, 0x70, 0x30, 0x7a, 0xe9}}
+	return a, nil
+}
+
+var __20190607103045_add_admin_scopes_to_applicationsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x67\x00\x98\xff\x55\x50\x44\x41\x54\x45\x20\x61\x70\x70\x6c\x69\x63\x61\x74\x69\x6f\x6e\x73\x0a\x53\x45\x54\x20\x73\x63\x6f\x70\x65\x20\x3d\x20\x61\x72\x72\x61\x79\x5f\x72\x65\x6d\x6f\x76\x65\x28\x61\x72\x72\x61\x79\x5f\x72\x65\x6d\x6f\x76\x65\x28\x73\x63\x6f\x70\x65\x2c\x20\x27\x64\x65\x6c\x65\x74\x65\x2d\x75\x73\x65\x72\x73\x27\x29\x2c\x20\x27\x75\x70\x64\x61\x74\x65\x2d\x6c\x6f\x63\x61\x74\x69\x6f\x6e\x73\x27\x29\x3b\x0a\x03\x00\x0f\x06\xf4\x94\x67\x00\x00\x00")
+
+func _20190607103045_add_admin_scopes_to_applicationsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190607103045_add_admin_scopes_to_applicationsDownSql,
+		"20190607103045_add_admin_scopes_to_applications.down.sql",
+	)
+}
+
+func _20190607103045_add_admin_scopes_to_applicationsDownSql() (*asset, error) {
+	bytes, err := _20190607103045_add_admin_scopes_to_applicationsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190607103045_add_admin_scopes_to_applications.down.sql", size: 103, mode: os.FileMode(0644), modTime: time.Unix(1792362341, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6f, 0x73, 0xc6, 0x5f, 0x3, 0x67, 0x78, 0x20, 0xe1, 0x73, 0x5e, 0x61, 0x15, 0x9b, 0xcd, 0x2e, 0x5, 0x6b, 0x91, 0x27, 0x97, 0x1b, 0x15, 0x5f, 0x5f, 0x26, 0x61, 0x13, 0x5, 0x77, 0xed, 0x79}}
+	return a, nil
+}
+
+var __20190607103045_add_admin_scopes_to_applicationsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0a\x0d\x70\x71\x0c\x71\x55\x48\x2c\x28\xc8\xc9\x4c\x4e\x2c\xc9\xcc\xcf\x2b\xe6\x0a\x76\x0d\x51\x28\x4e\xce\x2f\x48\x55\xb0\x55\x48\x2c\x2a\x4a\xac\x8c\x4f\x2c\x28\x48\xcd\x4b\xd1\x00\x0b\xea\x28\xa8\xa7\xa4\xe6\xa4\x96\xa4\xea\x96\x16\xa7\x16\x15\xab\x6b\x72\x85\x7b\xb8\x06\xb9\x2a\xa8\x27\x17\xa5\x26\xc2\x45\x15\x6c\x15\x1c\xfd\x22\x21\x3a\x34\xb9\x1c\xfd\x5c\x14\xfc\xfc\x43\xd0\x74\xa2\xa8\xb1\xe6\xe2\x22\xcb\x2d\xa5\x05\x29\x20\x5b\x73\xf2\xa1\x3a\x48\x73\x0f\x86\x6e\x34\x37\x01\x06\x00\x21\xce\x23\xd6\x1f\x01\x00\x00")
+
+func _20190607103045_add_admin_scopes_to_applicationsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190607103045_add_admin_scopes_to_applicationsUpSql,
+		"20190607103045_add_admin_scopes_to_applications.up.sql",
+	)
+}
+
+func _20190607103045_add_admin_scopes_to_applicationsUpSql() (*asset, error) {
+	bytes, err := _20190607103045_add_admin_scopes_to_applicationsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190607103045_add_admin_scopes_to_applications.up.sql", size: 287, mode: os.FileMode(0644), modTime: time.Unix(1792362344, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x57, 0x35, 0x7d, 0x56, 0x0, 0xa5, 0xc1, 0xdd, 0xb9, 0x4f, 0x3a, 0x4a, 0x88, 0xc4, 0x88, 0xbf, 0x6a, 0x6e, 0xcc, 0xac, 0x66, 0xb6, 0x6f, 0x44, 0xa9, 0xcf, 0xbc, 0x6c, 0x9f, 0x8f, 0x6e, 0x8e}}
+	return a, nil
+}
+
+var __20190608093000_add_stream_events_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x24\x00\xdb\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x74\x72\x65\x61\x6d\x5f\x65\x76\x65\x6e\x74\x73\x3b\x0a\x03\x00\xbc\x40\x14\xad\x24\x00\x00\x00")
+
+func _20190608093000_add_stream_events_tableDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190608093000_add_stream_events_tableDownSql,
+		"20190608093000_add_stream_events_table.down.sql",
+	)
+}
+
+func _20190608093000_add_stream_events_tableDownSql() (*asset, error) {
+	bytes, err := _20190608093000_add_stream_events_tableDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190608093000_add_stream_events_table.down.sql", size: 36, mode: os.FileMode(0644), modTime: time.Unix(1792363902, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4, 0x4c, 0xa, 0x70, 0x89, 0x6d, 0x49, 0x8c, 0xd7, 0x4a, 0x65, 0x3c, 0xe6, 0x46, 0x89, 0x61, 0xec, 0x45, 0x20, 0xba, 0xf1, 0xaf, 0x28, 0xf5, 0x8, 0x5b, 0x3d, 0x8f, 0xd3, 0xca, 0x8a, 0x27}}
+	return a, nil
+}
+
+var __20190608093000_add_stream_events_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8f\xd1\x4a\xc3\x30\x14\x86\xef\xf3\x14\xff\xe5\x0a\x7b\x83\x5e\xa5\xee\x4c\x8f\xa6\xe9\x68\xce\x58\xe7\x4d\x08\x36\x68\x40\xa7\x6c\x51\xf4\xed\xa5\x45\x5b\x14\x94\xe5\x2e\xfc\x1f\x1f\xe7\xbb\x68\x49\x0b\x41\x74\x65\x08\xbc\x86\x6d\x04\xd4\xb1\x13\x87\x53\x3e\xc6\xf0\xe4\xe3\x5b\x3c\xe4\x13\x16\x0a\x48\x3d\xbe\x5f\xc5\x97\x8e\x5a\xd6\x06\x9b\x96\x6b\xdd\xee\x71\x43\xfb\xa5\x02\xf2\x43\x3a\xdc\xfb\xd7\x01\x15\xea\x64\x14\xda\xad\x31\xc3\xf6\x12\x3e\x1e\x9f\xc3\x28\xb9\x76\x8d\xad\x7e\x8c\x77\xc7\x18\x72\xec\x7d\xc8\x10\xae\xc9\x89\xae\x37\xd8\xb1\x5c\x8d\x5f\xdc\x36\x96\x26\x1e\x2b\x5a\xeb\xad\x19\xec\xbb\x45\xa1\x8a\x52\xa9\xaf\x10\xb6\x2b\xea\xfe\x0b\xf1\xd3\x81\x3e\xf5\xef\x68\xec\xef\xce\x69\x5f\x22\xf5\x45\x79\xbe\x78\x0e\xf8\xc3\x3c\x03\x45\xa9\x3e\x07\x00\x32\x5c\x6f\xb6\x79\x01\x00\x00")
+
+func _20190608093000_add_stream_events_tableUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190608093000_add_stream_events_tableUpSql,
+		"20190608093000_add_stream_events_table.up.sql",
+	)
+}
+
+func _20190608093000_add_stream_events_tableUpSql() (*asset, error) {
+	bytes, err := _20190608093000_add_stream_events_tableUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190608093000_add_stream_events_table.up.sql", size: 377, mode: os.FileMode(0644), modTime: time.Unix(1792363902, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd5, 0x4e, 0x94, 0xc0, 0xe9, 0x6d, 0xfa, 0xe1, 0xdf, 0xcc, 0x30, 0xff, 0x82, 0x25, 0x19, 0x2c, 0x9a, 0xff, 0x8f, 0x1b, 0x54, 0x12, 0x27, 0xff, 0xcd, 0xe4, 0x47, 0x35, 0xd, 0x29, 0xc8, 0xb7}}
+	return a, nil
+}
+
+var __20190609091500_add_webhooksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\xc8\x4c\x49\xcd\x2b\xc9\x2c\xc9\x4c\x2d\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xcb\xcf\x4e\x4d\x89\x4f\x2c\xb1\xe6\x42\xd6\x58\x92\x91\x99\x97\x8e\x4b\x53\x71\x49\x62\x4e\x2a\x58\x0b\x58\x1e\xa2\x03\x21\x5d\x9e\x9a\x94\x91\x9f\x9f\x1d\x9f\x92\x9a\x93\x59\x96\x5a\x94\x99\x5a\x8c\x5f\x61\xb1\x35\x17\x60\x00\xe7\xd2\xd7\x6c\xb4\x00\x00\x00")
+
+func _20190609091500_add_webhooksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190609091500_add_webhooksDownSql,
+		"20190609091500_add_webhooks.down.sql",
+	)
+}
+
+func _20190609091500_add_webhooksDownSql() (*asset, error) {
+	bytes, err := _20190609091500_add_webhooksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190609091500_add_webhooks.down.sql", size: 180, mode: os.FileMode(0644), modTime: time.Unix(1792364229, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf9, 0x40, 0x7e, 0x4a, 0x7c, 0x31, 0x47, 0xa2, 0xfa, 0xc9, 0x5f, 0x73, 0xd3, 0xaf, 0x73, 0x54, 0x4, 0x28, 0x36, 0xb9, 0x4c, 0x81, 0x35, 0xca, 0x88, 0xab, 0x3, 0x66, 0xa2, 0xf2, 0xd0, 0x4}}
+	return a, nil
+}
+
+var __20190609091500_add_webhooksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x94\xd1\x6f\x9b\x30\x10\xc6\xdf\xf9\x2b\xee\x2d\x89\xd4\x4a\xdd\xf6\x98\x6e\x92\x03\xd7\xc6\x2b\x21\x1d\x38\x6b\xbb\x69\x42\x1e\xbe\xad\x56\x29\x20\xdb\x49\xd7\xff\x7e\x82\x40\x68\xda\x94\x75\xbc\xe1\xfb\xfc\xdd\x71\xbf\x4f\xf8\x31\x32\x81\x20\xd8\x2c\x44\xe0\x67\x10\x2d\x05\xe0\x35\x4f\x44\x02\x0f\xf4\xf3\xb6\x2c\xef\x2c\x8c\x3d\x00\xad\xa0\x7b\x66\xfc\x3c\xc1\x98\xb3\x10\x2e\x63\xbe\x60\xf1\x0d\x5c\xe0\xcd\x91\x07\xb0\xee\x45\x5f\x59\xec\xcf\x59\x3c\x7e\x77\x32\x69\x2c\xa3\x55\x18\xc2\x2a\xe2\x5f\x56\x58\x2b\x65\x55\xa5\xad\x98\x47\x02\xcf\x31\xee\x55\x31\x9e\x61\x8c\x91\x8f\x09\xc8\xaa\xca\x75\x26\x9d\x2e\x0b\x3b\xd6\x6a\x02\xcb\x08\x02\x0c\x51\x20\xf8\x2c\xf1\x59\xd0\x98\xad\x4d\xde\xb5\x15\x78\x2d\x7a\x27\x7f\x8e\xfe\x05\x8c\xeb\xfa\xe9\x27\x18\x8d\x26\xb5\xda\x52\x66\xc8\xbd\x54\xd7\x35\xda\x50\xe1\xec\xae\xf6\xfd\xc7\x5e\x35\x33\x24\x1d\xa9\x54\x3a\x10\x7c\x81\x89\x60\x8b\x4b\xb8\xe2\x62\xde\xbc\xc2\xb7\x65\x84\x3b\x3d\x04\x78\xc6\x56\x61\x6d\x7f\x35\x9e\x78\x93\xa9\xe7\xb5\x9b\xe6\x51\x80\xd7\xaf\x6c\x3a\xdd\xee\x25\xd5\xea\x4f\xfd\xa9\x3d\x80\xed\xf9\x13\x97\x01\x5e\xa9\xa2\x5c\x6f\xc8\x68\x7a\x41\xee\x6d\xec\x7a\x7a\xef\x0f\xd3\xeb\x1a\x35\x57\x66\xfc\x9c\x47\xe2\x20\xbe\x56\x37\x80\xae\x59\xf8\x41\x78\x75\x9f\x4a\x3e\xe6\xa5\x6c\xe7\xfa\x9c\x2c\xa3\xd9\x5e\x59\x3a\x47\xf7\x95\xb3\x87\x63\xd4\x01\x38\xa9\x9d\xac\x93\x6e\x6d\xd3\xac\x54\xb4\x93\xd6\xe7\xb9\xb4\x2e\x25\x63\x4a\xd3\xf6\x7f\xc6\x19\xfe\x97\xf4\x93\xb9\x86\x93\x52\x0b\x5b\x50\xff\x16\xfe\x92\x3a\xef\x06\x7a\x55\xf8\xc6\x90\x3d\x89\x47\xda\x1d\xbd\x88\xdc\x5e\x86\x7a\xd5\x11\x6c\x43\x78\x7c\x0c\xee\x56\x17\xbf\x2d\xc8\xdc\x90\x54\x8f\x60\x9d\xcc\x09\x1e\x6e\xa9\xe8\x2c\x2c\x3c\x90\x21\xd0\x85\x33\xa5\x5a\x67\xa4\x40\x1a\x82\xa2\x74\x20\x8b\xa2\x5c\x17\x19\x29\x8f\x85\x02\xe3\xf6\xdf\xd3\x1a\xb2\x20\x00\x7f\x19\xae\x16\xd1\xb3\xf1\x9b\x0e\x43\x8b\x9a\x7a\xab\xcb\x80\x89\x9d\x53\x82\xa2\xbf\xf4\x71\x8b\x07\xae\xe6\x18\xe3\x16\xbb\x95\xf7\x55\x4e\x70\xda\x56\x8e\xeb\x51\xc9\x6c\x64\x0e\xa3\x0f\x27\xa0\xe4\xa3\x1d\x4d\xbd\xbd\x11\xb5\xa2\xc2\x69\xa7\x69\x60\x4c\x43\x9b\xf2\x6e\x98\xe8\xd4\xfb\x3b\x00\xfc\x9f\xb4\xfb\x75\x05\x00\x00")
+
+func _20190609091500_add_webhooksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190609091500_add_webhooksUpSql,
+		"20190609091500_add_webhooks.up.sql",
+	)
+}
+
+func _20190609091500_add_webhooksUpSql() (*asset, error) {
+	bytes, err := _20190609091500_add_webhooksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190609091500_add_webhooks.up.sql", size: 1397, mode: os.FileMode(0644), modTime: time.Unix(1792364229, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf2, 0xdc, 0x9d, 0xa2, 0xf5, 0xb4, 0xe9, 0xce, 0x2e, 0xf, 0xb, 0xc1, 0xd7, 0x11, 0x5d, 0x5e, 0x6b, 0x1, 0x26, 0xb9, 0x7d, 0xe0, 0x92, 0xc7, 0xf6, 0xaf, 0x4b, 0xb6, 0xcd, 0x3, 0xf6, 0x1c}}
+	return a, nil
+}
+
+var __20190610090000_add_alertsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3f\x00\xc0\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x6c\x65\x72\x74\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x6c\x65\x72\x74\x5f\x72\x75\x6c\x65\x73\x3b\x0a\x03\x00\xaa\x90\x5a\x87\x3f\x00\x00\x00")
+
+func _20190610090000_add_alertsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190610090000_add_alertsDownSql,
+		"20190610090000_add_alerts.down.sql",
+	)
+}
+
+func _20190610090000_add_alertsDownSql() (*asset, error) {
+	bytes, err := _20190610090000_add_alertsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190610090000_add_alerts.down.sql", size: 63, mode: os.FileMode(0644), modTime: time.Unix(1792364702, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe7, 0x54, 0x25, 0x99, 0x61, 0x7a, 0x4d, 0x33, 0x23, 0xb, 0x17, 0x1f, 0xe1, 0xfa, 0x79, 0xc4, 0x14, 0x8c, 0x1d, 0x18, 0xcb, 0xf2, 0xc5, 0x2b, 0xd1, 0x93, 0x74, 0xaf, 0xd, 0x91, 0xe8, 0xa3}}
+	return a, nil
+}
+
+var __20190610090000_add_alertsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x94\xc1\x73\xa2\x30\x14\xc6\xef\xfc\x15\xef\x26\xcc\xf4\xd0\x3d\xbb\xdb\x19\x8a\xcf\x9a\x29\x06\x17\xe2\xd6\xee\x85\xc9\x48\xd4\xcc\xb0\xc0\x84\xd0\xee\x9f\xbf\x43\x20\x88\xdb\x4a\xf5\xa2\xf8\xbe\xf7\xe5\x3d\xf3\xfd\x0c\x62\xf4\x19\x02\xf3\x1f\x43\x04\xb2\x04\x1a\x31\xc0\x1d\x49\x58\x02\x3c\x17\x4a\xa7\xaa\xc9\x45\x0d\xae\x03\x20\x33\xb0\xaf\x47\xf2\x94\x60\x4c\xfc\x10\x36\x31\x59\xfb\xf1\x2b\x3c\xe3\xeb\x9d\x03\xd0\x9c\x45\xbf\xfc\x38\x58\xf9\xb1\xfb\xed\xde\x33\xae\x74\x1b\x86\xb0\xa5\xe4\xe7\x16\x5b\x25\xaf\xaa\xb4\x17\x13\xca\xf0\x09\xe3\xb3\x2a\xc6\x25\xc6\x48\x03\x4c\x80\x57\x55\x2e\xf7\x5c\xcb\xb2\xa8\x5d\x99\x79\x10\x51\x58\x60\x88\x0c\x21\xf0\x93\xc0\x5f\x18\x33\x7d\x92\xc5\xb1\xb3\xb3\x66\x23\x0f\x53\x9d\xe8\x2e\xdf\x0b\xa1\xae\x76\x37\xb5\x50\x13\xcd\xfb\x13\x2f\x0a\x91\xb7\x8b\x30\xdc\xb1\x61\x89\x76\xac\x7d\xf9\xa7\xe2\x8a\xeb\x52\x5d\xd6\x20\x58\x61\xf0\x0c\xee\xa8\x4e\x28\xb8\xb3\x5c\xcf\xee\x60\x96\x6b\xd1\xbe\x1d\xcd\xc3\x51\x8b\x99\xe7\xb5\x66\xfa\xa4\x44\x7d\x2a\xf3\x0c\x60\x11\x6d\xdb\xeb\xda\xc4\x18\x90\x84\x44\xf4\xe2\xd0\xac\x51\xe6\xe7\x1a\x6d\x33\x9c\xbb\xc0\xa5\xbf\x0d\x19\xdc\xdb\x09\x06\xf1\xc3\x0f\xb8\x37\xc7\xec\xcb\x32\xcf\xca\xf7\xdb\xda\x07\xf1\xb9\x5d\x09\xae\x45\x96\x72\x0d\x8c\xac\x31\x61\xfe\x7a\x03\x2f\x84\xad\xcc\x23\xfc\x8e\x28\x7e\xf4\xa3\xd1\x8b\x6b\xba\x7b\x5b\x77\xb8\x4f\x92\x18\xa5\x07\xdf\x1f\xc0\x1d\xee\xc9\x7e\xeb\x39\xde\xdc\x71\xfa\x08\x13\xba\xc0\xdd\xf5\x08\xa7\x5d\xe0\x52\x99\xfd\x6d\x33\x74\x11\xee\xae\xe4\xcd\x6f\xb5\xb2\xe3\x7d\x6a\x66\x8b\xb7\xdb\xd9\xbd\x3e\xb5\xb3\xc5\xd1\xa6\x57\x61\xfd\xc0\xe9\x14\xaa\xad\xbf\x25\xd0\xc8\x08\x1d\x25\x74\x04\xc0\x68\x9c\xeb\x18\xd8\xad\xe1\x4b\xa2\xbf\xa2\xb1\xd6\x5c\x8b\x7e\xaa\xff\x98\xb2\xb1\xeb\x24\x06\x99\x4a\x14\x99\x2c\x8e\x2d\x2a\x07\xa9\xfa\x4f\x4a\xd4\x65\xfe\x26\xb2\x9e\x9c\x37\x9e\x37\x67\xc7\x49\x78\x7a\xbb\xb4\x96\xc5\x5e\x5c\x4d\x70\x3b\xe6\x41\xaa\x2e\xe6\xad\xe7\xa4\xd2\x4e\xd3\x89\xa7\x94\x45\xa9\xe5\x41\xde\xa2\x6c\xaa\xcc\x62\x36\xa1\x9c\x20\xad\xfb\x23\x06\xb7\x4f\xc1\xdd\x70\x81\xb7\x31\x55\xa7\xe6\x0e\x2e\x12\x5b\x83\x5b\x6b\xae\x85\x37\x77\xfe\x0d\x00\x8b\x60\x1d\x7a\x56\x06\x00\x00")
+
+func _20190610090000_add_alertsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190610090000_add_alertsUpSql,
+		"20190610090000_add_alerts.up.sql",
+	)
+}
+
+func _20190610090000_add_alertsUpSql() (*asset, error) {
+	bytes, err := _20190610090000_add_alertsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190610090000_add_alerts.up.sql", size: 1622, mode: os.FileMode(0644), modTime: time.Unix(1792364702, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x59, 0x5d, 0xcd, 0x50, 0xa8, 0x60, 0x92, 0xe1, 0xdf, 0x5, 0xba, 0x48, 0x5e, 0xaf, 0x8d, 0x26, 0x56, 0xa9, 0x61, 0x3d, 0xe8, 0x56, 0xbc, 0xf6, 0xf3, 0x45, 0x24, 0x9, 0x19, 0xda, 0xf5, 0xd3}}
+	return a, nil
+}
+
+var __20190611090000_add_plant_statusesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x25\x00\xda\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x6c\x61\x6e\x74\x5f\x73\x74\x61\x74\x75\x73\x65\x73\x3b\x0a\x03\x00\x8e\x23\xb3\x04\x25\x00\x00\x00")
+
+func _20190611090000_add_plant_statusesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190611090000_add_plant_statusesDownSql,
+		"20190611090000_add_plant_statuses.down.sql",
+	)
+}
+
+func _20190611090000_add_plant_statusesDownSql() (*asset, error) {
+	bytes, err := _20190611090000_add_plant_statusesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190611090000_add_plant_statuses.down.sql", size: 37, mode: os.FileMode(0644), modTime: time.Unix(1792365194, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4e, 0x12, 0xbd, 0xbd, 0xd0, 0x8, 0x63, 0xd9, 0x39, 0xf5, 0xf6, 0x87, 0xd3, 0x2, 0x69, 0x72, 0xb1, 0x7d, 0xa5, 0x4e, 0x76, 0x9b, 0x2c, 0x1d, 0x23, 0x67, 0xda, 0x8, 0xfe, 0x33, 0xc1, 0x17}}
+	return a, nil
+}
+
+var __20190611090000_add_plant_statusesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xd0\xcd\x6e\xab\x30\x10\x05\xe0\x3d\x4f\x31\xbb\x04\x29\x6f\x70\x57\x0e\x0c\xb9\x56\x8d\xa1\xb6\x51\x93\x6e\x90\x9b\x58\xc5\x2a\x75\x2a\x6c\xa2\xf6\xed\x2b\x93\x42\x55\xa9\x3f\xde\x59\xfa\xe6\x68\xe6\x64\x02\x89\x42\x50\x64\xcb\x10\x68\x01\xbc\x52\x80\x7b\x2a\x95\x84\x97\x5e\xbb\xd0\xfa\xa0\xc3\xe8\x8d\x87\x75\x02\x60\x4f\xf0\xe5\x6d\xe9\x4e\xa2\xa0\x84\x41\x2d\x68\x49\xc4\x01\x6e\xf0\xb0\x49\x00\x42\x67\xdd\x63\xfb\xc9\x29\x57\xb8\x43\x31\xc5\xf3\x86\x31\x10\x58\xa0\x40\x9e\xa1\xbc\x5a\xbf\xb6\xa7\x14\x2a\x0e\x39\x32\x54\x08\x19\x91\x19\xc9\x31\x66\x5d\xf4\x60\xf5\x43\x6f\xe6\x2c\x85\x7b\xb5\x04\x45\x70\x5d\xb1\x7d\x32\x6f\xdf\x00\xc8\xb1\x20\x0d\x53\xb0\x5a\x45\x6b\x9d\x0f\xc3\x78\x0c\xf6\xec\xa6\x81\xdf\xec\xb3\x75\x6d\xe8\x06\xe3\xbb\x73\x1f\x2f\xc9\xab\x26\xb6\x54\x0b\xcc\xa8\xa4\x15\x9f\x8c\x7e\xfd\xd3\x1c\xc7\x61\x30\x2e\xb4\x17\xdd\x8f\xe6\x07\xf3\x71\x83\x0e\xf3\x91\xb4\x44\xa9\x48\x59\xc3\x1d\x55\xff\xa7\x2f\xdc\x57\x1c\x97\x5d\xe3\x50\xc3\xe9\x6d\x83\xb0\x9e\xdb\xde\x2c\x5d\xa5\x49\xfa\x2f\x79\x1f\x00\xcf\x89\x88\x9c\xdc\x01\x00\x00")
+
+func _20190611090000_add_plant_statusesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190611090000_add_plant_statusesUpSql,
+		"20190611090000_add_plant_statuses.up.sql",
+	)
+}
+
+func _20190611090000_add_plant_statusesUpSql() (*asset, error) {
+	bytes, err := _20190611090000_add_plant_statusesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190611090000_add_plant_statuses.up.sql", size: 476, mode: os.FileMode(0644), modTime: time.Unix(1792365194, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x92, 0xeb, 0xe0, 0x31, 0xc1, 0x32, 0x64, 0x92, 0xeb, 0x32, 0x9f, 0x2f, 0xa6, 0xc1, 0xbe, 0xb8, 0x11, 0x15, 0x2a, 0xcc, 0xe8, 0x27, 0xf3, 0x9e, 0x30, 0x28, 0x17, 0x8e, 0x65, 0x1c, 0x9a, 0x6f}}
+	return a, nil
+}
+
+var __20190612090000_add_thing_hardwareDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\xd1\x6a\xc2\x30\x14\xc6\xf1\xfb\x3c\x45\x2e\x37\xd8\x1b\xf4\xca\x69\x95\x40\xd7\x8e\x1a\xc1\xbb\xc3\x99\xc9\xda\x03\x9a\x94\x93\xa3\x6e\x6f\x3f\xd6\x8a\x88\x10\xbd\x4d\x7e\xe7\xe3\xbf\x68\x9b\x4f\x6d\x5b\xb3\x5a\x95\xad\x36\x4b\x5d\x6e\xcd\xda\xae\xb5\xf4\x14\x3a\xe8\x91\xdd\x19\xd9\x03\x1e\x1d\x89\x6e\xea\xe9\x3d\x15\x6a\x3c\x5b\x6e\xea\xb9\x35\x4d\xfd\xe4\xee\xe5\xf5\xe2\xed\xec\xbd\x2a\x6f\xf0\x95\xed\x7a\x0c\x9d\x4f\x85\x9a\x9c\xa9\x17\xe5\xf6\x7e\x34\xc1\x37\xf1\x61\xe4\x27\xcf\x89\x62\x00\x72\x3f\x85\x52\xb3\xca\x96\xed\x65\x7b\xca\x53\x5a\x8f\x3b\xf3\xa6\xda\x7c\xdc\xd6\xdd\x2f\xbc\x65\xe5\x35\x8d\xfd\x89\x1e\xd3\xe4\x43\x8a\x0c\xf2\x3b\xf8\x3c\xda\xe1\x9e\xbe\x18\xe5\x3f\xdb\xa1\x60\x5e\x52\x02\x0a\x2e\x46\x7e\x40\x02\x0c\x51\xf2\xff\xc3\x1e\x83\x00\xb9\x94\x27\x78\x94\x78\x46\xf1\x4c\xa1\x83\x43\x74\xbe\x50\x7f\x03\x00\x1e\x28\x08\xe8\x0a\x02\x00\x00")
+
+func _20190612090000_add_thing_hardwareDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190612090000_add_thing_hardwareDownSql,
+		"20190612090000_add_thing_hardware.down.sql",
+	)
+}
+
+func _20190612090000_add_thing_hardwareDownSql() (*asset, error) {
+	bytes, err := _20190612090000_add_thing_hardwareDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190612090000_add_thing_hardware.down.sql", size: 522, mode: os.FileMode(0644), modTime: time.Unix(1792365355, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8a, 0x10, 0x8d, 0xf0, 0xc6, 0x4f, 0xf9, 0x6c, 0xfb, 0xe2, 0xf7, 0x96, 0xf0, 0x74, 0x2f, 0x7, 0xf5, 0xb1, 0x61, 0x57, 0x12, 0x5, 0x9c, 0xab, 0x98, 0x4c, 0xed, 0x3e, 0x69, 0xe, 0x64, 0x70}}
+	return a, nil
+}
+
+var __20190612090000_add_thing_hardwareUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x96\xd1\x6e\xa3\x38\x14\x86\xef\x79\x8a\xff\x62\xa4\x49\x24\xba\x2f\x50\xed\x85\x03\x27\x29\x5a\x62\x22\x63\xb6\x9d\x5d\xad\x10\x2d\x6e\x62\x29\x85\x2c\x78\xa6\xd3\xb7\x1f\x39\x40\xa1\x10\x32\xe5\xaa\xe1\x9c\xf3\xdb\x9c\xf3\xf9\x77\x59\x28\x49\x40\xb2\x55\x48\x30\x07\x5d\xec\x6b\x07\x60\xbe\x0f\x2f\x0a\x93\x2d\x47\xb0\x06\x8f\x24\xe8\x21\x88\x65\x8c\x67\x5d\xbd\xbc\x66\x95\x4a\x7f\xa8\xaa\xd6\x65\x01\x49\x0f\xd2\xbd\x56\x71\xc8\xaa\xfc\x5c\x51\xa9\x1f\xfa\x73\x25\xb5\x2a\xea\xb2\x4a\xcd\xdb\x49\xfd\x3e\xf9\x29\x3b\xea\xc7\x2a\x33\xba\x2c\xd2\x3c\x33\xd9\xef\x2b\x74\x9d\xea\x22\x2f\xcb\x0a\xab\x28\x0a\x89\xf1\xeb\xd9\x45\x7a\x2a\xcd\xa7\x52\x4f\xc7\xac\x30\xa9\xce\x6b\xac\x82\x4d\xc0\xe5\xbf\xff\x5d\x4d\xcf\xbe\x9b\xf2\x35\x33\xaa\xd2\xc5\x3e\x7d\x29\xf3\xe6\x63\x6f\x1d\xc7\x13\xc4\x24\x21\xe0\x3e\x3d\x8c\x6a\x9a\x09\xa5\xe3\x31\xa4\x3a\xff\x89\x88\xb7\x03\xc4\x62\x1c\x5f\xf6\xaa\xcd\xa4\x67\x66\xf4\x74\xc8\x8a\xbd\xaa\xb1\x70\x00\x9d\x63\xf4\xac\x82\x4d\x4c\x22\x60\x21\x76\x22\xd8\x32\xf1\x0d\x7f\xd1\x37\xfb\x89\xe7\x65\xd3\x61\x41\xc0\x25\x6d\x48\x9c\x17\xe1\x49\x18\x42\xd0\x9a\x04\x71\x8f\xba\x6f\x58\xe8\x7c\x69\xb7\xec\x53\x48\x92\xe0\xb1\xd8\x63\x3e\x59\x35\x5d\xd4\xaa\x32\x2a\x4f\x33\xd3\xaa\xc9\x60\x4b\xb1\x64\xdb\x1d\xee\x03\x79\x77\xfe\x89\x7f\x22\x4e\xbd\xbc\x4f\x6b\x96\x84\x12\x3c\xba\x5f\x2c\xad\xc8\xb8\x03\x78\xe7\x62\x1e\xc8\x21\x77\xed\xc2\x6d\x64\x02\xd9\x7b\xa4\x87\xa9\x7b\x06\xa4\xb4\xec\x00\x97\x82\x3d\x2d\xdd\x33\xa4\xe6\x32\x1c\xce\xf2\x3a\x1e\xe3\x41\xa6\xdd\x64\x3a\x40\xc6\x09\x58\x74\x19\xee\xb0\xef\x76\x99\x9b\x1b\x54\xea\xa9\xac\xf2\x1a\xe6\xa0\xde\x2b\x51\x3e\x23\x6b\x66\x08\x95\x3d\x1d\x60\xf4\x8b\x42\x56\xbc\xd9\x80\x36\x68\x85\xdd\x8e\x45\x5d\xe4\xea\xa7\xca\x9d\x9b\x1b\x3c\xaa\xe7\xb2\x52\x1f\xd5\x5e\xb3\x1a\xb5\x29\x2b\x95\x63\xaf\x8c\x8d\xe9\xca\xba\x4c\x6d\xa0\x0a\x53\xbd\xe1\xf5\xa0\x0a\x68\x03\x5d\xe3\x59\x1f\x8f\x2a\x87\x2e\xba\x16\x44\x02\x82\x76\x21\xf3\x08\xeb\x84\x7b\x32\xe8\x8e\x40\xda\xe9\xa7\xd9\xf7\x5c\x9b\xc5\x12\x82\x64\x22\x78\x0c\x29\x82\x8d\x05\x93\xc5\xf8\xf2\x58\xe6\x6f\x5f\x9c\x15\x6d\x02\xee\xc0\x76\x72\x21\x37\x69\xb4\xc3\x9f\xf8\x9a\xec\x7c\x26\xe9\xeb\x12\xf2\x8e\x6c\xb0\x09\x73\xba\xff\x63\x02\x56\x10\x9f\x31\xf4\x83\x58\x06\xdc\x93\x58\x8b\x68\x8b\x28\xf4\xa7\x99\x8c\xfb\x4e\x37\x6b\x2b\x35\x25\x71\x56\x6b\x9a\x3a\x16\x1b\xc2\x3b\x2b\x33\x4c\x1a\x0b\x4c\x18\x9f\x55\x99\x64\x8e\xa5\xfa\x43\x31\xab\xd1\xa7\x4c\x8a\x9b\x43\x33\x5f\xd9\xc4\xc7\x65\xfd\x71\x9a\xad\xec\x53\xc6\xc5\xd3\xd3\x36\x2b\x32\x49\x1d\x30\x82\x16\xb3\xb3\x27\xdd\x9e\x5f\x11\xf7\x11\xac\xed\xdf\x14\xc6\x57\x19\xb2\x36\xc6\xb8\x7f\x71\x98\x49\x18\x0e\x96\x19\x2d\xd2\x2d\x61\x19\xe6\x31\x09\x89\x80\xcb\xe8\xea\x51\x1f\x6f\xc0\xc5\x04\x30\x77\x68\x87\xd6\x91\xa6\x2e\xe8\xf6\x57\xa9\xdb\xde\x93\x6e\x6f\x6b\xee\xd4\xc3\x96\x0e\xf0\x37\x0b\x13\x8a\x9b\x3e\x58\xd7\xb9\xd4\x8f\xe6\xed\x85\x2d\x8d\x7a\xe3\x3a\x73\xf0\xba\x1f\x39\x6c\x7f\xb6\x5b\xfc\x80\x8b\x7b\x19\x00\xeb\x80\xa3\x4e\x13\xf7\x6f\x9d\xc6\x34\x10\x32\xbe\x49\xd8\x86\x70\x3a\x9e\xf6\xf5\xff\xc7\xc1\xfd\xda\x1a\xcc\x25\x1f\x72\xd8\xda\xfe\xb3\xd5\x4e\x29\x12\x68\x6c\xa6\xbf\xb9\x1d\x60\x1d\x09\x10\xf3\xee\x20\xa2\x7b\x3b\xdc\x07\xf2\x12\x49\xd8\x89\xc8\x23\x3f\x11\x74\x51\x78\xb1\xbc\x75\x7e\x0d\x00\x06\x0e\x34\xd4\xc9\x09\x00\x00")
+
+func _20190612090000_add_thing_hardwareUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190612090000_add_thing_hardwareUpSql,
+		"20190612090000_add_thing_hardware.up.sql",
+	)
+}
+
+func _20190612090000_add_thing_hardwareUpSql() (*asset, error) {
+	bytes, err := _20190612090000_add_thing_hardwareUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190612090000_add_thing_hardware.up.sql", size: 2505, mode: os.FileMode(0644), modTime: time.Unix(1792365355, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb2, 0xb9, 0xc4, 0xbd, 0x43, 0xec, 0x32, 0x41, 0x99, 0x62, 0xa9, 0x5d, 0x13, 0xc6, 0x44, 0x13, 0x6c, 0x7, 0x47, 0xd3, 0xc4, 0x8a, 0xa6, 0xb4, 0x4, 0x8e, 0x76, 0x38, 0x55, 0xc0, 0xfa, 0x60}}
+	return a, nil
+}
+
+var __20190613090000_add_user_deletionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2c\xcc\x41\xaa\x83\x30\x10\x87\xf1\x7d\x4e\xf1\xbf\x80\x27\x70\xe5\x7b\x4d\x41\x48\xb5\x68\x84\xee\x24\x90\x69\x1d\x88\x13\xc8\x58\xc4\xdb\x97\x62\xb7\xdf\x0f\xbe\xcb\xd0\xdf\xe1\x9b\x3f\x67\xd1\x5e\x61\x1f\xed\xe8\x47\xbc\x95\xca\x1c\x29\xd1\xc6\x59\xb4\x36\xa6\xaa\xb0\x2d\xac\x78\x06\x4e\x8a\x7d\xe1\x44\x08\x72\x20\x48\x96\x63\x65\xa5\xf8\x75\x79\x29\x0a\xad\x81\xc5\x34\xce\xdb\xe1\xf7\x3d\xc5\x00\x67\xfc\xef\xdd\x74\xeb\x90\x77\xa1\x32\x73\xc4\x68\x3d\xba\xde\xa3\x9b\x9c\xab\xcd\x67\x00\x80\xf9\x5a\x6f\x90\x00\x00\x00")
+
+func _20190613090000_add_user_deletionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190613090000_add_user_deletionsDownSql,
+		"20190613090000_add_user_deletions.down.sql",
+	)
+}
+
+func _20190613090000_add_user_deletionsDownSql() (*asset, error) {
+	bytes, err := _20190613090000_add_user_deletionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190613090000_add_user_deletions.down.sql", size: 144, mode: os.FileMode(0644), modTime: time.Unix(1792366289, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x44, 0x2a, 0x5c, 0xed, 0xb, 0xc2, 0x7e, 0xa4, 0x65, 0xfb, 0x74, 0x9d, 0x28, 0xb9, 0x55, 0xbe, 0x94, 0x50, 0x81, 0xea, 0xda, 0x42, 0x49, 0x21, 0x3a, 0x25, 0x3f, 0x3f, 0x14, 0x2a, 0x66, 0xfb}}
+	return a, nil
+}
+
+var __20190613090000_add_user_deletionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x91\x5f\x6b\xdb\x30\x14\xc5\xdf\xf5\x29\xce\x9b\x6d\x68\x61\x0c\xf6\xd4\x27\xc5\xb9\x69\x44\x15\x39\xc8\xca\x9a\x6e\x0c\xe1\x56\xa2\x55\xd7\x58\xc1\x7f\xe8\xc6\xd8\x77\x1f\x76\xdc\x18\x36\x56\xbf\x59\xf7\x77\xcf\x3d\xf7\x9e\xcb\x4b\x54\x75\xac\x7f\x1e\x42\xeb\x1d\xba\xa7\x50\x3f\xb6\xa8\x1a\x8f\xef\xfe\xd8\xe1\x35\x74\x4f\xb1\xef\x50\xd5\x88\xaf\xb5\x6f\x18\x97\x86\x34\x0c\x5f\x48\x9a\x60\x06\x9c\x1e\xf3\x42\xee\x36\xea\xc4\xd9\xe0\xb0\xd4\xc5\x16\xaa\x30\x50\x3b\x29\xaf\x18\xcb\x35\x71\x43\x53\xaf\x58\x8d\x25\xda\x8b\xd2\x94\xe8\x5b\xdf\x58\xe7\x5f\x7c\x17\x62\xdd\x22\x65\x40\x70\x98\xbf\x85\xb8\x2e\x49\x0b\x2e\xb1\xd5\x62\xc3\xf5\x1d\x6e\xe8\xee\x82\xe1\xd4\xd8\x4f\xec\x67\xae\xf3\x35\xd7\xe9\xc7\x4f\x1f\xb2\xf3\xe0\x81\x3a\x44\xe7\xdf\xa4\x60\x68\x6f\xce\x55\xe4\x6b\xca\x6f\x90\x8e\x84\x50\x48\x93\x63\xdf\x3c\xfa\xe4\x02\xc9\xf9\x2a\x49\x96\x0d\x22\xe3\xb6\xb6\x0f\xae\x9d\x44\xbe\x7e\x9b\x65\x96\xb4\xe2\x3b\x69\x90\xfc\xfa\x9d\x0c\xf0\x73\xbc\xb7\xe7\x0d\x16\xe2\x5a\x28\x03\x4d\x2b\xd2\xa4\x72\x2a\xf1\x1c\xef\xdb\x34\xb8\x0c\x85\xc2\x92\x24\x19\x42\x49\xb3\xdf\x87\xc6\x57\x9d\x77\xb6\xea\x86\x51\x62\x43\xa5\xe1\x9b\x2d\x6e\x85\x59\x8f\xbf\xf8\x52\x28\xfa\x77\xb8\x2a\x6e\xd3\xd1\xea\x43\x3c\x1c\x5f\xfc\xa4\xf0\xbf\x7e\x96\xcd\x99\x08\xb5\xa4\xfd\xbb\x99\xd8\xb7\x4b\xdb\xe0\x7e\x0c\xb6\xff\x8e\xac\x6f\x7d\x63\xfb\xe0\xb2\x2b\xf6\x67\x00\xe0\x84\x4a\x7f\x53\x02\x00\x00")
+
+func _20190613090000_add_user_deletionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190613090000_add_user_deletionsUpSql,
+		"20190613090000_add_user_deletions.up.sql",
+	)
+}
+
+func _20190613090000_add_user_deletionsUpSql() (*asset, error) {
+	bytes, err := _20190613090000_add_user_deletionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190613090000_add_user_deletions.up.sql", size: 595, mode: os.FileMode(0644), modTime: time.Unix(1792366289, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdb, 0xa1, 0x3, 0xf2, 0xa3, 0x10, 0xba, 0x5a, 0x8d, 0x45, 0x63, 0x9a, 0x34, 0x4f, 0x15, 0x68, 0x54, 0xcd, 0x9, 0x3c, 0x9a, 0x8e, 0xfe, 0xdb, 0xbf, 0x2e, 0xa, 0x1f, 0x37, 0x82, 0x9f, 0x43}}
+	return a, nil
+}
+
+var __20190614090000_add_app_key_lifecycleDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xce\xcd\xca\x82\x40\x14\xc6\xf1\xfd\x5c\xc5\xd9\xf9\xbe\x60\x57\x20\x2d\x2c\x27\x10\x2c\x45\x47\x68\x37\x1c\xec\x90\x83\x1f\x33\xcc\x51\xc9\xbb\x0f\xda\x04\x41\x1f\xdb\x87\x1f\x7f\x9e\xba\x48\x62\x25\x01\x9d\xeb\x4d\x83\x93\xb1\x23\x8b\x4a\x2a\xe0\xc6\x3a\x82\x2d\xa0\xf7\xb8\x6a\x4f\x83\x5d\xe8\xef\x31\x86\x10\x0c\x38\xe2\x95\x36\xe8\x1c\x07\xff\x91\x10\x71\xa6\x64\x09\x2a\xde\x65\x2f\x25\x80\xa4\xcc\x0b\xd8\xe7\x59\x7d\x3c\x41\x7a\x00\x79\x4e\x2b\x55\x81\xa7\xc5\x76\x74\xd1\x38\x85\x6f\x0d\xdd\x9c\xf1\xc4\x1f\x4d\x8f\x3c\xe9\x99\xbf\x94\x9c\xa7\xc5\xd8\x99\x75\x47\xab\x6e\x91\xdb\x1f\xe9\xf3\x41\x24\xee\x03\x00\x59\x9f\xd4\x7a\x28\x01\x00\x00")
+
+func _20190614090000_add_app_key_lifecycleDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190614090000_add_app_key_lifecycleDownSql,
+		"20190614090000_add_app_key_lifecycle.down.sql",
+	)
+}
+
+func _20190614090000_add_app_key_lifecycleDownSql() (*asset, error) {
+	bytes, err := _20190614090000_add_app_key_lifecycleDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190614090000_add_app_key_lifecycle.down.sql", size: 296, mode: os.FileMode(0644), modTime: time.Unix(1792366499, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa6, 0xc7, 0xe4, 0x2f, 0xd6, 0xfe, 0xe4, 0x77, 0x53, 0xb3, 0x5e, 0xdd, 0xb5, 0x88, 0x98, 0x2b, 0xe2, 0x36, 0x6e, 0x68, 0xe0, 0x5e, 0x1d, 0x43, 0xf0, 0xc5, 0x9a, 0x80, 0xc5, 0x2a, 0x57, 0x21}}
+	return a, nil
+}
+
+var __20190614090000_add_app_key_lifecycleUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xd0\x41\x6b\xfa\x40\x10\x05\xf0\xfb\x7e\x8a\x77\x8b\x01\x85\x3f\x7f\xe8\x49\x3c\x6c\xcd\x42\x84\x64\x23\x71\xad\xb4\x97\x65\x88\x43\x0d\xda\x64\xd9\x49\xa4\x7e\xfb\x82\x3d\xa5\x87\x52\x8f\x33\xbc\x1f\x33\x3c\x5d\x38\x53\xc3\xe9\xe7\xc2\x80\x42\xb8\xb4\x0d\x0d\x6d\xdf\x89\x02\x74\x96\x61\x5d\x15\xfb\xd2\x22\xf2\xb5\x3f\xf3\xd1\xd3\x00\xb7\x29\xcd\xce\xe9\x72\x8b\xc3\xc6\xe5\xf7\x11\x6f\x95\x35\xf3\xa9\xe0\xcf\xd0\x46\x96\x07\xc4\x85\x64\xf0\xa3\x3c\x74\x25\x44\xbe\xb6\xfd\x28\xfe\xcc\x37\x7f\x22\x39\xe1\x45\xd7\xeb\x5c\xd7\xb3\xff\x4f\xff\xd2\xdf\xc2\x7f\xf8\x6f\xa9\xd4\x7e\x9b\x69\xf7\xa3\x98\x9d\x71\x90\xa6\x0f\x8c\x15\x28\x46\xba\x79\x0a\x81\xbb\xe3\xec\xbe\x9c\x23\xf9\xa0\x8e\xde\x79\x41\x21\x48\x92\xaa\x43\x6e\x6a\x83\xa4\x89\x4c\x03\x2f\x46\xe1\x28\x09\x56\xd0\xf6\xf5\x1b\xa4\x4a\xdb\x0c\xb6\x72\x53\x38\x89\x2c\xd5\xd7\x00\x0c\xa6\xba\xc4\xa7\x01\x00\x00")
+
+func _20190614090000_add_app_key_lifecycleUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190614090000_add_app_key_lifecycleUpSql,
+		"20190614090000_add_app_key_lifecycle.up.sql",
+	)
+}
+
+func _20190614090000_add_app_key_lifecycleUpSql() (*asset, error) {
+	bytes, err := _20190614090000_add_app_key_lifecycleUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190614090000_add_app_key_lifecycle.up.sql", size: 423, mode: os.FileMode(0644), modTime: time.Unix(1792366499, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf4, 0x2c, 0x3f, 0x60, 0xd3, 0x96, 0xa, 0x22, 0xa6, 0xe7, 0x87, 0xc1, 0x2f, 0x7d, 0x8e, 0x67, 0x18, 0xb2, 0x51, 0x1c, 0x12, 0xe2, 0x42, 0x1b, 0x3c, 0xa0, 0xc6, 0x7, 0x7c, 0x5c, 0xc7, 0x48}}
+	return a, nil
+}
+
+var __20190615090000_add_app_burstDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6d\x00\x92\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x61\x70\x70\x6c\x69\x63\x61\x74\x69\x6f\x6e\x73\x0a\x20\x20\x44\x52\x4f\x50\x20\x43\x4f\x4e\x53\x54\x52\x41\x49\x4e\x54\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x70\x70\x6c\x69\x63\x61\x74\x69\x6f\x6e\x73\x5f\x72\x61\x74\x65\x5f\x63\x68\x65\x63\x6b\x2c\x0a\x20\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x62\x75\x72\x73\x74\x3b\x0a\x03\x00\x57\x45\x51\xc9\x6d\x00\x00\x00")
+
+func _20190615090000_add_app_burstDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190615090000_add_app_burstDownSql,
+		"20190615090000_add_app_burst.down.sql",
+	)
+}
+
+func _20190615090000_add_app_burstDownSql() (*asset, error) {
+	bytes, err := _20190615090000_add_app_burstDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190615090000_add_app_burst.down.sql", size: 109, mode: os.FileMode(0644), modTime: time.Unix(1792367017, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x97, 0xa8, 0xa9, 0x4e, 0xe5, 0xa5, 0x92, 0xbb, 0x68, 0x6a, 0xdb, 0x5d, 0x8c, 0x27, 0xa6, 0x82, 0x12, 0x4, 0xb6, 0xee, 0x1b, 0xf7, 0xed, 0x3e, 0xb, 0x43, 0xe4, 0x2, 0x81, 0x1a, 0x21, 0x6c}}
+	return a, nil
+}
+
+var __20190615090000_add_app_burstUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x2c\x28\xc8\xc9\x4c\x4e\x2c\xc9\xcc\xcf\x2b\xe6\x52\x50\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x48\x2a\x2d\x2a\x2e\x51\xf0\xf4\x0b\x71\x75\x77\x0d\x52\x70\xf6\x70\x75\xf6\x56\xd0\x80\x08\xda\x29\x18\x68\xea\xc0\x55\xfb\x05\x87\x04\x39\x7a\xfa\x85\xa0\x98\x15\x5f\x94\x58\x92\x1a\x9f\x9c\x91\x9a\x9c\x0d\xd3\x5b\x94\x58\x92\xaa\x60\xa7\x60\xa0\x69\xcd\x05\x18\x00\x4f\xfd\xd8\x78\x82\x00\x00\x00")
+
+func _20190615090000_add_app_burstUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190615090000_add_app_burstUpSql,
+		"20190615090000_add_app_burst.up.sql",
+	)
+}
+
+func _20190615090000_add_app_burstUpSql() (*asset, error) {
+	bytes, err := _20190615090000_add_app_burstUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190615090000_add_app_burst.up.sql", size: 130, mode: os.FileMode(0644), modTime: time.Unix(1792367017, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x34, 0x91, 0xe5, 0x32, 0x11, 0x6c, 0xe8, 0x28, 0x4c, 0x43, 0x51, 0xa0, 0x3b, 0xf2, 0xc4, 0x2d, 0xea, 0x4d, 0x1a, 0x6e, 0x28, 0x6e, 0x6c, 0x18, 0x24, 0x6f, 0xa6, 0x24, 0x90, 0x3a, 0x61, 0x29}}
+	return a, nil
+}
+
+var __20190616090000_add_rate_limitsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x7d\x00\x82\xff\x44\x52\x4f\x50\x20\x46\x55\x4e\x43\x54\x49\x4f\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x61\x74\x65\x5f\x6c\x69\x6d\x69\x74\x28\x56\x41\x52\x43\x48\x41\x52\x2c\x20\x54\x49\x4d\x45\x53\x54\x41\x4d\x50\x20\x57\x49\x54\x48\x20\x54\x49\x4d\x45\x20\x5a\x4f\x4e\x45\x2c\x20\x49\x4e\x54\x45\x52\x56\x41\x4c\x2c\x20\x49\x4e\x54\x45\x47\x45\x52\x29\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x61\x74\x65\x5f\x6c\x69\x6d\x69\x74\x73\x3b\x0a\x03\x00\x35\x03\x72\xbf\x7d\x00\x00\x00")
+
+func _20190616090000_add_rate_limitsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190616090000_add_rate_limitsDownSql,
+		"20190616090000_add_rate_limits.down.sql",
+	)
+}
+
+func _20190616090000_add_rate_limitsDownSql() (*asset, error) {
+	bytes, err := _20190616090000_add_rate_limitsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190616090000_add_rate_limits.down.sql", size: 125, mode: os.FileMode(0644), modTime: time.Unix(1792367216, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc6, 0xe0, 0xff, 0x5, 0xdb, 0xdf, 0x44, 0x58, 0x26, 0xb, 0x35, 0x1e, 0xdc, 0x98, 0x6f, 0x8, 0xff, 0x2a, 0x46, 0x52, 0x54, 0x7f, 0x21, 0x51, 0x94, 0x91, 0xf7, 0x7f, 0x9c, 0x43, 0xc1, 0x49}}
+	return a, nil
+}
+
+var __20190616090000_add_rate_limitsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x54\x4d\x6f\xe3\x36\x10\xbd\xeb\x57\xbc\xc3\x02\xb5\x5b\x6f\x50\x14\xe8\x65\xdd\x1c\x18\x9b\xb6\x85\x2a\x52\x20\xd1\xd9\x6e\x2f\x01\x23\x4f\x62\x22\x14\xa9\x25\x69\xbb\xfe\xf7\x05\x69\xc7\xd6\xf6\x90\x5e\x0c\x70\x38\xf3\xf8\x3e\xc6\x9a\xd5\x9c\x09\x0e\xc1\xee\x0a\x8e\x7c\x81\xb2\x12\xe0\x7f\xe5\x8d\x68\xe0\x64\xa0\x27\xad\x3a\x15\x3c\x46\x19\xf0\x46\x47\x3c\xb2\x7a\xb6\x62\xf5\xe8\xb7\xdf\x7f\x1d\xe3\xa1\xce\xef\x59\xfd\x0d\x7f\xf2\x6f\x93\x0c\x08\x32\x40\xe4\xf7\xbc\x11\xec\xfe\x01\x5f\x73\xb1\x4a\x47\xfc\x5d\x95\x3c\xe1\x96\xeb\xa2\xc8\xc6\xd3\x2c\xfb\xfc\x79\x00\x0e\xd5\xf5\x9a\x3a\x32\xc1\x23\x6c\x09\xaf\x64\xc8\xa9\x16\x2d\x69\x9d\xda\x20\xf5\xab\x75\x2a\x6c\xbb\x09\x0e\x5b\x72\x94\x5e\x52\xa9\x3b\x42\x85\x2d\x59\x47\x41\xb5\x52\x43\x3a\xa7\xf6\x52\x23\xa8\x8e\x60\x5f\x62\x0b\x0c\xfd\x13\xe0\xe8\xfb\x8e\x7c\xc0\xab\xda\x93\x49\xe5\x73\xc5\x43\x6a\x6d\x0f\xb4\x81\xb7\x11\xed\x45\xba\x1b\xb0\x4b\xbf\xba\xde\xab\x17\xa8\x80\x83\xdd\xe9\x8d\xf9\x29\xa0\xdf\xf9\x6d\x02\x8a\x6c\x3a\x1b\x69\x6d\xa5\xc1\xf3\xce\xf9\x10\x81\xa8\x53\xde\x2b\x6b\xa0\x4c\x20\xb7\x97\xda\xa3\x97\x3e\xc0\xd8\xc3\x0d\x44\x9c\x8b\x1c\x55\xaa\x7a\xda\x40\x99\xa8\x76\x4b\xee\x84\xe3\x48\x6e\xf0\xe2\x6c\xf7\x2e\xb3\xd5\xb6\x7d\x83\xb7\xf1\x3a\x80\xf6\xe4\x8e\x70\xd4\x6b\xd5\x4a\xc8\xbe\xd7\x8a\x3c\xce\x69\x3d\x1f\xe3\x10\xbc\xec\x08\x6e\xa7\xc9\x43\x7a\xc4\x18\xed\xc1\x5c\xa0\x26\x90\x66\x73\xc1\x0b\xc9\x8a\x56\x1a\xb4\xd6\x04\x67\x35\x54\xb8\xc9\xce\xdb\x51\xd5\xa8\xf9\x43\xc1\x66\x1c\x8b\x75\x39\x13\x79\x55\x0e\x02\x1c\xa5\xdf\xa7\xc1\x7a\x4c\xa2\xc8\xa7\x0f\xd6\x61\x72\x75\x27\x2f\x05\xaf\x1f\x59\x31\x39\x39\x97\xce\x4b\x5e\x4f\x50\xad\xc5\xc5\xfa\xbb\xaa\x2a\x38\x2b\x4f\xc5\x8f\xf6\x6c\x0c\xd6\xe0\xd3\xb3\xdd\x1c\x3f\x65\x73\x3e\x2b\x58\xcd\x33\xa0\xdd\x39\x47\x26\x3c\x7d\x34\x39\xcd\x00\x43\x87\xff\xe9\xb9\xe3\xcb\xbc\xcc\x80\xbc\x6c\x78\x2d\x22\xd9\x6a\xe0\x84\xc7\xe8\x8d\x8e\x93\xb8\x9f\x63\x3c\xb2\x62\xcd\x1b\x5c\xdd\x79\x77\x65\x8c\xaa\xc4\xac\x2a\x17\x45\x3e\x13\x69\x62\x8c\x79\x15\xff\x21\xab\xbc\x5c\x4e\xb3\x0c\x68\x78\xc1\x67\x02\x4e\xdf\x44\x3a\xe9\x95\xa1\x86\x45\x5d\xdd\xff\xf0\xac\xd3\xf8\xba\xe2\x35\x8f\x13\x31\x87\x5b\x5c\x33\x59\x54\x35\xd6\x0f\x73\x26\xf8\x34\x1b\x68\xfc\x72\x8b\x65\x4a\xb7\x11\xa3\x01\xf6\x95\xe4\x2f\x97\x8c\xa2\x35\xef\x49\x7c\xb9\xbd\x20\x0c\x56\xfc\xe7\x73\x78\x7f\xdc\x9e\xc7\xd3\x53\xf9\xe2\x32\x26\x56\x3c\xda\x86\x33\x93\x1f\xc8\x37\xfc\x94\xe9\x15\xf9\xa4\xe5\x3f\x42\x22\x8b\xd3\x47\xe6\xca\x21\xd6\x78\xd1\xf0\xe1\xd5\x40\x4c\xba\x2e\xe7\xc8\x17\xd3\x8c\x97\xf3\x69\x76\xda\x0c\x14\xac\x5c\xae\xd9\x92\xa3\xd7\xfd\xab\xff\xae\xa7\xd9\xbf\x03\x00\xbc\x13\x50\x44\x07\x05\x00\x00")
+
+func _20190616090000_add_rate_limitsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190616090000_add_rate_limitsUpSql,
+		"20190616090000_add_rate_limits.up.sql",
+	)
+}
+
+func _20190616090000_add_rate_limitsUpSql() (*asset, error) {
+	bytes, err := _20190616090000_add_rate_limitsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190616090000_add_rate_limits.up.sql", size: 1287, mode: os.FileMode(0644), modTime: time.Unix(1792367216, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x65, 0xc, 0x18, 0x5a, 0x99, 0x3a, 0x77, 0x95, 0x54, 0xf1, 0x53, 0xe0, 0xcc, 0xf6, 0x78, 0x7, 0x66, 0xac, 0x5, 0xcc, 0x6e, 0x2e, 0xe5, 0x8b, 0x18, 0x97, 0xef, 0x67, 0xd3, 0x1b, 0x25, 0xd7}}
+	return a, nil
+}
+
+var __20190617090000_add_app_quotasDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x41\x00\xbe\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x70\x70\x5f\x75\x73\x61\x67\x65\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x70\x70\x5f\x71\x75\x6f\x74\x61\x73\x3b\x0a\x03\x00\xd9\x97\xb4\xaf\x41\x00\x00\x00")
+
+func _20190617090000_add_app_quotasDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190617090000_add_app_quotasDownSql,
+		"20190617090000_add_app_quotas.down.sql",
+	)
+}
+
+func _20190617090000_add_app_quotasDownSql() (*asset, error) {
+	bytes, err := _20190617090000_add_app_quotasDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190617090000_add_app_quotas.down.sql", size: 65, mode: os.FileMode(0644), modTime: time.Unix(1792367629, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf3, 0xd6, 0x90, 0x3, 0x9c, 0xc, 0x41, 0x28, 0xb6, 0x54, 0x67, 0x46, 0x6e, 0xd9, 0xc0, 0x38, 0xa7, 0x14, 0xdc, 0xa2, 0x2, 0x9a, 0xd8, 0xc4, 0x9f, 0xf1, 0x8f, 0x91, 0x4f, 0x22, 0xee, 0x11}}
+	return a, nil
+}
+
+var __20190617090000_add_app_quotasUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x91\x31\x6b\xf3\x30\x18\x84\x77\xfd\x8a\xdb\x6c\x81\x87\xec\x81\x0f\x14\xf9\x4d\x3e\x11\x55\x69\x65\x19\x92\xa9\x88\xc8\xb4\x86\x36\x76\x22\x67\xc8\xbf\x2f\x56\x9c\x12\x48\xa1\x5b\xb5\xde\xe9\xe1\xbd\x3b\x69\x49\x38\x82\x13\x0b\x4d\x50\x4b\x98\x8d\x03\x6d\x55\xe5\x2a\xf8\xbe\x7f\x3d\x9e\xbb\xc1\x47\xe4\x0c\x68\x03\x6e\xaf\x22\xab\x84\xc6\xb3\x55\x4f\xc2\xee\xb0\xa6\x5d\xc1\x90\xfc\x93\x49\x19\x47\x2b\xb2\x89\x66\x6a\xad\x61\x69\x49\x96\x8c\xa4\x84\xfd\x68\xf7\x7e\x68\xbb\x43\xcc\xdb\xc0\xb1\x31\x28\x49\x93\x23\x48\x51\x49\x51\xd2\x08\x8b\xfb\xae\x6f\x12\x0b\x8e\xb6\xee\x9b\x34\x6a\x7d\x73\x6a\xbb\xf0\xa8\x41\xfe\x27\xb9\x46\x3e\xe9\xca\x20\xcf\x82\xbf\x64\x05\xb2\xcf\xee\x30\xbc\x67\x9c\x8f\xdf\x53\x26\xfc\x7c\xe7\x44\xb8\x5a\xfe\x61\x96\x3e\xd4\x46\xbd\xd4\x84\xfc\x1a\xb0\xb8\xde\x56\x4c\x67\x70\xc6\xe7\x8c\xfd\x52\xe3\x39\xfa\xb7\x06\xf9\x5f\x96\x14\xfc\xe5\x36\x57\x39\x2e\x7c\xaf\x9d\x9a\xe3\xb9\x89\x43\x04\xb0\x50\x2b\x65\xee\x2a\x2c\x69\x29\x6a\xed\x30\x1b\x7d\x77\x03\x3f\xa4\x0f\xfe\xc2\x19\x9f\xb3\xaf\x01\x00\xf0\x68\xa1\x05\x41\x02\x00\x00")
+
+func _20190617090000_add_app_quotasUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190617090000_add_app_quotasUpSql,
+		"20190617090000_add_app_quotas.up.sql",
+	)
+}
+
+func _20190617090000_add_app_quotasUpSql() (*asset, error) {
+	bytes, err := _20190617090000_add_app_quotasUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190617090000_add_app_quotas.up.sql", size: 577, mode: os.FileMode(0644), modTime: time.Unix(1792367629, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe, 0x79, 0xbe, 0xf3, 0x61, 0x49, 0xb1, 0x91, 0x37, 0x95, 0x58, 0xe6, 0x1d, 0xb4, 0xc3, 0x91, 0x14, 0xcd, 0x66, 0xc8, 0x92, 0xa3, 0x42, 0xce, 0xc0, 0x83, 0xdc, 0xa9, 0x4c, 0x5f, 0x7, 0x9a}}
+	return a, nil
+}
+
+var __20190618090000_add_audit_logDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0a\x0d\x70\x71\x0c\x71\x55\x48\x2c\x28\xc8\xc9\x4c\x4e\x2c\xc9\xcc\xcf\x2b\xe6\x0a\x76\x0d\x51\x28\x4e\xce\x2f\x48\x55\xb0\x55\x48\x2c\x2a\x4a\xac\x8c\x2f\x4a\xcd\xcd\x2f\x4b\xd5\x00\x0b\xea\x28\xa8\x27\x96\xa6\x64\x96\xe8\xe6\xe4\xa7\xab\x6b\x5a\x73\x71\xb9\x04\xf9\x07\x28\x84\x04\x79\xba\xbb\xbb\x06\x29\x78\xba\x29\xb8\x46\x78\x06\x87\x04\x2b\x80\x15\xc5\xe7\xe4\xa7\xc7\x27\x16\x14\xa4\xe6\xa5\xc4\xe7\xe7\xe5\x54\x2a\xf8\xfb\x21\x24\xac\x21\x5a\xdd\x42\xfd\x9c\x43\x3c\xfd\xfd\x08\xe9\xd5\xd0\x84\x6a\x08\x71\x74\xf2\x71\xc5\xa6\xda\x9a\x0b\x30\x00\x07\xe4\xde\xbb\xcf\x00\x00\x00")
+
+func _20190618090000_add_audit_logDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190618090000_add_audit_logDownSql,
+		"20190618090000_add_audit_log.down.sql",
+	)
+}
+
+func _20190618090000_add_audit_logDownSql() (*asset, error) {
+	bytes, err := _20190618090000_add_audit_logDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190618090000_add_audit_log.down.sql", size: 207, mode: os.FileMode(0644), modTime: time.Unix(1792368099, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb8, 0x14, 0xea, 0x9d, 0x7e, 0x5b, 0x2e, 0x20, 0x5b, 0x1f, 0xb2, 0x8c, 0x30, 0xa8, 0x15, 0x59, 0x5f, 0x37, 0x12, 0xc, 0xe7, 0x43, 0xa0, 0x3b, 0x2f, 0xc8, 0xfb, 0x5c, 0xfd, 0xc6, 0x4a, 0xa1}}
+	return a, nil
+}
+
+var __20190618090000_add_audit_logUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x51\x6b\xdb\x3e\x14\xc5\xdf\xf5\x29\xce\x43\xc0\x36\x24\xf0\xff\x3f\x87\x3e\x28\xce\x8d\x23\xe6\xca\x41\x96\x97\x74\x63\x18\x11\x8b\xd4\xe0\xda\xae\xad\x6c\x2b\x63\xdf\x7d\x38\x75\x9b\x94\x36\xd0\xbc\xc5\xe7\xe8\xa7\x73\xcf\x55\xa8\x88\x6b\x82\xe6\x8b\x98\x20\x56\x90\x89\x06\xed\x44\xaa\x53\x98\x63\x51\xba\xbc\x6a\x0e\xf0\x19\x50\x16\x78\xfd\x2d\x44\x94\x92\x12\x3c\xc6\x46\x89\x5b\xae\xee\xf0\x85\xee\xa6\x0c\x30\x6d\x9b\x1f\x47\xe7\x57\xae\xc2\x35\x57\xfe\xff\xff\x05\x27\xaa\xcc\xe2\x78\xf0\x74\xf6\xf1\x68\x7b\x97\x0f\x36\x4d\x3b\xfd\x46\x7c\xb0\xee\xbe\x19\x6f\x7a\x27\x76\xcd\xd1\x59\x5c\x11\x5b\xe3\xee\x71\x4d\x74\xa6\x3b\x58\xd7\xbf\x8a\xdf\x7f\xbc\xca\x58\xd2\x8a\x67\xb1\x86\xf7\xe7\xaf\x37\x80\x7a\x67\xdc\xf1\xd9\x0a\x21\x35\x45\xa4\xde\xb0\xf6\x9d\x35\xce\x16\xb9\x71\x80\x16\xb7\x94\x6a\x7e\xbb\xc1\x56\xe8\xf5\xe9\x2f\xbe\x25\x92\xde\xd3\x65\xb2\xf5\x03\x16\xcc\x19\x1b\x1b\x17\x72\x49\xbb\x6b\x8d\xe7\x63\x91\x79\x59\xfc\x46\x22\x2f\x57\x31\x2a\x53\x94\x45\x30\xff\x1c\xec\x9c\xf8\x03\xde\x59\xfc\x2c\x6e\x2c\xf3\x3d\x2b\x4b\x85\x8c\x10\x09\x09\x7f\xf4\x0c\xf3\xce\x66\xe8\xec\xbe\xe9\x8a\x1e\xa6\xb3\xa8\xed\x4f\xdb\x61\x7f\x6f\xea\x83\x2d\xd0\xd4\x7b\x8b\x5f\x5d\xe9\x9c\xad\xa7\x68\xea\xea\x09\x85\xad\xac\x7b\x91\x5a\xd3\x3b\x74\xd6\xd9\xda\x95\x4d\xfd\x92\x2f\x51\x50\xb4\x89\x79\x48\x58\x65\x32\xd4\xe2\x32\xc6\xd0\x9d\xad\x8b\x7c\x80\xf9\x01\x14\xe9\x4c\xc9\x14\x5a\x89\x68\x58\x25\x4f\x31\x99\xb0\x05\x45\x42\x32\x40\x71\x91\x12\x68\x17\xd2\xe6\x44\xf1\xce\xd3\x94\xfd\xf0\x9c\x6d\x3d\x24\xa9\x9e\xbc\x39\x23\xb9\x9c\xb3\xc9\x04\x31\x97\x51\xc6\x23\x42\x5b\xb5\x87\xfe\xb1\x3a\xef\xf4\xe5\x8e\x0f\xb3\x30\x60\x41\xab\x44\x11\xb2\xcd\x72\x70\x5f\x86\x66\xc0\x2a\x51\x20\x1e\xae\xa1\x92\x2d\x68\x47\x61\xa6\x09\x1b\x95\x84\xb4\xcc\x14\x7d\xcc\xf4\x87\x82\x47\x9c\x69\xdb\xaa\xdc\x9b\xa1\xa7\x9e\xa5\xa4\xd1\xef\x9b\xd6\xe2\x06\xa6\xeb\xcc\xd3\x78\xca\x3f\x7d\x9c\x8e\x73\xce\xaa\xe6\xe0\x05\x6c\xbb\x26\x45\xf0\x1e\x4c\x6d\x0e\x76\x66\xda\xb6\xf7\x70\x03\x2e\xef\x9e\xdd\x01\xe3\x72\x79\x7a\x0a\x17\xa7\xde\x18\xe6\xec\xdf\x00\xac\x81\x9b\xf0\x47\x04\x00\x00")
+
+func _20190618090000_add_audit_logUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190618090000_add_audit_logUpSql,
+		"20190618090000_add_audit_log.up.sql",
+	)
+}
+
+func _20190618090000_add_audit_logUpSql() (*asset, error) {
+	bytes, err := _20190618090000_add_audit_logUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190618090000_add_audit_log.up.sql", size: 1095, mode: os.FileMode(0644), modTime: time.Unix(1792368099, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfb, 0xd7, 0x77, 0x53, 0xe, 0x96, 0x38, 0x34, 0x3d, 0x59, 0x26, 0x7e, 0x84, 0xba, 0x68, 0x4, 0x2e, 0x16, 0xa6, 0x29, 0x33, 0x61, 0x92, 0x56, 0xa, 0x3e, 0x58, 0x14, 0x31, 0xd3, 0x4a, 0xad}}
+	return a, nil
+}
+
+var __20190619090000_add_webhooks_scopeDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x41\x00\xbe\xff\x55\x50\x44\x41\x54\x45\x20\x61\x70\x70\x6c\x69\x63\x61\x74\x69\x6f\x6e\x73\x0a\x53\x45\x54\x20\x73\x63\x6f\x70\x65\x20\x3d\x20\x61\x72\x72\x61\x79\x5f\x72\x65\x6d\x6f\x76\x65\x28\x73\x63\x6f\x70\x65\x2c\x20\x27\x77\x65\x62\x68\x6f\x6f\x6b\x73\x27\x29\x3b\x0a\x03\x00\x11\x00\x2f\x97\x41\x00\x00\x00")
+
+func _20190619090000_add_webhooks_scopeDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190619090000_add_webhooks_scopeDownSql,
+		"20190619090000_add_webhooks_scope.down.sql",
+	)
+}
+
+func _20190619090000_add_webhooks_scopeDownSql() (*asset, error) {
+	bytes, err := _20190619090000_add_webhooks_scopeDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190619090000_add_webhooks_scope.down.sql", size: 65, mode: os.FileMode(0644), modTime: time.Unix(1792370051, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcf, 0xe4, 0xc3, 0xb1, 0xa0, 0x86, 0x9b, 0x9b, 0xdf, 0x21, 0xe0, 0xcf, 0x13, 0x19, 0x16, 0x57, 0x41, 0xe2, 0x27, 0x85, 0xe7, 0xd9, 0xc6, 0x62, 0x17, 0x1d, 0xc0, 0x10, 0xc8, 0x63, 0xa8, 0x25}}
+	return a, nil
+}
+
+var __20190619090000_add_webhooks_scopeUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8c\x41\x0a\xc2\x30\x14\x05\xf7\x39\xc5\xdb\xb5\x01\x6f\x20\x5d\x04\xfb\x45\x41\x53\x69\x23\xe2\xaa\xc4\x24\x60\x50\xfa\x3f\x8d\x20\xde\x5e\x14\x04\xb7\xc3\xcc\x1c\x0f\xad\x71\x04\x2f\x72\xcf\xc1\x3f\x32\x4f\x45\x0d\xe4\x50\x02\x4b\x42\x03\x3f\xcf\xfe\x35\x7a\x91\x34\xc5\xfa\x0b\x17\xa8\x9e\xe9\x72\x65\xbe\x95\x4a\xab\xd3\x86\x7a\x42\x8e\xd8\x5a\xd4\x03\xed\x68\xe5\x3e\xb3\x31\x47\xac\xfb\x6e\x8f\x9f\xaa\x95\xb1\x2d\x6c\xe7\xfe\x6a\x34\x30\xf6\x5c\x97\xc0\x92\xf4\x52\xbd\x07\x00\x6c\x2a\xbc\xba\x8b\x00\x00\x00")
+
+func _20190619090000_add_webhooks_scopeUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__20190619090000_add_webhooks_scopeUpSql,
+		"20190619090000_add_webhooks_scope.up.sql",
+	)
+}
+
+func _20190619090000_add_webhooks_scopeUpSql() (*asset, error) {
+	bytes, err := _20190619090000_add_webhooks_scopeUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "20190619090000_add_webhooks_scope.up.sql", size: 139, mode: os.FileMode(0644), modTime: time.Unix(1792370051, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc3, 0x53, 0x78, 0xd1, 0x64, 0xd8, 0xe6, 0xf0, 0xbd, 0xab, 0x1a, 0x92, 0xe4, 0xa5, 0xb8, 0xba, 0xe7, 0x9d, 0x1, 0x2a, 0xaf, 0xc1, 0xc0, 0xc0, 0x3d, 0x50, 0xe3, 0xf7, 0xee, 0x65, 0xd3, 0xd8}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -756,6 +1470,74 @@ var _bindata = map[string]func() (*asset, error){
 	"20190528213142_add_app_rate.down.sql": _20190528213142_add_app_rateDownSql,
 
 	"20190528213142_add_app_rate.up.sql": _20190528213142_add_app_rateUpSql,
+
+	"20190603101512_add_spatial_index_to_things.down.sql": _20190603101512_add_spatial_index_to_thingsDownSql,
+
+	"20190603101512_add_spatial_index_to_things.up.sql": _20190603101512_add_spatial_index_to_thingsUpSql,
+
+	"20190604093021_add_sample_range_to_channels.down.sql": _20190604093021_add_sample_range_to_channelsDownSql,
+
+	"20190604093021_add_sample_range_to_channels.up.sql": _20190604093021_add_sample_range_to_channelsUpSql,
+
+	"20190605141207_add_app_uid_to_location_changes.down.sql": _20190605141207_add_app_uid_to_location_changesDownSql,
+
+	"20190605141207_add_app_uid_to_location_changes.up.sql": _20190605141207_add_app_uid_to_location_changesUpSql,
+
+	"20190606112233_add_jobs_table.down.sql": _20190606112233_add_jobs_tableDownSql,
+
+	"20190606112233_add_jobs_table.up.sql": _20190606112233_add_jobs_tableUpSql,
+
+	"20190607103045_add_admin_scopes_to_applications.down.sql": _20190607103045_add_admin_scopes_to_applicationsDownSql,
+
+	"20190607103045_add_admin_scopes_to_applications.up.sql": _20190607103045_add_admin_scopes_to_applicationsUpSql,
+
+	"20190608093000_add_stream_events_table.down.sql": _20190608093000_add_stream_events_tableDownSql,
+
+	"20190608093000_add_stream_events_table.up.sql": _20190608093000_add_stream_events_tableUpSql,
+
+	"20190609091500_add_webhooks.down.sql": _20190609091500_add_webhooksDownSql,
+
+	"20190609091500_add_webhooks.up.sql": _20190609091500_add_webhooksUpSql,
+
+	"20190610090000_add_alerts.down.sql": _20190610090000_add_alertsDownSql,
+
+	"20190610090000_add_alerts.up.sql": _20190610090000_add_alertsUpSql,
+
+	"20190611090000_add_plant_statuses.down.sql": _20190611090000_add_plant_statusesDownSql,
+
+	"20190611090000_add_plant_statuses.up.sql": _20190611090000_add_plant_statusesUpSql,
+
+	"20190612090000_add_thing_hardware.down.sql": _20190612090000_add_thing_hardwareDownSql,
+
+	"20190612090000_add_thing_hardware.up.sql": _20190612090000_add_thing_hardwareUpSql,
+
+	"20190613090000_add_user_deletions.down.sql": _20190613090000_add_user_deletionsDownSql,
+
+	"20190613090000_add_user_deletions.up.sql": _20190613090000_add_user_deletionsUpSql,
+
+	"20190614090000_add_app_key_lifecycle.down.sql": _20190614090000_add_app_key_lifecycleDownSql,
+
+	"20190614090000_add_app_key_lifecycle.up.sql": _20190614090000_add_app_key_lifecycleUpSql,
+
+	"20190615090000_add_app_burst.down.sql": _20190615090000_add_app_burstDownSql,
+
+	"20190615090000_add_app_burst.up.sql": _20190615090000_add_app_burstUpSql,
+
+	"20190616090000_add_rate_limits.down.sql": _20190616090000_add_rate_limitsDownSql,
+
+	"20190616090000_add_rate_limits.up.sql": _20190616090000_add_rate_limitsUpSql,
+
+	"20190617090000_add_app_quotas.down.sql": _20190617090000_add_app_quotasDownSql,
+
+	"20190617090000_add_app_quotas.up.sql": _20190617090000_add_app_quotasUpSql,
+
+	"20190618090000_add_audit_log.down.sql": _20190618090000_add_audit_logDownSql,
+
+	"20190618090000_add_audit_log.up.sql": _20190618090000_add_audit_logUpSql,
+
+	"20190619090000_add_webhooks_scope.down.sql": _20190619090000_add_webhooks_scopeDownSql,
+
+	"20190619090000_add_webhooks_scope.up.sql": _20190619090000_add_webhooks_scopeUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -825,6 +1607,40 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"20190311125125_add_location_audit_table.up.sql":            &bintree{_20190311125125_add_location_audit_tableUpSql, map[string]*bintree{}},
 	"20190528213142_add_app_rate.down.sql":                      &bintree{_20190528213142_add_app_rateDownSql, map[string]*bintree{}},
 	"20190528213142_add_app_rate.up.sql":                        &bintree{_20190528213142_add_app_rateUpSql, map[string]*bintree{}},
+	"20190603101512_add_spatial_index_to_things.down.sql":       &bintree{_20190603101512_add_spatial_index_to_thingsDownSql, map[string]*bintree{}},
+	"20190603101512_add_spatial_index_to_things.up.sql":         &bintree{_20190603101512_add_spatial_index_to_thingsUpSql, map[string]*bintree{}},
+	"20190604093021_add_sample_range_to_channels.down.sql":      &bintree{_20190604093021_add_sample_range_to_channelsDownSql, map[string]*bintree{}},
+	"20190604093021_add_sample_range_to_channels.up.sql":        &bintree{_20190604093021_add_sample_range_to_channelsUpSql, map[string]*bintree{}},
+	"20190605141207_add_app_uid_to_location_changes.down.sql":   &bintree{_20190605141207_add_app_uid_to_location_changesDownSql, map[string]*bintree{}},
+	"20190605141207_add_app_uid_to_location_changes.up.sql":     &bintree{_20190605141207_add_app_uid_to_location_changesUpSql, map[string]*bintree{}},
+	"20190606112233_add_jobs_table.down.sql":                    &bintree{_20190606112233_add_jobs_tableDownSql, map[string]*bintree{}},
+	"20190606112233_add_jobs_table.up.sql":                      &bintree{_20190606112233_add_jobs_tableUpSql, map[string]*bintree{}},
+	"20190607103045_add_admin_scopes_to_applications.down.sql":  &bintree{_20190607103045_add_admin_scopes_to_applicationsDownSql, map[string]*bintree{}},
+	"20190607103045_add_admin_scopes_to_applications.up.sql":    &bintree{_20190607103045_add_admin_scopes_to_applicationsUpSql, map[string]*bintree{}},
+	"20190608093000_add_stream_events_table.down.sql":           &bintree{_20190608093000_add_stream_events_tableDownSql, map[string]*bintree{}},
+	"20190608093000_add_stream_events_table.up.sql":             &bintree{_20190608093000_add_stream_events_tableUpSql, map[string]*bintree{}},
+	"20190609091500_add_webhooks.down.sql":                      &bintree{_20190609091500_add_webhooksDownSql, map[string]*bintree{}},
+	"20190609091500_add_webhooks.up.sql":                        &bintree{_20190609091500_add_webhooksUpSql, map[string]*bintree{}},
+	"20190610090000_add_alerts.down.sql":                        &bintree{_20190610090000_add_alertsDownSql, map[string]*bintree{}},
+	"20190610090000_add_alerts.up.sql":                          &bintree{_20190610090000_add_alertsUpSql, map[string]*bintree{}},
+	"20190611090000_add_plant_statuses.down.sql":                &bintree{_20190611090000_add_plant_statusesDownSql, map[string]*bintree{}},
+	"20190611090000_add_plant_statuses.up.sql":                  &bintree{_20190611090000_add_plant_statusesUpSql, map[string]*bintree{}},
+	"20190612090000_add_thing_hardware.down.sql":                &bintree{_20190612090000_add_thing_hardwareDownSql, map[string]*bintree{}},
+	"20190612090000_add_thing_hardware.up.sql":                  &bintree{_20190612090000_add_thing_hardwareUpSql, map[string]*bintree{}},
+	"20190613090000_add_user_deletions.down.sql":                &bintree{_20190613090000_add_user_deletionsDownSql, map[string]*bintree{}},
+	"20190613090000_add_user_deletions.up.sql":                  &bintree{_20190613090000_add_user_deletionsUpSql, map[string]*bintree{}},
+	"20190614090000_add_app_key_lifecycle.down.sql":             &bintree{_20190614090000_add_app_key_lifecycleDownSql, map[string]*bintree{}},
+	"20190614090000_add_app_key_lifecycle.up.sql":               &bintree{_20190614090000_add_app_key_lifecycleUpSql, map[string]*bintree{}},
+	"20190615090000_add_app_burst.down.sql":                     &bintree{_20190615090000_add_app_burstDownSql, map[string]*bintree{}},
+	"20190615090000_add_app_burst.up.sql":                       &bintree{_20190615090000_add_app_burstUpSql, map[string]*bintree{}},
+	"20190616090000_add_rate_limits.down.sql":                   &bintree{_20190616090000_add_rate_limitsDownSql, map[string]*bintree{}},
+	"20190616090000_add_rate_limits.up.sql":                     &bintree{_20190616090000_add_rate_limitsUpSql, map[string]*bintree{}},
+	"20190617090000_add_app_quotas.down.sql":                    &bintree{_20190617090000_add_app_quotasDownSql, map[string]*bintree{}},
+	"20190617090000_add_app_quotas.up.sql":                      &bintree{_20190617090000_add_app_quotasUpSql, map[string]*bintree{}},
+	"20190618090000_add_audit_log.down.sql":                     &bintree{_20190618090000_add_audit_logDownSql, map[string]*bintree{}},
+	"20190618090000_add_audit_log.up.sql":                       &bintree{_20190618090000_add_audit_logUpSql, map[string]*bintree{}},
+	"20190619090000_add_webhooks_scope.down.sql":                &bintree{_20190619090000_add_webhooks_scopeDownSql, map[string]*bintree{}},
+	"20190619090000_add_webhooks_scope.up.sql":                  &bintree{_20190619090000_add_webhooks_scopeUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
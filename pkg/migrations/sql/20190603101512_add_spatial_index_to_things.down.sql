@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS things_geography_idx;
+DROP INDEX IF EXISTS things_lat_long_idx;
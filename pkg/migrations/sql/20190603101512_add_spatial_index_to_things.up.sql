@@ -0,0 +1,13 @@
+CREATE INDEX IF NOT EXISTS things_lat_long_idx ON things (lat, long);
+
+-- when PostGIS is installed we also add a geography index used by the radius
+-- and polygon queries. The statement is executed dynamically so the migration
+-- still parses on databases without the extension.
+DO $body$
+BEGIN
+  IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis') THEN
+    EXECUTE 'CREATE INDEX IF NOT EXISTS things_geography_idx ON things
+      USING GIST ((ST_SetSRID(ST_MakePoint(long, lat), 4326)::geography))';
+  END IF;
+END;
+$body$ LANGUAGE plpgsql;
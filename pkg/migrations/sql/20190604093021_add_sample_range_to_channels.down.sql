@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS channels_thing_uid_idx;
+
+ALTER TABLE channels
+  DROP COLUMN IF EXISTS first_sample,
+  DROP COLUMN IF EXISTS last_sample;
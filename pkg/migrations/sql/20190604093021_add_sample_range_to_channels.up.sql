@@ -0,0 +1,15 @@
+ALTER TABLE channels
+  ADD COLUMN first_sample TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN last_sample TIMESTAMP WITH TIME ZONE;
+
+-- backfill from the thing's range except for the water tank level which is only
+-- meaningful for pot sensors; the indexer fills this in as real data arrives
+UPDATE channels c
+SET first_sample = t.first_sample,
+    last_sample = t.last_sample
+FROM things t, data_sources ds
+WHERE t.uid = c.thing_uid
+  AND ds.id = c.data_source_id
+  AND ds.name <> 'water_tank_level';
+
+CREATE INDEX IF NOT EXISTS channels_thing_uid_idx ON channels (thing_uid);
@@ -0,0 +1,18 @@
+CREATE OR REPLACE FUNCTION thing_location_audit() RETURNS TRIGGER AS $body$
+BEGIN
+  IF (TG_OP = 'UPDATE') THEN
+    IF (NEW.long IS DISTINCT FROM OLD.long OR NEW.lat IS DISTINCT FROM OLD.lat) THEN
+      INSERT INTO location_changes (thing_id, previous_long, previous_lat, new_long, new_lat)
+      VALUES (NEW.id, OLD.long, OLD.lat, NEW.long, NEW.lat);
+    END IF;
+  ELSE
+    INSERT INTO location_changes (thing_id, new_long, new_lat)
+    VALUES (NEW.id, NEW.long, NEW.lat);
+  END IF;
+  RETURN NULL;
+END;
+$body$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS location_changes_thing_id_idx;
+
+ALTER TABLE location_changes DROP COLUMN IF EXISTS app_uid;
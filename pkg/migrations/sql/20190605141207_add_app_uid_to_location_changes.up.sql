@@ -0,0 +1,22 @@
+ALTER TABLE location_changes ADD COLUMN app_uid VARCHAR(10);
+
+CREATE INDEX IF NOT EXISTS location_changes_thing_id_idx ON location_changes (thing_id, inserted_at);
+
+-- the app making a change is passed in via a transaction local setting, which
+-- is empty for changes made by the indexer
+CREATE OR REPLACE FUNCTION thing_location_audit() RETURNS TRIGGER AS $body$
+DECLARE
+  changed_by VARCHAR(10) := NULLIF(current_setting('kudzu.app_uid', true), '');
+BEGIN
+  IF (TG_OP = 'UPDATE') THEN
+    IF (NEW.long IS DISTINCT FROM OLD.long OR NEW.lat IS DISTINCT FROM OLD.lat) THEN
+      INSERT INTO location_changes (thing_id, previous_long, previous_lat, new_long, new_lat, app_uid)
+      VALUES (NEW.id, OLD.long, OLD.lat, NEW.long, NEW.lat, changed_by);
+    END IF;
+  ELSE
+    INSERT INTO location_changes (thing_id, new_long, new_lat, app_uid)
+    VALUES (NEW.id, NEW.long, NEW.lat, changed_by);
+  END IF;
+  RETURN NULL;
+END;
+$body$ LANGUAGE plpgsql;
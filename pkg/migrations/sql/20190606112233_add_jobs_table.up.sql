@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS jobs (
+  id           BIGSERIAL PRIMARY KEY,
+  queue        VARCHAR(100) NOT NULL CHECK (queue <> ''),
+  payload      JSONB NOT NULL DEFAULT '{}'::jsonb,
+  attempts     INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL DEFAULT 10,
+  run_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  locked_at    TIMESTAMP WITH TIME ZONE,
+  last_error   TEXT,
+  created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  completed_at TIMESTAMP WITH TIME ZONE,
+  failed_at    TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS jobs_pending_idx ON jobs (queue, run_at)
+  WHERE completed_at IS NULL AND failed_at IS NULL;
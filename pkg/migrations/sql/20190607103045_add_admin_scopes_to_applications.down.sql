@@ -0,0 +1,2 @@
+UPDATE applications
+SET scope = array_remove(array_remove(scope, 'delete-users'), 'update-locations');
@@ -0,0 +1,9 @@
+UPDATE applications
+SET scope = array_append(scope, 'delete-users')
+WHERE 'create-users' = ANY(scope)
+AND NOT 'delete-users' = ANY(scope);
+
+UPDATE applications
+SET scope = array_append(scope, 'update-locations')
+WHERE 'create-users' = ANY(scope)
+AND NOT 'update-locations' = ANY(scope);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS stream_events (
+  id         BIGSERIAL PRIMARY KEY,
+  thing_uid  TEXT NOT NULL,
+  payload    JSONB NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS stream_events_thing_uid_idx ON stream_events (thing_uid, id);
+CREATE INDEX IF NOT EXISTS stream_events_created_at_idx ON stream_events (created_at);
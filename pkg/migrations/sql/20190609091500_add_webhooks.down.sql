@@ -0,0 +1,4 @@
+ALTER TABLE identities DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE things DROP COLUMN IF EXISTS stale_at;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
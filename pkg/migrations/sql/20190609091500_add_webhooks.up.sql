@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+  id         BIGSERIAL PRIMARY KEY,
+  uid        VARCHAR(10) NOT NULL UNIQUE,
+  app_id     INTEGER NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+  url        TEXT NOT NULL CHECK (url <> ''),
+  secret     TEXT NOT NULL,
+  events     TEXT[] NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_app_id_idx ON webhooks (app_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id           BIGSERIAL PRIMARY KEY,
+  uid          VARCHAR(20) NOT NULL UNIQUE,
+  webhook_id   BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event        TEXT NOT NULL,
+  payload      JSONB NOT NULL,
+  attempts     INTEGER NOT NULL DEFAULT 0,
+  status_code  INTEGER,
+  last_error   TEXT,
+  created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  attempted_at TIMESTAMP WITH TIME ZONE,
+  delivered_at TIMESTAMP WITH TIME ZONE,
+  failed_at    TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id);
+
+-- things already stale when webhooks were introduced are not announced
+ALTER TABLE things ADD COLUMN IF NOT EXISTS stale_at TIMESTAMP WITH TIME ZONE;
+UPDATE things SET stale_at = NOW() WHERE last_sample < NOW() - interval '30 days';
+
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id         BIGSERIAL PRIMARY KEY,
+  uid        VARCHAR(10) NOT NULL UNIQUE,
+  app_id     INTEGER NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+  thing_id   INTEGER REFERENCES things(id) ON DELETE CASCADE,
+  owner_id   INTEGER REFERENCES users(id) ON DELETE CASCADE,
+  channel    TEXT NOT NULL,
+  comparator TEXT NOT NULL CHECK (comparator IN ('lt', 'lte', 'gt', 'gte')),
+  threshold  DOUBLE PRECISION NOT NULL,
+  duration   INTEGER NOT NULL DEFAULT 0 CHECK (duration >= 0),
+  cooldown   INTEGER NOT NULL DEFAULT 0 CHECK (cooldown >= 0),
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  CHECK ((thing_id IS NULL) <> (owner_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS alert_rules_app_id_idx ON alert_rules (app_id);
+CREATE INDEX IF NOT EXISTS alert_rules_thing_id_idx ON alert_rules (thing_id);
+CREATE INDEX IF NOT EXISTS alert_rules_owner_id_idx ON alert_rules (owner_id);
+
+CREATE TABLE IF NOT EXISTS alerts (
+  id            BIGSERIAL PRIMARY KEY,
+  rule_id       BIGINT NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+  thing_id      INTEGER NOT NULL REFERENCES things(id) ON DELETE CASCADE,
+  state         TEXT NOT NULL CHECK (state IN ('pending', 'firing', 'resolved')),
+  value         DOUBLE PRECISION NOT NULL,
+  pending_since TIMESTAMP WITH TIME ZONE,
+  fired_at      TIMESTAMP WITH TIME ZONE,
+  resolved_at   TIMESTAMP WITH TIME ZONE,
+  notified_at   TIMESTAMP WITH TIME ZONE,
+  updated_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  UNIQUE (rule_id, thing_id)
+);
+
+CREATE INDEX IF NOT EXISTS alerts_state_idx ON alerts (state);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS plant_statuses (
+  id              BIGSERIAL PRIMARY KEY,
+  thing_id        INTEGER NOT NULL REFERENCES things(id) ON DELETE CASCADE,
+  variable        TEXT NOT NULL,
+  status_key      TEXT NOT NULL DEFAULT '',
+  instruction_key TEXT NOT NULL DEFAULT '',
+  min_threshold   DOUBLE PRECISION,
+  max_threshold   DOUBLE PRECISION,
+  current_value   DOUBLE PRECISION,
+  status_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+  UNIQUE (thing_id, variable)
+);
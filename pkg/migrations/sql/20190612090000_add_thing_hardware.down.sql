@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS thing_hardware_audit ON things;
+DROP FUNCTION IF EXISTS thing_hardware_audit();
+DROP TABLE IF EXISTS hardware_changes;
+
+DROP INDEX IF EXISTS things_firmware_version_idx;
+
+ALTER TABLE things
+  DROP COLUMN IF EXISTS firmware_version,
+  DROP COLUMN IF EXISTS hardware_revision,
+  DROP COLUMN IF EXISTS sensor_type,
+  DROP COLUMN IF EXISTS calibration_data,
+  DROP COLUMN IF EXISTS is_indoor,
+  DROP COLUMN IF EXISTS in_pot,
+  DROP COLUMN IF EXISTS plant_ids,
+  DROP COLUMN IF EXISTS autowatering_mode;
@@ -0,0 +1,60 @@
+ALTER TABLE things
+  ADD COLUMN IF NOT EXISTS firmware_version TEXT,
+  ADD COLUMN IF NOT EXISTS hardware_revision TEXT,
+  ADD COLUMN IF NOT EXISTS sensor_type TEXT,
+  ADD COLUMN IF NOT EXISTS calibration_data TEXT,
+  ADD COLUMN IF NOT EXISTS is_indoor BOOLEAN,
+  ADD COLUMN IF NOT EXISTS in_pot BOOLEAN,
+  ADD COLUMN IF NOT EXISTS plant_ids BIGINT[],
+  ADD COLUMN IF NOT EXISTS autowatering_mode TEXT;
+
+CREATE INDEX IF NOT EXISTS things_firmware_version_idx ON things (firmware_version);
+
+CREATE TABLE IF NOT EXISTS hardware_changes (
+  id                BIGSERIAL PRIMARY KEY,
+  thing_id          INTEGER NOT NULL REFERENCES things(id) ON DELETE CASCADE,
+  inserted_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  firmware_version  TEXT,
+  hardware_revision TEXT,
+  sensor_type       TEXT,
+  calibration_data  TEXT,
+  is_indoor         BOOLEAN,
+  in_pot            BOOLEAN,
+  plant_ids         BIGINT[],
+  autowatering_mode TEXT
+);
+
+CREATE INDEX IF NOT EXISTS hardware_changes_thing_id_idx ON hardware_changes (thing_id, inserted_at);
+
+-- records the hardware of a thing each time any of it changes, things indexed
+-- before the hardware was stored get their first entry when it is filled in
+CREATE OR REPLACE FUNCTION thing_hardware_audit() RETURNS TRIGGER AS $body$
+BEGIN
+  IF (TG_OP = 'UPDATE') THEN
+    IF (NEW.firmware_version IS NOT DISTINCT FROM OLD.firmware_version AND
+        NEW.hardware_revision IS NOT DISTINCT FROM OLD.hardware_revision AND
+        NEW.sensor_type IS NOT DISTINCT FROM OLD.sensor_type AND
+        NEW.calibration_data IS NOT DISTINCT FROM OLD.calibration_data AND
+        NEW.is_indoor IS NOT DISTINCT FROM OLD.is_indoor AND
+        NEW.in_pot IS NOT DISTINCT FROM OLD.in_pot AND
+        NEW.plant_ids IS NOT DISTINCT FROM OLD.plant_ids AND
+        NEW.autowatering_mode IS NOT DISTINCT FROM OLD.autowatering_mode) THEN
+      RETURN NULL;
+    END IF;
+  ELSIF (NEW.firmware_version IS NULL AND NEW.sensor_type IS NULL) THEN
+    RETURN NULL;
+  END IF;
+
+  INSERT INTO hardware_changes (thing_id, firmware_version, hardware_revision, sensor_type,
+    calibration_data, is_indoor, in_pot, plant_ids, autowatering_mode)
+  VALUES (NEW.id, NEW.firmware_version, NEW.hardware_revision, NEW.sensor_type,
+    NEW.calibration_data, NEW.is_indoor, NEW.in_pot, NEW.plant_ids, NEW.autowatering_mode);
+
+  RETURN NULL;
+END;
+$body$ LANGUAGE plpgsql;
+
+CREATE TRIGGER thing_hardware_audit
+AFTER INSERT OR UPDATE ON things
+  FOR EACH ROW
+  EXECUTE PROCEDURE thing_hardware_audit();
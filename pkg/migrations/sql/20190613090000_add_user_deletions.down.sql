@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS user_deletions;
+
+-- this fails while any anonymised things remain
+ALTER TABLE things
+  ALTER COLUMN owner_id SET NOT NULL;
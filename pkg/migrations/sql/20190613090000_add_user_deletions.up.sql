@@ -0,0 +1,15 @@
+-- anonymised things are kept without an owner
+ALTER TABLE things
+  ALTER COLUMN owner_id DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_deletions (
+  id           BIGSERIAL PRIMARY KEY,
+  user_uid     VARCHAR(250) NOT NULL,
+  mode         TEXT NOT NULL CHECK (mode IN ('purge', 'anonymise')),
+  thing_uids   TEXT[] NOT NULL DEFAULT '{}',
+  job_id       BIGINT REFERENCES jobs(id) ON DELETE SET NULL,
+  created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS user_deletions_user_uid_idx ON user_deletions (user_uid);
@@ -0,0 +1,9 @@
+UPDATE applications
+SET scope = array_remove(scope, 'manage-apps');
+
+ALTER TABLE applications
+  DROP COLUMN IF EXISTS revoked_at,
+  DROP COLUMN IF EXISTS expires_at,
+  DROP COLUMN IF EXISTS last_used_at,
+  DROP COLUMN IF EXISTS previous_key_hash,
+  DROP COLUMN IF EXISTS previous_key_expires_at;
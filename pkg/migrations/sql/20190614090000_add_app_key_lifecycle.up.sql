@@ -0,0 +1,11 @@
+ALTER TABLE applications
+  ADD COLUMN revoked_at TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN last_used_at TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN previous_key_hash VARCHAR(250),
+  ADD COLUMN previous_key_expires_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE applications
+SET scope = array_append(scope, 'manage-apps')
+WHERE 'create-users' = ANY(scope)
+AND NOT 'manage-apps' = ANY(scope);
@@ -0,0 +1,3 @@
+ALTER TABLE applications
+  DROP CONSTRAINT IF EXISTS applications_rate_check,
+  DROP COLUMN IF EXISTS burst;
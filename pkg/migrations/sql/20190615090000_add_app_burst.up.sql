@@ -0,0 +1,3 @@
+ALTER TABLE applications
+  ADD COLUMN burst INTEGER CHECK (burst > 0),
+  ADD CONSTRAINT applications_rate_check CHECK (rate > 0);
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS rate_limit(VARCHAR, TIMESTAMP WITH TIME ZONE, INTERVAL, INTEGER);
+
+DROP TABLE IF EXISTS rate_limits;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS rate_limits (
+  key VARCHAR(250) PRIMARY KEY,
+  tat TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- rate_limit implements the generic cell rate algorithm, where tat is the
+-- theoretical arrival time of the next request given the requests allowed so
+-- far. A request is allowed if it wouldn't push the tat more than burst
+-- emission intervals past now. The time is passed in rather than read from the
+-- clock so that every replica applies limits by the same rules as its own
+-- clock, and so that tests can control it.
+CREATE OR REPLACE FUNCTION rate_limit(limit_key VARCHAR, now_at TIMESTAMP WITH TIME ZONE, emission INTERVAL, burst INTEGER, OUT allowed BOOLEAN, OUT tat TIMESTAMP WITH TIME ZONE) AS $body$
+DECLARE
+  current_tat TIMESTAMP WITH TIME ZONE;
+  new_tat TIMESTAMP WITH TIME ZONE;
+BEGIN
+  INSERT INTO rate_limits (key, tat) VALUES (limit_key, now_at) ON CONFLICT (key) DO NOTHING;
+
+  SELECT rl.tat INTO current_tat FROM rate_limits rl WHERE rl.key = limit_key FOR UPDATE;
+
+  new_tat := GREATEST(current_tat, now_at) + emission;
+  allowed := new_tat - emission * burst <= now_at;
+
+  IF allowed THEN
+    UPDATE rate_limits SET tat = new_tat WHERE key = limit_key;
+    tat := new_tat;
+  ELSE
+    tat := current_tat;
+  END IF;
+END;
+$body$ LANGUAGE plpgsql;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS app_usage;
+DROP TABLE IF EXISTS app_quotas;
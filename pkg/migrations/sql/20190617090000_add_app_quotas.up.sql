@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS app_quotas (
+  id         SERIAL PRIMARY KEY,
+  app_id     INTEGER NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+  scope      TEXT NOT NULL,
+  period     TEXT NOT NULL CHECK (period IN ('day', 'month')),
+  quota      INTEGER NOT NULL CHECK (quota > 0),
+  UNIQUE (app_id, scope, period)
+);
+
+CREATE TABLE IF NOT EXISTS app_usage (
+  app_id     INTEGER NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+  scope      TEXT NOT NULL,
+  day        DATE NOT NULL,
+  requests   BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (app_id, scope, day)
+);
@@ -0,0 +1,6 @@
+UPDATE applications
+SET scope = array_remove(scope, 'audit-log');
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+  id          BIGSERIAL PRIMARY KEY,
+  app_uid     VARCHAR(10) NOT NULL,
+  request_id  TEXT NOT NULL,
+  method      TEXT NOT NULL,
+  route       TEXT NOT NULL,
+  path        TEXT NOT NULL,
+  targets     TEXT[] NOT NULL DEFAULT '{}',
+  status      INTEGER NOT NULL,
+  created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_app_uid_idx ON audit_log (app_uid, id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_targets_idx ON audit_log USING GIN (targets);
+
+-- records are never changed once written, only deleted once past retention
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+  BEFORE UPDATE ON audit_log
+  FOR EACH ROW EXECUTE PROCEDURE audit_log_append_only();
+
+UPDATE applications
+SET scope = array_append(scope, 'audit-log')
+WHERE 'manage-apps' = ANY(scope)
+AND NOT 'audit-log' = ANY(scope);
@@ -0,0 +1,2 @@
+UPDATE applications
+SET scope = array_remove(scope, 'webhooks');
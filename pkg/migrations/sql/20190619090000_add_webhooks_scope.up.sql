@@ -0,0 +1,4 @@
+UPDATE applications
+SET scope = array_append(scope, 'webhooks')
+WHERE id IN (SELECT app_id FROM webhooks)
+AND NOT 'webhooks' = ANY(scope);
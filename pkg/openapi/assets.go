@@ -0,0 +1,271 @@
+// Code generated by go-bindata. DO NOT EDIT.
+// sources:
+// spec/openapi.json (117.024kB)
+
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func bindataRead(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, gz)
+	clErr := gz.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %v", name, err)
+	}
+	if clErr != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type asset struct {
+	bytes  []byte
+	info   os.FileInfo
+	digest [sha256.Size]byte
+}
+
+type bindataFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (fi bindataFileInfo) Name() string {
+	return fi.name
+}
+func (fi bindataFileInfo) Size() int64 {
+	return fi.size
+}
+func (fi bindataFileInfo) Mode() os.FileMode {
+	return fi.mode
+}
+func (fi bindataFileInfo) ModTime() time.Time {
+	return fi.modTime
+}
+func (fi bindataFileInfo) IsDir() bool {
+	return false
+}
+func (fi bindataFileInfo) Sys() interface{} {
+	return nil
+}
+
+var _openapiJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x7d\x7b\x73\x1b\x37\xb2\xef\xff\xfa\x14\x7d\xb9\xa7\x2a\xbb\x75\x28\x5a\x56\x9c\x5b\x6b\xdf\x73\xcf\x2d\xc5\x4a\x1c\xdf\x75\xe2\xac\x24\x27\x95\xb2\xbd\x19\x90\xd3\x24\xb1\x1a\x02\x13\x00\x23\x8a\x49\xe9\xbb\x9f\x6a\x3c\x66\x30\x43\x0e\x5f\x92\x6c\x4a\xa1\x95\xda\xe5\xbc\xf0\x68\x74\xff\xd0\xdd\x68\x34\xfe\x38\x00\xe8\xc8\x1c\x05\xcb\x79\xe7\x05\x74\xbe\xec\x1d\xf5\x8e\x3b\x5d\xba\xcb\xc5\x50\x76\x5e\x00\xbd\x01\xd0\x31\xdc\x64\x48\x6f\xfc\xa3\x48\x7f\x2f\xec\x1b\x00\x9d\x14\xf5\x40\xf1\xdc\x70\x29\xe8\xd9\x77\xb3\x54\xc9\x1f\xd0\xc0\x40\x4e\x72\x66\x78\x3f\x43\x38\xf9\xf1\x35\x0c\xa5\x02\x33\x46\x78\x75\xf6\xf6\x67\x78\xdb\xd7\xa8\xae\x98\x91\x6a\xd6\x83\x53\xbc\xe2\x03\xd4\xf0\xd7\x4c\x0e\x18\x15\xa3\xff\x06\x4c\x21\xf0\x14\x85\xe1\x43\x8e\x29\x20\x37\x63\x54\xd0\x9f\x51\x11\x5c\x41\x9f\x9e\x5f\x8c\xb9\x18\x0d\x8b\x0c\xde\xbd\x3e\xed\x02\xf6\x46\x3d\x48\x8e\xf3\xeb\xdf\x2e\x9f\x25\x5d\x90\xf6\x6d\x06\xa1\xcc\xaa\x34\x05\xd3\x31\x1f\x8c\x21\x57\x38\xe4\xd7\xa8\xa9\x48\x2a\x02\xa6\xdc\x8c\x21\x79\xa5\xe4\xb4\x17\x8a\xfe\x4b\x12\x0a\xae\xdf\xf6\xd5\xf4\xe0\x27\xa6\x38\xeb\x67\xa8\x9b\x2d\xb6\x95\x5f\xf9\xa7\x30\x90\x29\x2e\xaa\x56\xb0\x09\x82\x1c\xda\x26\xa4\xcc\x30\xd0\xb2\x50\x03\xf4\x4d\x09\xd5\xf5\x5e\x4a\x21\x70\x60\xa4\xd2\x3d\x22\xdf\x39\x0a\x4d\xbf\xcb\xc6\xad\x7a\x91\x71\xf5\xab\xc1\x49\x8e\x8a\x99\x42\x61\xd2\x83\x0b\x3e\x41\x6d\xd8\x24\x77\x0d\x7f\x77\xf1\x12\x52\x66\x10\x0c\xdd\x0f\x2d\x1a\x4a\x35\x61\x06\x92\x5f\x7e\xf9\xe5\x97\xef\xbf\x3f\x3d\x1d\x8f\x27\x13\xad\xcb\x5a\x8f\x8f\x9e\x3e\x3f\xfa\xf2\xf8\xf9\x91\xfd\x97\xf4\xe0\x9b\x2b\x54\x33\x50\xa8\x73\x29\x34\x82\x91\xc0\x04\xb0\xc2\x8c\x69\x1c\x07\xcc\x60\x0a\x0a\x7f\x2b\x50\x1b\x18\x33\x0d\xc9\x19\x33\xf8\x86\x4f\xb8\x39\xb4\xff\x9b\x74\xe3\x5b\x67\x38\x61\x5c\x70\x31\x4a\x80\x89\xb4\xfe\x44\xa3\x49\x60\x8c\x2c\x45\xa5\xc1\xf1\x5f\x9f\x8b\x91\x25\xa3\xa2\x6e\x64\x54\x5e\xe8\x06\x71\xdf\x25\xce\xba\xb6\x1c\xdf\x00\x0d\x13\x36\x03\x96\x69\x09\x7d\xff\xba\x1b\xb4\x94\xf1\x6c\x46\xdc\x33\x91\xc2\x8c\xb3\x19\xfc\x56\x48\xc3\xb4\x65\x5f\x64\x83\x31\xe8\x81\xcc\xb1\x17\x98\xff\x0a\x95\xf6\x8c\xff\xb4\x77\xd4\x3b\xea\x1c\x00\xdc\xd0\xb3\x0e\x31\x38\x2a\xdd\x79\x01\xef\xed\xab\x4e\x86\x00\x3a\x85\xca\x48\x4e\x9e\x90\xb4\xd9\x7b\x37\x07\x00\x1f\xfd\x37\x83\x42\x71\x33\x9b\xff\xa8\x8f\x4c\xa1\x3a\x29\xcc\x98\x9e\x7d\x6c\x7c\x97\x33\x33\xd6\x95\x9c\x3e\x29\x34\xaa\x27\x02\xa7\xe5\x2d\x7a\x47\x6a\x13\x5d\x3b\x91\x57\x56\xde\x5e\xa7\xd4\xa0\x81\x42\x66\xf0\x9d\x46\xe5\x3b\x47\xff\x75\x74\x31\x99\x30\x45\x2d\xea\x9c\xe1\x88\x6b\x83\x0a\x18\x50\x05\x96\x9c\xda\x30\x65\x80\x8b\x14\xaf\xfd\x00\x70\x05\xa9\x13\xe8\xb8\x98\x06\x48\x9c\xe1\x6f\x05\x57\x5e\x08\x12\x57\xf3\x21\x15\xaa\x13\x4f\x60\xb8\x18\x23\xfc\xc8\x94\x92\x06\xd8\x60\x20\x0b\x51\x8e\x27\xbd\x07\x5c\x83\x42\x96\x02\x9f\x4c\x30\xe5\xcc\x60\xe6\x07\xb8\xd6\x04\xcb\xdc\xb6\x75\x98\x02\x17\xf6\xf3\x3e\x1b\x5c\x8e\x94\x2c\x44\x1a\x46\x91\xfe\x3a\x9e\x31\xbe\x96\xe9\xac\x46\x26\xff\x88\x2b\x24\x2a\x19\x55\x60\xf5\x11\x40\x67\x20\x85\x41\x51\xa7\x2c\xfd\x75\x58\x9e\x67\xdc\x61\xcf\x93\x7f\x6b\x29\xe6\xde\x20\xe2\x0e\xc6\x38\x61\x0b\x9e\x00\x74\xfe\x43\xe1\x90\xa8\xfe\x97\x27\x04\xa4\x52\xa0\x30\xfa\x89\xfb\x40\x3f\xa1\x51\x22\x1a\xa2\x36\x9d\xc6\xa7\x37\x07\x6d\x57\xd5\xef\x9b\x5a\xbf\x9d\xb8\x56\xfc\xe3\x1f\x1c\x1f\x1d\xcf\xb5\xac\x39\x8e\x17\x61\x38\xa6\x8c\xc6\xc3\xf1\x07\xa6\x11\x5d\x97\x12\x69\x3d\x32\x2d\x27\xd4\x7a\xa4\x72\x7d\x6c\xd2\xaa\x4e\x9f\xf9\xeb\xf8\x2a\xa2\x19\x40\xe7\xd9\xd1\xd3\xb9\xd6\x2c\x6e\x47\x49\xdf\x27\xef\x04\x61\xa1\x54\xfc\x77\x4c\x3b\x4b\x4a\xfe\x72\xe3\x92\xbf\x95\xaa\xcf\xd3\x14\xc5\x92\x62\x8f\x8f\x37\x2e\xf6\x9d\xc8\x95\x1c\xa0\xd6\x34\xc3\x7d\x23\x0c\x21\xd3\x92\x0a\x9e\x6f\x5c\xc1\x85\x94\xdf\x33\x31\xf3\x9c\xac\xdb\x0b\xff\xea\xe8\x78\xe3\xc2\xbf\x66\xe9\x2b\x66\x70\xca\xea\x8d\x3e\x68\xfe\xba\x39\x88\xea\xf3\xd8\x99\x62\x86\x06\xa3\x2a\x3b\x73\x77\xe6\x01\xd4\xbd\xb2\x04\x40\x4f\xed\x0b\x34\xb9\x30\x21\xc5\x6c\xc2\x35\xc6\x58\xba\x1d\x78\xba\x5a\x17\x80\xa7\x7d\x60\x35\x1f\x0d\x03\xa6\x14\x69\x25\xb2\x30\x34\xc9\xb1\x08\x04\xe1\xdf\xb2\xef\x55\x13\x8b\xa9\xc6\xbe\x58\x08\xc3\x33\xe0\x06\x74\x31\x18\x20\xa6\xda\x81\x2b\x37\x1a\x72\x25\x47\x0a\x35\x15\x2a\x68\xf2\x1c\xca\x2c\x93\x53\x4c\x81\x54\x85\x57\xdf\x5c\x80\x23\xe1\x1f\x05\x4f\x6f\x9e\x84\x46\x90\xca\xe1\xb1\xe2\x0b\x1d\x70\xdd\xc8\x4b\x14\x4e\x01\x51\x38\x91\x57\x58\x83\xf3\x1e\xbc\x16\x90\xe4\x85\x1a\x61\x02\x13\x52\xa0\xec\xfc\xeb\xe8\x43\x9d\xb2\x65\x63\x0a\x43\x25\x27\xa5\x32\x58\x4e\x02\x02\x90\xd4\x11\x43\xf7\x41\x1b\xa9\xa8\x85\x7d\x22\x40\x39\x87\xcc\x11\x9d\x4a\xf5\x2d\x71\xb5\x97\xe3\xe4\x5b\x40\x9f\x86\x77\xa3\xaf\x49\x73\xa3\x5e\x5c\x62\x6e\xba\xd0\x77\x75\x70\x05\x03\x29\x55\xca\x05\x33\x7e\x26\xb2\xb3\x0e\xa6\xa4\x19\x49\x41\x25\x0d\xf8\x84\x65\x90\x67\x6c\x80\x5d\xff\x8d\xe0\x83\x4b\x52\x0b\x35\xf4\x33\x26\x2e\x31\x0d\x0f\x4a\x4d\x76\xcc\xa9\x3b\xb3\xd0\xd2\xb2\xc7\x33\x5b\x47\x8a\x86\x0d\xc6\x81\x2c\x65\x5f\xfb\x38\x94\x0a\xab\xeb\xb8\xa7\x4e\x6f\x93\x5e\x27\x27\x96\x89\xfb\x39\x66\x57\x08\x79\xd1\xcf\xb8\xa6\x62\xb9\x9d\xb4\x05\x68\x14\x34\x82\x95\x16\xce\x46\x8c\x0b\x62\x02\x33\xae\x7a\x1a\x51\xe0\xe1\xce\xb6\xa7\xa5\x60\xef\xca\x9c\x5b\xca\xb6\x9b\x77\x6d\xa3\x76\x6f\xda\x3d\xf5\xad\xfc\x93\x4f\xbf\x76\xfa\x3d\x7a\xb6\x71\xb1\x3f\x48\xf3\x2d\x01\xc6\x23\x9a\xd4\x0f\x9a\x43\xbb\x68\xf2\x1d\x28\xb4\x76\x34\xcb\x62\xf9\xe8\xe4\xcc\x0c\xc6\x4b\x27\xe0\x22\x4f\xbd\x05\xf3\x32\x2a\x62\xf1\x5c\x7c\x86\x16\x75\xc1\x54\x66\x86\x9f\x8e\xe4\x90\xcc\x56\xbc\xe6\xda\x70\x31\xb2\x68\xb9\xf6\x64\xdc\x6a\xc9\x08\x9c\x92\x19\x43\xd3\xa6\xad\x06\x26\x85\x36\xd0\xc7\x4c\x92\xd5\x24\x6d\x33\x34\x9b\x94\x6d\x09\x26\x0f\xd3\xa5\xc9\xd3\x83\x13\x41\xa0\x7f\x25\x2f\x69\x26\x57\x30\x64\x3c\xc3\x14\xb4\x21\x8b\x97\x6b\x18\x64\x64\x24\xa6\x61\x3a\x88\x70\x3e\x58\x40\x4c\x83\x96\x52\x00\xd3\x90\x4b\xad\xc9\x37\xd3\x85\x4b\xc4\x9c\x3a\xca\xb2\xcc\xcf\x34\x65\xdf\xc9\x25\xb1\x37\x92\xee\xc2\x48\x8a\x78\x1a\xa6\xa8\x10\x1c\xaf\xee\x1c\x64\x47\x82\xb3\x47\xed\x3d\x6a\x3b\xd4\xfe\xac\xa6\x98\xb3\x23\x08\xe4\x8a\xda\x74\x30\x42\xb3\x74\x32\x18\xa1\x21\x00\x38\x77\x1f\x2e\x9e\x04\xce\xc7\x72\x4a\x98\x57\xd9\x34\x72\x58\x39\xb3\x58\x30\x57\xbc\x26\x7c\xbb\x59\xe0\x0c\x4d\xa1\x84\x7b\x83\x7a\x53\xfa\x7d\xeb\x36\x91\x73\x53\x9b\x99\x45\x71\x6a\xde\xa4\x18\x8c\xe9\xd5\xc8\xf8\xf9\x42\x97\x36\x00\xf9\x51\xfb\x88\xc2\x1a\x74\x43\x9e\x65\x58\xf7\x6a\xe5\x4c\xb1\x09\x9a\xd8\x11\xe9\xfe\x2a\xc2\xd1\x5f\x87\x2c\x0e\xa2\x48\xc1\xe7\x20\x89\xdb\x1e\x92\x8b\xb1\xf9\x64\x09\xe4\x2f\x46\x41\xf2\x49\x57\x4e\x72\x15\x53\xa0\x59\x76\x2b\x8e\x75\xcc\x2c\x27\xcb\xbc\xa3\x8d\xe2\x62\x14\x33\x54\xc5\x48\xf5\xdf\x1f\xd7\x43\xf2\xa3\xb9\xda\x16\xf5\xa1\x64\x0f\x1a\xc4\x42\x2f\xeb\xc3\xe7\x06\x73\xc7\xfb\x7b\x1c\xbf\x37\x1c\xff\x0c\xca\xb1\x83\x43\xbc\xce\xa5\x8a\x19\x6b\x25\x1c\xba\x2f\x96\x38\xa7\xbe\xb1\x2f\xc4\x4e\x93\x31\x66\xc1\x65\xc2\x9a\xfc\xbd\x1c\x00\x5d\x65\x2d\x00\xc8\xe0\x77\x9e\x03\x53\x83\x31\xbf\x22\xef\x11\x39\xbf\xf4\x14\x49\x98\x41\x17\xfd\x7f\xe3\xc0\x04\x55\xd9\xab\x9b\xba\x0b\x24\x4a\x6e\x39\x28\x94\xde\x23\x1d\x28\x71\x2b\x65\xe8\x17\x9e\x06\x52\xa5\x5d\x60\x56\xa9\xb6\x0f\x18\xbc\x3c\xff\x09\x86\x3c\x43\xc8\x51\x01\x41\x07\x09\xac\x7b\xf3\x05\x24\xd4\xc2\xa4\x0b\x89\x47\x5d\x8e\xb4\xba\x95\xd8\xee\xdb\x5f\x83\x31\x13\x02\x33\xfb\x3b\xf8\x5f\x7e\xa5\x9b\x23\xf7\xe6\x98\xa9\x74\xca\x14\xc6\xf7\xf2\x8c\x09\xf3\xab\x43\x07\x77\x87\x65\xa8\xcc\xaf\xaa\xc8\xa2\x4b\xfb\x6b\x8a\xfd\xb1\x94\x97\xbf\xa6\x98\xf1\x2b\x54\x54\xbd\xc5\xfd\x84\x15\x29\x37\xbf\xba\x66\xea\xa4\x07\x27\xce\x74\xa0\x67\x0a\x87\x0a\xf5\x38\x76\x9e\x09\xea\x3f\x38\xb2\x90\xbf\xa8\xea\x0d\x48\x91\x05\xc2\xc0\x74\x8c\x76\x15\xd5\xba\x8a\xc6\x32\xb3\x4e\xa3\x49\xaf\x5c\x8c\xa5\x95\x57\xeb\x42\xb2\x1f\x85\xe2\xfc\xa2\x2b\x24\x97\xb4\xe8\x6b\xf9\x40\xfb\xba\x12\x5a\xe3\x9d\x30\xb1\x9f\x72\xc2\x94\xd3\x5d\xdd\xdd\xc8\xcd\xa6\x17\xf7\xfb\xb7\x02\xd5\x6c\x49\xc7\x87\x2c\xd3\x2b\x7a\x5e\x1b\x52\xf2\xd3\xda\x21\xed\x63\xc9\x24\x4e\x6e\x56\x8d\x6b\x17\xb4\x84\x9c\x69\x4d\xb2\x99\xd0\xda\x57\xe2\x5c\xa4\x24\xa4\x75\x4d\x63\x4d\x0a\xf6\xa5\xcc\x90\x89\xc6\x87\x56\x5b\x18\xb2\x22\x33\xa1\x7b\xed\x24\xbe\x9f\x59\xdd\xd1\x25\xa0\xd2\x96\x13\xf9\xef\x3c\x5f\xf0\xca\x32\xba\x2c\xe2\xae\xee\xa2\x77\xdc\x52\x3c\xb5\xb6\xcf\x05\x21\xf6\xdc\x4b\x37\x07\xcb\xae\x6f\xda\x67\xb1\xbd\x51\xf6\x20\x8d\xb2\x83\xe6\xd0\xb6\x6b\x0b\xc1\x5b\xbc\x89\xbe\xe0\xcd\xa7\xe0\xc2\x6d\x51\x1a\x16\x1a\x50\xae\xba\xd2\x80\x8a\x3f\x5d\xaa\x35\x2c\x5c\xc9\x8a\xcd\xa6\x8c\x16\x52\x4c\xe5\xfc\xf6\xea\x01\xa6\x65\x70\x11\xc1\xfa\x7e\x32\xf2\x93\xd1\xfd\x20\x65\x3c\xd2\x26\x5a\x8a\xd8\x12\x33\xef\xcd\xf8\x09\x9c\xbb\x37\x7f\x1e\x89\xf9\x43\x52\x63\x66\x4f\xc8\x27\x7d\x6e\xa3\xe4\xca\xd8\xbb\x27\x75\x30\x5b\x1d\xea\x34\x42\x73\x3a\x5f\x4c\x0b\xc6\xbd\xe1\xda\xad\x69\x86\x68\x3e\xed\x15\x6a\x1f\x30\xb6\xa1\x73\x68\x82\x86\x51\x1f\x4a\x84\x73\x5e\x7d\x4b\x10\xe8\xcb\x74\x46\x1a\x16\x1f\x09\xa9\xea\x0a\xd6\xed\x24\xd7\x2d\xf1\x5e\x0a\x39\x15\x65\x3f\xe0\x12\x67\xae\x0f\xb4\xac\xcf\xd3\x5d\x92\xe0\x05\xc3\xf3\x27\x17\x64\x2b\xc8\x9f\x47\xe4\x82\xe5\xbb\x95\xa0\xbd\x09\x1f\x2f\x13\xaf\x5b\x0b\xd1\x6b\x5a\xb4\x9b\xd1\x9c\x94\x94\xb1\xa4\x2f\x0b\xa5\xa5\x22\x3b\xfb\x5c\x2a\xf3\xf5\x2c\xa1\x45\xb3\xe4\x14\xf5\x00\x45\xea\x02\x4b\x15\xc2\x88\x5f\xa1\x0b\x78\x08\x34\x23\x01\xcc\xd9\xc8\xaf\xa4\x71\x31\xc8\x8a\x14\x35\x24\x6f\x55\xea\xbc\x05\x17\xd2\xb0\xec\x25\x85\x24\x7a\x73\xfd\x07\xbc\x36\xbe\xb6\x2d\x96\xcb\xe6\xec\xb9\x56\xc9\x5b\x47\xee\x96\x49\xdd\x72\x99\x2b\x47\xca\xf3\x4a\xcc\x2a\x31\x93\xcc\x5f\xdd\x1c\x2c\xe0\xd7\xdb\x01\x56\x08\x0a\x9f\xd0\xaa\x6f\x19\xdb\xeb\x61\xb2\x04\xae\xc0\x99\x91\x52\xb5\x4b\x28\x16\x51\xf4\x4f\x8e\x5d\x84\x5d\x8f\xcc\xc0\x9a\x03\x47\xb7\xa4\xbb\x4d\xc8\x42\xe0\x93\x16\x88\xfc\x5e\x5e\x51\xa0\xa0\x03\xc9\xb5\x31\xd2\x15\x7d\x58\xb6\x2f\x60\xe5\xe6\x00\xd5\x34\x6e\x3e\x33\x3e\xbd\xb3\x1d\x8b\xc7\x2a\x1e\xa5\xf9\xab\x9b\x83\x05\x0c\x73\x3b\x74\x22\x43\xc8\xd1\x37\x9d\x1f\x96\x9d\x81\x9d\x26\x89\xea\x64\x99\xbf\x8e\xaf\x1e\x3a\xda\xec\x9d\x44\xeb\x38\x89\xe6\x30\xac\x5f\x64\x97\xdb\xe0\x18\x7d\xf7\xae\x86\x65\x6d\xfa\x9e\x05\xb3\x09\x29\x6b\x5e\xe9\xa3\x90\x51\x29\xee\x00\xd8\xe0\x1b\x5a\x22\x47\x61\x94\xb5\xa1\xae\x58\xc6\xa9\x41\xb4\xf1\x23\xe5\x57\x3c\x2d\x58\x16\x6d\x12\x01\x59\x98\x81\x9c\x60\xb9\xb6\x6e\x1d\xdb\xde\x3b\xee\xb7\x8a\x24\x67\xa8\x8b\xcc\xe8\x24\x38\x3b\x02\xd5\xc3\x5e\x12\x1b\xb2\x25\x49\x2f\x0c\x91\x5a\x1e\x51\x1f\x2e\xca\x7e\x5d\x64\x97\xe5\x18\xee\x08\xd4\x36\x87\xca\x73\xe8\x0e\x41\xee\x02\xaa\xed\x75\xbe\x3f\x81\xce\xe7\xe3\x70\xb6\x35\x8a\xbf\xf3\x9f\x2f\x86\x4a\xeb\x79\xa2\xb5\xdd\x19\xb8\xf5\xe5\x10\x29\x1a\xef\x2b\xa0\x70\x55\x0f\xa5\x5b\x9b\xcf\x0f\x16\xac\x1a\x74\xf4\x63\x1a\x0f\x69\x3c\x98\xf3\x57\x37\x07\x0b\xf8\xea\xf6\x78\x55\x8d\x4f\x19\xa8\x55\x7a\xcb\x69\xce\xeb\x82\xcc\x52\xf2\xf7\x0d\xb9\xd2\x66\x97\x70\x6c\x8e\xa0\x7b\xe7\xf9\x3d\x39\xcf\x1f\x3d\x32\xfa\xd0\x98\x6d\x21\xf2\x3b\xff\xbd\xe7\xc4\x4d\x21\x32\x44\xe6\xec\xf1\x71\x07\xf1\xb1\x1c\x9c\x07\x86\x8e\x0d\x9e\xdc\xa3\xe3\x1e\x1d\x37\x42\x47\xca\xae\x71\x6e\xe3\xfc\x5e\x0b\x17\xd1\xb3\xed\xaa\xca\xc5\xc2\x92\x96\x81\x24\x01\x5b\x08\x67\x5c\xb4\x80\x19\x87\x4d\xba\x6d\x40\x9a\x4d\xf2\x0c\x41\x11\xac\xea\xad\xb1\x13\x5e\x0f\x43\xfa\x96\x6a\x75\xc6\xae\xc4\xf8\x15\x93\x0d\x57\x61\x1e\xe9\xe2\xcb\xe2\x01\xdd\x05\xc0\x7e\x19\x98\x66\xf9\x52\xcc\x6e\xad\x1f\xb7\x91\x73\x6f\x92\x3f\x36\x93\xbc\xc2\xd4\xdb\xe1\x68\x0b\x76\x9e\x51\x92\x19\xaa\x03\xb4\x05\x6e\x4a\x93\xc4\x6e\x15\x00\x42\x85\x69\x1f\xec\xed\x21\xf2\xc4\xc0\x44\x6a\x03\x4f\x8f\xca\x5d\xfe\x2e\xd8\xfb\xe9\x11\xa4\x6c\x66\x8d\x7c\x5b\x2f\x25\x2d\xea\x97\xc2\x47\xd8\xe8\x7c\xa7\x0f\x57\x43\xad\x06\x60\x17\xb0\xee\xa2\x42\x36\x4c\xed\x50\xef\x26\xa8\xed\x81\xec\xf1\x01\x19\xcb\x73\xbd\x65\xd2\xae\x93\x3c\x6f\x81\xaf\x97\xf6\x39\x6d\x68\xf7\x59\xd0\x6c\xa8\x2c\x83\x41\xc6\x29\x7f\x47\xc4\xb2\x6b\xe3\xd7\x84\x09\x36\xc2\x43\x6a\x6e\x09\x60\x24\x37\x54\x38\x2f\x77\x9c\x50\xd4\x2e\x6d\x53\x17\x03\xb4\x48\x36\x60\x42\x48\xda\xf1\x6e\xb1\xf3\x0a\xe7\xa3\xdb\x1e\x10\x68\x9d\xe4\xf9\xfd\xa3\xd5\xd3\xb5\xd0\x8a\xe5\xb9\x4d\x02\xe2\x32\x0f\xec\x94\x0e\x66\xa9\xb4\xc7\xa9\xc7\x88\x53\x9b\xec\x24\xc8\xb8\x36\x27\x79\xde\xa6\x60\xd9\x45\x8e\x79\x38\xd2\xb7\xc2\xa3\x7f\xe0\x2c\xde\x1a\x17\xe0\xa8\x81\x38\x9e\x1a\xdb\x28\x0a\x2e\x9e\x96\x84\xaf\x6e\x36\x57\x5b\xbb\x29\xa6\xf6\x12\x67\x3b\x26\x90\x7b\xcd\x81\x34\x87\xcf\x30\xb1\x17\x9a\x8d\x70\x13\xb1\x19\xa1\x79\x3b\x15\x27\x79\xfe\xce\x7e\xb9\x58\x78\x5e\x61\x48\x67\xc6\x46\x65\x42\x01\xe2\xca\x09\xbb\x6c\x58\xea\x4b\xe5\xa9\xda\x63\x23\x8a\x49\xdf\xed\x4c\xf1\xdf\xe9\xb2\x4c\xca\x35\x30\x61\x94\x05\x4d\xa6\x6c\xe6\xe3\x28\xb8\x76\x99\x52\x1b\x09\x52\xeb\xbb\x70\x45\x48\xa3\xda\x83\x53\x32\x67\xe8\x53\xfb\x95\x4f\x9b\x2b\xe0\xdd\xc5\x4b\x4a\x91\x10\x6a\x94\xa0\x64\x41\xab\x9b\xd4\x08\x4e\xfb\x5c\x40\xc8\x50\xb2\xa6\x8c\x08\x36\xdc\xc2\xaa\x1b\xd8\xb5\x85\x90\x76\x61\x73\xe6\xdc\xa5\x98\x5f\x2c\xa0\xed\x8e\x49\xb4\x65\x8f\xbd\x54\x7f\x1e\xa9\xb6\xfb\xeb\x36\x8b\x94\x72\xb1\x2b\x4b\x34\x76\xbf\x8c\x55\x4b\x65\xdc\x75\xbc\xaf\xc9\x6f\x8a\xd7\x39\x77\x0b\xca\x4c\x34\xf8\x71\xf3\x89\x92\x9c\x7b\x23\x7a\x81\x5d\x22\xe0\x70\x48\x9b\xef\xcb\xc4\x81\x2c\xcf\xbf\xd0\x20\xf0\xda\x04\x30\x88\x64\xd4\x22\x81\x17\x6f\xda\x37\x4c\x6e\x05\xf2\x06\xfa\x74\x8e\x6e\x87\xb0\x97\x7f\x78\x76\xf4\x65\xe9\xd8\xdd\xe1\xfd\x79\x94\x05\xbc\x5d\xd0\xef\x6f\x4b\xde\x03\x32\x7d\xde\x05\xf6\xf5\x8c\x50\xef\x6c\xbd\xbb\x6d\x9d\xbf\xb9\x53\x78\xf6\x61\xb8\xbb\x87\xcc\xff\xc0\x7d\xfc\xc4\x3e\x7e\x62\xcd\xf8\x89\x6a\x3a\x79\xe2\x52\xfc\x6d\xe4\x06\x72\x9f\xb4\x4f\x2a\x67\xf6\xb9\x05\xb6\x4b\xbc\xab\xb9\xe3\x84\xb2\xe7\xbb\x9a\x49\xa5\x9b\xd1\xd1\x06\x57\x5c\x16\xd6\xee\x01\x6d\x78\x96\xb9\x20\x5c\x60\x43\x97\x36\x5e\x49\x63\x1b\x4d\xd3\xc8\x95\xb4\x1a\x6a\xe5\x17\x2a\x44\x2a\x05\xce\x65\xe9\x2f\x67\x99\x32\xf5\xe1\x7e\x92\xd9\x6a\x92\xf1\x5c\xba\x2d\xce\x06\xea\xef\x71\xf6\xcf\x85\xb3\x9f\x0f\x06\x09\x2c\x36\x84\x41\x69\x96\xea\xd6\xaf\x50\x10\x6c\xd2\x66\x2a\x4a\xc8\x5a\xba\xc3\x6f\x8f\x85\x24\x21\x83\x42\x29\x72\xaa\x53\xb1\xca\x9e\x5e\xe2\x37\x21\x94\xe9\x29\x3c\xac\x91\x5f\xfc\x0a\x55\xc6\x72\x9b\x60\xc7\x8c\x7d\xd2\x6c\xef\x05\xa3\x7c\x3d\x7a\xca\xcd\x60\x1c\x82\xd9\x42\x63\x49\x9b\xa6\x74\x5c\xa9\x9c\x0a\x5a\x46\xac\x72\xcb\x6e\xee\x7c\x77\x10\xec\x14\x27\x1d\xbf\x61\x69\x98\xee\x01\x74\x0e\x40\x1f\x52\x38\xc9\x59\x90\x04\x2f\x85\xf5\xde\xd6\xfb\xdb\xd6\xfb\x9b\x3b\x9d\x3e\x6c\x83\xd2\xa6\xcb\x94\xbc\xa4\x9e\x7f\xf7\xb3\xca\x9f\x68\x56\x79\xbc\xda\xfb\x36\x8e\xde\x4d\xbd\xbc\x77\xa0\xbc\xef\x88\xcf\x77\x3f\xcb\x34\x67\x19\xcf\x8e\xdb\xe2\xec\xde\x5b\xfd\x30\xbc\xd5\x8f\x4d\x5b\x77\xb2\xbe\xa5\x2b\xfc\x9f\xee\xe3\xc5\xe8\x77\xee\xd1\xaf\xed\x64\xbe\xbb\xc1\xc3\x73\x34\x2b\xc1\x90\xa2\xef\x26\xe4\x28\x5f\xb4\x3e\xc5\x5c\x49\x36\xcf\x6c\xca\xaa\x86\x76\xcb\xe5\xad\x93\xea\x58\xc2\xdf\x51\x49\x7f\xfe\x8e\x2b\xde\x92\xcf\xf9\x3e\x7c\xb7\x68\x3d\xcb\xe5\xa6\x21\xb8\xcc\x70\x68\xa0\x10\x14\xc0\x3d\xa2\x23\x7b\xde\x5a\xd5\xde\x91\x80\x36\x3b\x17\x9a\x0e\x6d\xca\xab\x36\x2f\x74\x90\x1c\x3f\x2f\x1d\x24\xfe\x84\x27\xaa\x3b\x47\xc5\x65\x0a\x28\x52\xbd\xd7\xfa\xb7\xd2\xfa\x9b\x3d\x68\x45\xd9\x75\x30\x76\x19\xc2\xae\xc4\x57\x27\x49\x5e\x98\xeb\x9d\xad\x77\xb7\xad\xf3\x37\x77\x35\x19\xb9\x08\x09\xc7\xa0\x3b\x3b\x1d\x05\x72\xed\xf5\xfc\xbd\x9e\xbf\x8e\x9e\x7f\x75\x6c\xf3\xaa\x86\x49\xcf\x25\x0a\xdf\x44\xd9\xa7\x60\x28\x4a\xc2\x6e\x4f\x4d\x5b\xb9\x5f\xc7\x95\x0f\x72\x2a\xc2\x89\xc2\x1b\x25\x54\x9d\xdb\x8a\xf3\x86\x5f\xa2\x3d\xe7\xe7\xea\xd8\x4f\x60\x5d\x3f\x21\xa4\x3a\x4c\x12\xff\xff\xfc\xed\x0f\x2f\x28\x58\x34\x95\x83\x62\x82\xc2\xd0\xb9\x43\x50\x08\x97\x3b\x90\xea\xb7\x46\x81\xa0\x6d\x96\xd4\xba\x87\x31\x65\xdc\x53\x8a\xd5\xee\xea\x2e\x0e\x79\x66\x50\xbd\xa7\xb8\xb0\x42\x7f\xbc\xbf\x8c\xdf\x95\xe7\xcf\x0f\x8c\xeb\x3a\xd7\xe0\xcf\x4e\xd9\xb6\xf7\xf5\xef\x00\x3a\x28\x8a\x09\x59\x68\x1d\x4a\x5a\xdf\xe9\x12\x99\x58\x66\x7f\xa4\xc8\xd2\xce\xc7\xbb\x20\x57\x95\xe5\xf3\xfe\x48\x76\xe2\xd2\x9c\x83\x46\xb2\x3c\x29\x51\x0c\x49\x67\xb9\xd7\xc2\x1f\xc5\x4d\x0d\xd3\xe1\xc8\xeb\xc6\x41\xda\x5d\x2d\x79\xf6\xeb\x44\x72\xed\x8f\xd5\xb6\xa3\xe0\xc9\x1f\x9d\x4c\x10\x97\xe7\x14\xb3\xf9\x78\xc4\x4f\xc8\x92\x9a\xce\x8e\xb8\x2f\xaa\x92\x11\x3a\xe4\x74\x74\x84\x91\x40\x35\x41\x7f\xd6\x0d\xa7\x9e\x7b\x55\x34\x39\x4c\xac\xef\x20\x2d\x73\x31\x02\x6d\xa7\xd9\x5e\x42\xdb\x79\xd4\x3a\x09\xa0\x73\xe8\xff\x3f\x63\xda\x9c\xdb\x7d\x8d\xc4\xaf\x87\xf5\x4b\x1f\x43\x7d\x62\xe8\xe2\xb0\x76\x15\x4e\xe5\xb4\x4f\xca\x8b\xdb\xb1\x7a\xce\x46\xf8\x5e\xf3\xdf\xef\x91\xc5\x2f\x6a\x06\x8d\x67\x4c\xb2\x50\xf2\xba\xb7\x69\x4d\x6a\x73\x61\x70\x34\x37\x4c\x00\x9d\x09\x17\x7c\x62\x51\xe1\xe9\xfc\x33\x76\x1d\x9e\x1d\x1d\x1d\x2d\x49\xfc\xff\xd5\xd1\xed\xc9\x69\x57\x9a\xef\x97\x9e\x03\xbb\xd7\x34\x4c\x2c\x36\x3a\x8a\xa8\xd9\xa5\x0c\x50\xce\x60\xeb\xcf\x20\xc9\xb8\xb8\xd4\x3d\x7a\x9a\x6c\x4e\xe7\x95\x42\xde\x62\xab\x78\x15\x67\x1b\x75\xfd\xc4\x6e\xb6\xad\xb8\x64\x4b\x45\xfd\x4a\xa4\x3d\x96\xf3\xff\xbc\x17\x85\xfd\xa7\x63\xa7\x36\x9d\x7a\xcd\xa4\x4e\xa0\x26\x91\xe6\xaf\x5b\x39\xaa\xf3\xec\xe8\x68\x63\x3d\xf2\xa7\xe3\xaf\x59\xba\xc0\xd2\x7a\xe8\x96\xc0\xa7\x57\xa9\x1d\xc7\xcd\x07\x53\xae\xe1\x39\xb7\x0c\xd1\xa2\x46\xbf\x42\x3a\xb8\xc8\x16\x5e\x1e\x5e\x1d\x76\xc0\xc7\x9f\x6c\xa6\x43\x5f\xc4\xfb\xe8\x3d\x08\xf8\x3a\xa2\x99\x9d\x9c\x3e\x89\xdf\xb4\x9e\x26\x3b\xac\x23\x97\xc7\x37\x47\xca\xb2\x69\x10\x75\x05\x6a\x2d\x95\x59\x5b\xfe\xbb\xd7\xa7\x31\x4f\x54\xbc\x70\x4f\x60\x76\xd1\xd6\x89\x1d\x42\xb1\x3d\x88\xdd\x27\x88\x6d\xe1\xce\xf8\xe9\x78\xf7\x1c\xec\x0d\x70\x7c\x12\x80\xe7\xc9\x1f\x74\x55\x3b\x4a\x6a\x03\xdc\x24\x33\x27\x3e\x21\xaa\x05\x3f\x4b\x37\x44\x5c\x4f\x6d\xeb\x3b\x0b\x48\x48\x7a\x03\x9b\x13\xb9\xe5\xc8\xba\x60\x1f\x3c\xc9\xed\x94\x8b\x54\x4e\xa1\x8f\x66\x4a\xe7\x5a\x26\x95\xfd\xac\xcc\x47\x9f\xf2\xc3\xdf\x43\x91\x7e\x4c\xac\x57\x9e\x9c\xe5\x78\x3d\x40\x2c\xb7\xcc\xef\x21\x77\x19\xe4\x76\x57\xd3\xe0\x5e\x49\xd0\x34\xb0\x03\x19\x3c\x3b\xb5\x98\xdb\xf7\xa1\x49\xaf\x41\x89\x1a\x07\xde\xab\x69\x71\xf6\xed\xcb\x2f\xbf\xfc\xf2\x39\x79\x6c\x94\x09\x34\x71\x02\xd1\x83\x53\x67\x28\xd1\x01\xe2\x70\xfc\x0c\xc6\xb2\x50\x74\xf6\xeb\x50\x2a\xac\x4b\x44\x6f\x6b\x32\x75\x0f\x5a\x8f\x1d\xa3\x95\xba\x43\x12\xd9\x3b\xa1\x25\x49\xee\x27\xa1\x24\x8a\x74\x19\x1d\x85\x9c\xee\x32\xb5\xee\xdd\x51\x43\x48\x5e\x1e\x43\x55\x9b\x4e\xee\x8c\x28\xa5\x27\x26\x4c\x1c\xde\xbd\x12\x5d\x2e\x71\xa3\xdc\x8f\x6e\x58\x9b\xd0\xc8\x23\xe5\xd3\x39\x3b\x16\xd9\x4d\xa5\x31\x9e\xb0\xf7\xba\xe3\x03\xd4\x1d\xef\xf0\xe0\xf4\xcf\xaa\x93\x8e\x14\xcb\xc7\xbf\x65\x1b\x05\x66\x87\x6f\x16\x2b\x9a\x36\xc7\x12\xad\xd0\xe8\xae\xf7\x3e\x75\x83\x26\x40\x1b\x4b\xd2\x79\x79\x05\x06\xaf\xa8\xc8\x7f\xbe\x81\x26\x12\x2e\x57\x3a\x1b\xe6\x7c\x95\x0d\x7e\x5e\x1d\xa5\xf9\x41\x63\x46\x3b\x22\x93\xb8\x01\xee\x18\x21\x77\x16\x61\x24\x94\xc1\xfb\x4f\xed\xa1\x3c\x51\xe4\xe7\xd7\x45\xee\x12\xca\xff\x1f\x98\x14\xc6\xb7\x9f\x6a\xd4\x45\xbf\x6c\xa4\x4f\x54\x20\x4d\x0f\x28\xc1\x8f\xbb\xac\x34\x01\xf2\x04\x6a\x9f\xdb\x9e\xca\x76\x67\xbe\x33\x18\x50\xc2\x28\xd4\x86\x4f\x6c\x5c\x6d\xb9\x69\xd3\xfa\xde\x35\x70\xe3\x1b\xaf\xcb\x2e\x56\x8e\x60\x6e\x70\x62\x5f\x09\xe1\x22\x5d\x98\x8e\xf9\x60\xdc\xd4\xa5\x8f\xbf\x3a\xaa\x62\xfc\xfc\xbb\x36\xc0\x45\xe3\x40\x56\x33\x6b\xb5\x4f\x35\xdc\xf1\xc9\x66\x5c\x7c\xba\xc3\x30\xe0\xfa\xc5\x07\xf1\x41\x24\x49\xe2\xb9\xe1\x83\x20\x7f\x27\xfc\xd3\x76\xea\x8f\x0f\x02\xec\x71\x91\x7f\x2d\x78\xfa\x02\xce\xed\x6c\xf2\xbf\xfe\xf6\x02\x68\xb5\x94\x9e\x59\xc6\x68\x3e\xb4\x96\x74\xf9\x54\xd3\x63\xfd\x02\xde\xfb\x17\x3e\xd2\x2b\xef\xed\x3b\x1f\xe9\xa5\x6a\x51\x89\x5e\xaa\x8e\x18\xfb\xf8\x41\xdc\x50\xd3\x6c\x73\xa8\x3e\xdf\x1a\xaa\xeb\xf5\x69\x54\xbc\x5b\x49\x0b\x0d\xe8\x36\x0a\x74\x77\x3f\x76\x5d\xde\xd2\x17\xf0\x5a\x18\xf8\xbf\xf0\xd5\x51\xdc\x88\xaa\x1e\x7b\x67\xae\xa2\x90\xfe\xeb\x75\x79\x9e\x51\xa8\x8d\x5e\x0c\x0b\x0f\xf1\x3d\x32\xa1\x58\xf6\x83\x1d\xdb\xf8\x3e\x85\x62\x72\x53\xa4\xf8\x02\xbe\xcd\x24\x33\xf6\x1e\x33\xcd\x5b\xb6\xad\x6e\x09\xa4\xf6\x35\x5b\x74\x97\x16\xa2\x55\x35\x24\x41\x46\x5f\xc0\x7b\x9f\x24\xb0\xd6\x43\x7f\xcf\xf5\xb1\xea\x62\x45\xb4\x17\x50\x8d\xc1\xc6\x6d\x99\x13\xc0\x17\xf1\x41\xd6\x54\x5c\x2c\xb4\x7f\xb5\x2a\x75\xf8\xbe\x4b\xc1\x4e\xe1\x82\x86\x27\xfa\xb2\xd6\x85\xaa\x7d\x51\x2f\x84\xa5\x65\x73\x1c\xc2\xa9\x7a\x2f\x65\x5a\xbb\x5f\x08\x6e\xe2\x6b\xea\xfe\xc5\x2c\x8f\xde\xa9\xaa\x8b\x9a\xe1\xea\xab\x94\xa4\xb8\x88\x2b\x96\x15\xd5\x18\xde\x58\x99\x8a\x11\xf0\x41\x05\x2d\x79\x1c\x5f\xa0\x47\xc4\x73\xd0\xfc\xd5\xcd\xc1\x82\x69\xf6\xf6\xaa\xa1\xb2\x27\x7e\x04\x1c\xb3\x80\xdb\x83\x6f\x1d\xa6\x3a\x8c\x1c\xc8\x22\x4b\xa1\xdc\x4f\xa3\x65\x76\x45\xbb\x68\x28\x05\x45\x91\x65\x5d\x3f\x3f\x89\x70\x10\x89\x80\x04\x95\x92\x4a\x27\xbd\x2d\x15\xcb\x7b\x51\x28\x4b\xb2\xef\x43\x9f\x8e\x8f\xd7\xe2\x0c\xcb\x0b\x36\xd7\x16\x17\x6e\x5b\x97\x54\x6e\x4d\xc1\xce\xc4\x76\xc6\x44\x37\xd3\x2e\x9a\x0b\xff\x1c\x83\xff\x89\x75\x51\x6d\x14\xb2\xc9\x26\xbe\x4f\xf7\x05\x69\x9c\x4b\x02\xb0\xce\xed\x4b\x20\x70\x9a\x91\x04\xa7\x78\x8d\x29\x28\xff\x0d\xad\xf3\x9e\xa3\xba\x42\x75\x78\x4e\xdb\xfd\xbe\xb9\xa2\x0e\xc5\x25\x2d\xd5\x40\x17\xb8\x3d\x4f\x20\x09\x85\x27\x14\x31\x22\x0c\x85\xf4\x6a\x2a\xdc\x6a\x5e\xf4\x09\x30\xef\xc0\x20\x68\x2a\x9b\xc2\x75\xd9\x3c\xd2\xd3\xfc\x11\x98\xf5\xad\x86\x65\x46\x51\x0f\x4d\xf4\x94\x97\xba\x9b\xab\x8e\x54\x44\x17\xfa\x05\x49\xa0\x8d\xed\x57\x42\xbd\x25\x26\xa7\x69\xab\x07\x2f\xed\x0e\x45\x0a\x7b\x1e\x48\x21\x70\x60\x8f\xf9\xf6\x0a\x79\xf2\x86\x69\x73\x68\xbf\x3a\x7c\x7d\x9a\xc0\x18\x19\x39\x17\x48\x9b\xb5\x13\xbb\xeb\x10\x35\xd1\xd6\x69\xc9\x31\x83\x09\xd7\x3a\x56\x5f\x29\x22\x83\x1c\xb8\xb7\xf2\xdf\x96\x5d\xde\xce\x79\xb2\xda\x87\xd9\x1e\x3a\x54\xe4\x64\x37\x3c\x3d\xaa\x79\x79\x63\xee\xd8\xcc\x9d\x52\x7b\xdc\x2a\x77\x2d\x64\xa8\x0d\x48\xb3\x09\x8e\x14\x6e\x90\x96\xd0\x62\x4d\x47\x52\xc5\x50\x76\x00\xed\x08\x13\x97\x20\xbf\xc2\xb4\x4b\x24\x51\x98\x67\x6c\x16\x8f\x7f\x5e\xf4\x33\xae\xc7\x98\x82\xe6\xf5\xf3\xbf\x6e\x1b\x8e\x52\x39\xe2\xb8\x30\xff\xfb\xd9\x12\x2a\xde\xbd\x7f\xe9\x44\x78\x11\x76\x48\x43\x64\xa9\x49\x14\xe4\x6c\x96\x49\x96\xea\x0d\x26\x04\x83\xd7\xe6\x89\x2d\xf5\x70\x0e\xf1\xd6\xa1\xd8\x4a\xce\x6a\xd2\x65\xfe\xba\x95\xf7\x1e\xc9\x84\xff\x90\xa3\x92\xa7\xd8\x1f\x4b\x79\xb9\x6d\xf6\xd8\x9f\xdd\xe7\x2d\x73\xe1\x19\x8e\xb8\x76\xe9\x3b\xa6\xf3\x2f\x2e\x9d\xea\x42\xbb\xca\x89\xce\x0a\x80\x73\x70\x90\xcb\x88\xb4\x25\x0a\x23\x46\x78\x77\xf6\x06\x34\x1f\x89\x10\x20\x68\xc6\x51\x28\x85\xc6\x81\x42\x13\x5c\x14\x0b\x77\xb9\xdb\x18\xe5\xb0\x45\x47\x95\x2d\xae\xd2\x6a\x87\xa6\x54\x6d\xe8\xd3\x46\x7a\x72\x38\x94\x0e\x12\xae\xbc\x9d\x6f\x1b\x68\x67\x2b\xdf\xbc\xf0\x35\x09\x33\x96\x99\x1f\xc7\x32\x23\x85\x00\xfa\xd2\xb7\x38\x09\xef\xf9\x65\xca\x86\xb3\x49\xdb\x2e\x64\x14\x81\x8d\xca\x63\xa4\x23\x86\xcd\x99\x1b\x57\x48\xc5\xdb\xb4\x00\x3e\x08\xd1\x16\xaf\x8a\xec\x01\x27\xfa\xf6\x5c\xe6\x19\x3c\xe6\xef\x98\xb3\xe7\xaf\x6e\x0e\x16\x08\xd9\x32\x88\x7e\xba\x12\xa2\x2f\xaa\x21\xb5\xfa\x7c\x60\x98\xdd\x4a\x9f\x5b\x12\xec\xce\x95\xf4\x3d\x60\xef\x08\x60\x6f\x62\xb8\x90\x82\xe9\x59\x42\xb7\x40\x75\x19\xb0\x11\xca\x2f\xa1\xd0\x6d\x1f\x99\xdf\x83\xb5\x19\x7e\xc7\x1f\x96\x84\xd8\x46\x47\x8a\x04\x50\xef\xe8\xe6\xb0\x40\xea\x3f\xb9\xf8\x39\xf1\xfb\x4c\xea\x4c\x8a\xb4\xc3\x84\x4c\xe5\x2d\x05\xe5\xb4\x2a\x60\x89\xc4\x90\x99\x22\x0c\x54\xb5\x45\xb3\xf0\x17\xba\x64\xd4\xad\xc5\xa6\xcc\xb5\x10\x6c\xc3\xa3\xa8\xae\x2e\x79\x19\xca\xc3\xce\x6e\x65\xf5\xfa\x7a\x17\x1b\x7a\x77\x10\x30\x10\x6f\x31\x9a\x27\x96\xaf\x3d\xe8\x6f\x5c\xd3\xf6\xab\x66\x85\xab\x6d\xba\xbb\xb0\x7b\xad\x66\xf5\xa9\xc9\x10\x76\x5b\x2d\xac\xbc\xbd\xdf\xeb\x80\x90\x55\x59\x6f\x47\x92\xc5\x7b\xc0\xee\x9b\x26\x15\x2f\xdc\xdb\x1e\xb4\xdc\xed\x1f\xa2\x90\x12\x5f\x31\x69\x71\xd0\x19\x32\x9e\xe1\xd2\xcd\x68\x77\x6f\xfa\xd3\xb4\xe6\x1b\x31\x83\x4c\x8e\x76\x70\x3e\xab\x10\x71\xaf\x57\x3e\x5e\xbd\x72\x7e\x2b\x45\x8a\x19\x1a\x5c\x3a\x6f\xba\x57\x3c\x9f\xb4\xcc\x97\xa7\xf6\x9d\xca\x15\x50\xee\xab\x68\x61\xfb\xcd\x26\xca\xdb\x4c\x7e\xf7\xbb\x4b\x22\x8a\xd4\xf5\x8d\xde\x1a\xca\x6a\x8f\x6f\x0e\x16\xfd\x5e\x13\x90\x16\x84\x29\x2d\x68\xba\x6f\xaf\x35\x74\xdd\x10\xa7\x9d\x47\x24\xc2\x5b\x04\x6b\xed\x5e\x98\xbf\x75\x08\xe9\x27\xe4\xe0\xd9\xfa\x08\x28\x2a\xe2\xac\xc8\xb0\x45\x72\xab\x83\xa0\x6c\x65\xa0\x1a\xaf\x2e\x15\xd4\xca\x91\x45\xf2\x3e\x1f\x2a\xa5\x7b\x70\x62\x4b\x24\x2b\x2e\xf3\x4a\x21\x23\xb7\xfe\x28\xf3\xb1\xed\x5d\x8a\x9a\x72\xbe\x33\x7b\x4d\x66\x9f\x4b\x67\x50\xf3\xdd\x0d\x7c\x33\x4b\xcf\xdd\x91\x2d\x57\x3f\x5c\xbf\x57\x39\x30\x9e\x33\x62\xc6\x88\x59\x62\xfe\xea\xe6\x60\x01\x77\x2e\x03\x84\xa7\x6b\x01\x02\xd1\x73\x67\x8f\x8c\xaa\x88\xb5\xd7\x4e\x1e\x9b\x76\x12\xa3\xdc\xa6\x06\x7d\x29\x45\xba\x05\xdf\x4a\xdf\x57\x05\x6f\x2d\xae\xa5\x75\xa1\xee\x1e\x7c\x5e\x2b\xdb\xb6\x3b\x02\xb8\xb7\x0f\xc8\x3e\xf8\x8c\x6a\xc0\xf6\x2a\x7c\x39\x86\x2d\xa2\x12\x94\xf8\x58\x15\x28\x15\x79\x7b\x4b\xdf\x89\xb8\xec\xac\x0a\xdf\xd0\x7d\x76\x5b\x7f\x2f\xa7\xeb\xbd\xf2\xbe\xcb\xca\xfb\x56\x13\xda\x7a\x93\x59\x3c\x57\x7c\xa1\x9d\x3e\x1c\x7f\xb8\xa6\x80\xb6\xaa\xee\x71\x2a\x60\x5f\xa1\x4f\x65\x9f\xcd\x28\x96\x9d\xb4\xf5\x42\x64\xa8\x69\xa5\x5c\xda\x93\xfc\xc9\x9d\x67\x4f\xe8\xf7\xaa\x38\xa6\xb7\x92\x7c\x5b\xdc\x62\xd9\xbf\x63\xe7\xa4\xef\x1f\x17\x95\x5b\x72\x7b\x28\x58\xcb\x2b\xe9\x08\x48\x2e\xc9\x10\x2b\xdc\xf9\x38\xf7\x61\x95\x04\x27\xbc\x5f\x7b\xe3\xe6\x60\xd1\xef\x8f\x77\xae\x9a\xe8\x9d\x53\x45\xf4\xde\x10\x78\x84\x86\x40\x91\x72\xb3\x09\x60\x6a\x64\x6a\x30\x3e\xa1\xcf\xde\xc8\x51\x0b\x68\x9e\xdb\x97\x1c\x50\xd2\x9b\x1b\xb9\x22\xed\x17\x87\x99\x1c\xcd\x2d\xda\xd1\xe3\x89\xac\x96\x09\x3d\xe2\x69\x1f\x9f\xe3\x12\xf2\x5a\xef\x06\x33\xb4\x57\xdd\x47\x35\xd1\xde\x18\x4a\x0a\xa5\xe5\x04\x9d\xc3\x23\x1c\x5b\xe9\xbc\x1e\x5d\x60\x65\x64\x2e\x94\x67\x90\x34\x56\x03\xe1\x6d\x46\x21\xb4\x14\x6d\xab\x52\x7b\x48\x07\xed\x39\x18\xa2\x19\x50\xd4\x64\x7f\x06\x39\xd3\x3a\x9c\xf3\x99\xbc\x4e\x93\x30\x53\xd8\x20\x4c\xf7\x99\x47\x3e\xda\xa2\xe0\xfb\xea\xf6\x8c\x27\x50\xa2\xf4\xad\xb0\x7b\x81\x01\x73\x2f\xc8\x5d\xd2\xdd\x1e\x09\x55\x45\x33\xec\xc2\x82\xa3\x61\x8a\xe6\xfc\x4f\x4a\x06\xcb\x7e\xcc\xa6\x77\x96\x7e\x36\x73\xcd\x08\xc9\x13\x88\xcb\x5e\x7c\xd5\xff\xfb\xe0\x29\x4b\xbf\xf2\x9b\x16\x3d\xc3\xbd\x38\xce\xaf\x7f\xbb\x7c\xf6\x99\xf2\x29\xd8\xcc\xa3\x9f\x98\x58\xb4\x46\x4d\xea\x0a\x55\x1d\x08\x64\x53\xb9\x3e\x71\x6a\xb5\xa3\x8f\x4b\x68\xfe\xa2\xe0\xa9\x3f\x84\xed\x33\x51\x68\x61\x3c\xf4\xfd\x52\xc8\x4a\x15\x33\x44\x06\x9b\x43\xcf\x73\x14\x25\x7c\xd8\x96\x04\x4b\xc2\xb2\xef\x28\x9b\x84\xcd\x61\xfe\x19\xe8\xe4\xb3\x6e\x3c\x04\x12\xb9\xa6\x7e\x22\x1a\xd1\x7c\xa3\x41\xda\x49\xcb\x8c\x19\xc1\x12\x05\xb7\xfa\x13\xfc\x2c\xb9\x78\xba\x39\xb1\xd6\x49\x3b\x79\x2b\x22\xd9\xfd\x5a\xf7\x46\xa3\x7a\x12\xce\x40\x25\xbb\x25\x82\xe8\x76\x3f\xf4\x58\x92\x86\xf3\xab\x15\x59\x38\x97\xa4\xe1\xbc\x1f\xdb\x63\xc2\xcc\xc0\xaa\x48\x9e\x36\x3b\x65\x85\x90\x62\xb8\x37\x42\x1e\x8f\x11\x72\xe0\x2b\xee\x54\x25\x96\xf5\x76\x34\x0e\x0a\xc5\xcd\xec\x9c\x18\xa6\xc6\xd0\x9d\x3e\x32\x85\xea\xa4\x30\x8d\x33\x4d\x82\x50\x8e\x8d\x89\x15\x61\xc7\x73\x1e\x82\xe9\xcb\xf8\x59\x43\x06\x4e\x44\xd8\x24\x1a\xf4\x77\xda\xc0\x04\xc9\x7f\xd1\x36\x80\x82\xa7\xff\x7d\xf8\x5f\x6e\x0f\xc4\x7f\x27\x61\xd1\xce\xe7\x51\xbe\x2c\xd2\xdf\x0b\x60\x39\x3f\xbc\xc4\x99\xd3\x61\x7e\x7c\x7b\x7e\x01\x4e\x91\x11\x38\x4d\x7c\xe6\x08\xab\x73\x81\x8a\x6d\x1e\x3a\x32\xd0\x48\xbb\x8b\x01\x18\x19\x03\x86\x0f\x8a\x8c\xa9\x90\x19\x83\x70\x5b\x0e\x21\x71\x35\x1e\x92\xb2\xa4\x93\x2e\x24\x4e\x61\xaa\xae\xf1\x9a\x72\x5c\x54\xd7\xf1\xe9\x2a\xdd\xd8\xb2\xea\x46\x11\x1f\x5d\x48\xdc\xc1\xcd\x87\xe5\x5e\xbd\xa4\x1b\x7b\xab\xa4\xaa\x39\xab\x7a\x9d\xfa\x28\x7a\x53\xb2\x64\x85\x78\xa4\x6a\x72\x52\x1b\xab\x05\xd8\x73\xe2\x5f\xb5\x7a\x71\xd8\xb3\x48\xab\xa3\xb4\x31\x91\x30\x89\xd2\x54\x73\xb7\xdf\xbb\xd0\xb4\x98\x8d\xf0\xb5\x65\x05\xf0\x23\xdc\x3d\x58\x01\x54\xab\x41\x6a\x09\xe6\x2f\x05\xa7\x6f\x68\x63\x79\xcc\xf5\x81\x3e\xcd\xdf\x37\x07\x0d\x91\xeb\x54\x22\x1f\x57\xba\x08\x9d\x03\x6b\x36\x76\x3e\xa7\x12\xb5\xa5\x8a\x65\x20\x22\x8b\x3f\xa2\x3e\x4c\x8e\x21\xe2\xbc\xa9\xee\x3f\x24\x2a\x95\xde\xe6\x55\x44\xf2\xfa\x21\x1d\xc2\x43\xf9\x43\x88\x3e\x78\xc5\x07\x58\x91\x09\xaf\xb9\x36\x0f\x94\x0c\x8b\x80\x7c\x4d\x8a\x40\x5f\xa6\x35\xde\xe9\xc1\xeb\xf2\x68\x25\x77\x3c\xaa\xed\x2b\x39\x93\x51\xd0\x44\x91\x76\x21\xb1\x8c\xad\x13\x20\x4f\xb9\x0d\xa9\x55\xb3\x90\x54\x9e\xcc\xde\x20\x90\x56\x71\xf0\x9e\xdc\x1c\x07\x7c\xe8\x09\xd7\xfb\x9c\x74\xfe\xa9\xec\xd3\x2d\x28\xde\x9c\xd9\x56\x52\xbb\x2d\x05\x4f\x37\x64\x29\x58\x7c\x7c\x96\x4d\xef\xe3\x8f\xae\xea\xda\x61\x0a\x89\x0c\x1e\x06\xab\x46\x8d\x74\xd8\x5d\x27\x15\xd0\x7a\xa5\x51\xb3\xc3\x13\xb2\x69\xe7\xda\xb1\x5c\x53\x77\x49\x8e\xb4\x3f\x29\x8b\x95\x4c\x3b\xb5\x09\x40\xfa\xb4\x58\x92\xc9\xe9\x7c\x00\xcd\xfa\x0a\x7c\x7b\x0f\xe3\x22\x3b\x67\xcc\xe0\x1b\x32\x51\x0e\xed\xff\x6e\xd8\x8d\xf6\x63\xcc\xc8\xdc\xbf\xd5\xc6\xeb\x8d\xdb\x7f\x66\x8f\x45\x26\xcb\xf7\x8e\xfb\x10\x3b\x2d\xa4\xf8\xb4\x5d\xd2\x68\x36\xec\x4e\x9d\xb3\xe6\xfa\x44\xf9\x16\x86\x45\x96\x41\xbf\xa0\xbc\x09\x71\xff\xd9\x88\xf1\x5b\x58\x8c\xed\xbd\x3b\x68\xfe\x2a\xfb\xdb\x89\x92\xd0\xc5\x55\x34\xbb\x48\xa7\x04\xe5\x7e\xd3\x3b\xe9\x0c\x8d\x3c\x39\x8c\x7c\xd6\x0f\x03\x54\xe6\x28\x50\xcb\x74\xb8\x94\x06\x3e\x45\x5b\xe9\x44\x8f\x67\xbe\x2e\xf0\x90\xd5\xcb\x3a\xef\x13\x77\xc2\x4c\xaf\x7c\x39\xd9\x90\x3c\x4b\x33\x4e\x6e\x4b\xa6\x9f\x8e\x2d\xa1\x6a\xc6\x54\x4c\x9d\x55\x94\xda\x42\x65\xf2\x01\xa1\x2a\x24\x14\xbb\x9d\xd2\xb4\x3b\x54\xa9\x99\x2a\xbe\xa4\xa8\xe6\x8e\x25\x74\xad\x29\xa5\xc0\xca\x3e\x1d\xdd\x18\xf7\x3c\xa8\xd5\x14\x4b\xd3\xf9\x81\x1c\x57\x5d\xe8\x7c\x4f\x3a\xd9\x08\xe3\x15\xe5\x4e\xae\x68\xf5\xcc\xd4\x77\xcd\xd1\x9f\xfb\xaa\x7e\x6f\x01\x46\x54\x45\xb5\x8d\xdc\x77\x17\x17\x3f\xfa\x1d\x3d\x30\x90\x69\x99\xbd\x39\x58\x63\x31\x91\x4a\xd6\xa0\xff\xca\xf6\xb6\x36\xc2\xfb\x45\x97\xd3\x35\x14\xd9\x69\xaa\x5a\x71\xb1\x7b\x52\xce\x93\xb2\x56\x80\x67\xe8\xd6\xef\x99\x52\x6c\xce\xf3\x69\x33\x47\x6e\x2c\x3d\x36\x7f\xda\x2d\xf0\x37\xfa\x3e\xae\x7a\xdd\x21\xb6\x9f\x6f\x3e\xc6\xee\xb3\x56\xfa\x78\xfa\xae\x1e\x63\xca\x98\x1e\x76\x49\xfa\xa9\xc0\x19\x33\x61\x21\x8a\xf2\xc3\xa0\xd2\xef\x8f\x3e\xf6\xce\xd1\x50\x4e\x25\xdd\x3b\xa7\x7c\x85\xa7\xcc\x60\xd2\xf5\xcb\xc3\xb4\xb6\x3c\xf3\x09\x41\xad\xda\x4e\xe5\x59\xeb\xca\x2e\x2a\x4f\xc7\x72\x3e\xaa\x0d\xaf\x6d\xde\xc4\x90\x64\xb5\xb5\x92\xdb\xb3\x59\x7b\xc5\x93\x42\x57\x96\x9a\xa5\x86\x41\x25\xe0\x5f\xef\x8f\x0e\x9f\x7f\xfc\xe3\xe9\xb3\x9b\xff\xa8\xd5\xbe\x84\x0d\x6c\x7a\x54\xc3\x26\x79\xad\x35\xed\x2d\xe9\xf8\xba\xa8\x15\x71\x75\xdd\x83\xb6\xe1\x3a\xa1\x43\xc9\x29\xad\x27\xda\x75\x9d\x20\x8d\xe4\x8e\x63\x06\x7e\xf9\xe5\x97\x5f\xbe\xff\xfe\xf4\x74\x3c\x9e\x4c\x74\x2d\x04\x2b\xea\xee\xf1\xd1\xd3\xe7\x47\x5f\x1e\x3f\x3f\xb2\xff\x3a\xf3\x9d\x08\xd9\xf9\xb7\xe9\xc3\xbf\xfe\xf2\xe1\x83\xfe\xf8\x9f\xcb\xba\xb0\xe8\x84\x01\xe6\xbd\x11\x6d\x4d\xb6\xab\xc1\xf3\x4d\x7d\x33\x97\x8c\x74\x9b\x46\xbf\x52\x72\xfa\xe1\x43\x2f\xb4\xe9\x2f\xb7\xed\x44\xe3\x2c\x39\x2a\xbe\x2a\xbc\xa5\x87\xf5\x97\x56\xf5\xf7\xa5\x4c\x71\x9b\x9e\xfe\xb5\xd9\xd5\xbf\xfd\xbf\x75\x3a\xfb\x0d\xb7\x31\x75\x51\x3c\x48\xa9\x27\x5a\x4f\x12\x83\x3e\x25\x89\x09\xa5\x36\xc2\x1c\x36\xef\xe6\x4f\x51\xa6\xd2\x6d\xba\x19\x2a\xf8\xf0\xa1\xf7\xd2\x65\x81\x93\x4a\x7f\xf8\xd0\x7b\x75\xf6\xf6\xe7\x73\x14\xda\x5d\xbd\x67\x87\xbf\xff\xba\x7a\x94\xc3\xe9\x0f\xac\x76\xe8\x62\x7d\x88\x43\x85\x51\x75\x71\x65\xbd\x16\x6a\xac\xfa\xac\x71\xba\xc4\x22\x7e\xf0\x29\x74\x17\x53\xc9\x99\xa7\x71\xe5\xd5\xfa\xdb\xe1\xd3\xbf\x47\x8b\x6c\xf1\x19\x78\x7f\x3f\x6a\x27\xc8\x09\xfc\xfc\xea\xfc\xef\xcf\xaa\xdc\xbd\x0b\xda\xc4\xcc\xb6\x4d\x7a\xbe\xb8\x45\xcf\xd7\x69\x50\xa8\x75\xae\x3d\x94\x0b\x78\xa1\x65\xb4\xe6\xdc\x4c\xdf\xaf\x39\x1f\xdb\x57\xeb\xf7\x96\xd5\x33\x5f\x57\x84\x63\x5d\xe8\x9c\x0c\x68\x69\xea\x42\x5e\xa2\x88\x1b\xb0\xbc\x11\xf4\x17\x97\x33\xff\x74\x99\x18\x85\x7f\x34\x2a\x6f\x50\x8c\xcc\x78\xd1\x52\xed\xfc\x30\x90\xa0\x10\xe7\x56\x06\x64\x79\x68\x03\x2d\xbb\x74\x0e\x6a\x5f\x57\xa3\x13\xfe\x75\x7e\x54\xf2\x8a\xa7\xdb\xb7\x37\x92\xaa\x9c\x29\x25\xcd\xea\x2a\x63\xf2\x7e\x1a\x2a\x9d\xc0\x8f\xb6\x6d\xc0\x6c\xd5\x60\x68\x68\x4b\x4d\x69\x3d\x42\x9d\xe1\x50\xa1\x1e\xdf\xaa\xd9\x6d\xcd\x52\xae\xec\x75\xda\x75\xd0\x76\x75\x73\xd0\xfc\x55\xf6\xc1\xef\xeb\x78\xa0\x02\xf9\xc8\x24\xb0\x76\x7d\x73\xd0\xc2\x72\x9d\xef\x9b\x53\xf0\xea\xd6\x47\x41\xe6\x85\xa2\x43\x5c\xa1\xc3\x84\x14\xb3\x09\xd7\x35\xc3\xa6\x11\xd6\x11\xde\x3e\x58\xd6\xcb\x9f\xc7\x68\x55\x11\x1b\x9b\x32\x91\x57\x58\x76\xf0\x0b\xed\xb5\xaf\x38\x5b\x1e\xcd\xd8\xd6\x3a\x31\x12\x2e\x11\x73\x7a\x7b\x02\x65\x6b\xea\x41\x0a\x07\xcd\x5f\x15\xe3\x12\xcb\x95\x21\x16\x31\x35\xda\x79\x69\x01\xcf\x76\xa1\x73\x21\x0d\xcb\xec\xb4\xaf\xef\x84\x85\x17\x0f\xc0\xf6\xac\x51\x1f\xfc\xb8\xb5\x77\xe0\x4a\xa8\xdc\xcb\x61\xa4\xca\x73\xe3\x17\xb6\x65\xc5\x80\xbc\x54\x68\x27\x1b\x96\x55\x31\xf8\x71\x2b\x37\x1d\x9b\x73\xbb\xc7\x63\xf7\x47\xc5\xb5\x73\x6b\x99\xf4\x1b\x3f\x3e\xae\x6e\x1d\x35\x83\x56\x3d\xfd\x5e\x91\x6a\x31\x80\x4e\xf8\xb8\x42\xe8\xd3\xf1\x77\x0a\x7d\x7e\xe7\x4d\x86\x8e\xba\x50\xdc\xe5\xa8\x75\x49\xef\xd4\xe6\xb5\x6b\xca\x89\x69\xca\x5a\x17\x3a\x5f\xb3\xc1\xe5\x90\x67\x94\x73\xa7\x0b\x9d\xbb\x97\xc1\x7b\x1e\xaf\x2e\x39\xed\x52\xbc\x8e\x7f\xd7\x12\x09\xd9\xfd\x3b\x57\xf2\x12\xd3\x55\x63\x5b\x1f\x4f\x9b\xd6\x2b\x24\xe9\xee\x42\xa8\x24\x64\x02\x15\x33\x2f\xad\xc4\x09\x7d\xa4\x27\xfd\x92\x92\x16\x6a\xfd\xa7\x7e\x95\x2b\xcb\x28\x61\x68\x0f\x5c\xab\x80\x57\x1c\x4d\xc7\xc2\x88\x2f\x68\xb1\x05\x45\x2d\x29\xb8\x99\x4a\x48\xd9\x4c\x3b\xa4\xa6\xd3\x6b\xdd\xe6\x2e\x50\x85\x08\x1f\x85\xf7\xa3\xf6\xf8\x63\xb1\x98\xb0\x27\xcd\xb9\x83\x72\x3c\x05\xa8\xda\x52\xa7\x21\xed\xde\x27\x28\x8d\xf5\xae\x5e\xfb\x88\xd5\x59\x69\x6e\xe4\x58\x96\xbd\x1d\x36\xf6\x1e\xcc\xc7\x6e\xae\x76\x3a\x56\xde\xa2\xc6\x87\x95\xf8\x34\x22\x19\xe9\xbf\x8e\x28\xb2\x8c\xc2\x10\xfc\xc6\xd2\xd6\x5e\x6c\x84\xde\xad\xa5\x44\x62\xd3\x5a\xc8\x9c\x51\xd7\x30\xec\x22\xfb\xad\x69\x6c\x1e\x1d\x2d\xe7\x55\x32\x2c\x72\x54\x94\xff\xae\x3c\xfd\x1b\x61\xcc\xb5\x91\xca\xa6\x92\x67\x59\xe6\x19\xc2\xef\x38\xa0\xc3\x87\x2c\x8b\x55\x6c\xda\x85\x29\xf2\xd1\x98\x76\x22\xf4\x67\x30\x96\x53\x6b\xbf\xba\x43\x83\xca\xd7\x5d\x68\xe7\x52\x49\x5e\x45\xcc\x3b\x74\x48\xdb\xaa\x08\xdb\x8a\x2d\xd7\xb9\xe2\x02\xe2\xca\x97\xe0\x6b\x3b\x00\xce\x39\x62\xb6\x3b\xe3\xb3\x4e\xcd\x6f\xab\xc3\x74\x16\xfb\x4d\x01\x00\x3a\x2c\xcb\xfe\x87\xbd\x6f\xeb\xad\xdc\x36\x1e\x7f\xd7\xa7\x10\xf4\xd2\x17\xd9\xdd\x38\xfb\x2f\xfe\x0d\x8a\x02\x5e\x6f\xd2\x18\xc9\x6e\x1c\xef\x25\xbb\x2d\x0c\x9b\x3e\x87\xb6\x05\xeb\x48\xaa\x2e\xbe\x04\xd0\x77\xff\x61\x78\x91\x44\x8a\x43\x52\xd2\x39\xf6\x6e\xd2\x7a\xd1\xe8\xe8\x32\x33\x1c\xce\x0c\x87\xc3\xe1\xf0\x6b\x50\xb6\x9f\xc9\x1f\xab\x2d\x1f\x0a\x28\x01\x4f\xd7\x7f\x94\x36\x7d\x91\x66\x8c\x45\xf0\xe5\x39\xc2\x30\x4c\xf2\xb1\x18\x06\x32\xb6\x3f\xae\x62\xcc\xd7\xed\x5a\x23\xba\x26\x0a\x4c\x4c\x6a\x03\xad\xf9\xcc\x57\x66\x33\xef\x24\xcf\xb6\xe2\x72\xb1\x39\xe1\xd0\xf5\xd2\x3c\x2d\x31\xb9\x97\x7e\xd8\x51\x0e\xad\xe8\x7e\x1e\xf2\x4d\x88\x95\xf4\xd9\xf8\xaa\xdb\x53\x38\x62\x5b\x9f\xcb\xa2\x98\xb6\xe6\xf2\x95\x4d\x96\x89\xcb\x95\x64\xe3\xb0\x78\xa4\x5d\xea\x4c\x8e\x5e\xb7\x07\x54\x38\x4c\x1c\x81\xf0\xf6\xb8\x7f\x57\xd2\xba\x4c\x60\xd4\x64\x8b\x7f\x97\x74\x45\x20\xaf\x58\x06\xab\xc1\x0f\x6c\x32\x72\x47\x12\xe6\x85\x74\x9e\x1e\x73\xff\x44\xed\x79\x8e\x63\x20\xb2\x15\xec\xbf\x47\xd9\x35\xc9\x53\x99\x33\xcf\xe4\xed\x12\xf5\x29\xc0\xcb\x44\x42\x00\x0a\x55\x43\x31\x1e\x51\x35\xdd\x96\xa9\x4d\x1e\x2a\xc5\xd7\x6a\x53\x3b\x4d\x76\xf6\x19\x0a\xa2\x37\x00\x13\x95\x45\xa3\xd1\x2d\x14\xec\x88\x33\x69\x78\x99\x75\x15\x72\x2b\x14\x42\xe9\xaa\x40\x67\x54\x47\x75\xd4\x1f\xb8\x27\x57\xa9\x14\xd2\x2d\xb6\x14\x37\x6b\x63\x98\xc7\xeb\x05\x4c\x45\x97\xcf\xdc\x12\xa3\x7c\x8a\x22\xe8\xe9\x35\xa3\xb0\x77\x9d\xff\x02\x18\xde\x44\x7b\x76\x8b\x13\xef\x61\x52\x86\xef\xc7\x0b\x6b\x63\x3c\x0b\xdb\x87\x2c\xe0\x8d\xf1\x7c\xc8\x92\xda\x0b\x17\x0a\xe1\xb5\x38\xb5\x71\x3e\x84\x37\x04\xa2\xa3\xa4\x4e\x56\x24\x5d\x08\x89\x92\xaa\x29\x29\x1c\x00\xbd\x0c\x90\x63\x1c\x75\x6a\xc2\x71\x75\xd4\x6c\x9a\x94\xd4\xc9\x9d\x05\xcc\x65\x9e\xa7\x94\xa8\x5b\xcb\x02\xfd\xca\x66\x01\x96\x45\xb2\x0c\xf0\x3c\xdd\x21\xd3\x97\x68\x2b\x47\x54\xc0\xbf\x88\xac\xd7\x09\x98\x49\x92\x9e\x60\x68\xdc\x56\x63\x4c\xc7\x90\x97\x43\x6e\xda\x39\x2b\xd3\x1b\x2a\x31\xf4\xfa\xb2\x13\xe7\x10\x38\x8c\xc7\x6b\xa7\x00\xda\x87\x8f\x61\x75\x03\x34\x72\x3d\xd8\x41\xac\x45\x75\x15\xb9\x1a\x62\x1a\xf4\x3c\xe8\x7e\x85\x93\x69\x0c\x2c\x78\x50\xd9\xc5\x34\x80\xcc\x8a\x76\x47\xc0\x56\x71\x78\x9d\xdc\xd1\x2c\xa4\x22\xb3\xa3\xea\x1e\xb1\x7c\xc9\x0a\x7c\xa4\x61\xba\x03\x24\x41\x54\xde\x91\x0d\x2b\x77\xf5\xa5\x2d\xe5\x61\x8b\xf2\xea\x0d\xec\xa4\x39\xcc\x1e\x7b\x9e\xb9\x15\xda\xde\xad\xa7\x18\xaf\x20\xc6\x58\x82\x31\x14\x7b\xc4\x21\xc4\x94\x5f\x0d\x8e\x02\xe6\xbd\x85\x92\x6a\x88\xba\x4c\x71\xff\xa1\x4e\x35\xf8\xf8\x55\x4d\x20\x5f\x0e\xe6\x92\x44\xad\x13\xaf\xa2\x3b\xe6\x19\x7b\x52\x79\x96\xb2\xc5\x24\x47\xf7\x37\x79\x45\xc3\x55\xce\x18\x04\xc7\x1a\xcb\x13\xc1\x43\xd8\x5a\x80\x52\xf6\x0e\x5a\x00\x6c\xdb\x05\x4d\x62\x52\x7c\xc7\x4e\x26\x17\xbd\x47\xd7\x3c\x49\x87\x1f\x98\x91\x5a\x36\xf3\xbe\x82\x0c\xf0\x24\xbb\x7e\x95\x3f\x6c\x5f\xed\x44\x64\x1a\x3c\xcd\xcb\xfc\x41\xa8\x1a\xa9\xc2\xff\x6c\x92\xec\x53\x1c\x6e\x92\xec\x73\x1c\x6e\xc8\x03\x5c\x93\x87\xcf\x67\x3a\x8a\x4d\x92\x1d\x0b\xdd\x7a\xa9\x3f\x22\x0f\xd8\x23\xa7\x3a\x8a\xf8\x86\xa7\xc2\xbd\xa5\xa4\x9c\x3a\x9a\xf8\xf3\xe6\x94\xac\x93\xa6\x0a\x37\xb4\x2e\xfb\x52\xa3\x45\x9e\x64\x75\xf8\x29\x0e\x3f\xeb\x80\x95\x01\xf3\x13\x28\xc5\x67\xf8\x3f\x0e\x65\x34\x0d\x46\xc7\x03\xf8\x17\x7d\x32\xdc\x74\x8d\x70\x7d\xa2\x95\xf6\xa1\xc2\x33\xf8\x17\x7d\x9e\x03\x5e\xcb\x5e\xc2\xa1\x8b\x16\x1b\x51\x68\xfd\xac\x7f\xab\xc6\xb2\xb4\x87\x6d\x80\xfd\x52\x68\x88\x4e\xf2\xf4\xf1\x3a\xcf\x76\xaa\x33\x05\xc7\xd1\xeb\x0d\xe9\x0e\x2f\xfd\x0f\x08\xc7\x19\x17\x94\xca\xa2\x36\xdf\x4e\xd5\x0d\x13\xd9\x2a\xc8\x83\xf1\x43\xf2\x80\x3f\xc4\x30\x8e\xfb\x69\x66\x4f\x1c\x67\xeb\x3c\x2f\xb7\x69\x59\xe1\xf4\xde\xbc\x84\x53\x70\x61\xf2\x05\x6b\x68\xac\x70\x29\xf8\x02\x79\x53\xeb\xcf\x58\x5d\x91\x08\x25\x8f\xe7\x30\x7a\xb9\xff\xfe\x14\x4a\xf4\xf2\x28\x1b\x06\x08\x9f\x7e\x5d\xc1\xee\xc7\x72\xaf\xc8\xef\x55\x36\xab\x94\xfe\x90\x94\x9b\x7b\x52\xd2\x57\xbc\x10\xcd\xf6\xa9\xe5\xa3\xe7\x95\x40\x13\xde\xd1\xb2\x82\xfd\xc4\x55\x0e\x95\x33\x87\x95\x7a\xd8\xb1\xf9\xfb\xe1\x47\xfe\x02\x2b\xfe\x58\xd6\xd2\xb1\x84\x7c\x96\x92\xa6\x94\x54\x14\x06\x39\x56\xb9\x8d\x27\xa5\xd2\xae\x72\xdb\xc1\x8b\x6f\xfe\xb6\xf7\xe2\xef\x7b\xdf\xbc\x14\x34\x54\x3d\x11\x00\x85\x17\xbe\x91\x94\xd8\x56\x40\xcd\xdb\x38\xf5\x89\x57\x1c\x60\xe6\xc5\x16\x2a\x7f\x81\xa2\x3d\x6a\xca\x2a\x2f\x17\x76\x01\x44\x7b\xde\xd2\x87\x9a\x03\x83\x90\x0f\x81\x8c\xec\xbb\x24\x6f\xaa\xb0\x80\xad\x02\x28\xfe\x77\x79\x59\xbf\x7a\x9c\x8a\xbf\xf3\xdc\x1a\xa8\x1d\x14\x46\x10\x5d\x3a\xe7\xb1\x7b\xf8\x29\x0a\x62\x9c\x13\x96\x95\x90\x25\xab\x5b\xf0\xa6\x2d\x0e\xdd\x6b\x5a\xad\x44\x08\xd8\xa9\xdd\x81\xc9\x54\xb4\x81\x06\xb6\x9b\x59\x29\x4d\xc3\x07\x73\xcb\x8c\xca\x1c\xa5\x58\xb8\x14\xd8\xbb\xd6\xff\x2a\xf3\xa6\xf0\x8a\x84\xe0\x6a\xaf\x24\x99\xe3\x48\x2d\x29\x7a\x8e\xd1\x5a\x30\x73\x00\x00\x45\xe2\x15\xa8\x42\xbf\x96\xdd\xb6\x28\x1c\x78\x52\xe6\x30\x61\xf6\x04\x83\xb2\xf5\x5b\x14\xc1\x27\x1c\xea\x78\x88\x53\x3f\xfd\x3c\xff\xd3\x7f\xcf\xff\xd4\x6f\xe5\x79\x7a\x68\x5d\xc5\x02\xb1\xed\x1f\x78\x25\xcc\x9d\xe3\x82\x38\xc7\x87\x64\x3d\x5f\xd2\xde\xd1\x32\x21\xe9\x5b\xce\xf3\xd9\x50\x4e\x52\x92\xd5\xd8\xf4\x77\x5b\xab\x1a\x43\x24\xda\xa7\xf6\x75\x0d\x7c\xa8\x86\x7d\x1c\x37\x34\x63\x6e\x26\x44\x4b\xba\x92\x3f\x31\x4b\x50\xea\x7e\xca\xa4\x22\x58\xd8\x22\x55\x45\x2b\xfb\xba\xd6\x8f\xa4\x5c\xc3\x40\xbb\x3b\x5e\x74\x18\x76\xcf\x08\xb6\xbe\x37\x68\x7f\x49\xa1\xaa\x92\x48\xac\xe2\x5e\xc6\x5f\xaa\xf0\x46\x10\xa4\x72\x25\xd0\xaf\x3a\x3e\xa1\x22\x63\x19\x9c\x34\xf2\x39\x4d\x7f\xa9\xc2\x14\x22\x13\xb5\xe8\x18\x08\x3e\x77\xf3\x4a\xc0\x01\x47\xf6\xf4\x3b\x98\xf6\xfd\x46\xbb\x0f\xc5\x9a\x20\x8b\x74\x4b\x55\x76\x76\xc8\x56\x6b\xfe\x4f\xf4\x91\xe7\x35\xc9\x08\xde\x77\xa1\xb6\xec\x10\x87\x29\x64\x3f\xc5\xe1\x15\x4c\x8a\xd3\xe4\x77\x5a\x9e\xa7\xf4\x8e\xa6\xe0\x42\x26\xe9\xf9\x26\x4f\x2a\x58\x06\x89\xc3\x4b\x58\x0c\x2b\x1f\xf9\x53\xf0\xfb\x49\x53\xe7\x6c\x49\xe0\xfc\x9e\xd4\x14\x06\xab\xfd\x9d\x44\x90\x07\x52\xb0\x2c\x84\x6c\x02\xe4\x29\x56\xb8\x14\x18\xc4\xd3\x64\x16\xd1\xa1\x53\x1c\x6e\x79\x7e\x4f\xca\xcc\x6a\x3f\x8f\xb3\xaa\x2e\x9b\x95\xe8\xda\xd9\xd8\x86\x9d\x7a\x5e\xe7\xf9\x79\x9a\xdf\xe3\x48\xdf\x24\xd9\xfb\x1b\xd8\xf7\x91\xa7\x6b\xe7\x98\x6a\x5f\x86\xc5\x71\x90\x87\x9d\xe3\x38\xe2\x59\xa3\x1f\x61\x12\xb5\x45\x1c\x16\x61\x33\xda\x77\x8b\x84\x69\x9a\xfb\xbe\x33\x9c\x60\xa8\x06\xbb\x2b\x21\x31\x03\xb2\x14\x93\x1a\x92\x2d\x92\x0c\x22\xc1\xac\xec\xd4\xd0\xec\x5e\x3e\x8a\xd1\xc8\xd3\x94\xc9\x69\xae\x98\x5d\xe2\x1c\x32\xcb\x97\xc6\x21\x5c\xfa\xfa\x39\xe8\xf9\x0d\xb9\x27\x49\xb2\x77\xb0\xff\x62\xff\xdb\x73\x39\x32\xec\xad\xf2\xec\x2a\xb9\xde\x7b\x73\x82\x8b\xa4\x64\xec\x29\xbd\x4b\xb6\x40\x2c\x8a\x67\x7e\x90\xc2\x9b\x1d\x7e\x31\x88\x23\x92\x26\x97\xfc\x04\x0e\x7b\xbc\x7c\x59\x7b\x8f\xab\x99\x51\x23\x6f\x04\xd9\x49\x5e\xef\x0c\x3a\x33\xee\xc7\xeb\x6a\x62\xf4\xd1\x19\xfc\x33\x4c\xa1\x86\x8a\xaf\x93\x71\xd8\xd4\xb9\x1c\x0e\xe7\xe4\x94\x69\xcd\xc5\x65\x27\xbf\xba\x52\x44\x26\xd0\x69\x1b\x4f\xf0\x97\x2d\x45\x77\x50\x3c\x17\xa0\xfb\xf7\x51\x16\x78\xf9\x30\xaf\x45\x10\xf8\x56\xfa\x32\x9d\x29\x1c\x2c\xa3\xee\xc2\xef\x90\x0d\xf0\xed\xfa\x5f\x4a\xd3\x1e\x55\xbb\xf8\x19\xac\xbe\xa1\x79\xfd\x22\x08\x24\x88\x26\x3c\x3c\x08\x69\x75\x0c\xa3\xaf\x44\x2f\x88\x5b\xd8\x9a\xcd\x12\xf4\x8e\xf2\x26\xab\xf1\xb6\x1b\x94\x48\x05\xd2\xc7\xe4\xa6\x2a\x8c\xc6\xc1\x13\x52\xc1\xf4\x2b\x14\x01\x3e\xb6\x17\x90\xf0\x79\x48\x46\x1f\x6a\xc6\xc1\xfd\xf0\x97\x4d\x52\x77\x67\x0f\x88\x94\x2f\xf6\x44\xa1\x30\xd0\x1b\x3f\x56\x29\x3e\x07\x50\x68\xc6\xa5\x5b\xd5\xa7\x23\x91\x29\x2b\x56\xac\xce\x9e\x2f\xc2\xf6\x69\x22\x44\xe3\x82\x97\x33\x76\x33\x79\x91\xab\x0d\xf4\xab\x0e\x47\xf4\xaa\x49\x6f\x65\x1f\x54\x0b\x3a\x61\x07\x46\xcd\xa8\xe7\x83\xb5\xa2\x6f\xf0\x55\xdb\x6f\x5e\xb8\x72\xc5\xbf\xcf\xea\xfe\x6c\x7a\x92\x75\x15\x79\x20\x31\x03\xe6\x64\xfa\x32\x7c\xe7\x11\x8a\xe5\xb3\x53\x5a\x35\x69\x5d\xe9\x85\x9a\x94\x94\x86\x92\x02\xcb\xe4\xc1\x2c\xa2\xd0\x99\xb7\x95\x41\x19\xef\xe2\xad\x4d\xc2\x35\xd8\x46\x4b\x60\x66\xd8\xa1\x69\xb8\x00\x4e\x8d\xab\x92\x8c\xa0\x29\x22\x8d\xea\x8a\x46\x9b\x98\x43\x78\x01\xfb\x3c\x1b\x58\x60\xfb\xdd\x06\xa6\xeb\x36\xd0\x08\x31\x29\xd1\x22\x0f\x41\x08\x97\xa7\x2a\xc9\xb7\xd5\xdb\x2e\x45\x9a\x2d\x77\x46\xdb\x2b\x26\xef\x67\xa3\xb7\xb7\x28\xa5\x5e\x92\x80\x44\x11\x90\x61\x74\x0c\x60\xb0\xd2\x74\xf0\xe2\x45\x1c\xbe\x7c\xf1\x32\x0e\x5f\x1e\x1c\x9c\xf9\xe8\x88\xb1\x94\x1b\x7f\xe7\x52\x9a\x81\xbc\xa9\x57\xb9\xac\x29\xc5\xaa\x1d\xd7\xe5\xa3\x5f\xdb\xcc\x39\xdc\x5a\xd3\xa6\x30\x4b\xca\x2c\x06\x73\x86\x57\xa7\xaa\x8a\xe9\x77\x1b\x98\xae\xdb\x40\x23\xb3\x23\xee\x47\xbe\x0d\x71\x62\xb6\xe2\x58\x4a\xfd\x94\xc9\x28\x8f\x7e\x8c\x18\xa5\x73\x4f\x69\xdc\x02\x6b\x21\x75\x50\xc0\x5a\xd4\x50\x5c\x8a\x3a\xda\xe1\x5f\x87\xeb\x89\x6c\x0e\xda\x0c\xf1\xfc\x88\x1d\xb3\x66\x8a\x20\xfb\x74\xa0\x31\x8e\x6c\x68\xb5\x80\x75\x58\x14\xa6\x55\x20\xad\x29\x82\x83\xe3\xef\x5d\x73\x53\xdc\xb4\x88\x0a\x68\xb2\x50\x30\xcb\xcd\x83\xf2\x1c\xdc\x2b\xe7\x39\x3e\x6c\x7b\x37\xcc\x7d\x21\x7b\x24\xa9\x59\xe1\x59\xfe\x8e\x48\xa3\xe5\x7b\xbc\x3d\xc7\xd5\x13\xb1\xa2\xef\x3b\x58\xfb\xb4\x76\x12\xe2\xcf\x4f\x8d\xf8\x8f\xe4\x96\xc8\x00\xdf\xff\x8c\x0c\x33\x32\xd8\x72\xa0\x69\x41\xd0\xc6\x98\x30\x74\xb7\xbc\xff\x9f\xd3\x36\x2d\xb2\x4f\xaa\x08\xd8\xee\xb5\xb1\x5f\xb3\xad\x94\x48\x89\x8a\x02\x17\xc2\xb3\x00\x7b\xda\x06\xfa\x55\x47\x5a\x04\x2d\x85\x45\x72\x5a\x1d\x67\xbc\xda\xe6\x56\x37\x2a\x0c\x47\xea\x71\x8f\xcd\x14\x2b\x2b\xc3\x86\x18\x55\xa6\xb5\x01\xd2\x37\xdb\xdf\x4e\x21\xea\x69\x57\xd6\xa2\x40\xcb\xb6\x56\xc8\x65\xc0\xe7\x8f\x1b\xf7\xc5\x6c\x47\x74\x4c\x57\x32\x15\xf4\xf7\xd9\x7a\x37\x80\xff\x3c\x89\x80\x81\x7e\xe5\xd0\x7d\x85\xb8\x59\x3a\x6f\x04\xeb\x93\xa3\x85\xb2\xcd\x10\xe1\x9d\x26\x11\x06\x00\x28\x32\x93\x8a\x2d\xa0\xfd\x2b\xd1\x8e\xa9\x62\xb2\x6c\x41\xc6\x0c\xd2\xd3\xc5\x41\x3e\x46\xbb\x68\x44\xcb\xf6\xd6\x59\x8c\xa4\x0c\xf9\x3a\xe4\xec\x9f\x7c\xf5\x81\xaf\x3e\xf4\x1c\x5b\x12\x5e\x10\x35\xd5\x3d\x05\x46\xbe\x8d\x32\x68\x49\xdc\x7b\xea\xe0\x6a\x94\x46\xbd\x7d\xbd\x0d\x92\xb3\x00\x59\x3a\x3e\x3a\x1b\x7d\x89\x36\x7c\x64\xd0\x8c\x93\x03\x8d\x3e\xa3\xb0\x68\x71\x3a\xe7\x06\x77\xd5\x17\x1d\x49\xad\x00\xd7\xb5\xc9\x41\x12\xc2\xb2\x31\xdb\xa4\x99\x87\x76\x56\x51\x3c\x34\xbe\x71\x6f\x2e\x47\x1c\xf4\xe1\xa2\x87\x37\x6b\xa0\xdc\x24\x58\x5e\x22\x86\x08\x9b\xe6\x76\x78\xc8\x9d\xb7\x25\x33\x2c\xb5\x85\xa1\x49\xa7\x1d\xbd\x3a\x2a\xd5\xb0\x0d\x46\x2d\x6d\xdd\x90\x9e\xed\xb4\x10\x1f\xd6\x7d\x69\x32\x8e\xc3\x6e\xc4\xd8\xa0\xbf\x63\xb4\xe6\x0c\x01\x43\x4f\x5a\xec\x87\x69\xd8\xf9\xe5\xb2\xa2\xe5\x9d\x48\x43\x86\x75\x2d\x91\x1f\x40\xe4\x5e\x11\x88\xaa\xb1\x39\x52\x02\x8b\xe3\xab\x38\xcc\x61\x07\xf3\x7d\x02\xdb\x85\xfa\x0d\x25\x28\xba\x41\xda\x09\xa9\x56\xd3\xda\xfc\x8e\x25\x2d\x36\x25\x35\x66\x52\xe1\x6d\x9f\x84\xe4\x88\xa4\x70\xfa\x2b\x30\x60\x97\x68\x8e\xb3\x55\xda\xac\xa9\xb4\x60\x3e\x68\xcc\x59\x4d\x78\x57\x1e\x66\x59\x0e\x85\x2d\x78\x01\xc2\xbc\xef\xd8\x6e\xab\x97\xb2\xc6\x2b\xe2\xaa\xb2\xea\x25\xe4\xa9\x8b\xe8\xa9\xdc\xf4\x8c\xb4\x30\xf0\xd1\xdd\x36\xb0\xfd\x6e\x03\xd3\x75\x1b\x68\x3c\x1c\x4a\xa7\xc2\x31\x7c\x68\xb2\xb8\x21\x30\x0e\x81\xf6\x69\xf7\x67\x29\xac\xd2\xcd\x91\x5f\x6e\x28\xfa\xf9\x21\x2f\x7a\x95\xa4\xfa\xf1\xa2\xd3\x5c\xd1\x5f\x1b\xb2\x10\xc2\x27\xfc\x5b\xd1\x86\x38\xb0\x49\x20\x0b\xc1\x24\x5c\xd2\xd7\x60\x38\x34\xa9\x17\xd2\x25\xea\x5f\xe1\x74\x7c\x7e\x3e\x3a\x2c\xc2\xc8\xdd\x66\x85\xb4\x59\x72\x88\x0f\x5d\x8b\x05\x11\x1b\x9c\x16\x03\x36\x4c\xe0\xa7\xe1\x30\x00\x70\x22\x33\xc7\xd2\x27\xbb\x51\x2a\x70\xc5\x15\x99\x06\x1c\xf3\x62\x54\x04\xbd\xbb\xbf\x9d\xf8\x05\x73\xe9\x97\xed\xf7\xdb\xce\x2e\xac\xe5\xa5\xa0\x40\xd0\xfe\x9d\x67\xf4\x97\xab\x2b\xe3\xd9\xa1\xbe\x70\xe6\xd7\x5f\x52\xc0\x7c\xa8\xa8\xd3\x66\x3a\x81\xb8\xfc\x07\xef\x46\xbd\xcd\x81\xc1\x8e\xa1\xc4\x29\x2e\xc7\x59\x0d\x23\x66\x8a\x83\x18\x99\x2a\xbb\xb9\x82\xbf\xc8\xd8\x32\x47\xeb\x46\xb4\x59\x86\x4a\x57\x1b\x55\xd3\xac\xff\x52\x39\x60\x4f\x74\x37\x4d\x74\xd0\xe9\x8d\xdd\x1c\x0c\x9d\x13\x9c\xb6\x40\xbf\x32\x05\xf6\x16\x05\xf3\x58\x83\xa1\x7e\x2a\xad\x49\x74\xe6\x35\x04\x3d\x19\x8f\xc4\x98\xa9\x7c\x83\x77\xdd\x1b\x5a\x93\xa9\x82\xab\x0d\xfd\x3f\x3e\xae\xcb\xfc\x2d\xad\xa1\x04\x0c\x61\x45\x7b\xb4\x6c\x24\x99\xd3\x57\xc5\x61\x93\x25\x75\x15\x87\x45\xb7\x91\x99\x9f\xf1\x21\x37\xbc\xc1\xa6\x98\x2b\x5a\xd2\x6c\xc5\xd7\x8d\x18\xd3\x26\xa9\x4d\xbf\x45\xda\xf4\x78\xdc\x34\xed\x05\x85\x39\xca\xc8\xb8\x25\x70\x50\x1e\x70\x4b\xa0\xc6\xa3\xde\x96\x00\x2f\x03\x17\x60\xbf\xda\x40\xbf\xea\x50\x47\x87\x45\xb1\x24\x38\x7a\x58\x14\x9e\x6a\x08\x6f\xaa\xb7\x6c\x58\xc6\x98\x98\x57\x70\x36\x49\x24\x8d\x8e\x84\xc9\x92\xc7\x81\x29\x5c\x86\xd4\x57\x33\x75\xdc\xbb\x55\xee\x1a\x34\x4c\x86\xc6\xa4\xd4\xaf\xf9\x69\x3e\x15\x44\xc5\xa1\xb8\x75\xc5\xac\x0a\x4c\x30\x72\x9e\x8d\x6f\x82\x81\x19\xac\xe9\xd1\x56\x5e\x69\x63\x0f\xd6\x8c\x59\x54\x93\x17\xfc\xed\x7f\xd3\x07\xc8\x94\xee\x7f\x6f\x48\x46\xae\xe9\x1e\x29\x0a\xf6\x3a\x69\xd6\x49\xbd\x97\xe6\xac\xdc\xf2\x3d\xbd\xbc\xc9\xf3\x5b\xf6\xa0\x61\xc9\xe7\x7b\x9d\x45\x82\x7b\xd2\x6c\xc1\x75\xdf\x54\xa5\xaa\x87\x60\xb8\xb3\x03\x4e\x49\xed\xe0\xbf\x1c\x69\xc7\xcd\x1f\x2c\xb5\x1a\x22\xa3\x83\xe3\x95\x5e\x1a\x9f\x5a\x6a\x27\x8b\x69\x17\x1c\xff\x02\x35\x81\xe0\x3c\xf8\xd1\x41\xf0\x6e\xab\xf0\x0a\x0e\x88\xdf\x59\xe3\x7c\xc8\xc7\x0e\xe4\x8f\x43\xc1\x1c\x2e\xad\xf7\x70\xc4\x09\xbc\x5b\x6a\xa7\xcd\x8e\xfb\xb0\x0d\x4c\xd7\x6d\xa0\xf1\x80\x9b\xa7\x05\xfe\xc2\x61\x91\xfc\x44\x7d\xf3\x99\xc4\xcb\xea\xdd\xb1\x02\xf9\x93\x3e\x8b\x64\xc8\x3e\x8c\x85\xf1\x8a\xa5\x69\x89\x85\x88\xc7\x52\x1c\xe2\x30\x3a\x62\xaa\x2a\x4a\xc2\x9f\xf2\x53\x5b\xf8\x8f\xef\x1f\x8a\xa4\xa4\x15\xff\x01\x35\x34\x3e\x54\xf2\x91\x4c\xbd\xfb\x89\x3e\xf6\x6f\xf9\xb1\x67\x51\x71\x8c\x85\x93\x3a\xa3\x7d\xb5\xda\x56\xdc\x89\xd3\x90\xc6\xc1\x57\x63\x0b\x5b\x94\x41\x46\xfb\xe7\x30\x0f\xdb\xb5\x5d\x2a\x3d\x66\x9b\xb5\x1d\x82\x30\x6b\x84\x53\xd3\x6b\x8a\x4b\x02\x35\x82\xf8\x3a\x3b\x30\x87\xf5\x18\x74\x8c\xfe\x0a\x9e\x86\xaa\xd2\xd0\x2b\xe8\xf3\xd1\xd0\x6b\xfc\xce\x69\xb0\xf7\xec\x5b\x91\xc2\x0c\x5d\x79\x4b\x1f\xc3\x0c\x0e\x3d\x08\x29\x27\x0f\xef\xc7\x81\x29\x7b\xe6\x06\x9c\xca\x5a\xa9\x2c\x49\xbb\xaa\xc5\xc9\x5d\x50\x97\xb4\xb1\x9d\xb4\x6e\x34\xbf\xcf\xdc\x96\xdf\x6e\xc4\xd1\x29\xd0\x13\x25\x2d\x52\x22\x26\x81\x5d\xd6\x45\x09\x6b\x2d\x10\xd3\xae\xea\xbc\x90\x47\x41\xc0\x41\x60\xc5\x84\x90\xf2\x61\x51\x2c\x9b\xfb\x03\x00\xef\x91\xbc\xa8\x70\xb6\x6e\x71\xce\x0f\xb3\x1a\xe5\x83\xd6\x9f\x1b\x3f\xd1\x65\x49\xdb\x0b\xe7\x5e\x53\xda\xd5\xc6\x2a\x38\x2f\x3f\xc9\x2e\x75\x6c\xb4\xa1\xf7\xe1\x2d\x7d\x84\xc2\x48\xe9\x23\x14\x18\xac\xa0\xe2\x10\xb9\xaa\xd9\x49\xe9\x52\xea\x14\x42\x2c\x2c\xe5\x5b\xf3\xbe\xe4\x09\xad\xc6\x02\xb6\x74\x03\x4a\xd6\x23\x91\x05\x5b\x4b\xca\xf2\x7a\xaf\x47\xd3\x3d\x9c\xa0\xdd\xcf\x83\x7c\x06\xe7\x2f\x76\xa6\x63\x35\x89\x5b\xf0\x3e\xfa\xad\x39\x50\xee\xcc\x31\x17\xd2\xb1\x23\x0e\xae\xd3\x6a\xc9\x76\x77\x49\x3b\xee\x66\x9d\x72\x03\xcf\xdd\xa8\x0a\xd0\x76\xcb\xe3\xa4\x28\x4c\xf0\x31\xcb\xe8\xd6\xf9\x2f\xde\xa1\x56\x4d\x0a\xd6\x39\xf8\x48\xed\xc7\x02\x8f\x11\x7b\x9e\x88\x2a\x23\xb7\xf0\x9e\x54\x39\x24\xec\x11\xdb\x4b\x86\xfa\x58\x63\x26\xb4\x81\xe9\xba\x0d\x34\x06\x45\xa7\x79\x3d\xcb\xe2\xa2\x36\x2c\xfa\xe5\x8e\x96\x29\xb1\x18\x56\xb3\xfe\x7b\x65\xcb\x1f\xfc\xbf\xbf\x1f\x98\x2a\x07\xc8\xf0\xce\xff\xff\xdb\x4b\x57\x5d\x81\x1f\xf3\xfb\x70\x03\x67\x1b\x70\x13\x57\x29\x47\xad\x02\xa7\x4b\xba\x21\x49\xc6\xca\x10\x27\xeb\x90\xc0\x19\x99\x55\xb2\xa6\x22\xb5\xf5\x3e\xcc\x33\x1a\x79\x32\xf7\xe3\x01\xdb\x11\x5c\x2d\x67\x2a\x1d\xc3\x71\x5a\x16\xdc\x1f\x42\x29\x70\xd1\x21\x9e\x57\x5e\x7b\xb8\x85\x32\x8d\x5e\xe9\xf8\xd3\xff\x45\x75\x52\x6b\x25\xea\x16\x02\x5c\xd3\x9a\x24\xe9\x36\x21\x56\xe6\xe3\x36\xfc\x58\xea\xc3\x56\xf8\x8b\x0a\x52\x92\x0d\xad\x69\x89\xbe\xe2\x41\xbe\x2a\x97\xb6\x7b\x6d\x60\xfb\xdd\x06\xa6\xeb\x36\xd0\x98\x14\x7d\x3c\xf8\x39\xc9\x6e\xd5\x56\xe1\x1c\xc1\xf9\x10\x55\x34\xbd\xd2\xee\x59\x9b\xab\xf4\x53\x04\x65\x6f\x26\x7d\x6d\x6d\xd2\x29\xe5\x3d\x8e\xe4\x90\xcc\x6a\x9f\xf8\x66\x78\xcf\x4e\x61\x0f\x15\xe6\x37\xd3\x82\x77\x81\x7e\x35\x6c\x1d\x4b\xc9\x56\xc0\xed\xae\x41\x71\x60\xf6\x25\x6a\x71\xfa\xf8\xa4\x06\x2f\xcc\xa7\x21\x75\x5d\x26\x97\x4d\x4d\x2b\x9c\xec\x11\x03\xec\x4c\x80\xbf\xbe\xa0\xf9\xf8\xd9\x98\x21\xda\x0b\x0a\x85\x42\x0f\x2c\x19\x30\xb3\x20\xa6\xe3\x84\x26\x23\x5c\x2b\x7b\x7f\x1e\x03\xf1\x40\x2c\x4b\x28\xcd\xc1\x67\x28\xbf\x84\xa0\x21\xf5\x6c\x2c\xa4\xf6\x44\x72\xd5\x57\xcc\xf6\xea\x95\xad\xf9\x91\x4e\xca\x52\xf2\x65\x10\x16\x60\xbf\x14\x92\xa3\x92\x42\x36\x54\x9e\x55\x37\x49\xb1\x65\x45\x84\x23\xc9\x1c\x3a\x63\x04\xeb\x06\x0d\x7f\xd1\xda\x94\x8a\xe2\x27\x67\xc6\x21\xc5\x00\xa7\x0d\x5c\x77\xc6\xfd\x2f\x77\x10\x3f\x57\xc3\x6d\x7e\xa8\xd3\x1f\xdd\x2e\xfb\x4c\x0c\xf4\x62\x6a\x80\x3d\x6d\x03\xfd\xaa\xeb\x80\xe8\xe3\x01\x94\x0f\xc8\xa8\xea\x6a\xe2\x1c\xc7\x39\x2d\xbf\x19\xde\x73\x69\xee\x60\x5e\x2e\x05\x60\xda\x68\xaa\x41\x47\xbf\xdd\xd5\xb0\xb9\xf6\xc9\x3e\x35\xce\x1e\xb5\x37\x14\x72\xe1\x5f\x74\x67\xcb\x9c\xf5\x10\xb7\xe1\xe7\x4e\x64\x90\x9b\x65\xa7\xdb\xd8\x7f\xb3\x6c\xfd\x1a\x4b\x6c\x35\xe1\x73\x42\xfb\xdf\x98\x36\x7f\x4c\x0b\xf4\xab\xae\x11\xd1\xc7\x83\xad\x6e\x06\x11\xdf\x0c\xef\xb9\x38\xd0\xdb\x86\xc1\xde\x9a\xaf\xcd\x3e\xc8\xbd\x3d\xdb\x0d\xdc\x69\x6f\x99\x6c\x87\x33\xed\x57\xec\xec\xd0\x5e\x68\x03\xec\x57\x1b\xe8\x57\xa3\xe9\x58\xf5\x3a\x5f\x35\x70\x62\x83\x82\x19\xe7\x9f\x45\x5e\x8c\xa3\xb5\x75\x94\xc6\xa3\x45\x8e\x51\xf9\xfd\x8d\x26\x19\xc3\xb6\x6a\xcc\x8d\x36\x33\x52\x66\xed\x12\x52\xe3\xdb\xe2\xbd\xc6\x0d\x4f\xc2\xd3\x51\x80\xc3\x87\x37\x3c\x2c\x12\x98\x10\xb4\x81\x86\x46\x4a\xc1\xae\x85\x60\x6a\x77\xaa\x7c\x90\xdb\x94\xc6\x70\x77\x22\x5c\xd2\xb9\x52\x3e\x6b\x51\xea\x9e\xa4\x97\x06\x86\xfd\xeb\xd4\xd8\xe1\xc8\xa4\x7c\x8a\x33\x76\xfb\x7a\xcb\x4e\xf5\x33\x3c\xd8\x9d\x51\xa7\xd9\x7a\x97\xf8\x3c\x39\xf9\x04\x22\xfa\xaf\x92\x14\x37\xbf\xfe\xbc\x64\x0d\xfd\xbf\x0d\xf5\xae\x21\xc7\xdf\x55\x6f\xba\xb8\x8a\x26\x67\xab\xcc\x02\x01\x62\x72\xea\x95\x7b\x18\x07\x9e\x6e\x9d\x8a\xa4\xdb\x40\x31\x55\xc0\x2d\x08\x7c\x7a\x67\x5a\xde\xc8\x5c\xcb\x31\x99\xf4\xe1\x9b\x5f\xd8\xba\xd3\x86\x56\x15\x14\xac\x32\x3d\x1c\x8b\xc3\xe8\x95\x36\x1e\x83\xec\x17\x9c\x1d\x40\x4d\x2d\x75\xb4\xd7\xaf\xd5\x7e\x6d\x17\x6f\xa5\x49\x86\x35\x5f\xc3\x86\xf8\x3b\x16\x5e\x08\x00\xab\x3c\x6d\x36\xea\x94\x65\x36\x12\xe3\xfd\x36\x18\xdf\x6c\x03\x0f\x02\xa3\x82\xd4\x37\x08\x61\x93\xfa\xc9\x80\x2d\xb0\xd1\xd3\x06\xa6\xeb\x36\xd0\x28\x65\x95\x83\xc0\x8f\xff\xfe\x8e\x66\xf3\xec\x2e\x5b\x12\x11\xe9\xe3\xa5\x80\xe6\x69\x86\xbb\x4f\xa7\x8d\x2d\xee\x25\x93\x8e\x8e\x11\x60\x1b\xcf\x51\xad\xc0\x59\x61\x62\xc8\x60\x02\x18\x8b\x79\x99\xc2\x10\x17\x5b\xfc\xe6\x91\x63\xe3\xa1\x63\xf0\x75\x03\x34\xe6\x89\x0f\x05\xdd\x0e\xd4\x08\x43\x4c\x59\x0c\x90\xd9\xc4\x80\x42\xe2\x0f\x2b\xa0\x21\x22\xc0\xfd\xe1\x31\xf2\x46\x62\xda\x6e\xe4\x5f\xd1\x4c\xa3\xd1\x3c\xed\x55\x15\x03\xbb\xd3\x06\xd8\xaf\x36\xd0\xaf\x3a\x2e\x46\xbf\xf1\x2d\x48\x16\x9d\x1a\x75\x59\x1f\xfa\x80\x4d\x4e\xfb\xbc\xa2\x32\xd3\x29\xa6\x24\xfb\xe2\x2c\xe2\xfe\x86\x1c\x05\xce\xbb\x8c\x3d\xf9\xe4\x9e\x66\xf5\x39\x9c\x3c\xc6\x36\x6e\xf0\x9a\x9b\xf5\xe3\x7e\xc9\x53\xc1\xe1\x1e\x49\x69\x59\xef\x5f\x25\x9c\x06\xf9\xbb\xa4\x55\x9e\xde\xd1\x75\x74\x86\x35\x68\x89\x7b\x26\x40\x78\x5a\x06\xf9\xb6\x7a\xdb\x86\x6d\x8c\xf1\x43\x99\x42\xe3\x58\x2f\x8c\x14\xd0\xae\x7e\xec\xdb\xf1\x6d\x5b\x07\x1a\x34\xae\x29\x13\xd3\x0b\x9a\x5e\x1c\x66\x21\xb9\xac\xf2\xb4\xa9\x69\x78\x53\xd7\x05\x64\x52\xc1\x7f\xab\xf0\xc3\xe9\xcf\x71\x78\x7f\x93\xac\x6e\xc2\x4d\x53\xd5\x61\x96\xd7\xfc\x00\x7d\xd8\xf7\x04\xfd\x9f\xde\xb0\x24\x70\x48\xb8\x4a\xf3\xbc\xb8\x24\xab\x5b\xd8\x6c\x9b\xdc\x41\x8d\x1a\x38\xfe\x34\xc9\x6e\x59\x7e\x5a\x1a\x92\xf5\xba\xa4\x95\xe2\x8f\x2b\x5d\x2c\xff\x24\xbb\xac\x8d\x9f\x9b\x14\x88\xd9\x57\xb7\xc9\x17\xf2\xc0\x88\x1b\x6b\x72\x1b\xd8\x7e\xb7\x81\xe9\xba\x0d\x34\x0e\x74\x12\x3a\xa4\x0f\x97\x37\x4d\xd6\xc0\x64\x85\x9a\xc8\x29\xbb\xa5\x9e\x60\xb7\x93\x49\x68\xbd\x3f\x46\xfa\xdd\xda\xe7\xf8\x80\x39\xb3\x33\x5b\x94\xbc\x77\x74\x55\x7a\x14\xb4\x88\x03\x9b\xaa\xbd\x17\x49\x8b\x4d\x05\xa7\xdf\xe6\x61\x95\x5c\x67\xe1\x9a\xa6\xc9\x1d\xdb\xee\x2a\x32\xc2\xd5\x03\x75\x21\x87\x4e\x64\x7d\xc2\x99\x8e\xd2\x0e\xa3\x84\x6e\x75\xd7\x4f\x60\x62\x4d\x1b\x68\x78\xa5\xe4\x2e\xca\xeb\xdf\x92\x7d\xf6\xe9\x78\x85\x79\xee\x66\x2d\xdb\xbf\x21\x81\x4c\x6b\x98\x7e\x7f\xa7\x8a\x30\xe4\xc7\x90\x23\x5e\xdc\x79\xcd\xc5\xf7\x71\x16\x73\x84\xdb\x2e\x40\x89\x5f\x5c\x2f\xfb\x03\x78\xe2\x30\x3a\xac\xe1\x54\x62\x6e\xd2\x4e\xc8\x63\x9a\x93\xf5\xae\xac\x9b\x5d\x83\xdf\x41\xca\x2b\xe1\x19\xb0\x9f\xf6\x7e\x6a\xd6\xbf\x37\x7b\x92\x03\xe1\x0d\xab\x82\xaa\x48\x57\x6c\xe8\xdb\x45\x36\x76\xec\xd3\xcd\xb7\x77\x2a\x64\xe4\x94\x21\xdf\x15\xb3\x42\xd6\x09\x04\x07\x83\x31\x84\xfb\x7a\x57\x24\x49\x07\x3e\xdd\x18\x6f\xd7\xb7\x28\x66\xc3\x94\xd9\x44\xba\xb9\xde\x93\x0e\xc4\x1e\xd5\xb1\xf7\x3e\xd8\xef\xe1\x31\x48\x62\x07\x01\xac\xba\x0e\x4e\x4b\xa3\x2b\x9a\xdc\xd9\x4c\x34\x6c\xe8\x33\x9f\x7b\xe4\x60\xb6\x6f\x08\x4a\xaa\x08\x0a\x7e\xa4\x96\x58\x6b\x2f\xf3\xf5\x63\x58\xe4\x15\x54\x24\xae\xf3\xe1\x60\xf4\x0c\x23\x90\x49\x6c\xe6\xe0\xb1\xf7\xf9\x24\x2a\x84\xee\x3f\x33\x15\x3f\x30\x1d\x7b\x6a\x12\x02\xfd\x0a\x1b\x1b\x16\x97\x3f\xea\xc0\x78\xda\xf9\x1e\x2f\xce\x91\xed\x0f\xa3\x02\xab\x76\xf0\x59\xeb\xc9\xb2\x43\x98\x13\x9f\x36\x29\x5d\x32\xc9\x85\xef\x3d\x79\xc4\x5e\x45\xb9\x33\xc2\x33\xc6\x25\xd7\x19\x61\x1c\xce\x37\x90\x84\x5f\xe7\x25\x58\xfc\xfe\xbc\xf6\x01\x25\x76\x6a\xe0\x8f\x97\xbc\x36\x0d\x8e\xf3\x22\x72\x0a\x7b\xe5\x1f\x3b\x88\x04\xc5\x61\xd5\x16\x93\x89\x3c\x2c\x0a\xb1\x99\xb1\x6c\x52\x0a\x2e\x3d\x6c\x37\x82\x3b\xb0\x57\x58\x0c\x0f\x10\x50\x89\xd9\x61\xec\x94\xac\x21\xf8\x44\xc2\x2a\xc9\xae\xe1\xfd\x71\x1e\x80\x89\x66\xc9\xe9\x99\x34\x77\x43\x34\x49\xca\x73\x18\x6b\x61\x81\xa8\x29\x59\x74\xe6\x0a\xdc\xeb\x34\xf9\x9d\x96\xe7\x29\xbd\xe3\xbd\x99\x26\xd7\x37\xcc\xff\xaa\xf2\x24\x3d\xdf\xe4\x49\x25\xdf\x5e\x89\xc3\xc6\xe9\xfa\x7c\xf4\x8c\x9d\x6b\x7d\x5e\x93\xec\xb6\x87\x74\x49\xea\x9a\x96\x8f\xe2\xc6\x99\xbb\xa1\xbd\x1c\x2d\x6d\x6b\xca\x5a\x90\xf2\xba\xe4\xd7\xec\xc7\x75\x4d\x3d\x68\xe8\xa5\x77\x4e\x2e\xcb\x08\xdc\xeb\xa6\x1c\xe7\x33\x8d\xa0\x99\x9d\x13\xfb\x1e\x2f\x6d\x1f\xd7\x0b\xb7\xb4\x1a\xf7\x71\xd5\xb2\xbd\x3c\xc6\x73\x49\xc3\xcb\x12\xa2\xa3\x10\x11\xa5\x57\x79\x09\x52\x4a\x43\x16\xa1\x0b\xaf\x8c\x9b\xe8\x74\xc4\xd1\x51\x9e\xa7\xeb\xfc\xfe\x8b\x68\x33\x38\x31\x02\xdc\x60\x5f\xab\x64\xc0\x25\xad\xef\x29\xcd\x20\xb2\x95\x5c\x25\x62\x59\x8b\x6f\x1c\xec\x5b\x9d\x54\xa1\x08\x55\x6a\xd8\xda\x00\xfb\xd5\x06\xfa\x95\xc1\xd0\x2b\xec\xc1\x2d\xaf\x71\xc6\xe4\x61\x7c\xe3\x81\xf0\xc5\x83\x4e\x79\xea\xa8\x10\x6a\xd6\x75\x08\x76\xe7\x04\x85\x8f\x59\xf4\x2d\x81\xc7\x8c\xaf\x0e\x1e\x07\x80\x1b\x35\x07\x89\x53\x8c\x99\x8a\x13\x37\x62\x16\x03\xa6\x82\x40\x0d\xd7\x48\x81\x51\x10\xa8\x1d\x98\x00\x62\x57\x73\x89\x40\xbf\x32\x28\xe8\x22\xa7\x75\xb1\x2b\x66\x75\x78\x7a\x23\x32\xb5\x51\xcb\x5c\x71\x06\x61\x42\xb3\xf4\x9b\xbb\x71\xc0\x8d\xdc\x18\xf2\xc3\x83\x37\xb3\xd9\x21\x2c\x72\x67\xe6\x44\xe0\x8a\x39\x1e\xbc\xd6\x6f\x2c\xab\x74\xf8\x5b\x5c\x09\x18\xe5\x9e\x90\xfa\xc1\x43\xdd\x02\x60\x56\x77\xb2\x23\xad\x02\x86\x58\x0b\x75\xd2\x85\x59\xb2\x41\x94\xa8\x5f\xfe\x1b\x2d\xfc\x8d\xb1\x3a\x8a\x26\x0b\x63\x16\x07\x4e\x57\x00\x16\xac\x4a\xba\x82\x30\x5e\xb7\x18\x0c\x43\xbd\x58\xfa\xc5\xdb\x7d\xc2\x29\x7f\x97\x64\x2b\x0b\x21\x5e\x63\xcd\x44\x53\x35\x7c\x3d\xfa\x21\x29\xe7\x98\xc3\x2d\x52\x70\x2a\x7a\xeb\x59\x89\xe8\xf5\x69\x22\x0d\x2e\x2c\x81\x7e\xd5\xe1\xe5\x26\x74\x99\xf9\xe4\x20\x3c\x6d\x80\x78\x19\x6d\xe0\xb6\x0d\xe8\x90\x0f\x43\x4e\x38\xb8\x52\x14\xbf\x36\x79\x4d\x66\xf1\xa3\x2b\xf3\x78\x42\xcb\x24\x67\x76\x94\x9f\x1b\xe9\xc7\x21\x73\xfd\x18\x87\x04\x74\xb6\xe8\x0b\xab\xcb\xa2\x9b\x1b\xc6\x90\xb9\x6d\x5b\xc3\xd0\x1a\x46\x9b\x3c\xab\x6f\xa2\xb3\x9d\x1d\xd2\xf9\x8d\xdb\xe2\xfa\x14\x13\xaa\xf3\xb0\xcc\x61\x27\x0c\xab\xa8\xc4\xc6\x85\xbe\x50\x4f\x98\x64\xfc\xbc\x9b\x82\xcb\xc8\x44\xb9\x5c\x74\x0a\x1e\x87\xe0\x29\x8d\xe2\x65\x94\x9b\xd3\xf4\x15\xa5\xd0\x44\xa7\x8f\x1e\xb9\xe8\xb7\xea\x94\x46\x94\x51\xfa\x74\x19\xfc\xc2\xf4\x4b\x13\x10\xb7\xae\xcd\x69\x33\xaa\x77\x38\x76\xb3\x0e\x6a\xc8\xcd\x9a\x38\xd2\x47\x43\x40\xe4\x69\xb4\x92\xd5\x5f\xfa\x9d\x96\x39\xc4\x40\x4b\x0a\xa7\xf3\x33\x80\xff\x05\x9d\x50\x07\x16\x55\x71\x4d\xbf\xdb\xc0\x74\xdd\x06\x1a\x0f\x87\x2a\xbe\x60\x4c\x7e\x46\x1d\xb7\x8f\xc9\xa2\x75\x2a\xf7\x26\xf0\xe6\xc3\x28\xf1\x7b\xf2\xb0\xfc\x3e\x17\x02\xfd\xfe\x26\xa9\xde\x30\xa1\x86\xb0\x12\x49\xd2\x47\x4e\x1b\x1c\x4a\x01\xb7\xe5\xef\x3f\xfd\xb8\xcd\x39\x36\x75\x44\xf5\xd3\xd0\x0d\x81\x9a\x5a\x80\xc0\xae\x9b\x11\x4e\x5d\xd7\x8d\xbb\xa4\x10\xce\x12\x64\x9e\xc7\x5c\x32\x07\x02\x36\x95\x4e\xfb\x1c\xc3\xaf\x15\x2c\xfe\x0b\x75\x96\x2f\x29\x6f\x11\x94\x77\x5c\x93\x47\xb5\xc8\x5c\x96\x8f\xad\x9b\xda\x0c\x45\x33\xbe\x98\x86\xb0\x9e\xf1\x68\x8a\x87\x71\x59\x64\x77\x45\xac\xe4\x35\x81\x7a\xb0\xb4\x16\x75\xdb\x19\xcf\x86\x37\x18\x22\x4f\xbb\xb2\x28\x62\x32\x24\xc4\x05\xc4\x63\x52\x69\xef\x30\x5e\xc3\x10\x54\xf9\x2f\x55\xd8\x40\x13\x21\xb9\x0f\x4a\xc1\x5a\x32\x74\x54\xde\x3c\x11\x8d\x9d\x32\x4f\x20\x74\x3c\xf2\xb8\x07\x49\x0d\xf9\xf7\x6c\x1d\x95\xbb\x1a\xd2\x33\xb9\x21\x40\x02\x5d\x0f\x88\x02\x31\x86\xdb\x84\x0b\x30\x28\xe6\xd6\x46\x5f\xde\x0a\xe5\xab\x36\x30\x5d\xb7\x81\xc6\x84\xe8\x10\xc6\x18\x5e\x2d\x5b\xc1\xec\xab\x1b\xc7\x4c\x35\x80\x06\xae\x24\x62\x0e\xc3\x6f\xbf\xa1\xf5\x0d\x9f\x2b\x9f\x82\x7b\x06\x17\x27\xb0\xcb\x07\x86\x69\x52\x5e\xd3\xba\x52\x93\xe7\xa6\xae\xf6\x2c\x3a\xcc\x4e\xd0\x3c\x51\x38\xb5\xce\x07\x0f\xf5\xc3\xf1\x6b\x19\x9b\x83\xbe\x17\xa6\x4c\x14\x95\x14\x16\x0e\x27\xa3\x67\xd8\x72\x4a\x94\x38\xe1\xc5\xa7\xbd\xa3\xbc\x94\xc5\x98\xf6\x8e\x5f\x5f\x88\xb4\x3f\xf9\x82\xcc\x03\xc3\x69\x13\xfd\xe7\x22\x0c\xfd\x9e\xf7\xfa\xf2\x76\xb1\x91\x39\x2c\xd8\x1a\x7d\x36\x64\x6b\xb8\x21\x35\x2c\x00\xc7\x21\xdd\xbf\xde\x0f\x2f\xfe\x0a\xe7\xdc\xfc\xf5\xbb\x26\x59\xff\x95\x6f\xe2\xb8\xc0\x89\x3b\x31\xed\x37\x9b\x4e\x1b\x6c\x5b\xe3\xeb\xb0\x9e\x07\x8a\x4a\xc9\x5f\x64\x74\x7e\x93\x0b\xbf\x92\x11\x64\x25\x8e\xe2\x4f\x38\x83\x20\x7a\x18\x5e\xfc\xe3\x36\xc9\xd6\xff\xfc\xee\x1f\xc9\xfa\x9f\x17\xb0\x41\x82\x96\x34\x84\x5b\x60\x19\xf3\x8c\x89\xca\x05\x24\x7d\x5c\xc4\xe1\x85\xf4\x26\xe1\x9a\x14\x05\xfc\x47\xf8\x9c\x17\x60\xbb\x2e\xd8\x12\xf3\x1e\x64\x8f\x5c\x78\x1b\x2e\x8d\x9b\xca\xe3\x16\xe5\x90\x2b\xbf\x13\xf1\x43\xfc\x52\x20\xdd\x52\xdf\x5b\xa1\x89\xd2\x61\x1a\xb8\x02\x53\x83\xdb\x40\xc3\x2b\xcd\xb0\x20\x6d\x88\xd7\xd7\x10\x73\x1b\xee\x3b\x3b\x94\x6f\xa3\x2d\x34\x0a\xe1\xcc\x01\x6a\x30\xc4\x28\x1f\xb6\x76\xe6\x04\xf2\xff\xdb\xa0\x0d\xfe\x6f\x00\x7d\x11\xe7\xbc\x20\xc9\x01\x00")
+
+func openapiJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_openapiJson,
+		"openapi.json",
+	)
+}
+
+func openapiJson() (*asset, error) {
+	bytes, err := openapiJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "openapi.json", size: 117024, mode: os.FileMode(0644), modTime: time.Unix(1792370881, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6c, 0x5c, 0x31, 0xa1, 0xc, 0xbc, 0x77, 0xd4, 0x97, 0x15, 0x33, 0xf, 0x5d, 0x1, 0xbd, 0xd7, 0x46, 0x2f, 0xe4, 0x42, 0x1f, 0xf3, 0xb9, 0xf3, 0x83, 0x40, 0xb5, 0xae, 0xcd, 0xea, 0x5a, 0xe0}}
+	return a, nil
+}
+
+// Asset loads and returns the asset for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func Asset(name string) ([]byte, error) {
+	canonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[canonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("Asset %s can't read by error: %v", name, err)
+		}
+		return a.bytes, nil
+	}
+	return nil, fmt.Errorf("Asset %s not found", name)
+}
+
+// AssetString returns the asset contents as a string (instead of a []byte).
+func AssetString(name string) (string, error) {
+	data, err := Asset(name)
+	return string(data), err
+}
+
+// MustAsset is like Asset but panics when Asset would return an error.
+// It simplifies safe initialization of global variables.
+func MustAsset(name string) []byte {
+	a, err := Asset(name)
+	if err != nil {
+		panic("asset: Asset(" + name + "): " + err.Error())
+	}
+
+	return a
+}
+
+// MustAssetString is like AssetString but panics when Asset would return an
+// error. It simplifies safe initialization of global variables.
+func MustAssetString(name string) string {
+	return string(MustAsset(name))
+}
+
+// AssetInfo loads and returns the asset info for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func AssetInfo(name string) (os.FileInfo, error) {
+	canonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[canonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("AssetInfo %s can't read by error: %v", name, err)
+		}
+		return a.info, nil
+	}
+	return nil, fmt.Errorf("AssetInfo %s not found", name)
+}
+
+// AssetDigest returns the digest of the file with the given name. It returns an
+// error if the asset could not be found or the digest could not be loaded.
+func AssetDigest(name string) ([sha256.Size]byte, error) {
+	canonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[canonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return [sha256.Size]byte{}, fmt.Errorf("AssetDigest %s can't read by error: %v", name, err)
+		}
+		return a.digest, nil
+	}
+	return [sha256.Size]byte{}, fmt.Errorf("AssetDigest %s not found", name)
+}
+
+// Digests returns a map of all known files and their checksums.
+func Digests() (map[string][sha256.Size]byte, error) {
+	mp := make(map[string][sha256.Size]byte, len(_bindata))
+	for name := range _bindata {
+		a, err := _bindata[name]()
+		if err != nil {
+			return nil, err
+		}
+		mp[name] = a.digest
+	}
+	return mp, nil
+}
+
+// AssetNames returns the names of the assets.
+func AssetNames() []string {
+	names := make([]string, 0, len(_bindata))
+	for name := range _bindata {
+		names = append(names, name)
+	}
+	return names
+}
+
+// _bindata is a table, holding each asset generator, mapped to its name.
+var _bindata = map[string]func() (*asset, error){
+	"openapi.json": openapiJson,
+}
+
+// AssetDir returns the file names below a certain
+// directory embedded in the file by go-bindata.
+// For example if you run go-bindata on data/... and data contains the
+// following hierarchy:
+//     data/
+//       foo.txt
+//       img/
+//         a.png
+//         b.png
+// then AssetDir("data") would return []string{"foo.txt", "img"},
+// AssetDir("data/img") would return []string{"a.png", "b.png"},
+// AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
+// AssetDir("") will return []string{"data"}.
+func AssetDir(name string) ([]string, error) {
+	node := _bintree
+	if len(name) != 0 {
+		canonicalName := strings.Replace(name, "\\", "/", -1)
+		pathList := strings.Split(canonicalName, "/")
+		for _, p := range pathList {
+			node = node.Children[p]
+			if node == nil {
+				return nil, fmt.Errorf("Asset %s not found", name)
+			}
+		}
+	}
+	if node.Func != nil {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	rv := make([]string, 0, len(node.Children))
+	for childName := range node.Children {
+		rv = append(rv, childName)
+	}
+	return rv, nil
+}
+
+type bintree struct {
+	Func     func() (*asset, error)
+	Children map[string]*bintree
+}
+
+var _bintree = &bintree{nil, map[string]*bintree{
+	"openapi.json": &bintree{openapiJson, map[string]*bintree{}},
+}}
+
+// RestoreAsset restores an asset under the given directory.
+func RestoreAsset(dir, name string) error {
+	data, err := Asset(name)
+	if err != nil {
+		return err
+	}
+	info, err := AssetInfo(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(_filePath(dir, filepath.Dir(name)), os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(_filePath(dir, name), data, info.Mode())
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(_filePath(dir, name), info.ModTime(), info.ModTime())
+}
+
+// RestoreAssets restores an asset under the given directory recursively.
+func RestoreAssets(dir, name string) error {
+	children, err := AssetDir(name)
+	// File
+	if err != nil {
+		return RestoreAsset(dir, name)
+	}
+	// Dir
+	for _, child := range children {
+		err = RestoreAssets(dir, filepath.Join(name, child))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func _filePath(dir, name string) string {
+	canonicalName := strings.Replace(name, "\\", "/", -1)
+	return filepath.Join(append([]string{dir}, strings.Split(canonicalName, "/")...)...)
+}
@@ -0,0 +1,217 @@
+package openapi
+
+//go:generate retool do go-bindata -pkg $GOPACKAGE -prefix "spec/" -o assets.go -ignore "\\.swp$" spec/
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// specName is the name of the embedded OpenAPI document
+	specName = "openapi.json"
+
+	// refPrefix is the prefix of every $ref we are able to resolve
+	refPrefix = "#/components/schemas/"
+)
+
+// Spec returns the raw bytes of the embedded OpenAPI document
+func Spec() []byte {
+	return MustAsset(specName)
+}
+
+// Document is the subset of an OpenAPI 3 document we need in order to validate
+// incoming requests.
+type Document struct {
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+
+	// patterns holds the compiled form of every pattern in the document
+	patterns map[string]*regexp.Regexp
+}
+
+// Operation is a single method on a path of the document
+type Operation struct {
+	OperationID string       `json:"operationId"`
+	RequestBody *RequestBody `json:"requestBody"`
+}
+
+// RequestBody describes the body an operation accepts
+type RequestBody struct {
+	Required bool `json:"required"`
+	Content  map[string]struct {
+		Schema *Schema `json:"schema"`
+	} `json:"content"`
+}
+
+// Schema is the subset of the OpenAPI schema object that we validate against
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Nullable   bool               `json:"nullable"`
+	Properties map[string]*Schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Items      *Schema            `json:"items"`
+	Enum       []interface{}      `json:"enum"`
+	Pattern    string             `json:"pattern"`
+	MinLength  *int               `json:"minLength"`
+	MinItems   *int               `json:"minItems"`
+	MaxItems   *int               `json:"maxItems"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+}
+
+// Load parses the embedded OpenAPI document, returning an error if it cannot be
+// parsed or contains a $ref or pattern we are unable to use.
+func Load() (*Document, error) {
+	return Parse(Spec())
+}
+
+// Parse parses the given OpenAPI document
+func Parse(b []byte) (*Document, error) {
+	var doc Document
+
+	err := json.Unmarshal(b, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse OpenAPI document")
+	}
+
+	doc.patterns = map[string]*regexp.Regexp{}
+
+	v := &validator{doc: &doc}
+
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			if op.RequestBody == nil {
+				continue
+			}
+
+			for _, content := range op.RequestBody.Content {
+				err = v.check(content.Schema)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid request schema for %s %s", strings.ToUpper(method), path)
+				}
+			}
+		}
+	}
+
+	return &doc, nil
+}
+
+// Operation returns the operation of the document for the given method and
+// path, or nil if the document does not describe it. Path templates such as
+// /user/{uid} match any single path segment.
+func (d *Document) Operation(method, path string) *Operation {
+	method = strings.ToLower(method)
+
+	if ops, ok := d.Paths[path]; ok {
+		return ops[method]
+	}
+
+	for template, ops := range d.Paths {
+		if matchPath(template, path) {
+			return ops[method]
+		}
+	}
+
+	return nil
+}
+
+// Routes returns every method and path described by the document in the form
+// "POST /user/new"
+func (d *Document) Routes() []string {
+	routes := []string{}
+
+	for path, ops := range d.Paths {
+		for method := range ops {
+			routes = append(routes, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	return routes
+}
+
+// FieldError describes a single field of a request body that does not match
+// the document
+type FieldError struct {
+	Field   string `json:"Field"`
+	Message string `json:"Message"`
+}
+
+// Error is our implementation of the error interface
+func (f FieldError) Error() string {
+	if f.Field == "" {
+		return f.Message
+	}
+
+	return fmt.Sprintf("%s %s", f.Field, f.Message)
+}
+
+// ValidateRequest validates the body of a request against the document,
+// returning a FieldError for each field that does not match. Requests for
+// operations the document does not describe, or that take no body, are not
+// validated.
+func (d *Document) ValidateRequest(method, path string, body []byte) []FieldError {
+	op := d.Operation(method, path)
+	if op == nil || op.RequestBody == nil {
+		return nil
+	}
+
+	content, ok := op.RequestBody.Content["application/json"]
+	if !ok || content.Schema == nil {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return []FieldError{{Message: "request body is required"}}
+		}
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var data interface{}
+	err := dec.Decode(&data)
+	if err != nil {
+		return []FieldError{{Message: fmt.Sprintf("request body is not valid JSON: %s", err.Error())}}
+	}
+
+	v := &validator{doc: d}
+	v.validate("", content.Schema, data)
+
+	return v.errors
+}
+
+// matchPath returns true if the path matches the path template
+func matchPath(template, path string) bool {
+	templateParts := strings.Split(template, "/")
+	pathParts := strings.Split(path, "/")
+
+	if len(templateParts) != len(pathParts) {
+		return false
+	}
+
+	for i, part := range templateParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if pathParts[i] == "" {
+				return false
+			}
+			continue
+		}
+
+		if part != pathParts[i] {
+			return false
+		}
+	}
+
+	return true
+}
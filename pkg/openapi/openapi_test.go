@@ -0,0 +1,188 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/openapi"
+)
+
+func TestLoad(t *testing.T) {
+	doc, err := openapi.Load()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, doc.Routes())
+}
+
+func TestParseInvalid(t *testing.T) {
+	testcases := []struct {
+		label string
+		input string
+	}{
+		{
+			label: "invalid json",
+			input: `{`,
+		},
+		{
+			label: "unknown ref",
+			input: `{"paths":{"/a":{"post":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Missing"}}}}}}}}`,
+		},
+		{
+			label: "invalid pattern",
+			input: `{"paths":{"/a":{"post":{"requestBody":{"content":{"application/json":{"schema":{"type":"string","pattern":"("}}}}}}}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			_, err := openapi.Parse([]byte(tc.input))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestOperation(t *testing.T) {
+	doc, err := openapi.Parse([]byte(`{"paths":{"/user/new":{"post":{"operationId":"new"}},"/user/{uid}":{"get":{"operationId":"get"}}}}`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "new", doc.Operation(http.MethodPost, "/user/new").OperationID)
+	assert.Equal(t, "get", doc.Operation(http.MethodGet, "/user/abc123").OperationID)
+	assert.Nil(t, doc.Operation(http.MethodGet, "/user/"))
+	assert.Nil(t, doc.Operation(http.MethodDelete, "/user/new"))
+	assert.Nil(t, doc.Operation(http.MethodPost, "/apps/new"))
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc, err := openapi.Load()
+	assert.Nil(t, err)
+
+	testcases := []struct {
+		label    string
+		method   string
+		path     string
+		body     string
+		expected []openapi.FieldError
+	}{
+		{
+			label:  "valid timeseries request",
+			method: http.MethodPost,
+			path:   "/timeSeries/get",
+			body: `{
+				"Readers": [{
+					"DataSourceCode": "Thingful.Connectors.GROWSensors",
+					"Settings": {
+						"LocationCodes": ["2pxqk4"],
+						"VariableCodes": ["Thingful.Connectors.GROWSensors.air_temperature"],
+						"StartDate": "20190329000000",
+						"EndDate": "20190330000000"
+					}
+				}]
+			}`,
+		},
+		{
+			label:  "invalid timeseries request",
+			method: http.MethodPost,
+			path:   "/timeSeries/get",
+			body: `{
+				"Readers": [{
+					"DataSourceCode": "Thingful.Connectors.Other",
+					"Settings": {
+						"LocationCodes": ["2pxqk4"],
+						"VariableCodes": ["air_temperature"],
+						"StartDate": "2019-03-29"
+					}
+				}]
+			}`,
+			expected: []openapi.FieldError{
+				{Field: "Readers[0].DataSourceCode", Message: "must be one of: Thingful.Connectors.GROWSensors"},
+				{Field: "Readers[0].Settings.EndDate", Message: "is required"},
+				{Field: "Readers[0].Settings.StartDate", Message: "must match the pattern ^[0-9]{14}$"},
+				{Field: "Readers[0].Settings.VariableCodes[0]", Message: `must match the pattern ^Thingful\.Connectors\.GROWSensors\.[a-z_]+$`},
+			},
+		},
+		{
+			label:  "wrong types",
+			method: http.MethodPost,
+			path:   "/entity/locations/get",
+			body:   `{"UserId": 12, "MatchAnyDataSource": "yes", "Limit": 1.5, "BoundingBox": [1, 2, 3]}`,
+			expected: []openapi.FieldError{
+				{Field: "BoundingBox", Message: "must contain at least 4 items"},
+				{Field: "Limit", Message: "must be of type integer"},
+				{Field: "MatchAnyDataSource", Message: "must be of type boolean"},
+				{Field: "UserId", Message: "must be of type string"},
+			},
+		},
+		{
+			label:  "out of range",
+			method: http.MethodPatch,
+			path:   "/entity/locations/update",
+			body:   `{"Code": "2pxqk4", "X": 181, "Y": -91}`,
+			expected: []openapi.FieldError{
+				{Field: "X", Message: "must be less than or equal to 180"},
+				{Field: "Y", Message: "must be greater than or equal to -90"},
+			},
+		},
+		{
+			label:  "null value",
+			method: http.MethodPost,
+			path:   "/entity/locations/history",
+			body:   `{"Code": null}`,
+			expected: []openapi.FieldError{
+				{Field: "Code", Message: "must be of type string, not null"},
+			},
+		},
+		{
+			label:  "missing required body",
+			method: http.MethodPost,
+			path:   "/apps/new",
+			body:   ``,
+			expected: []openapi.FieldError{
+				{Message: "request body is required"},
+			},
+		},
+		{
+			label:  "missing optional body",
+			method: http.MethodPost,
+			path:   "/entity/timeSeriesInformations/get",
+			body:   ``,
+		},
+		{
+			label:  "invalid json",
+			method: http.MethodPost,
+			path:   "/apps/new",
+			body:   `{"App":`,
+			expected: []openapi.FieldError{
+				{Message: "request body is not valid JSON: unexpected EOF"},
+			},
+		},
+		{
+			label:  "wrong root type",
+			method: http.MethodPost,
+			path:   "/apps/new",
+			body:   `[]`,
+			expected: []openapi.FieldError{
+				{Message: "must be of type object"},
+			},
+		},
+		{
+			label:  "operation without body",
+			method: http.MethodPost,
+			path:   "/entity/dataSourceVariables/get",
+			body:   `not json`,
+		},
+		{
+			label:  "unknown path",
+			method: http.MethodPost,
+			path:   "/unknown",
+			body:   `not json`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			got := doc.ValidateRequest(tc.method, tc.path, []byte(tc.body))
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// validator walks a decoded JSON value alongside a schema, collecting an error
+// for every field that does not match
+type validator struct {
+	doc    *Document
+	errors []FieldError
+}
+
+// resolve follows any $ref of the schema, returning the schema it refers to
+func (v *validator) resolve(schema *Schema) (*Schema, error) {
+	for schema.Ref != "" {
+		if !strings.HasPrefix(schema.Ref, refPrefix) {
+			return nil, errors.Errorf("unsupported $ref: %s", schema.Ref)
+		}
+
+		resolved, ok := v.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, refPrefix)]
+		if !ok {
+			return nil, errors.Errorf("unknown $ref: %s", schema.Ref)
+		}
+
+		schema = resolved
+	}
+
+	return schema, nil
+}
+
+// check walks the schema, returning an error if it contains a $ref we cannot
+// resolve or a pattern we cannot compile. Compiled patterns are stored on the
+// document for use when validating.
+func (v *validator) check(schema *Schema) error {
+	if schema == nil {
+		return nil
+	}
+
+	schema, err := v.resolve(schema)
+	if err != nil {
+		return err
+	}
+
+	if schema.Pattern != "" {
+		if _, ok := v.doc.patterns[schema.Pattern]; !ok {
+			re, err := regexp.Compile(schema.Pattern)
+			if err != nil {
+				return errors.Wrapf(err, "invalid pattern: %s", schema.Pattern)
+			}
+			v.doc.patterns[schema.Pattern] = re
+		}
+	}
+
+	for _, prop := range schema.Properties {
+		err = v.check(prop)
+		if err != nil {
+			return err
+		}
+	}
+
+	return v.check(schema.Items)
+}
+
+// fail records an error for the given field
+func (v *validator) fail(field, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validate validates the value against the schema, recording an error for
+// every field of the value that does not match
+func (v *validator) validate(field string, schema *Schema, value interface{}) {
+	schema, err := v.resolve(schema)
+	if err != nil {
+		v.fail(field, "%s", err.Error())
+		return
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			v.fail(field, "must be of type %s, not null", schema.Type)
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(field, "must be of type object")
+			return
+		}
+		v.validateObject(field, schema, obj)
+
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			v.fail(field, "must be of type array")
+			return
+		}
+		v.validateArray(field, schema, arr)
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.fail(field, "must be of type string")
+			return
+		}
+		v.validateString(field, schema, str)
+
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if !ok {
+			v.fail(field, "must be of type %s", schema.Type)
+			return
+		}
+		v.validateNumber(field, schema, num)
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(field, "must be of type boolean")
+			return
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		v.fail(field, "must be one of: %s", formatEnum(schema.Enum))
+	}
+}
+
+// validateObject checks required properties are present, then validates each
+// property in name order so errors are reported consistently
+func (v *validator) validateObject(field string, schema *Schema, obj map[string]interface{}) {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(join(field, name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if value, ok := obj[name]; ok {
+			v.validate(join(field, name), schema.Properties[name], value)
+		}
+	}
+}
+
+// validateArray checks the length of the array and validates each item
+func (v *validator) validateArray(field string, schema *Schema, arr []interface{}) {
+	if schema.MinItems != nil && len(arr) < *schema.MinItems {
+		v.fail(field, "must contain at least %d items", *schema.MinItems)
+	}
+
+	if schema.MaxItems != nil && len(arr) > *schema.MaxItems {
+		v.fail(field, "must contain at most %d items", *schema.MaxItems)
+	}
+
+	if schema.Items == nil {
+		return
+	}
+
+	for i, item := range arr {
+		v.validate(fmt.Sprintf("%s[%d]", field, i), schema.Items, item)
+	}
+}
+
+// validateString checks the length and pattern of the string
+func (v *validator) validateString(field string, schema *Schema, str string) {
+	if schema.MinLength != nil && len(str) < *schema.MinLength {
+		v.fail(field, "must be at least %d characters long", *schema.MinLength)
+	}
+
+	if schema.Pattern != "" {
+		re, ok := v.doc.patterns[schema.Pattern]
+		if ok && !re.MatchString(str) {
+			v.fail(field, "must match the pattern %s", schema.Pattern)
+		}
+	}
+}
+
+// validateNumber checks the number is an integer if required, and within any
+// minimum and maximum
+func (v *validator) validateNumber(field string, schema *Schema, num json.Number) {
+	f, err := num.Float64()
+	if err != nil {
+		v.fail(field, "must be a valid number")
+		return
+	}
+
+	if schema.Type == "integer" {
+		if _, err := num.Int64(); err != nil {
+			v.fail(field, "must be of type integer")
+			return
+		}
+	}
+
+	if schema.Minimum != nil && f < *schema.Minimum {
+		v.fail(field, "must be greater than or equal to %v", *schema.Minimum)
+	}
+
+	if schema.Maximum != nil && f > *schema.Maximum {
+		v.fail(field, "must be less than or equal to %v", *schema.Maximum)
+	}
+}
+
+// join returns the path to the named property of the field
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+// inEnum returns true if the value is one of the values of the enum. Numbers
+// are compared by value as the decoded value is a json.Number.
+func inEnum(enum []interface{}, value interface{}) bool {
+	if num, ok := value.(json.Number); ok {
+		f, err := num.Float64()
+		if err != nil {
+			return false
+		}
+		value = f
+	}
+
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatEnum returns the values of the enum as a comma separated list
+func formatEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprintf("%v", e)
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,410 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// LessThan fires a rule when the value of a channel is below the threshold
+	LessThan = "lt"
+
+	// LessThanOrEqual fires a rule when the value of a channel is at or below
+	// the threshold
+	LessThanOrEqual = "lte"
+
+	// GreaterThan fires a rule when the value of a channel is above the
+	// threshold
+	GreaterThan = "gt"
+
+	// GreaterThanOrEqual fires a rule when the value of a channel is at or above
+	// the threshold
+	GreaterThanOrEqual = "gte"
+
+	// AlertStatePending is the state of an alert whose rule has been breached
+	// for less than its duration
+	AlertStatePending = "pending"
+
+	// AlertStateFiring is the state of an alert whose rule has been breached for
+	// at least its duration
+	AlertStateFiring = "firing"
+
+	// AlertStateResolved is the state of an alert whose rule is no longer
+	// breached
+	AlertStateResolved = "resolved"
+
+	// MaxAlertRulesPerApp is the number of alert rules a single app may create
+	MaxAlertRulesPerApp = 100
+)
+
+var (
+	// comparators is a map of the comparators a rule may use
+	comparators = map[string]func(value, threshold float64) bool{
+		LessThan:           func(v, t float64) bool { return v < t },
+		LessThanOrEqual:    func(v, t float64) bool { return v <= t },
+		GreaterThan:        func(v, t float64) bool { return v > t },
+		GreaterThanOrEqual: func(v, t float64) bool { return v >= t },
+	}
+
+	// alertStates is a map of the states an alert may be in
+	alertStates = map[string]bool{
+		AlertStatePending:  true,
+		AlertStateFiring:   true,
+		AlertStateResolved: true,
+	}
+)
+
+// IsValidComparator returns true if the given value is a comparator rules may
+// use
+func IsValidComparator(comparator string) bool {
+	_, ok := comparators[comparator]
+	return ok
+}
+
+// IsValidAlertState returns true if the given value is a state alerts may be
+// in
+func IsValidAlertState(state string) bool {
+	return alertStates[state]
+}
+
+// IsKnownChannel returns true if the given value is the name of a channel of
+// our things
+func IsKnownChannel(name string) bool {
+	for _, channel := range makeChannels() {
+		if channel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertRule is a threshold on a channel of either a single thing, or all things
+// belonging to a user. Duration and Cooldown are in seconds.
+type AlertRule struct {
+	ID         int64       `db:"id"`
+	UID        string      `db:"uid"`
+	AppID      int64       `db:"app_id"`
+	ThingUID   null.String `db:"thing_uid"`
+	UserUID    null.String `db:"user_uid"`
+	Channel    string      `db:"channel"`
+	Comparator string      `db:"comparator"`
+	Threshold  float64     `db:"threshold"`
+	Duration   int         `db:"duration"`
+	Cooldown   int         `db:"cooldown"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
+
+// Breached returns true if the given value of the rule's channel breaches the
+// threshold
+func (r *AlertRule) Breached(value float64) bool {
+	compare, ok := comparators[r.Comparator]
+	if !ok {
+		return false
+	}
+	return compare(value, r.Threshold)
+}
+
+// Alert is the state of a rule for a single thing. Alerts are only stored once
+// the rule has been breached.
+type Alert struct {
+	ID           int64     `db:"id"`
+	RuleID       int64     `db:"rule_id"`
+	RuleUID      string    `db:"rule_uid"`
+	ThingID      int64     `db:"thing_id"`
+	ThingUID     string    `db:"thing_uid"`
+	State        string    `db:"state"`
+	Value        float64   `db:"value"`
+	PendingSince null.Time `db:"pending_since"`
+	FiredAt      null.Time `db:"fired_at"`
+	ResolvedAt   null.Time `db:"resolved_at"`
+	NotifiedAt   null.Time `db:"notified_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// alertRuleColumns are the columns selected when loading alert rules, which
+// expect the alert_rules table to be aliased as r
+const alertRuleColumns = `r.id, r.uid, r.app_id,
+	(SELECT uid FROM things WHERE things.id = r.thing_id) AS thing_uid,
+	(SELECT uid FROM users WHERE users.id = r.owner_id) AS user_uid,
+	r.channel, r.comparator, r.threshold, r.duration, r.cooldown, r.created_at`
+
+// alertColumns are the columns selected when loading alerts, which expect the
+// alerts table to be aliased as al
+const alertColumns = `al.id, al.rule_id, (SELECT uid FROM alert_rules WHERE alert_rules.id = al.rule_id) AS rule_uid,
+	al.thing_id, (SELECT uid FROM things WHERE things.id = al.thing_id) AS thing_uid,
+	al.state, al.value, al.pending_since, al.fired_at, al.resolved_at, al.notified_at, al.updated_at`
+
+// CreateAlertRule saves a new rule for the app. The rule must identify either a
+// thing or a user by their UID, and apps may create at most 100 rules.
+func (d *DB) CreateAlertRule(ctx context.Context, appUID string, rule *AlertRule) (*AlertRule, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "creating alert rule",
+			"appUID", appUID,
+			"thingUID", rule.ThingUID.String,
+			"userUID", rule.UserUID.String,
+			"channel", rule.Channel,
+		)
+	}
+
+	if rule.ThingUID.Valid == rule.UserUID.Valid {
+		return nil, errors.Wrap(ClientError, "a rule must apply to either a thing or a user")
+	}
+
+	if !IsKnownChannel(rule.Channel) {
+		return nil, errors.Wrap(ClientError, fmt.Sprintf("unknown channel: %s", rule.Channel))
+	}
+
+	if !IsValidComparator(rule.Comparator) {
+		return nil, errors.Wrap(ClientError, fmt.Sprintf("unknown comparator: %s", rule.Comparator))
+	}
+
+	if rule.Duration < 0 || rule.Cooldown < 0 {
+		return nil, errors.Wrap(ClientError, "duration and cooldown must not be negative")
+	}
+
+	uid, err := randomUID(10)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create random UID when creating alert rule")
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+
+	var appID int64
+
+	// lock the app so concurrent requests can't exceed the limit
+	err = tx.Get(&appID, `SELECT id FROM applications WHERE uid = $1 FOR UPDATE`, appUID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to load app")
+	}
+
+	var count int
+	err = tx.Get(&count, `SELECT COUNT(*) FROM alert_rules WHERE app_id = $1`, appID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to count alert rules")
+	}
+
+	if count >= MaxAlertRulesPerApp {
+		tx.Rollback()
+		return nil, errors.Wrap(ClientError, fmt.Sprintf("an app may create at most %d alert rules", MaxAlertRulesPerApp))
+	}
+
+	var thingID, ownerID null.Int
+
+	if rule.ThingUID.Valid {
+		err = tx.Get(&thingID, `SELECT id FROM things WHERE uid = $1`, rule.ThingUID.String)
+		if err != nil {
+			tx.Rollback()
+			if err == sql.ErrNoRows {
+				return nil, errors.Wrap(ClientError, fmt.Sprintf("unknown thing: %s", rule.ThingUID.String))
+			}
+			return nil, errors.Wrap(err, "failed to load thing")
+		}
+	} else {
+		err = tx.Get(&ownerID, `SELECT id FROM users WHERE uid = $1`, rule.UserUID.String)
+		if err != nil {
+			tx.Rollback()
+			if err == sql.ErrNoRows {
+				return nil, errors.Wrap(ClientError, fmt.Sprintf("unknown user: %s", rule.UserUID.String))
+			}
+			return nil, errors.Wrap(err, "failed to load user")
+		}
+	}
+
+	created := *rule
+	created.UID = uid
+	created.AppID = appID
+
+	sqlQuery := `INSERT INTO alert_rules
+		(uid, app_id, thing_id, owner_id, channel, comparator, threshold, duration, cooldown)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id, created_at`
+
+	err = tx.QueryRowx(
+		sqlQuery,
+		created.UID, appID, thingID, ownerID, created.Channel,
+		created.Comparator, created.Threshold, created.Duration, created.Cooldown,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to insert alert rule")
+	}
+
+	return &created, tx.Commit()
+}
+
+// ListAlertRules returns the alert rules of the app in the order they were
+// created
+func (d *DB) ListAlertRules(ctx context.Context, appUID string) ([]AlertRule, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "listing alert rules", "appUID", appUID)
+	}
+
+	sql := `SELECT ` + alertRuleColumns + `
+		FROM alert_rules r
+		JOIN applications a ON a.id = r.app_id
+		WHERE a.uid = $1
+		ORDER BY r.id`
+
+	rules := []AlertRule{}
+
+	err := d.DB.Select(&rules, sql, appUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list alert rules")
+	}
+
+	return rules, nil
+}
+
+// DeleteAlertRule deletes the app's alert rule with the given uid along with
+// its alerts. Clients can unwrap the returned error to check for an
+// sql.ErrNoRows error to determine if the rule does not exist.
+func (d *DB) DeleteAlertRule(ctx context.Context, appUID, uid string) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "deleting alert rule", "appUID", appUID, "uid", uid)
+	}
+
+	sql := `DELETE FROM alert_rules r
+		USING applications a
+		WHERE a.id = r.app_id AND a.uid = $1 AND r.uid = $2
+		RETURNING r.id`
+
+	var id int64
+
+	err := d.DB.Get(&id, sql, appUID, uid)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete alert rule")
+	}
+
+	return nil
+}
+
+// GetAlertRulesForThing returns the rules that apply to the thing, either
+// directly or via the user who owns it. Rules of apps that no longer hold the
+// timeseries scope are skipped, as alerts carry the reading that breached the
+// rule.
+func (d *DB) GetAlertRulesForThing(ctx context.Context, thingID, ownerID int64) ([]AlertRule, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "loading alert rules for thing", "thingID", thingID)
+	}
+
+	sql := `SELECT ` + alertRuleColumns + `
+		FROM alert_rules r
+		JOIN applications a ON a.id = r.app_id
+		WHERE (r.thing_id = $1 OR r.owner_id = $2)
+		AND $3 = ANY(a.scope)
+		ORDER BY r.id`
+
+	rules := []AlertRule{}
+
+	err := d.DB.Select(&rules, sql, thingID, ownerID, GetTimeSeriesDataScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load alert rules for thing")
+	}
+
+	return rules, nil
+}
+
+// GetAlert returns the state of the rule for the thing. Clients can unwrap the
+// returned error to check for an sql.ErrNoRows error to determine if the rule
+// has never been breached by the thing.
+func (d *DB) GetAlert(ctx context.Context, ruleID, thingID int64) (*Alert, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "loading alert", "ruleID", ruleID, "thingID", thingID)
+	}
+
+	sql := `SELECT ` + alertColumns + `
+		FROM alerts al
+		WHERE al.rule_id = $1 AND al.thing_id = $2`
+
+	var alert Alert
+
+	err := d.DB.Get(&alert, sql, ruleID, thingID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load alert")
+	}
+
+	return &alert, nil
+}
+
+// SaveAlert inserts or updates the state of the rule for the thing
+func (d *DB) SaveAlert(ctx context.Context, alert *Alert) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "saving alert", "ruleID", alert.RuleID, "thingID", alert.ThingID, "state", alert.State)
+	}
+
+	sql := `INSERT INTO alerts
+		(rule_id, thing_id, state, value, pending_since, fired_at, resolved_at, notified_at)
+	VALUES (:rule_id, :thing_id, :state, :value, :pending_since, :fired_at, :resolved_at, :notified_at)
+	ON CONFLICT (rule_id, thing_id) DO UPDATE SET
+		state = EXCLUDED.state,
+		value = EXCLUDED.value,
+		pending_since = EXCLUDED.pending_since,
+		fired_at = EXCLUDED.fired_at,
+		resolved_at = EXCLUDED.resolved_at,
+		notified_at = EXCLUDED.notified_at,
+		updated_at = NOW()
+	RETURNING id, updated_at`
+
+	sql, args, err := d.DB.BindNamed(sql, alert)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind named alert query")
+	}
+
+	err = d.DB.QueryRowx(sql, args...).Scan(&alert.ID, &alert.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to save alert")
+	}
+
+	return nil
+}
+
+// ListAlerts returns the alerts of the app's rules in the given state, most
+// recently updated first
+func (d *DB) ListAlerts(ctx context.Context, appUID, state string) ([]Alert, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "listing alerts", "appUID", appUID, "state", state)
+	}
+
+	sql := `SELECT ` + alertColumns + `
+		FROM alerts al
+		JOIN alert_rules r ON r.id = al.rule_id
+		JOIN applications a ON a.id = r.app_id
+		WHERE a.uid = $1 AND al.state = $2
+		ORDER BY al.updated_at DESC, al.id DESC`
+
+	alerts := []Alert{}
+
+	err := d.DB.Select(&alerts, sql, appUID, state)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list alerts")
+	}
+
+	return alerts, nil
+}
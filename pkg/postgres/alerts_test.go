@@ -0,0 +1,214 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type AlertsSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *AlertsSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *AlertsSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+// createThing inserts a user with a single thing, returning the thing
+func (s *AlertsSuite) createThing(ctx context.Context) *postgres.Thing {
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ('user1') RETURNING id`)
+	assert.Nil(s.T(), err)
+
+	thing := &postgres.Thing{
+		UID:        null.StringFrom("abc123"),
+		OwnerID:    null.IntFrom(userID),
+		Provider:   null.StringFrom("parrot"),
+		SerialNum:  "PA123",
+		LocationID: "abc123",
+	}
+
+	err = s.db.CreateThing(ctx, thing)
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), int64(0), thing.ID)
+
+	return thing
+}
+
+func (s *AlertsSuite) TestCreateListDeleteRules() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	thing := s.createThing(ctx)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	thingRule, err := s.db.CreateAlertRule(ctx, app.UID, &postgres.AlertRule{
+		ThingUID:   null.StringFrom("abc123"),
+		Channel:    "soil_moisture",
+		Comparator: postgres.LessThan,
+		Threshold:  15,
+		Duration:   3600,
+	})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), thingRule.UID, 10)
+
+	_, err = s.db.CreateAlertRule(ctx, app.UID, &postgres.AlertRule{
+		UserUID:    null.StringFrom("user1"),
+		Channel:    "battery_level",
+		Comparator: postgres.LessThanOrEqual,
+		Threshold:  10,
+	})
+	assert.Nil(s.T(), err)
+
+	rules, err := s.db.ListAlertRules(ctx, app.UID)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rules, 2)
+	assert.Equal(s.T(), null.StringFrom("abc123"), rules[0].ThingUID)
+	assert.False(s.T(), rules[0].UserUID.Valid)
+	assert.Equal(s.T(), null.StringFrom("user1"), rules[1].UserUID)
+
+	rules, err = s.db.GetAlertRulesForThing(ctx, thing.ID, thing.OwnerID.Int64)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rules, 2)
+
+	// rules stop applying once the app loses the timeseries scope
+	_, err = s.db.DB.Exec(`UPDATE applications SET scope = '{metadata}' WHERE uid = $1`, app.UID)
+	assert.Nil(s.T(), err)
+
+	rules, err = s.db.GetAlertRulesForThing(ctx, thing.ID, thing.OwnerID.Int64)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rules, 0)
+
+	err = s.db.DeleteAlertRule(ctx, "other", thingRule.UID)
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	err = s.db.DeleteAlertRule(ctx, app.UID, thingRule.UID)
+	assert.Nil(s.T(), err)
+
+	rules, err = s.db.ListAlertRules(ctx, app.UID)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rules, 1)
+}
+
+func (s *AlertsSuite) TestCreateRuleInvalid() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	testcases := []struct {
+		label string
+		rule  postgres.AlertRule
+	}{
+		{
+			label: "no target",
+			rule:  postgres.AlertRule{Channel: "light", Comparator: postgres.LessThan},
+		},
+		{
+			label: "unknown channel",
+			rule:  postgres.AlertRule{UserUID: null.StringFrom("user1"), Channel: "humidity", Comparator: postgres.LessThan},
+		},
+		{
+			label: "unknown comparator",
+			rule:  postgres.AlertRule{UserUID: null.StringFrom("user1"), Channel: "light", Comparator: "eq"},
+		},
+		{
+			label: "unknown user",
+			rule:  postgres.AlertRule{UserUID: null.StringFrom("user1"), Channel: "light", Comparator: postgres.LessThan},
+		},
+		{
+			label: "unknown thing",
+			rule:  postgres.AlertRule{ThingUID: null.StringFrom("abc123"), Channel: "light", Comparator: postgres.LessThan},
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			_, err := s.db.CreateAlertRule(ctx, app.UID, &tc.rule)
+			assert.Equal(t, postgres.ClientError, errors.Cause(err))
+		})
+	}
+}
+
+func (s *AlertsSuite) TestSaveAndListAlerts() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	thing := s.createThing(ctx)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	rule, err := s.db.CreateAlertRule(ctx, app.UID, &postgres.AlertRule{
+		ThingUID:   null.StringFrom("abc123"),
+		Channel:    "soil_moisture",
+		Comparator: postgres.LessThan,
+		Threshold:  15,
+	})
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.GetAlert(ctx, rule.ID, thing.ID)
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	alert := &postgres.Alert{
+		RuleID:  rule.ID,
+		ThingID: thing.ID,
+		State:   postgres.AlertStateFiring,
+		Value:   12,
+		FiredAt: null.TimeFrom(now),
+	}
+
+	err = s.db.SaveAlert(ctx, alert)
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), int64(0), alert.ID)
+
+	alerts, err := s.db.ListAlerts(ctx, app.UID, postgres.AlertStateFiring)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), alerts, 1)
+	assert.Equal(s.T(), rule.UID, alerts[0].RuleUID)
+	assert.Equal(s.T(), "abc123", alerts[0].ThingUID)
+	assert.Equal(s.T(), 12.0, alerts[0].Value)
+
+	alert.State = postgres.AlertStateResolved
+	alert.ResolvedAt = null.TimeFrom(now)
+
+	err = s.db.SaveAlert(ctx, alert)
+	assert.Nil(s.T(), err)
+
+	readAlert, err := s.db.GetAlert(ctx, rule.ID, thing.ID)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), alert.ID, readAlert.ID)
+	assert.Equal(s.T(), postgres.AlertStateResolved, readAlert.State)
+
+	alerts, err = s.db.ListAlerts(ctx, app.UID, postgres.AlertStateFiring)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), alerts, 0)
+}
+
+func TestAlertsSuite(t *testing.T) {
+	suite.Run(t, new(AlertsSuite))
+}
@@ -3,11 +3,14 @@ package postgres
 import (
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"database/sql/driver"
 	"encoding/base32"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/guregu/null"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/thingful/kudzu/pkg/logger"
@@ -15,6 +18,11 @@ import (
 
 const (
 	pqUniqueViolation = "23505"
+
+	// AppChangesChannel is the name of the channel on which we notify listeners
+	// when the key, scopes or limits of an app change. The payload of each
+	// notification is the UID of the app.
+	AppChangesChannel = "app_changes"
 )
 
 // ScopeClaim is a custom type used to represent existing scope levels
@@ -87,11 +95,41 @@ const (
 	// GetTimeSeriesDataScope is used for clients that can query time series data
 	GetTimeSeriesDataScope = ScopeClaim("timeseries")
 
+	// DeleteUserScope is used for clients allowed to delete users
+	DeleteUserScope = ScopeClaim("delete-users")
+
+	// UpdateLocationScope is used for clients allowed to move devices
+	UpdateLocationScope = ScopeClaim("update-locations")
+
+	// ExportUserScope is used for clients allowed to export everything we hold
+	// about a user
+	ExportUserScope = ScopeClaim("export-users")
+
+	// ManageAppsScope is used for clients allowed to list, revoke, rotate and
+	// expire the keys of client applications
+	ManageAppsScope = ScopeClaim("manage-apps")
+
+	// ReadAuditLogScope is used for clients allowed to search the audit log of
+	// requests made by every app
+	ReadAuditLogScope = ScopeClaim("audit-log")
+
+	// ManageWebhooksScope is used for clients allowed to register webhooks, which
+	// receive events about every user
+	ManageWebhooksScope = ScopeClaim("webhooks")
+
 	// encodeCrockford is a list of characters for generating crockford style base 32
 	encodeCrockford = "0123456789abcdefghjkmnpqrstvwxyz"
 
 	// keyLength is the length of the hash in bytes we generate
 	keyLength = 16
+
+	// defaultAppRate is the rate limit in requests per second of apps created
+	// without one
+	defaultAppRate = 4
+
+	// lastUsedResolution is how often we record that an app was used, so that
+	// we don't write to the applications table on every request
+	lastUsedResolution = "1 minute"
 )
 
 var (
@@ -100,6 +138,12 @@ var (
 		CreateUserScope:        "Can create new users",
 		GetMetadataScope:       "Can query metadata",
 		GetTimeSeriesDataScope: "Can query time series data",
+		DeleteUserScope:        "Can delete users",
+		UpdateLocationScope:    "Can update the location of devices",
+		ExportUserScope:        "Can export all data held about users",
+		ManageAppsScope:        "Can manage the keys of client applications",
+		ReadAuditLogScope:      "Can search the audit log of requests",
+		ManageWebhooksScope:    "Can register webhooks receiving events about every user",
 	}
 
 	// crockfordEncoding is our base32 encoding that uses our custom string
@@ -108,17 +152,53 @@ var (
 
 // App is our type used for reading auth information back from the DB
 type App struct {
-	UID   string      `db:"uid"`
-	Name  string      `db:"app_name"`
-	Hash  string      `db:"key_hash"`
-	Roles ScopeClaims `db:"scope"`
-	Rate  int         `db:"rate"`
-	Key   string
+	UID        string      `db:"uid"`
+	Name       string      `db:"app_name"`
+	Hash       string      `db:"key_hash"`
+	Roles      ScopeClaims `db:"scope"`
+	Rate       int         `db:"rate"`
+	Burst      null.Int    `db:"burst"`
+	CreatedAt  null.Time   `db:"created_at"`
+	RevokedAt  null.Time   `db:"revoked_at"`
+	ExpiresAt  null.Time   `db:"expires_at"`
+	LastUsedAt null.Time   `db:"last_used_at"`
+
+	// PreviousKeyExpiresAt is when the key replaced by the last rotation stops
+	// being accepted
+	PreviousKeyExpiresAt null.Time `db:"previous_key_expires_at"`
+
+	Key string
+}
+
+// BurstSize returns the number of requests the app may make at once before
+// being held to its rate, which unless set is twice the rate
+func (a *App) BurstSize() int {
+	if a.Burst.Valid {
+		return int(a.Burst.Int64)
+	}
+
+	return a.Rate * 2
+}
+
+// AppUpdate holds changes to the limits, scopes and expiry of an app, nil
+// fields being left unchanged
+type AppUpdate struct {
+	Rate *int
+
+	// Burst set to a null Int resets the burst to twice the rate
+	Burst *null.Int
+
+	Scope ScopeClaims
+
+	// ExpiresAt set to a null Time removes any expiry
+	ExpiresAt *null.Time
 }
 
 // CreateApp attempts to create and store an app record into the DB. We generate
-// a random UID and a random api key which is hashed and stored to the DB
-func (d *DB) CreateApp(ctx context.Context, name string, claims ScopeClaims) (*App, error) {
+// a random UID and a random api key which is hashed and stored to the DB. A
+// rate of zero gives the app our default rate, and a burst of zero twice its
+// rate.
+func (d *DB) CreateApp(ctx context.Context, name string, claims ScopeClaims, rate, burst int) (*App, error) {
 	log := logger.FromContext(ctx)
 
 	if d.verbose {
@@ -126,6 +206,8 @@ func (d *DB) CreateApp(ctx context.Context, name string, claims ScopeClaims) (*A
 			"msg", "creating app",
 			"name", name,
 			"claims", claims,
+			"rate", rate,
+			"burst", burst,
 		)
 	}
 
@@ -133,6 +215,14 @@ func (d *DB) CreateApp(ctx context.Context, name string, claims ScopeClaims) (*A
 		return nil, errors.New("invalid scope claims")
 	}
 
+	if rate < 0 || burst < 0 {
+		return nil, errors.New("rate and burst must not be negative")
+	}
+
+	if rate == 0 {
+		rate = defaultAppRate
+	}
+
 	b, err := randomBytes(keyLength)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get random bytes when creating app")
@@ -148,17 +238,22 @@ func (d *DB) CreateApp(ctx context.Context, name string, claims ScopeClaims) (*A
 		Name:  name,
 		Hash:  fmt.Sprintf("%x", b),
 		Roles: claims,
+		Rate:  rate,
 		Key:   fmt.Sprintf("%s-%x", uid, b),
 	}
 
+	if burst > 0 {
+		app.Burst = null.IntFrom(int64(burst))
+	}
+
 	tx, err := d.DB.Beginx()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create transaction to save app")
 	}
 
 	sql := `INSERT INTO applications
-		(uid, app_name, key_hash, scope)
-	VALUES (:uid, :app_name, crypt(:key_hash, gen_salt('bf', 5)), :scope)`
+		(uid, app_name, key_hash, scope, rate, burst)
+	VALUES (:uid, :app_name, crypt(:key_hash, gen_salt('bf', 5)), :scope, :rate, :burst)`
 
 	sql, args, err := tx.BindNamed(sql, app)
 	if err != nil {
@@ -181,7 +276,9 @@ func (d *DB) CreateApp(ctx context.Context, name string, claims ScopeClaims) (*A
 }
 
 // LoadApp attempts to load an app on being given the key. We compare against
-// the hashed version in the DB.
+// the hashed version in the DB, falling back to the key replaced by the last
+// rotation while its overlap window lasts. Clients can check for a
+// RevokedKeyError or ExpiredKeyError to tell why a valid key was rejected.
 func (d *DB) LoadApp(ctx context.Context, key string) (*App, error) {
 	log := logger.FromContext(ctx)
 
@@ -193,21 +290,259 @@ func (d *DB) LoadApp(ctx context.Context, key string) (*App, error) {
 
 	parts := strings.Split(key, "-")
 	if len(parts) != 2 {
-		return nil, errors.New("invalid key")
+		return nil, InvalidKeyError
 	}
 
-	sql := `SELECT uid, app_name, scope, rate FROM applications WHERE uid = $1 AND key_hash = crypt($2, key_hash)`
+	sqlQuery := `SELECT uid, app_name, scope, rate, burst, revoked_at, expires_at, previous_key_expires_at
+		FROM applications WHERE uid = $1 AND key_hash = crypt($2, key_hash)`
 
 	var app App
 
-	err := d.DB.Get(&app, sql, parts[0], parts[1])
+	err := d.DB.Get(&app, sqlQuery, parts[0], parts[1])
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get app from DB")
+		if errors.Cause(err) != sql.ErrNoRows {
+			return nil, errors.Wrap(err, "failed to get app from DB")
+		}
+
+		// only apps that have been rotated pay for a second comparison
+		sqlQuery = `SELECT uid, app_name, scope, rate, burst, revoked_at, expires_at, previous_key_expires_at
+			FROM applications WHERE uid = $1 AND previous_key_hash = crypt($2, previous_key_hash)`
+
+		err = d.DB.Get(&app, sqlQuery, parts[0], parts[1])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get app from DB")
+		}
+
+		if !app.PreviousKeyExpiresAt.Valid || !app.PreviousKeyExpiresAt.Time.After(time.Now()) {
+			return nil, ExpiredKeyError
+		}
+	}
+
+	if app.RevokedAt.Valid {
+		return nil, RevokedKeyError
+	}
+
+	if app.ExpiresAt.Valid && !app.ExpiresAt.Time.After(time.Now()) {
+		return nil, ExpiredKeyError
+	}
+
+	_, err = d.DB.Exec(
+		`UPDATE applications SET last_used_at = NOW()
+		WHERE uid = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - interval '`+lastUsedResolution+`')`,
+		app.UID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to record app use")
+	}
+
+	return &app, nil
+}
+
+// ListApps returns every app in order of creation, without their keys
+func (d *DB) ListApps(ctx context.Context) ([]App, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "listing apps")
+	}
+
+	sql := `SELECT uid, app_name, scope, rate, burst, created_at, revoked_at, expires_at,
+			last_used_at, previous_key_expires_at
+		FROM applications
+		ORDER BY created_at, id`
+
+	apps := []App{}
+
+	err := d.DB.Select(&apps, sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list apps")
+	}
+
+	return apps, nil
+}
+
+// GetApp returns the app with the given UID, without its key. Clients can
+// unwrap the returned error to check for an sql.ErrNoRows error to determine if
+// no such app exists.
+func (d *DB) GetApp(ctx context.Context, uid string) (*App, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting app", "uid", uid)
+	}
+
+	sql := `SELECT uid, app_name, scope, rate, burst, created_at, revoked_at, expires_at,
+			last_used_at, previous_key_expires_at
+		FROM applications
+		WHERE uid = $1`
+
+	var app App
+
+	err := d.DB.Get(&app, sql, uid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
 	}
 
 	return &app, nil
 }
 
+// RevokeApp revokes the keys of the app with the given UID, including any key
+// still within its rotation overlap window. Revoking cannot be undone. Clients
+// can unwrap the returned error to check for an sql.ErrNoRows error to
+// determine if no such app exists.
+func (d *DB) RevokeApp(ctx context.Context, uid string) (*App, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "revoking app", "uid", uid)
+	}
+
+	_, err := d.DB.Exec(
+		`UPDATE applications SET
+			revoked_at = COALESCE(revoked_at, NOW()),
+			previous_key_hash = NULL,
+			previous_key_expires_at = NULL
+		WHERE uid = $1`,
+		uid,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to revoke app")
+	}
+
+	err = d.notifyAppChanged(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.GetApp(ctx, uid)
+}
+
+// RotateApp generates a new key for the app with the given UID. The current key
+// remains valid for the given overlap, giving clients time to switch to the new
+// one, which is returned as the app's Key. Clients can unwrap the returned
+// error to check for an sql.ErrNoRows error to determine if no such app exists,
+// or a ClientError if the app has been revoked.
+func (d *DB) RotateApp(ctx context.Context, uid string, overlap time.Duration) (*App, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "rotating app", "uid", uid, "overlap", overlap)
+	}
+
+	app, err := d.GetApp(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.RevokedAt.Valid {
+		return nil, errors.Wrap(ClientError, "revoked apps can't be rotated")
+	}
+
+	b, err := randomBytes(keyLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get random bytes when rotating app")
+	}
+
+	sql := `UPDATE applications SET
+			previous_key_hash = key_hash,
+			previous_key_expires_at = NOW() + make_interval(secs => $3),
+			key_hash = crypt($2, gen_salt('bf', 5))
+		WHERE uid = $1 AND revoked_at IS NULL
+		RETURNING previous_key_expires_at`
+
+	err = d.DB.Get(&app.PreviousKeyExpiresAt, sql, uid, fmt.Sprintf("%x", b), overlap.Seconds())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to rotate app")
+	}
+
+	err = d.notifyAppChanged(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Key = fmt.Sprintf("%s-%x", uid, b)
+
+	return app, nil
+}
+
+// SetAppExpiry sets when the key of the app with the given UID expires, a null
+// time meaning it never does. Clients can unwrap the returned error to check
+// for an sql.ErrNoRows error to determine if no such app exists.
+func (d *DB) SetAppExpiry(ctx context.Context, uid string, expiresAt null.Time) (*App, error) {
+	return d.UpdateApp(ctx, uid, &AppUpdate{ExpiresAt: &expiresAt})
+}
+
+// UpdateApp applies the given changes to the app with the given UID. Clients
+// can unwrap the returned error to check for an sql.ErrNoRows error to
+// determine if no such app exists, or a ClientError if the changes are
+// invalid.
+func (d *DB) UpdateApp(ctx context.Context, uid string, update *AppUpdate) (*App, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "updating app", "uid", uid)
+	}
+
+	sets := []string{}
+	args := []interface{}{uid}
+
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if update.Rate != nil {
+		if *update.Rate <= 0 {
+			return nil, errors.Wrap(ClientError, "rate must be greater than zero")
+		}
+		set("rate", *update.Rate)
+	}
+
+	if update.Burst != nil {
+		if update.Burst.Valid && update.Burst.Int64 <= 0 {
+			return nil, errors.Wrap(ClientError, "burst must be greater than zero")
+		}
+		set("burst", *update.Burst)
+	}
+
+	if update.Scope != nil {
+		if len(update.Scope) == 0 || !areKnownClaims(update.Scope) {
+			return nil, errors.Wrap(ClientError, "scope must contain at least one known scope claim")
+		}
+		set("scope", update.Scope)
+	}
+
+	if update.ExpiresAt != nil {
+		set("expires_at", *update.ExpiresAt)
+	}
+
+	if len(sets) > 0 {
+		_, err := d.DB.Exec(`UPDATE applications SET `+strings.Join(sets, ", ")+` WHERE uid = $1`, args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to update app")
+		}
+
+		err = d.notifyAppChanged(uid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d.GetApp(ctx, uid)
+}
+
+// notifyAppChanged notifies listeners that the key, scopes or limits of the app
+// with the given UID have changed, so that any copies of the app they hold can
+// be discarded
+func (d *DB) notifyAppChanged(uid string) error {
+	_, err := d.DB.Exec(`SELECT pg_notify($1, $2)`, AppChangesChannel, uid)
+	if err != nil {
+		return errors.Wrap(err, "failed to notify listeners of app change")
+	}
+
+	return nil
+}
+
 // checks the passed in claim set is one of our known values
 func areKnownClaims(scope ScopeClaims) bool {
 	for _, claim := range scope {
@@ -2,10 +2,14 @@ package postgres_test
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"testing"
+	"time"
 
 	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
@@ -44,7 +48,7 @@ func (s *AppsSuite) TearDownTest() {
 func (s *AppsSuite) TestCreateLoadApp() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
-	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope})
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
 	assert.Nil(s.T(), err)
 	assert.NotEqual(s.T(), "", app.UID)
 	assert.Equal(s.T(), "app", app.Name)
@@ -59,10 +63,10 @@ func (s *AppsSuite) TestCreateLoadApp() {
 func (s *AppsSuite) TestDuplicateAppName() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
-	_, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope})
+	_, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
 	assert.Nil(s.T(), err)
 
-	_, err = s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope})
+	_, err = s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
 	assert.NotNil(s.T(), err)
 	assert.Equal(s.T(), "duplicate application name error. an application with this name is already registered", err.Error())
 }
@@ -70,7 +74,7 @@ func (s *AppsSuite) TestDuplicateAppName() {
 func (s *AppsSuite) TestInvalidClaim() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
-	_, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.ScopeClaim("create-bananas")})
+	_, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.ScopeClaim("create-bananas")}, 0, 0)
 	assert.NotNil(s.T(), err)
 }
 
@@ -85,7 +89,194 @@ func (s *AppsSuite) TestLoadInvalidKey() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
 	_, err := s.db.LoadApp(ctx, "foo")
+	assert.Equal(s.T(), postgres.InvalidKeyError, err)
+}
+
+func (s *AppsSuite) TestListApps() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	_, err := s.db.CreateApp(ctx, "first", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	app, err := s.db.CreateApp(ctx, "second", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.LoadApp(ctx, app.Key)
+	assert.Nil(s.T(), err)
+
+	apps, err := s.db.ListApps(ctx)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), apps, 2)
+	assert.Equal(s.T(), "first", apps[0].Name)
+	assert.False(s.T(), apps[0].LastUsedAt.Valid)
+	assert.Equal(s.T(), "second", apps[1].Name)
+	assert.True(s.T(), apps[1].LastUsedAt.Valid)
+	assert.Equal(s.T(), "", apps[1].Hash)
+}
+
+func (s *AppsSuite) TestRevokeApp() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	rotated, err := s.db.RotateApp(ctx, app.UID, time.Hour)
+	assert.Nil(s.T(), err)
+
+	revoked, err := s.db.RevokeApp(ctx, app.UID)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), revoked.RevokedAt.Valid)
+
+	// both the current and previous keys are revoked
+	_, err = s.db.LoadApp(ctx, rotated.Key)
+	assert.Equal(s.T(), postgres.RevokedKeyError, err)
+
+	_, err = s.db.LoadApp(ctx, app.Key)
 	assert.NotNil(s.T(), err)
+
+	_, err = s.db.RotateApp(ctx, app.UID, time.Hour)
+	assert.Equal(s.T(), postgres.ClientError, errors.Cause(err))
+
+	_, err = s.db.RevokeApp(ctx, "unknown")
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+}
+
+func (s *AppsSuite) TestRotateApp() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	rotated, err := s.db.RotateApp(ctx, app.UID, time.Hour)
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), app.Key, rotated.Key)
+	assert.True(s.T(), rotated.PreviousKeyExpiresAt.Valid)
+
+	// both keys are valid during the overlap
+	_, err = s.db.LoadApp(ctx, app.Key)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.LoadApp(ctx, rotated.Key)
+	assert.Nil(s.T(), err)
+
+	// with no overlap the previous key is expired immediately
+	again, err := s.db.RotateApp(ctx, app.UID, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.LoadApp(ctx, rotated.Key)
+	assert.Equal(s.T(), postgres.ExpiredKeyError, err)
+
+	_, err = s.db.LoadApp(ctx, again.Key)
+	assert.Nil(s.T(), err)
+
+	// keys from before the last rotation are unknown
+	_, err = s.db.LoadApp(ctx, app.Key)
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	_, err = s.db.RotateApp(ctx, "unknown", time.Hour)
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+}
+
+func (s *AppsSuite) TestSetAppExpiry() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	updated, err := s.db.SetAppExpiry(ctx, app.UID, null.TimeFrom(time.Now().Add(time.Hour)))
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), updated.ExpiresAt.Valid)
+
+	_, err = s.db.LoadApp(ctx, app.Key)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.SetAppExpiry(ctx, app.UID, null.TimeFrom(time.Now().Add(-time.Minute)))
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.LoadApp(ctx, app.Key)
+	assert.Equal(s.T(), postgres.ExpiredKeyError, err)
+
+	updated, err = s.db.SetAppExpiry(ctx, app.UID, null.Time{})
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), updated.ExpiresAt.Valid)
+
+	_, err = s.db.LoadApp(ctx, app.Key)
+	assert.Nil(s.T(), err)
+}
+
+func (s *AppsSuite) TestUpdateApp() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, 10, 0)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 10, app.Rate)
+	assert.Equal(s.T(), 20, app.BurstSize())
+
+	rate := 2
+	burst := null.IntFrom(5)
+
+	updated, err := s.db.UpdateApp(ctx, app.UID, &postgres.AppUpdate{
+		Rate:  &rate,
+		Burst: &burst,
+		Scope: postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope},
+	})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, updated.Rate)
+	assert.Equal(s.T(), 5, updated.BurstSize())
+
+	loaded, err := s.db.LoadApp(ctx, app.Key)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), loaded.Roles.Permits(postgres.GetMetadataScope))
+	assert.Equal(s.T(), 5, loaded.BurstSize())
+
+	// a null burst resets it to twice the rate, and other fields are untouched
+	burst = null.Int{}
+	updated, err = s.db.UpdateApp(ctx, app.UID, &postgres.AppUpdate{Burst: &burst})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, updated.Rate)
+	assert.Equal(s.T(), 4, updated.BurstSize())
+	assert.Len(s.T(), updated.Roles, 2)
+
+	rate = 0
+	_, err = s.db.UpdateApp(ctx, app.UID, &postgres.AppUpdate{Rate: &rate})
+	assert.Equal(s.T(), postgres.ClientError, errors.Cause(err))
+
+	_, err = s.db.UpdateApp(ctx, app.UID, &postgres.AppUpdate{Scope: postgres.ScopeClaims{"create-bananas"}})
+	assert.Equal(s.T(), postgres.ClientError, errors.Cause(err))
+
+	_, err = s.db.UpdateApp(ctx, "unknown", &postgres.AppUpdate{})
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+}
+
+func (s *AppsSuite) TestAppChangesNotified() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.CreateUserScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	listener, err := s.db.Listen(postgres.AppChangesChannel)
+	assert.Nil(s.T(), err)
+	defer listener.Close()
+
+	rate := 10
+
+	_, err = s.db.UpdateApp(ctx, app.UID, &postgres.AppUpdate{Rate: &rate})
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.RotateApp(ctx, app.UID, time.Hour)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.RevokeApp(ctx, app.UID)
+	assert.Nil(s.T(), err)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case n := <-listener.NotificationChannel():
+			assert.Equal(s.T(), app.UID, n.Extra)
+		case <-time.After(5 * time.Second):
+			s.T().Fatal("app change was not notified")
+		}
+	}
 }
 
 func TestAppsSuite(t *testing.T) {
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/elgris/sqrl"
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// MaxAuditRecords is the maximum number of records returned when searching
+	// the audit log
+	MaxAuditRecords = 500
+)
+
+// AuditRecord is a record of a request made by an app that changed, or
+// attempted to change, something. Targets identify what the request acted on
+// in the form <kind>:<id>, e.g. user:5b8c1ad5.
+type AuditRecord struct {
+	ID        int64          `db:"id"`
+	AppUID    string         `db:"app_uid"`
+	RequestID string         `db:"request_id"`
+	Method    string         `db:"method"`
+	Route     string         `db:"route"`
+	Path      string         `db:"path"`
+	Targets   pq.StringArray `db:"targets"`
+	Status    int            `db:"status"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+// AuditFilter restricts the records returned when searching the audit log.
+// Empty fields are ignored.
+type AuditFilter struct {
+	AppUID string
+	Target string
+	Route  string
+	Since  null.Time
+	Until  null.Time
+
+	// Before returns only records older than the one with the given id, for
+	// paging through results
+	Before int64
+
+	// Limit is the number of records to return, at most MaxAuditRecords
+	Limit int
+}
+
+// RecordAudit appends a record to the audit log. Records can't be changed
+// once written.
+func (d *DB) RecordAudit(ctx context.Context, record *AuditRecord) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "recording audit", "appUID", record.AppUID, "route", record.Route)
+	}
+
+	if record.Targets == nil {
+		record.Targets = pq.StringArray{}
+	}
+
+	sql := `INSERT INTO audit_log
+		(app_uid, request_id, method, route, path, targets, status)
+	VALUES (:app_uid, :request_id, :method, :route, :path, :targets, :status)`
+
+	_, err := d.DB.NamedExec(sql, record)
+	if err != nil {
+		return errors.Wrap(err, "failed to record audit")
+	}
+
+	return nil
+}
+
+// ListAuditRecords returns the most recent records in the audit log matching
+// the filter, newest first
+func (d *DB) ListAuditRecords(ctx context.Context, filter *AuditFilter) ([]AuditRecord, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "listing audit records",
+			"appUID", filter.AppUID,
+			"target", filter.Target,
+			"route", filter.Route,
+		)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > MaxAuditRecords {
+		limit = MaxAuditRecords
+	}
+
+	builder := sq.Select(
+		"id", "app_uid", "request_id", "method", "route", "path", "targets", "status", "created_at",
+	).
+		From("audit_log").
+		OrderBy("id DESC").
+		Limit(uint64(limit))
+
+	if filter.AppUID != "" {
+		builder = builder.Where(sq.Eq{"app_uid": filter.AppUID})
+	}
+
+	if filter.Target != "" {
+		builder = builder.Where("targets @> ?", pq.StringArray{filter.Target})
+	}
+
+	if filter.Route != "" {
+		builder = builder.Where(sq.Eq{"route": filter.Route})
+	}
+
+	if filter.Since.Valid {
+		builder = builder.Where("created_at >= ?", filter.Since.Time)
+	}
+
+	if filter.Until.Valid {
+		builder = builder.Where("created_at < ?", filter.Until.Time)
+	}
+
+	if filter.Before > 0 {
+		builder = builder.Where("id < ?", filter.Before)
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build sql query")
+	}
+
+	records := []AuditRecord{}
+
+	err = d.DB.Select(&records, d.DB.Rebind(sql), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list audit records")
+	}
+
+	return records, nil
+}
+
+// DeleteAuditRecords removes records written before the given time, returning
+// the number removed
+func (d *DB) DeleteAuditRecords(ctx context.Context, before time.Time) (int64, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "deleting audit records", "before", before)
+	}
+
+	result, err := d.DB.Exec(`DELETE FROM audit_log WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete audit records")
+	}
+
+	return result.RowsAffected()
+}
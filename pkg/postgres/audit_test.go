@@ -0,0 +1,121 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type AuditSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *AuditSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *AuditSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *AuditSuite) TestRecordAndListAuditRecords() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	records := []*postgres.AuditRecord{
+		{AppUID: "app1", RequestID: "req1", Method: "DELETE", Route: "/user/delete", Path: "/user/delete", Targets: []string{"user:abc123"}, Status: 202},
+		{AppUID: "app2", RequestID: "req2", Method: "PATCH", Route: "/entity/locations/update", Path: "/entity/locations/update", Targets: []string{"location:2pxqk4"}, Status: 200},
+		{AppUID: "app1", RequestID: "req3", Method: "POST", Route: "/apps/new", Path: "/apps/new", Status: 403},
+	}
+
+	for _, record := range records {
+		err := s.db.RecordAudit(ctx, record)
+		assert.Nil(s.T(), err)
+	}
+
+	all, err := s.db.ListAuditRecords(ctx, &postgres.AuditFilter{})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), all, 3)
+
+	// newest first
+	assert.Equal(s.T(), "req3", all[0].RequestID)
+	assert.Equal(s.T(), []string{}, []string(all[0].Targets))
+	assert.Equal(s.T(), "req1", all[2].RequestID)
+	assert.Equal(s.T(), []string{"user:abc123"}, []string(all[2].Targets))
+	assert.Equal(s.T(), 202, all[2].Status)
+
+	testcases := []struct {
+		label    string
+		filter   *postgres.AuditFilter
+		expected []string
+	}{
+		{"app", &postgres.AuditFilter{AppUID: "app1"}, []string{"req3", "req1"}},
+		{"target", &postgres.AuditFilter{Target: "location:2pxqk4"}, []string{"req2"}},
+		{"route", &postgres.AuditFilter{Route: "/apps/new"}, []string{"req3"}},
+		{"since", &postgres.AuditFilter{Since: null.TimeFrom(time.Now().Add(time.Hour))}, []string{}},
+		{"until", &postgres.AuditFilter{Until: null.TimeFrom(time.Now().Add(time.Hour))}, []string{"req3", "req2", "req1"}},
+		{"before", &postgres.AuditFilter{Before: all[0].ID}, []string{"req2", "req1"}},
+		{"limit", &postgres.AuditFilter{Limit: 1}, []string{"req3"}},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			got, err := s.db.ListAuditRecords(ctx, tc.filter)
+			assert.Nil(t, err)
+
+			requestIDs := []string{}
+			for _, record := range got {
+				requestIDs = append(requestIDs, record.RequestID)
+			}
+			assert.Equal(t, tc.expected, requestIDs)
+		})
+	}
+}
+
+func (s *AuditSuite) TestAuditRecordsCannotBeChanged() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	err := s.db.RecordAudit(ctx, &postgres.AuditRecord{AppUID: "app1", Method: "DELETE", Route: "/user/delete", Path: "/user/delete", Status: 202})
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`UPDATE audit_log SET app_uid = 'app2'`)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *AuditSuite) TestDeleteAuditRecords() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	err := s.db.RecordAudit(ctx, &postgres.AuditRecord{AppUID: "app1", Method: "DELETE", Route: "/user/delete", Path: "/user/delete", Status: 202})
+	assert.Nil(s.T(), err)
+
+	deleted, err := s.db.DeleteAuditRecords(ctx, time.Now().Add(-time.Hour))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), int64(0), deleted)
+
+	deleted, err = s.db.DeleteAuditRecords(ctx, time.Now().Add(time.Hour))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), int64(1), deleted)
+
+	records, err := s.db.ListAuditRecords(ctx, &postgres.AuditFilter{})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), records, 0)
+}
+
+func TestAuditSuite(t *testing.T) {
+	suite.Run(t, new(AuditSuite))
+}
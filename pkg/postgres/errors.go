@@ -23,4 +23,15 @@ const (
 
 	// ServerError used to signal a server error that the client cannot fix
 	ServerError = Error("server error - unexpected database error")
+
+	// InvalidKeyError is returned when loading an app with a key that isn't of
+	// the form <uid>-<secret>
+	InvalidKeyError = Error("invalid key")
+
+	// RevokedKeyError is returned when loading an app whose key has been revoked
+	RevokedKeyError = Error("api key has been revoked")
+
+	// ExpiredKeyError is returned when loading an app whose key has expired, or
+	// with a key that was rotated out after its overlap window
+	ExpiredKeyError = Error("api key has expired")
 )
@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+// UserExport is everything we store about a single user, as returned for a
+// subject access request
+type UserExport struct {
+	User            ExportedUser
+	Identities      []ExportedIdentity
+	Things          []Thing
+	Channels        []ExportedChannel
+	LocationChanges []LocationChange
+	HardwareChanges []HardwareChange
+	PlantStatuses   []PlantStatus
+	AlertRules      []ExportedAlertRule
+	Alerts          []ExportedAlert
+	Deliveries      []ExportedWebhookDelivery
+	AuditRecords    []AuditRecord
+}
+
+// ExportedUser is the user row of an export
+type ExportedUser struct {
+	ID        int64       `db:"id"`
+	UID       string      `db:"uid"`
+	ParrotID  null.String `db:"parrot_id"`
+	CreatedAt null.Time   `db:"created_at"`
+}
+
+// ExportedIdentity is an identity of an export. Tokens are never read, we only
+// record whether the identity holds them.
+type ExportedIdentity struct {
+	ID              int64       `db:"id"`
+	Provider        null.String `db:"auth_provider"`
+	HasAccessToken  bool        `db:"has_access_token"`
+	HasRefreshToken bool        `db:"has_refresh_token"`
+	CreatedAt       null.Time   `db:"created_at"`
+	IndexedAt       null.Time   `db:"indexed_at"`
+	RevokedAt       null.Time   `db:"revoked_at"`
+}
+
+// ExportedChannel is the metadata of a single channel of one of the user's
+// things
+type ExportedChannel struct {
+	ThingUID       string      `db:"thing_uid"`
+	Name           string      `db:"name"`
+	Unit           null.String `db:"unit"`
+	DataType       string      `db:"data_type"`
+	FirstSampleUTC null.Time   `db:"first_sample"`
+	LastSampleUTC  null.Time   `db:"last_sample"`
+}
+
+// ExportedAlertRule is an alert rule watching the user or one of their things
+type ExportedAlertRule struct {
+	UID        string      `db:"uid"`
+	AppUID     string      `db:"app_uid"`
+	ThingUID   null.String `db:"thing_uid"`
+	Channel    string      `db:"channel"`
+	Comparator string      `db:"comparator"`
+	Threshold  float64     `db:"threshold"`
+	Duration   int         `db:"duration"`
+	Cooldown   int         `db:"cooldown"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
+
+// ExportedAlert is the state of an alert rule for one of the user's things
+type ExportedAlert struct {
+	RuleUID      string    `db:"rule_uid"`
+	ThingUID     string    `db:"thing_uid"`
+	State        string    `db:"state"`
+	Value        float64   `db:"value"`
+	PendingSince null.Time `db:"pending_since"`
+	FiredAt      null.Time `db:"fired_at"`
+	ResolvedAt   null.Time `db:"resolved_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// ExportedWebhookDelivery is an event about the user or one of their things
+// sent to the webhook of an app
+type ExportedWebhookDelivery struct {
+	UID         string    `db:"uid"`
+	AppUID      string    `db:"app_uid"`
+	WebhookUID  string    `db:"webhook_uid"`
+	Event       string    `db:"event"`
+	Payload     []byte    `db:"payload"`
+	CreatedAt   time.Time `db:"created_at"`
+	DeliveredAt null.Time `db:"delivered_at"`
+	FailedAt    null.Time `db:"failed_at"`
+}
+
+// GetUserExport returns everything we store about the user with the given UID.
+// Clients can unwrap the returned error to check for an sql.ErrNoRows error to
+// determine if no such user exists.
+func (d *DB) GetUserExport(ctx context.Context, userUID string) (*UserExport, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "loading user export", "userUID", userUID)
+	}
+
+	export := &UserExport{
+		Identities:      []ExportedIdentity{},
+		Things:          []Thing{},
+		Channels:        []ExportedChannel{},
+		LocationChanges: []LocationChange{},
+		HardwareChanges: []HardwareChange{},
+		PlantStatuses:   []PlantStatus{},
+		AlertRules:      []ExportedAlertRule{},
+		Alerts:          []ExportedAlert{},
+		Deliveries:      []ExportedWebhookDelivery{},
+		AuditRecords:    []AuditRecord{},
+	}
+
+	err := d.DB.Get(&export.User, `SELECT id, uid, parrot_id, created_at FROM users WHERE uid = $1`, userUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load user")
+	}
+
+	sql := `SELECT id, auth_provider,
+			COALESCE(access_token, '') <> '' AS has_access_token,
+			COALESCE(refresh_token, '') <> '' AS has_refresh_token,
+			created_at, indexed_at, revoked_at
+		FROM identities
+		WHERE owner_id = $1
+		ORDER BY id`
+
+	err = d.DB.Select(&export.Identities, sql, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load identities")
+	}
+
+	err = d.DB.Select(&export.Things, `SELECT * FROM things WHERE owner_id = $1 ORDER BY uid`, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load things")
+	}
+
+	sql = `SELECT c.thing_uid, ds.name, ds.unit, ds.data_type, c.first_sample, c.last_sample
+		FROM channels c
+		JOIN data_sources ds ON ds.id = c.data_source_id
+		JOIN things t ON t.uid = c.thing_uid
+		WHERE t.owner_id = $1
+		ORDER BY c.thing_uid, ds.name`
+
+	err = d.DB.Select(&export.Channels, sql, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load channels")
+	}
+
+	sql = `SELECT lc.id, t.uid AS thing_uid, lc.app_uid, lc.inserted_at, lc.previous_long,
+			lc.previous_lat, lc.new_long, lc.new_lat
+		FROM location_changes lc
+		JOIN things t ON t.id = lc.thing_id
+		WHERE t.owner_id = $1
+		ORDER BY t.uid, lc.inserted_at, lc.id`
+
+	err = d.DB.Select(&export.LocationChanges, sql, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load location history")
+	}
+
+	sql = `SELECT hc.id, t.uid AS thing_uid, hc.inserted_at, hc.firmware_version,
+			hc.hardware_revision, hc.sensor_type, hc.calibration_data, hc.is_indoor,
+			hc.in_pot, hc.plant_ids, hc.autowatering_mode
+		FROM hardware_changes hc
+		JOIN things t ON t.id = hc.thing_id
+		WHERE t.owner_id = $1
+		ORDER BY t.uid, hc.inserted_at, hc.id`
+
+	err = d.DB.Select(&export.HardwareChanges, sql, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load hardware history")
+	}
+
+	sql = `SELECT
+			t.uid AS thing_uid,
+			ps.variable,
+			ps.status_key,
+			ps.instruction_key,
+			ps.min_threshold,
+			ps.max_threshold,
+			ps.current_value,
+			ps.status_at
+		FROM plant_statuses ps
+		JOIN things t ON t.id = ps.thing_id
+		WHERE t.owner_id = $1
+		ORDER BY t.uid, ps.variable`
+
+	err = d.DB.Select(&export.PlantStatuses, sql, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load plant statuses")
+	}
+
+	sql = `SELECT r.uid, a.uid AS app_uid, t.uid AS thing_uid, r.channel, r.comparator,
+			r.threshold, r.duration, r.cooldown, r.created_at
+		FROM alert_rules r
+		JOIN applications a ON a.id = r.app_id
+		LEFT JOIN things t ON t.id = r.thing_id
+		WHERE r.owner_id = $1 OR t.owner_id = $1
+		ORDER BY r.created_at, r.id`
+
+	err = d.DB.Select(&export.AlertRules, sql, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load alert rules")
+	}
+
+	sql = `SELECT r.uid AS rule_uid, t.uid AS thing_uid, al.state, al.value,
+			al.pending_since, al.fired_at, al.resolved_at, al.updated_at
+		FROM alerts al
+		JOIN alert_rules r ON r.id = al.rule_id
+		JOIN things t ON t.id = al.thing_id
+		WHERE t.owner_id = $1
+		ORDER BY t.uid, r.uid`
+
+	err = d.DB.Select(&export.Alerts, sql, export.User.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load alerts")
+	}
+
+	thingUIDs := []string{}
+	for _, t := range export.Things {
+		thingUIDs = append(thingUIDs, t.UID.String)
+	}
+
+	// user and thing events carry the uid of the user, while alert events only
+	// carry the uid of the thing
+	sql = `SELECT wd.uid, a.uid AS app_uid, w.uid AS webhook_uid, wd.event, wd.payload,
+			wd.created_at, wd.delivered_at, wd.failed_at
+		FROM webhook_deliveries wd
+		JOIN webhooks w ON w.id = wd.webhook_id
+		JOIN applications a ON a.id = w.app_id
+		WHERE wd.payload->'data'->>'userUid' = $1
+		OR wd.payload->'data'->>'thingUid' = ANY($2)
+		ORDER BY wd.id`
+
+	err = d.DB.Select(&export.Deliveries, sql, userUID, pq.Array(thingUIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load webhook deliveries")
+	}
+
+	// requests are recorded against the user, their things as locations and the
+	// alert rules watching them
+	targets := []string{"user:" + userUID}
+	for _, uid := range thingUIDs {
+		targets = append(targets, "location:"+uid)
+	}
+	for _, r := range export.AlertRules {
+		targets = append(targets, "alert-rule:"+r.UID)
+	}
+
+	sql = `SELECT id, app_uid, request_id, method, route, path, targets, status, created_at
+		FROM audit_log
+		WHERE targets && $1
+		ORDER BY id`
+
+	err = d.DB.Select(&export.AuditRecords, sql, pq.Array(targets))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load audit records")
+	}
+
+	return export, nil
+}
@@ -57,6 +57,17 @@ func Truncate(t *testing.T, db *postgres.DB) error {
 	TRUNCATE users CASCADE;
 	TRUNCATE applications CASCADE;
 	TRUNCATE location_changes CASCADE;
+	TRUNCATE hardware_changes CASCADE;
+	TRUNCATE jobs CASCADE;
+	TRUNCATE stream_events CASCADE;
+	TRUNCATE webhooks CASCADE;
+	TRUNCATE alert_rules CASCADE;
+	TRUNCATE plant_statuses CASCADE;
+	TRUNCATE user_deletions CASCADE;
+	TRUNCATE rate_limits CASCADE;
+	TRUNCATE app_quotas CASCADE;
+	TRUNCATE app_usage CASCADE;
+	TRUNCATE audit_log CASCADE;
 	`
 
 	_, err := db.DB.Exec(sql)
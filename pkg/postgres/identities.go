@@ -12,6 +12,7 @@ import (
 // looking for the next token to index
 type Identity struct {
 	OwnerID     int64  `db:"owner_id"`
+	UserUID     string `db:"user_uid"`
 	AccessToken string `db:"access_token"`
 }
 
@@ -34,7 +35,7 @@ func (d *DB) NextIdentity(ctx context.Context) (*Identity, error) {
 		FOR UPDATE SKIP LOCKED
 	) UPDATE identities SET indexed_at = NOW()
 	WHERE id = (SELECT id FROM next_identity)
-	RETURNING owner_id, access_token`
+	RETURNING owner_id, access_token, COALESCE((SELECT uid FROM users WHERE users.id = identities.owner_id), '') AS user_uid`
 
 	tx, err := d.DB.Beginx()
 	if err != nil {
@@ -2,10 +2,13 @@ package postgres_test
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"testing"
+	"time"
 
 	kitlog "github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
@@ -66,6 +69,39 @@ func (s *IdentitiesSuite) TestNextAccessToken() {
 	assert.Equal(s.T(), "", identity.AccessToken)
 }
 
+func (s *IdentitiesSuite) TestGetIndexingStatus() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	_, err := s.db.GetIndexingStatus(ctx, "abc123")
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	var userID int64
+
+	err = s.db.DB.Get(&userID, `INSERT INTO users (uid, parrot_id) VALUES ('abc123', 'bob@example.com') RETURNING id`)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`INSERT INTO identities (owner_id, auth_provider, access_token, indexed_at) VALUES ($1, 'parrot', 'token', NOW() - interval '2 hours')`, userID)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, first_sample, last_sample, last_uploaded_sample, indexed_at)
+		VALUES
+			('1234', $1, 'PA1', 0, 0, 'LOC1', NOW() - interval '10 days', NOW(), NOW(), NOW()),
+			('1235', $1, 'PA2', 0, 0, 'LOC2', NOW() - interval '10 days', NOW(), NOW() - interval '5 days', NOW())`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	status, err := s.db.GetIndexingStatus(ctx, "abc123")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "abc123", status.UserUID)
+	assert.True(s.T(), status.IndexedAt.Valid)
+	assert.Len(s.T(), status.Things, 2)
+	assert.Equal(s.T(), "1234", status.Things[0].UID)
+	assert.Equal(s.T(), 100.0, status.Things[0].Backfilled())
+	assert.InDelta(s.T(), 50.0, status.Things[1].Backfilled(), 0.1)
+	assert.Equal(s.T(), postgres.IdentityIndexing, status.State(time.Now()))
+}
+
 func TestIdentitiesSuite(t *testing.T) {
 	suite.Run(t, new(IdentitiesSuite))
 }
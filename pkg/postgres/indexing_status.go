@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// IdentityPending is the state of an identity that has never been indexed
+	IdentityPending = "pending"
+
+	// IdentityIndexing is the state of an identity whose things are still being
+	// backfilled
+	IdentityIndexing = "indexing"
+
+	// IdentityIndexed is the state of an identity whose things are all fully
+	// backfilled
+	IdentityIndexed = "indexed"
+
+	// IdentityFailed is the state of an identity the indexer is failing to make
+	// progress with
+	IdentityFailed = "failed"
+
+	// IdentityRevoked is the state of an identity whose access token Parrot
+	// rejected
+	IdentityRevoked = "revoked"
+
+	// staleIdentityAge is how long since an identity was last indexed before we
+	// consider indexing to have failed, as identities are reindexed daily
+	staleIdentityAge = 48 * time.Hour
+
+	// indexingRunTimeout is how long after an indexing run starts we expect it
+	// to have indexed at least one of the identity's things
+	indexingRunTimeout = time.Hour
+)
+
+// IndexingStatus describes the progress of indexing a user's data
+type IndexingStatus struct {
+	UserUID   string    `db:"user_uid"`
+	CreatedAt null.Time `db:"created_at"`
+	IndexedAt null.Time `db:"indexed_at"`
+	RevokedAt null.Time `db:"revoked_at"`
+	Things    []ThingIndexingStatus
+}
+
+// ThingIndexingStatus describes the progress of backfilling a single thing
+type ThingIndexingStatus struct {
+	UID             string    `db:"uid"`
+	FirstSampleUTC  null.Time `db:"first_sample"`
+	LastSampleUTC   null.Time `db:"last_sample"`
+	LastUploadedUTC null.Time `db:"last_uploaded_sample"`
+	IndexedAt       null.Time `db:"indexed_at"`
+}
+
+// Backfilled returns the percentage of the thing's samples that have been
+// uploaded, measured by time between its first and last samples
+func (t *ThingIndexingStatus) Backfilled() float64 {
+	if !t.FirstSampleUTC.Valid || !t.LastSampleUTC.Valid || !t.LastUploadedUTC.Valid {
+		return 0
+	}
+
+	if !t.LastUploadedUTC.Time.Before(t.LastSampleUTC.Time) {
+		return 100
+	}
+
+	total := t.LastSampleUTC.Time.Sub(t.FirstSampleUTC.Time)
+	if total <= 0 {
+		return 0
+	}
+
+	done := t.LastUploadedUTC.Time.Sub(t.FirstSampleUTC.Time)
+
+	return math.Max(0, math.Min(100, 100*float64(done)/float64(total)))
+}
+
+// Backfilled returns the percentage of all the user's samples that have been
+// uploaded, weighting each thing by how long it has been recording. A user
+// without any things is considered fully backfilled.
+func (s *IndexingStatus) Backfilled() float64 {
+	var total, done float64
+
+	for i := range s.Things {
+		t := &s.Things[i]
+
+		duration := 0.0
+		if t.FirstSampleUTC.Valid && t.LastSampleUTC.Valid {
+			duration = t.LastSampleUTC.Time.Sub(t.FirstSampleUTC.Time).Seconds()
+		}
+
+		// things with a single sample still count for something
+		duration = math.Max(duration, 1)
+
+		total += duration
+		done += duration * t.Backfilled() / 100
+	}
+
+	if total == 0 {
+		return 100
+	}
+
+	return 100 * done / total
+}
+
+// State returns the state of the user's identity at the given time, which is
+// one of pending, indexing, indexed, failed or revoked
+func (s *IndexingStatus) State(now time.Time) string {
+	if s.RevokedAt.Valid {
+		return IdentityRevoked
+	}
+
+	if !s.IndexedAt.Valid {
+		return IdentityPending
+	}
+
+	if now.Sub(s.IndexedAt.Time) > staleIdentityAge {
+		return IdentityFailed
+	}
+
+	// the identity's indexed_at is set as a run starts, and each thing's as it
+	// is indexed, so a run that has indexed none of them has failed
+	if len(s.Things) > 0 && now.Sub(s.IndexedAt.Time) > indexingRunTimeout {
+		progressed := false
+
+		for _, t := range s.Things {
+			if t.IndexedAt.Valid && !t.IndexedAt.Time.Before(s.IndexedAt.Time) {
+				progressed = true
+				break
+			}
+		}
+
+		if !progressed {
+			return IdentityFailed
+		}
+	}
+
+	for i := range s.Things {
+		if s.Things[i].Backfilled() < 100 {
+			return IdentityIndexing
+		}
+	}
+
+	return IdentityIndexed
+}
+
+// GetIndexingStatus returns the indexing progress of the user with the given
+// UID. Clients can unwrap the returned error to check for an sql.ErrNoRows
+// error to determine if no such user, or no identity for them, exists.
+func (d *DB) GetIndexingStatus(ctx context.Context, userUID string) (*IndexingStatus, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting indexing status", "userUID", userUID)
+	}
+
+	sqlQuery := `SELECT u.uid AS user_uid, i.created_at, i.indexed_at, i.revoked_at
+		FROM identities i
+		JOIN users u ON u.id = i.owner_id
+		WHERE u.uid = $1
+		ORDER BY i.id DESC
+		LIMIT 1`
+
+	var status IndexingStatus
+
+	err := d.DB.Get(&status, sqlQuery, userUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load identity")
+	}
+
+	sqlQuery = `SELECT t.uid, t.first_sample, t.last_sample, t.last_uploaded_sample, t.indexed_at
+		FROM things t
+		JOIN users u ON u.id = t.owner_id
+		WHERE u.uid = $1
+		ORDER BY t.uid`
+
+	status.Things = []ThingIndexingStatus{}
+
+	err = d.DB.Select(&status.Things, sqlQuery, userUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load things")
+	}
+
+	return &status, nil
+}
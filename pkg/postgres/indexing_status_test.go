@@ -0,0 +1,140 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+func TestThingBackfilled(t *testing.T) {
+	first := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := first.Add(100 * time.Hour)
+
+	testcases := []struct {
+		label    string
+		thing    postgres.ThingIndexingStatus
+		expected float64
+	}{
+		{
+			label:    "not uploaded",
+			thing:    postgres.ThingIndexingStatus{FirstSampleUTC: null.TimeFrom(first), LastSampleUTC: null.TimeFrom(last)},
+			expected: 0,
+		},
+		{
+			label:    "part uploaded",
+			thing:    postgres.ThingIndexingStatus{FirstSampleUTC: null.TimeFrom(first), LastSampleUTC: null.TimeFrom(last), LastUploadedUTC: null.TimeFrom(first.Add(25 * time.Hour))},
+			expected: 25,
+		},
+		{
+			label:    "fully uploaded",
+			thing:    postgres.ThingIndexingStatus{FirstSampleUTC: null.TimeFrom(first), LastSampleUTC: null.TimeFrom(last), LastUploadedUTC: null.TimeFrom(last)},
+			expected: 100,
+		},
+		{
+			label:    "single sample",
+			thing:    postgres.ThingIndexingStatus{FirstSampleUTC: null.TimeFrom(first), LastSampleUTC: null.TimeFrom(first), LastUploadedUTC: null.TimeFrom(first)},
+			expected: 100,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.thing.Backfilled())
+		})
+	}
+}
+
+func TestIndexingStatusState(t *testing.T) {
+	now := time.Date(2019, 6, 12, 12, 0, 0, 0, time.UTC)
+	first := now.AddDate(0, -1, 0)
+
+	done := postgres.ThingIndexingStatus{
+		FirstSampleUTC:  null.TimeFrom(first),
+		LastSampleUTC:   null.TimeFrom(now),
+		LastUploadedUTC: null.TimeFrom(now),
+		IndexedAt:       null.TimeFrom(now.Add(-time.Minute)),
+	}
+
+	partial := postgres.ThingIndexingStatus{
+		FirstSampleUTC:  null.TimeFrom(first),
+		LastSampleUTC:   null.TimeFrom(now),
+		LastUploadedUTC: null.TimeFrom(first.AddDate(0, 0, 10)),
+		IndexedAt:       null.TimeFrom(now.Add(-time.Minute)),
+	}
+
+	untouched := partial
+	untouched.IndexedAt = null.TimeFrom(now.Add(-25 * time.Hour))
+
+	testcases := []struct {
+		label    string
+		status   postgres.IndexingStatus
+		expected string
+	}{
+		{
+			label:    "never indexed",
+			status:   postgres.IndexingStatus{},
+			expected: postgres.IdentityPending,
+		},
+		{
+			label:    "revoked",
+			status:   postgres.IndexingStatus{IndexedAt: null.TimeFrom(now), RevokedAt: null.TimeFrom(now)},
+			expected: postgres.IdentityRevoked,
+		},
+		{
+			label:    "backfilling",
+			status:   postgres.IndexingStatus{IndexedAt: null.TimeFrom(now.Add(-2 * time.Hour)), Things: []postgres.ThingIndexingStatus{done, partial}},
+			expected: postgres.IdentityIndexing,
+		},
+		{
+			label:    "backfilled",
+			status:   postgres.IndexingStatus{IndexedAt: null.TimeFrom(now.Add(-2 * time.Hour)), Things: []postgres.ThingIndexingStatus{done}},
+			expected: postgres.IdentityIndexed,
+		},
+		{
+			label:    "run just started",
+			status:   postgres.IndexingStatus{IndexedAt: null.TimeFrom(now.Add(-time.Minute)), Things: []postgres.ThingIndexingStatus{untouched}},
+			expected: postgres.IdentityIndexing,
+		},
+		{
+			label:    "run made no progress",
+			status:   postgres.IndexingStatus{IndexedAt: null.TimeFrom(now.Add(-2 * time.Hour)), Things: []postgres.ThingIndexingStatus{untouched}},
+			expected: postgres.IdentityFailed,
+		},
+		{
+			label:    "not indexed for days",
+			status:   postgres.IndexingStatus{IndexedAt: null.TimeFrom(now.AddDate(0, 0, -3))},
+			expected: postgres.IdentityFailed,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.status.State(now))
+		})
+	}
+}
+
+func TestIndexingStatusBackfilled(t *testing.T) {
+	first := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	status := postgres.IndexingStatus{
+		Things: []postgres.ThingIndexingStatus{
+			{
+				FirstSampleUTC:  null.TimeFrom(first),
+				LastSampleUTC:   null.TimeFrom(first.Add(300 * time.Hour)),
+				LastUploadedUTC: null.TimeFrom(first.Add(300 * time.Hour)),
+			},
+			{
+				FirstSampleUTC: null.TimeFrom(first),
+				LastSampleUTC:  null.TimeFrom(first.Add(100 * time.Hour)),
+			},
+		},
+	}
+
+	assert.Equal(t, 75.0, status.Backfilled())
+	assert.Equal(t, 100.0, (&postgres.IndexingStatus{}).Backfilled())
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// SyncThingQueue is the name of the queue used for jobs that push the
+	// current state of a thing to Thingful
+	SyncThingQueue = "sync_thing"
+
+	// jobLockTimeout is how long a job may be locked by a worker before we
+	// assume the worker died and make the job available again
+	jobLockTimeout = "5 minutes"
+)
+
+// Job is a unit of background work read from the jobs table. The payload is
+// arbitrary JSON understood by whatever handles the queue.
+type Job struct {
+	ID          int64       `db:"id"`
+	Queue       string      `db:"queue"`
+	Payload     []byte      `db:"payload"`
+	Attempts    int         `db:"attempts"`
+	MaxAttempts int         `db:"max_attempts"`
+	RunAt       time.Time   `db:"run_at"`
+	LastError   null.String `db:"last_error"`
+}
+
+// SyncThingPayload is the payload of a job on the SyncThingQueue
+type SyncThingPayload struct {
+	ThingUID string `json:"thingUid"`
+}
+
+// EnqueueJob adds a new job to the named queue to be run as soon as possible
+func (d *DB) EnqueueJob(ctx context.Context, queue string, payload interface{}) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "enqueuing job", "queue", queue)
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	_, err = enqueueJob(tx, queue, payload)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// enqueueJob adds a job within an existing transaction, so that the job is
+// only created if the rest of the transaction commits. Returns the ID of the
+// new job.
+func enqueueJob(tx *sqlx.Tx, queue string, payload interface{}) (int64, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to marshal job payload")
+	}
+
+	var id int64
+
+	err = tx.Get(&id, `INSERT INTO jobs (queue, payload) VALUES ($1, $2) RETURNING id`, queue, string(b))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to insert job")
+	}
+
+	return id, nil
+}
+
+// NextJob locks and returns the next job that is due to run on any of the given
+// queues. Returns nil if there is no job ready to run. Jobs are locked for a
+// limited time, so a job held by a worker that crashed will eventually be
+// retried.
+func (d *DB) NextJob(ctx context.Context, queues []string) (*Job, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "retrieving next job")
+	}
+
+	query := `WITH next_job AS (
+		SELECT id FROM jobs
+		WHERE queue = ANY($1)
+		AND completed_at IS NULL
+		AND failed_at IS NULL
+		AND run_at <= NOW()
+		AND (locked_at IS NULL OR locked_at < NOW() - interval '` + jobLockTimeout + `')
+		ORDER BY run_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	) UPDATE jobs SET locked_at = NOW(), attempts = attempts + 1
+	WHERE id = (SELECT id FROM next_job)
+	RETURNING id, queue, payload, attempts, max_attempts, run_at, last_error`
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open transaction")
+	}
+
+	var job Job
+
+	err = tx.Get(&job, query, pq.Array(queues))
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to execute next job query")
+	}
+
+	return &job, tx.Commit()
+}
+
+// CompleteJob marks the job as successfully completed
+func (d *DB) CompleteJob(ctx context.Context, job *Job) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "completing job", "jobID", job.ID, "queue", job.Queue)
+	}
+
+	_, err := d.DB.Exec(`UPDATE jobs SET completed_at = NOW(), locked_at = NULL WHERE id = $1`, job.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to complete job")
+	}
+
+	return nil
+}
+
+// FailJob records a failed attempt to run the job. The job is scheduled to run
+// again after the given delay, unless it has used all of its attempts in which
+// case it is marked as permanently failed.
+func (d *DB) FailJob(ctx context.Context, job *Job, jobErr error, retryIn time.Duration) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "failing job",
+			"jobID", job.ID,
+			"queue", job.Queue,
+			"attempts", job.Attempts,
+			"err", jobErr,
+		)
+	}
+
+	query := `UPDATE jobs SET
+		locked_at = NULL,
+		last_error = $2,
+		run_at = NOW() + make_interval(secs => $3),
+		failed_at = CASE WHEN attempts >= max_attempts THEN NOW() END
+	WHERE id = $1`
+
+	_, err := d.DB.Exec(query, job.ID, jobErr.Error(), retryIn.Seconds())
+	if err != nil {
+		return errors.Wrap(err, "failed to record job failure")
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package postgres_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type JobsSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *JobsSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *JobsSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *JobsSuite) TestJobLifecycle() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	job, err := s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), job)
+
+	err = s.db.EnqueueJob(ctx, postgres.SyncThingQueue, &postgres.SyncThingPayload{ThingUID: "abc123"})
+	assert.Nil(s.T(), err)
+
+	// jobs on other queues are ignored
+	job, err = s.db.NextJob(ctx, []string{"other"})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), job)
+
+	job, err = s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), job)
+	assert.Equal(s.T(), 1, job.Attempts)
+
+	var payload postgres.SyncThingPayload
+	err = json.Unmarshal(job.Payload, &payload)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "abc123", payload.ThingUID)
+
+	// a locked job is not returned again
+	next, err := s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), next)
+
+	// a failed job is retried after the delay
+	err = s.db.FailJob(ctx, job, errors.New("boom"), 0)
+	assert.Nil(s.T(), err)
+
+	job, err = s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), job)
+	assert.Equal(s.T(), 2, job.Attempts)
+	assert.Equal(s.T(), "boom", job.LastError.String)
+
+	err = s.db.FailJob(ctx, job, errors.New("boom"), time.Hour)
+	assert.Nil(s.T(), err)
+
+	next, err = s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), next)
+
+	_, err = s.db.DB.Exec(`UPDATE jobs SET run_at = NOW()`)
+	assert.Nil(s.T(), err)
+
+	job, err = s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), job)
+
+	err = s.db.CompleteJob(ctx, job)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`UPDATE jobs SET run_at = NOW()`)
+	assert.Nil(s.T(), err)
+
+	next, err = s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), next)
+}
+
+func (s *JobsSuite) TestJobFailsPermanently() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	_, err := s.db.DB.Exec(`INSERT INTO jobs (queue, max_attempts) VALUES ($1, 1)`, postgres.SyncThingQueue)
+	assert.Nil(s.T(), err)
+
+	job, err := s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), job)
+
+	err = s.db.FailJob(ctx, job, errors.New("boom"), 0)
+	assert.Nil(s.T(), err)
+
+	job, err = s.db.NextJob(ctx, []string{postgres.SyncThingQueue})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), job)
+
+	var failed bool
+	err = s.db.DB.Get(&failed, `SELECT failed_at IS NOT NULL FROM jobs`)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), failed)
+}
+
+func TestJobsSuite(t *testing.T) {
+	suite.Run(t, new(JobsSuite))
+}
@@ -2,9 +2,17 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	sq "github.com/elgris/sqrl"
 	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/thingful/kudzu/pkg/logger"
 )
@@ -22,42 +30,174 @@ type Location struct {
 	LocationID            string    `db:"location_identifier"`
 	SerialNum             string    `db:"serial_num"`
 	UserUID               string    `db:"user_uid"`
+	SortKey               string    `db:"sort_key"`
+
+	// Hardware is only loaded when listing locations
+	Hardware
+}
+
+const (
+	// earthRadius is the mean radius of the earth in metres, used for haversine
+	// distance calculations
+	earthRadius = 6371008.8
+
+	// metresPerDegree is the approximate length in metres of a degree of
+	// latitude, used to build a bounding box around radius queries so they can
+	// use the index on things(lat, long)
+	metresPerDegree = 111320.0
+
+	// MaxLocationsLimit is the maximum number of locations that may be
+	// requested in a single page
+	MaxLocationsLimit = 1000
+)
+
+// sortColumn describes an expression we are able to order locations by, along
+// with the type the cursor value must be cast to when comparing against it.
+type sortColumn struct {
+	expr string
+	cast string
+}
+
+// locationSortColumns is the set of permitted sort keys for listing locations.
+// Nullable columns are coalesced so that keyset comparisons behave.
+var locationSortColumns = map[string]sortColumn{
+	"uid":         {expr: "t.uid", cast: "text"},
+	"last_sample": {expr: "COALESCE(t.last_sample, 'epoch'::timestamptz)", cast: "timestamptz"},
+	"created_at":  {expr: "COALESCE(t.created_at, 'epoch'::timestamptz)", cast: "timestamptz"},
+	"nickname":    {expr: "COALESCE(t.nickname, '')", cast: "text"},
+}
+
+// IsValidLocationSort returns true if the given value is a key we are able to
+// sort locations by
+func IsValidLocationSort(sortBy string) bool {
+	_, ok := locationSortColumns[sortBy]
+	return ok
+}
+
+// LocationCursor marks a position in a sorted list of locations. It holds the
+// sort key and uid of the last location on the previous page.
+type LocationCursor struct {
+	SortKey string `json:"k"`
+	UID     string `json:"u"`
 }
 
-// ListLocations returns a list of locations with some optional filtering
-// parameters applied.
-func (d *DB) ListLocations(ctx context.Context, ownerUID string, invalidLocation, staleData bool) ([]Location, error) {
+// Point is a single WGS84 coordinate pair
+type Point struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// BoundingBox is a rectangular area defined by its south west and north east
+// corners
+type BoundingBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+// Radius is a circular area defined by a centre point and a distance in
+// metres
+type Radius struct {
+	Center   Point
+	Distance float64
+}
+
+// LocationFilter contains the optional filtering parameters that can be
+// applied when listing locations. The zero value returns every location.
+type LocationFilter struct {
+	OwnerUID        string
+	ThingUIDs       []string
+	InvalidLocation bool
+	StaleData       bool
+	BoundingBox     *BoundingBox
+	Radius          *Radius
+	Polygon         []Point
+
+	// Status is one of live, stale or dead. If empty things of any status are
+	// returned.
+	Status string
+
+	// DataSourceCodes restricts the results to things that have all of the
+	// given data sources, or any of them if MatchAnyDataSource is true.
+	DataSourceCodes    []string
+	MatchAnyDataSource bool
+
+	// Indoor restricts the results to indoor or outdoor sensors if set, and
+	// SensorType to sensors of the given type. FirmwareBefore restricts the
+	// results to sensors whose firmware version sorts before it, as versions
+	// start with their release date this selects older firmware.
+	Indoor         *bool
+	SensorType     string
+	FirmwareBefore string
+
+	// SortBy is one of uid, last_sample, created_at or nickname, defaulting to
+	// uid if empty. After and Limit are used to page through the results.
+	SortBy     string
+	Descending bool
+	After      *LocationCursor
+	Limit      uint64
+}
+
+// ListLocations returns a list of locations with the given filter applied.
+func (d *DB) ListLocations(ctx context.Context, filter *LocationFilter) ([]Location, error) {
 	log := logger.FromContext(ctx)
 
 	if d.verbose {
 		log.Log(
 			"msg", "listing locations",
-			"ownerUID", ownerUID,
-			"invalidLocation", invalidLocation,
-			"staleData", staleData,
+			"ownerUID", filter.OwnerUID,
+			"numThings", len(filter.ThingUIDs),
+			"invalidLocation", filter.InvalidLocation,
+			"staleData", filter.StaleData,
+			"boundingBox", filter.BoundingBox != nil,
+			"radius", filter.Radius != nil,
+			"polygon", len(filter.Polygon) > 0,
+			"status", filter.Status,
+			"dataSourceCodes", strings.Join(filter.DataSourceCodes, ","),
+			"sortBy", filter.SortBy,
+			"descending", filter.Descending,
+			"limit", filter.Limit,
 		)
 	}
 
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = "uid"
+	}
+
+	column, ok := locationSortColumns[sortBy]
+	if !ok {
+		return nil, errors.Errorf("invalid sort key: %s", sortBy)
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
 	builder := sq.Select(
 		"t.id", "t.uid", "t.long", "t.lat", "t.first_sample", "t.last_sample",
 		"t.last_uploaded_sample", "t.nickname", "t.location_identifier", "t.serial_num",
-		"u.uid AS user_uid",
+		"u.uid AS user_uid", fmt.Sprintf("(%s)::text AS sort_key", column.expr),
+		"t.firmware_version", "t.hardware_revision", "t.sensor_type", "t.calibration_data",
+		"t.is_indoor", "t.in_pot", "t.plant_ids", "t.autowatering_mode",
 	).
 		From("things t").
 		Join("users u ON u.id = t.owner_id").
-		OrderBy("t.uid")
+		OrderBy(fmt.Sprintf("%s %s", column.expr, direction), fmt.Sprintf("t.uid %s", direction))
 
-	if ownerUID != "" {
-		builder = builder.Where(sq.Eq{"u.uid": ownerUID})
-	}
+	builder = d.applyLocationFilter(builder, filter)
 
-	if invalidLocation {
-		builder = builder.Where(sq.Eq{"t.long": 0}).Where(sq.Eq{"t.lat": 0})
-		builder = builder.Where("t.last_sample >= NOW() - interval '90 days'")
+	if filter.After != nil {
+		builder = builder.Where(
+			fmt.Sprintf("(%s, t.uid) %s (?::%s, ?)", column.expr, comparison, column.cast),
+			filter.After.SortKey, filter.After.UID,
+		)
 	}
 
-	if staleData {
-		builder = builder.Where("t.last_sample < NOW() - interval '30 days' AND t.last_sample >= NOW() - interval '90 days'")
+	if filter.Limit > 0 {
+		builder = builder.Limit(filter.Limit)
 	}
 
 	sql, args, err := builder.ToSql()
@@ -86,38 +226,359 @@ func (d *DB) ListLocations(ctx context.Context, ownerUID string, invalidLocation
 	return locations, nil
 }
 
-// UpdateGeolocation takes as input a thing UID, and a long/lat pair and then is
-// responsible for updating the DB with the new values.
-func (d *DB) UpdateGeolocation(ctx context.Context, thingUID string, longitude, latitude float64) (*Location, error) {
+// CountLocations returns the total number of locations matching the filter,
+// ignoring any paging parameters.
+func (d *DB) CountLocations(ctx context.Context, filter *LocationFilter) (int64, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "counting locations", "ownerUID", filter.OwnerUID)
+	}
+
+	builder := sq.Select("COUNT(*)").
+		From("things t").
+		Join("users u ON u.id = t.owner_id")
+
+	builder = d.applyLocationFilter(builder, filter)
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build sql query")
+	}
+
+	var count int64
+	err = d.DB.Get(&count, d.DB.Rebind(sql), args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count locations")
+	}
+
+	return count, nil
+}
+
+// applyLocationFilter adds where clauses to the given builder for each of the
+// filtering parameters set on the filter.
+func (d *DB) applyLocationFilter(builder *sq.SelectBuilder, filter *LocationFilter) *sq.SelectBuilder {
+	if filter.OwnerUID != "" {
+		builder = builder.Where(sq.Eq{"u.uid": filter.OwnerUID})
+	}
+
+	if len(filter.ThingUIDs) > 0 {
+		builder = builder.Where("t.uid = ANY(?)", pq.Array(filter.ThingUIDs))
+	}
+
+	if filter.InvalidLocation {
+		builder = builder.Where(invalidLocationCondition)
+	}
+
+	if filter.StaleData {
+		builder = builder.Where(staleCondition)
+	}
+
+	if condition, ok := statusConditions[filter.Status]; ok {
+		builder = builder.Where(condition)
+	}
+
+	if filter.Indoor != nil {
+		builder = builder.Where(sq.Eq{"t.is_indoor": *filter.Indoor})
+	}
+
+	if filter.SensorType != "" {
+		builder = builder.Where(sq.Eq{"t.sensor_type": filter.SensorType})
+	}
+
+	if filter.FirmwareBefore != "" {
+		builder = builder.Where(sq.Lt{"t.firmware_version": filter.FirmwareBefore})
+	}
+
+	if len(filter.DataSourceCodes) > 0 {
+		subquery := `t.uid IN (
+			SELECT c.thing_uid FROM channels c
+			JOIN data_sources ds ON ds.id = c.data_source_id
+			WHERE ds.name = ANY(?)`
+
+		if filter.MatchAnyDataSource {
+			builder = builder.Where(subquery+")", pq.Array(filter.DataSourceCodes))
+		} else {
+			builder = builder.Where(
+				subquery+" GROUP BY c.thing_uid HAVING COUNT(DISTINCT ds.name) = ?)",
+				pq.Array(filter.DataSourceCodes), len(uniqueStrings(filter.DataSourceCodes)),
+			)
+		}
+	}
+
+	if filter.BoundingBox != nil {
+		builder = whereWithinBoundingBox(builder, filter.BoundingBox)
+	}
+
+	if filter.Radius != nil {
+		builder = whereWithinBoundingBox(builder, filter.Radius.boundingBox())
+
+		if d.postgis {
+			builder = builder.Where(
+				"ST_DWithin(ST_SetSRID(ST_MakePoint(t.long, t.lat), 4326)::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)",
+				filter.Radius.Center.Longitude, filter.Radius.Center.Latitude, filter.Radius.Distance,
+			)
+		} else {
+			// rounding can push the argument of asin just past 1 for antipodal
+			// points, which postgres rejects as out of range
+			builder = builder.Where(
+				`? * 2 * asin(LEAST(1, sqrt(
+					power(sin(radians(t.lat - ?) / 2), 2) +
+					cos(radians(?)) * cos(radians(t.lat)) * power(sin(radians(t.long - ?) / 2), 2)
+				))) <= ?`,
+				earthRadius, filter.Radius.Center.Latitude, filter.Radius.Center.Latitude,
+				filter.Radius.Center.Longitude, filter.Radius.Distance,
+			)
+		}
+	}
+
+	if len(filter.Polygon) > 0 {
+		builder = whereWithinBoundingBox(builder, polygonBoundingBox(filter.Polygon))
+
+		if d.postgis {
+			builder = builder.Where(
+				"ST_Covers(ST_GeomFromText(?, 4326), ST_SetSRID(ST_MakePoint(t.long, t.lat), 4326))",
+				polygonWKT(filter.Polygon),
+			)
+		} else {
+			builder = builder.Where("?::polygon @> point(t.long, t.lat)", polygonText(filter.Polygon))
+		}
+	}
+
+	return builder
+}
+
+// whereWithinBoundingBox adds a simple range query to the builder which is
+// able to use our index on things(lat, long)
+func whereWithinBoundingBox(builder *sq.SelectBuilder, bbox *BoundingBox) *sq.SelectBuilder {
+	return builder.
+		Where("t.lat BETWEEN ? AND ?", bbox.MinLatitude, bbox.MaxLatitude).
+		Where("t.long BETWEEN ? AND ?", bbox.MinLongitude, bbox.MaxLongitude)
+}
+
+// boundingBox returns a box that fully contains the circle described by the
+// radius. This is used to narrow down the rows we calculate distances for.
+func (r *Radius) boundingBox() *BoundingBox {
+	latDelta := r.Distance / metresPerDegree
+
+	// near the poles (or for very large distances) the longitude delta becomes
+	// unbounded, so just search all longitudes
+	longDelta := 180.0
+	if cos := math.Cos(r.Center.Latitude * math.Pi / 180); cos > 0.01 {
+		longDelta = math.Min(latDelta/cos, 180.0)
+	}
+
+	// a box crossing the antimeridian would wrap around, which a simple range
+	// can't express, so again just search all longitudes
+	if r.Center.Longitude-longDelta < -180 || r.Center.Longitude+longDelta > 180 {
+		longDelta = 360.0
+	}
+
+	return &BoundingBox{
+		MinLongitude: math.Max(r.Center.Longitude-longDelta, -180),
+		MinLatitude:  math.Max(r.Center.Latitude-latDelta, -90),
+		MaxLongitude: math.Min(r.Center.Longitude+longDelta, 180),
+		MaxLatitude:  math.Min(r.Center.Latitude+latDelta, 90),
+	}
+}
+
+// polygonBoundingBox returns the bounding box of the given polygon
+func polygonBoundingBox(polygon []Point) *BoundingBox {
+	bbox := &BoundingBox{
+		MinLongitude: polygon[0].Longitude,
+		MinLatitude:  polygon[0].Latitude,
+		MaxLongitude: polygon[0].Longitude,
+		MaxLatitude:  polygon[0].Latitude,
+	}
+
+	for _, p := range polygon[1:] {
+		bbox.MinLongitude = math.Min(bbox.MinLongitude, p.Longitude)
+		bbox.MinLatitude = math.Min(bbox.MinLatitude, p.Latitude)
+		bbox.MaxLongitude = math.Max(bbox.MaxLongitude, p.Longitude)
+		bbox.MaxLatitude = math.Max(bbox.MaxLatitude, p.Latitude)
+	}
+
+	return bbox
+}
+
+// polygonWKT returns the well known text representation of the polygon for
+// PostGIS. WKT requires the ring to be closed so we repeat the first point if
+// required.
+func polygonWKT(polygon []Point) string {
+	points := []string{}
+	for _, p := range closeRing(polygon) {
+		points = append(points, fmt.Sprintf("%s %s", formatFloat(p.Longitude), formatFloat(p.Latitude)))
+	}
+
+	return fmt.Sprintf("POLYGON((%s))", strings.Join(points, ", "))
+}
+
+// polygonText returns the polygon in the format understood by the native
+// Postgres polygon type, i.e. ((x1,y1),(x2,y2),...)
+func polygonText(polygon []Point) string {
+	points := []string{}
+	for _, p := range polygon {
+		points = append(points, fmt.Sprintf("(%s,%s)", formatFloat(p.Longitude), formatFloat(p.Latitude)))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(points, ","))
+}
+
+// closeRing returns the polygon with the first point appended if the ring
+// isn't already closed
+func closeRing(polygon []Point) []Point {
+	if polygon[0] == polygon[len(polygon)-1] {
+		return polygon
+	}
+
+	return append(append([]Point{}, polygon...), polygon[0])
+}
+
+// uniqueStrings returns the given slice with any duplicate values removed
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+
+	return unique
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// GeolocationUpdate is a single change of coordinates for a thing
+type GeolocationUpdate struct {
+	ThingUID  string
+	Longitude float64
+	Latitude  float64
+}
+
+// UpdateGeolocation takes as input a thing UID, the UID of the app making the
+// change, and a long/lat pair and then is responsible for updating the DB with
+// the new values. The app UID is recorded in the location history.
+func (d *DB) UpdateGeolocation(ctx context.Context, thingUID, appUID string, longitude, latitude float64) (*Location, error) {
 	log := logger.FromContext(ctx)
 
 	if d.verbose {
 		log.Log(
 			"msg", "updating geolocation",
 			"thingUID", thingUID,
+			"appUID", appUID,
 			"longitude", longitude,
 			"latitude", latitude,
 		)
 	}
 
-	sql := `UPDATE things SET long = :long, lat = :lat WHERE uid = :uid
-		RETURNING id, uid, long, lat, first_sample, last_sample, last_uploaded_sample,
-			nickname, location_identifier, serial_num`
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction when updating geolocation")
+	}
 
-	mapArgs := map[string]interface{}{
-		"long": longitude,
-		"lat":  latitude,
-		"uid":  thingUID,
+	err = setAppUID(tx, appUID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	loc, err := updateGeolocation(tx, &GeolocationUpdate{
+		ThingUID:  thingUID,
+		Longitude: longitude,
+		Latitude:  latitude,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return loc, tx.Commit()
+}
+
+// BulkUpdateGeolocations applies all of the given updates in a single
+// transaction, and enqueues a job to sync each updated thing to Thingful. The
+// updated locations are returned keyed by thing UID; things that don't exist
+// are missing from the returned map.
+func (d *DB) BulkUpdateGeolocations(ctx context.Context, appUID string, updates []GeolocationUpdate) (map[string]*Location, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "bulk updating geolocations",
+			"appUID", appUID,
+			"numUpdates", len(updates),
+		)
 	}
 
 	tx, err := d.DB.Beginx()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to begin transaction when updating geolocation")
+		return nil, errors.Wrap(err, "failed to begin transaction when updating geolocations")
 	}
 
-	sql, args, err := tx.BindNamed(sql, mapArgs)
+	err = setAppUID(tx, appUID)
 	if err != nil {
 		tx.Rollback()
+		return nil, err
+	}
+
+	locations := map[string]*Location{}
+
+	for _, update := range updates {
+		loc, err := updateGeolocation(tx, &update)
+		if err != nil {
+			if errors.Cause(err) == sql.ErrNoRows {
+				continue
+			}
+			tx.Rollback()
+			return nil, err
+		}
+
+		_, err = enqueueJob(tx, SyncThingQueue, &SyncThingPayload{ThingUID: loc.UID})
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		locations[loc.UID] = loc
+	}
+
+	return locations, tx.Commit()
+}
+
+// setAppUID records the app making changes within the transaction, which is
+// read by the location audit trigger
+func setAppUID(tx *sqlx.Tx, appUID string) error {
+	_, err := tx.Exec(`SELECT set_config('kudzu.app_uid', $1, true)`, appUID)
+	if err != nil {
+		return errors.Wrap(err, "failed to set app uid when updating geolocation")
+	}
+
+	return nil
+}
+
+// updateGeolocation updates the coordinates of a single thing within the given
+// transaction, publishing an event to any subscribed webhooks. Clients can
+// unwrap the returned error to check for an sql.ErrNoRows error to determine if
+// the thing does not exist.
+func updateGeolocation(tx *sqlx.Tx, update *GeolocationUpdate) (*Location, error) {
+	sql := `UPDATE things SET long = :long, lat = :lat WHERE uid = :uid
+		RETURNING id, uid, long, lat, first_sample, last_sample, last_uploaded_sample,
+			nickname, location_identifier, serial_num,
+			COALESCE((SELECT uid FROM users WHERE users.id = things.owner_id), '') AS user_uid`
+
+	mapArgs := map[string]interface{}{
+		"long": update.Longitude,
+		"lat":  update.Latitude,
+		"uid":  update.ThingUID,
+	}
+
+	sql, args, err := tx.BindNamed(sql, mapArgs)
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to bind named parameters when updating geolocation")
 	}
 
@@ -125,9 +586,100 @@ func (d *DB) UpdateGeolocation(ctx context.Context, thingUID string, longitude,
 
 	err = tx.Get(&loc, sql, args...)
 	if err != nil {
-		tx.Rollback()
 		return nil, errors.Wrap(err, "failed to execute update query to update geolocation")
 	}
 
-	return &loc, tx.Commit()
+	err = publishWebhookEvent(tx, ThingLocationChangedEvent, &ThingEventData{
+		ThingUID:   loc.UID,
+		UserUID:    loc.UserUID,
+		Longitude:  loc.Longitude,
+		Latitude:   loc.Latitude,
+		LastSample: loc.LastSampleUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &loc, nil
+}
+
+// LocationChange is a single entry in the coordinate history of a thing. The
+// previous coordinates are null for the entry recorded when the thing was
+// created.
+type LocationChange struct {
+	ID                int64       `db:"id"`
+	ThingUID          string      `db:"thing_uid"`
+	AppUID            null.String `db:"app_uid"`
+	InsertedAt        time.Time   `db:"inserted_at"`
+	PreviousLongitude null.Float  `db:"previous_long"`
+	PreviousLatitude  null.Float  `db:"previous_lat"`
+	NewLongitude      float64     `db:"new_long"`
+	NewLatitude       float64     `db:"new_lat"`
+}
+
+// GetLocationHistory returns the coordinate history of the thing identified by
+// the given UID in chronological order. Clients can unwrap the returned error to
+// check for an sql.ErrNoRows error to determine if the thing does not exist.
+func (d *DB) GetLocationHistory(ctx context.Context, thingUID string) ([]LocationChange, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting location history", "thingUID", thingUID)
+	}
+
+	var thingID int64
+
+	err := d.DB.Get(&thingID, `SELECT id FROM things WHERE uid = $1`, thingUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load thing from DB")
+	}
+
+	sql := `SELECT lc.id, t.uid AS thing_uid, lc.app_uid, lc.inserted_at, lc.previous_long,
+			lc.previous_lat, lc.new_long, lc.new_lat
+		FROM location_changes lc
+		JOIN things t ON t.id = lc.thing_id
+		WHERE lc.thing_id = $1
+		ORDER BY lc.inserted_at, lc.id`
+
+	changes := []LocationChange{}
+
+	err = d.DB.Select(&changes, sql, thingID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read location history")
+	}
+
+	return changes, nil
+}
+
+// GetLocationHistories returns the coordinate history of each of the things
+// identified by the given UIDs in chronological order, keyed by UID. Things we
+// have no record of, or that have no recorded changes, are absent from the
+// returned map.
+func (d *DB) GetLocationHistories(ctx context.Context, thingUIDs []string) (map[string][]LocationChange, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting location histories", "numThings", len(thingUIDs))
+	}
+
+	sql := `SELECT lc.id, t.uid AS thing_uid, lc.app_uid, lc.inserted_at, lc.previous_long,
+			lc.previous_lat, lc.new_long, lc.new_lat
+		FROM location_changes lc
+		JOIN things t ON t.id = lc.thing_id
+		WHERE t.uid = ANY($1)
+		ORDER BY t.uid, lc.inserted_at, lc.id`
+
+	changes := []LocationChange{}
+
+	err := d.DB.Select(&changes, sql, pq.Array(thingUIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read location histories")
+	}
+
+	histories := map[string][]LocationChange{}
+	for _, c := range changes {
+		histories[c.ThingUID] = append(histories[c.ThingUID], c)
+	}
+
+	return histories, nil
 }
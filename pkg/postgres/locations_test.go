@@ -64,25 +64,361 @@ func (s *LocationsSuite) TestListLocations() {
 
 	ctx := logger.ToContext(context.Background(), s.logger)
 
-	locations, err := s.db.ListLocations(ctx, "", false, false)
+	locations, err := s.db.ListLocations(ctx, &postgres.LocationFilter{})
 	assert.Nil(s.T(), err)
 	assert.Len(s.T(), locations, 4)
 
-	locations, err = s.db.ListLocations(ctx, "abc123", false, false)
+	locations, err = s.db.ListLocations(ctx, &postgres.LocationFilter{OwnerUID: "abc123"})
 	assert.Nil(s.T(), err)
 	assert.Len(s.T(), locations, 4)
 
-	locations, err = s.db.ListLocations(ctx, "foobar", false, false)
+	locations, err = s.db.ListLocations(ctx, &postgres.LocationFilter{OwnerUID: "foobar"})
 	assert.Nil(s.T(), err)
 	assert.Len(s.T(), locations, 0)
 
-	locations, err = s.db.ListLocations(ctx, "", true, false)
+	locations, err = s.db.ListLocations(ctx, &postgres.LocationFilter{InvalidLocation: true})
 	assert.Nil(s.T(), err)
 	assert.Len(s.T(), locations, 1)
 
-	locations, err = s.db.ListLocations(ctx, "", false, true)
+	locations, err = s.db.ListLocations(ctx, &postgres.LocationFilter{StaleData: true})
 	assert.Nil(s.T(), err)
 	assert.Len(s.T(), locations, 1)
+
+	locations, err = s.db.ListLocations(ctx, &postgres.LocationFilter{ThingUIDs: []string{"1234", "1236", "9999"}})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), locations, 2)
+}
+
+func (s *LocationsSuite) TestListLocationsSpatial() {
+	var userID int64
+
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "abc123")
+	assert.Nil(s.T(), err)
+
+	// london, paris, barcelona and fiji
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, last_sample)
+		VALUES
+			('1234', $1, 'PA1', -0.1276, 51.5072, 'LOC1', NOW()),
+			('1235', $1, 'PA2', 2.3522, 48.8566, 'LOC2', NOW()),
+			('1236', $1, 'PA3', 2.1734, 41.3851, 'LOC3', NOW()),
+			('1237', $1, 'PA4', 179.95, -16.8, 'LOC4', NOW())`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	testcases := []struct {
+		label       string
+		filter      *postgres.LocationFilter
+		expectedUID []string
+	}{
+		{
+			label: "bounding box around london",
+			filter: &postgres.LocationFilter{
+				BoundingBox: &postgres.BoundingBox{
+					MinLongitude: -1,
+					MinLatitude:  51,
+					MaxLongitude: 1,
+					MaxLatitude:  52,
+				},
+			},
+			expectedUID: []string{"1234"},
+		},
+		{
+			label: "within 5km of paris",
+			filter: &postgres.LocationFilter{
+				Radius: &postgres.Radius{
+					Center:   postgres.Point{Longitude: 2.35, Latitude: 48.85},
+					Distance: 5000,
+				},
+			},
+			expectedUID: []string{"1235"},
+		},
+		{
+			label: "within 500km of paris",
+			filter: &postgres.LocationFilter{
+				Radius: &postgres.Radius{
+					Center:   postgres.Point{Longitude: 2.35, Latitude: 48.85},
+					Distance: 500000,
+				},
+			},
+			expectedUID: []string{"1234", "1235"},
+		},
+		{
+			label: "within 50km across the antimeridian",
+			filter: &postgres.LocationFilter{
+				Radius: &postgres.Radius{
+					Center:   postgres.Point{Longitude: -179.95, Latitude: -16.8},
+					Distance: 50000,
+				},
+			},
+			expectedUID: []string{"1237"},
+		},
+		{
+			label: "half way around the world from the antipode of london",
+			filter: &postgres.LocationFilter{
+				Radius: &postgres.Radius{
+					Center:   postgres.Point{Longitude: 179.8724, Latitude: -51.5072},
+					Distance: 20100000,
+				},
+			},
+			expectedUID: []string{"1234", "1235", "1236", "1237"},
+		},
+		{
+			label: "polygon around spain",
+			filter: &postgres.LocationFilter{
+				Polygon: []postgres.Point{
+					{Longitude: -9.5, Latitude: 36},
+					{Longitude: 3.5, Latitude: 36},
+					{Longitude: 3.5, Latitude: 43.8},
+					{Longitude: -9.5, Latitude: 43.8},
+				},
+			},
+			expectedUID: []string{"1236"},
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			locations, err := s.db.ListLocations(ctx, tc.filter)
+			assert.Nil(t, err)
+
+			uids := []string{}
+			for _, l := range locations {
+				uids = append(uids, l.UID)
+			}
+
+			assert.Equal(t, tc.expectedUID, uids)
+		})
+	}
+}
+
+func (s *LocationsSuite) TestListLocationsByDataSourceAndStatus() {
+	var userID int64
+
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "abc123")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, last_sample)
+		VALUES
+			('1234', $1, 'PA1', 12.2, 13.3, 'LOC1', NOW()),
+			('1235', $1, 'PA2', 12.2, 13.3, 'LOC2', NOW() - interval '31 days'),
+			('1236', $1, 'PA3', 12.2, 13.3, 'LOC3', NOW() - interval '91 days')`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO data_sources (id, name, data_type)
+		VALUES (1, 'air_temperature', 'xsd:double'), (2, 'soil_moisture', 'xsd:double')`,
+	)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO channels (thing_uid, data_source_id)
+		VALUES ('1234', 1), ('1234', 2), ('1235', 1), ('1236', 2)`,
+	)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	testcases := []struct {
+		label       string
+		filter      *postgres.LocationFilter
+		expectedUID []string
+	}{
+		{
+			label:       "single data source",
+			filter:      &postgres.LocationFilter{DataSourceCodes: []string{"air_temperature"}},
+			expectedUID: []string{"1234", "1235"},
+		},
+		{
+			label:       "all data sources",
+			filter:      &postgres.LocationFilter{DataSourceCodes: []string{"air_temperature", "soil_moisture"}},
+			expectedUID: []string{"1234"},
+		},
+		{
+			label: "any data source",
+			filter: &postgres.LocationFilter{
+				DataSourceCodes:    []string{"air_temperature", "soil_moisture"},
+				MatchAnyDataSource: true,
+			},
+			expectedUID: []string{"1234", "1235", "1236"},
+		},
+		{
+			label:       "unknown data source",
+			filter:      &postgres.LocationFilter{DataSourceCodes: []string{"foo"}},
+			expectedUID: []string{},
+		},
+		{
+			label:       "live",
+			filter:      &postgres.LocationFilter{Status: postgres.StatusLive},
+			expectedUID: []string{"1234"},
+		},
+		{
+			label:       "stale",
+			filter:      &postgres.LocationFilter{Status: postgres.StatusStale},
+			expectedUID: []string{"1235"},
+		},
+		{
+			label:       "dead",
+			filter:      &postgres.LocationFilter{Status: postgres.StatusDead},
+			expectedUID: []string{"1236"},
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			locations, err := s.db.ListLocations(ctx, tc.filter)
+			assert.Nil(t, err)
+
+			uids := []string{}
+			for _, l := range locations {
+				uids = append(uids, l.UID)
+			}
+
+			assert.Equal(t, tc.expectedUID, uids)
+		})
+	}
+}
+
+func (s *LocationsSuite) TestListLocationsByHardware() {
+	var userID int64
+
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "abc123")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, location_identifier, firmware_version, sensor_type, is_indoor)
+		VALUES
+			('1234', $1, 'PA1', 'LOC1', '2016-09-14_hawaii-2.0.3_hardware-config-MP', 'flower-power', false),
+			('1235', $1, 'PA2', 'LOC2', '2015-11-02_hawaii-1.1.0_hardware-config-MP', 'flower-power', true),
+			('1236', $1, 'PA3', 'LOC3', '2017-03-01_pot-1.4.0', 'pot', true)`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+	indoor := true
+
+	testcases := []struct {
+		label    string
+		filter   *postgres.LocationFilter
+		expected []string
+	}{
+		{
+			label:    "indoor",
+			filter:   &postgres.LocationFilter{Indoor: &indoor},
+			expected: []string{"1235", "1236"},
+		},
+		{
+			label:    "sensor type",
+			filter:   &postgres.LocationFilter{SensorType: "pot"},
+			expected: []string{"1236"},
+		},
+		{
+			label:    "firmware before",
+			filter:   &postgres.LocationFilter{FirmwareBefore: "2017"},
+			expected: []string{"1234", "1235"},
+		},
+		{
+			label:    "combined",
+			filter:   &postgres.LocationFilter{Indoor: &indoor, FirmwareBefore: "2016-09-14"},
+			expected: []string{"1235"},
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			locations, err := s.db.ListLocations(ctx, tc.filter)
+			assert.Nil(t, err)
+
+			uids := []string{}
+			for _, l := range locations {
+				uids = append(uids, l.UID)
+			}
+
+			assert.Equal(t, tc.expected, uids)
+		})
+	}
+}
+
+func (s *LocationsSuite) TestListLocationsPaging() {
+	var userID int64
+
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "abc123")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, nickname, last_sample)
+		VALUES
+			('1234', $1, 'PA1', 12.2, 13.3, 'LOC1', 'charlie', NOW() - interval '1 day'),
+			('1235', $1, 'PA2', 12.2, 13.3, 'LOC2', 'alpha', NOW()),
+			('1236', $1, 'PA3', 12.2, 13.3, 'LOC3', 'bravo', NOW() - interval '2 days')`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	testcases := []struct {
+		label       string
+		sortBy      string
+		descending  bool
+		expectedUID []string
+	}{
+		{
+			label:       "default order",
+			expectedUID: []string{"1234", "1235", "1236"},
+		},
+		{
+			label:       "by nickname",
+			sortBy:      "nickname",
+			expectedUID: []string{"1235", "1236", "1234"},
+		},
+		{
+			label:       "by last sample descending",
+			sortBy:      "last_sample",
+			descending:  true,
+			expectedUID: []string{"1235", "1234", "1236"},
+		},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			filter := &postgres.LocationFilter{
+				SortBy:     tc.sortBy,
+				Descending: tc.descending,
+				Limit:      2,
+			}
+
+			uids := []string{}
+
+			locations, err := s.db.ListLocations(ctx, filter)
+			assert.Nil(t, err)
+			assert.Len(t, locations, 2)
+
+			for _, l := range locations {
+				uids = append(uids, l.UID)
+			}
+
+			last := locations[len(locations)-1]
+			filter.After = &postgres.LocationCursor{SortKey: last.SortKey, UID: last.UID}
+
+			locations, err = s.db.ListLocations(ctx, filter)
+			assert.Nil(t, err)
+			assert.Len(t, locations, 1)
+
+			for _, l := range locations {
+				uids = append(uids, l.UID)
+			}
+
+			assert.Equal(t, tc.expectedUID, uids)
+
+			count, err := s.db.CountLocations(ctx, filter)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(3), count)
+		})
+	}
 }
 
 func (s *LocationsSuite) TestUpdateGeolocation() {
@@ -110,7 +446,7 @@ func (s *LocationsSuite) TestUpdateGeolocation() {
 
 	ctx := logger.ToContext(context.Background(), s.logger)
 
-	loc, err := s.db.UpdateGeolocation(ctx, "1234", 25, 25)
+	loc, err := s.db.UpdateGeolocation(ctx, "1234", "app123", 25, 25)
 	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), loc)
 
@@ -118,13 +454,82 @@ func (s *LocationsSuite) TestUpdateGeolocation() {
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), 2, locationChangesCount)
 
-	locations, err := s.db.ListLocations(ctx, "", false, false)
+	locations, err := s.db.ListLocations(ctx, &postgres.LocationFilter{})
 	assert.Nil(s.T(), err)
 	assert.Len(s.T(), locations, 1)
 
 	location := locations[0]
 	assert.Equal(s.T(), 25.0, location.Longitude)
 	assert.Equal(s.T(), 25.0, location.Latitude)
+
+	history, err := s.db.GetLocationHistory(ctx, "1234")
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), history, 2)
+
+	assert.False(s.T(), history[0].AppUID.Valid)
+	assert.False(s.T(), history[0].PreviousLongitude.Valid)
+	assert.Equal(s.T(), 12.2, history[0].NewLongitude)
+	assert.Equal(s.T(), 13.3, history[0].NewLatitude)
+
+	assert.Equal(s.T(), "app123", history[1].AppUID.String)
+	assert.Equal(s.T(), 12.2, history[1].PreviousLongitude.Float64)
+	assert.Equal(s.T(), 13.3, history[1].PreviousLatitude.Float64)
+	assert.Equal(s.T(), 25.0, history[1].NewLongitude)
+	assert.Equal(s.T(), 25.0, history[1].NewLatitude)
+
+	// the app uid setting must not leak into subsequent transactions
+	_, err = s.db.DB.Exec(`UPDATE things SET long = 30 WHERE uid = '1234'`)
+	assert.Nil(s.T(), err)
+
+	history, err = s.db.GetLocationHistory(ctx, "1234")
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), history, 3)
+	assert.False(s.T(), history[2].AppUID.Valid)
+
+	_, err = s.db.GetLocationHistory(ctx, "unknown")
+	assert.NotNil(s.T(), err)
+
+	histories, err := s.db.GetLocationHistories(ctx, []string{"1234", "unknown"})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), histories, 1)
+	assert.Equal(s.T(), history, histories["1234"])
+}
+
+func (s *LocationsSuite) TestBulkUpdateGeolocations() {
+	var userID int64
+
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, "abc123")
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`
+		INSERT INTO things (uid, owner_id, serial_num, long, lat, location_identifier, last_sample)
+		VALUES
+			('1234', $1, 'PA1', 12.2, 13.3, 'LOC1', NOW()),
+			('1235', $1, 'PA2', 0, 0, 'LOC2', NOW())`, userID,
+	)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	locations, err := s.db.BulkUpdateGeolocations(ctx, "app123", []postgres.GeolocationUpdate{
+		{ThingUID: "1234", Longitude: 1, Latitude: 2},
+		{ThingUID: "1235", Longitude: 3, Latitude: 4},
+		{ThingUID: "unknown", Longitude: 5, Latitude: 6},
+	})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), locations, 2)
+	assert.Equal(s.T(), 1.0, locations["1234"].Longitude)
+	assert.Equal(s.T(), 4.0, locations["1235"].Latitude)
+
+	var jobCount int
+	err = s.db.DB.Get(&jobCount, `SELECT COUNT(*) FROM jobs WHERE queue = $1`, postgres.SyncThingQueue)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, jobCount)
+
+	history, err := s.db.GetLocationHistory(ctx, "1235")
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), history, 2)
+	assert.Equal(s.T(), "app123", history[1].AppUID.String)
 }
 
 func TestLocationsSuite(t *testing.T) {
@@ -2,9 +2,20 @@ package postgres
 
 import (
 	"context"
+	"time"
 
+	sq "github.com/elgris/sqrl"
 	"github.com/guregu/null"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// MaxMetadataLimit is the maximum number of channels that may be requested
+	// in a single page of metadata
+	MaxMetadataLimit = 1000
 )
 
 // Metadata is a struct used for returning metadata info from the DB
@@ -16,18 +27,77 @@ type Metadata struct {
 	LastSampleUTC  null.Time `db:"last_sample"`
 }
 
-// GetMetadata returns a slice of all "metadata" in the db relating to available
-// channels with timestamps
-func (d *DB) GetMetadata(ctx context.Context) ([]Metadata, error) {
-	sql := `SELECT c.id, c.data_source_id, c.thing_uid, t.first_sample, t.last_sample
-		FROM channels c
-		LEFT OUTER JOIN things t
-		ON t.uid = c.thing_uid
-		ORDER BY c.id`
+// MetadataFilter contains the optional filtering parameters that can be
+// applied when reading metadata. The zero value returns every channel.
+type MetadataFilter struct {
+	ThingUIDs     []string
+	OwnerUID      string
+	DataSourceIDs []int64
+
+	// StartDate and EndDate restrict the results to channels whose sample range
+	// overlaps the given window. Channels with no samples are excluded if
+	// either is set.
+	StartDate time.Time
+	EndDate   time.Time
+
+	// AfterID and Limit are used to page through results ordered by channel id
+	AfterID int64
+	Limit   uint64
+}
+
+// ChannelRange is used to record the first and last observation we have seen
+// for a single channel of a thing
+type ChannelRange struct {
+	Name           string
+	FirstSampleUTC time.Time
+	LastSampleUTC  time.Time
+}
+
+// GetMetadata returns a slice of "metadata" in the db relating to available
+// channels with timestamps, with the given filter applied
+func (d *DB) GetMetadata(ctx context.Context, filter *MetadataFilter) ([]Metadata, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "getting metadata",
+			"ownerUID", filter.OwnerUID,
+			"numThings", len(filter.ThingUIDs),
+			"numDataSources", len(filter.DataSourceIDs),
+			"afterID", filter.AfterID,
+			"limit", filter.Limit,
+		)
+	}
+
+	// channels not reported by every sensor, e.g. water_tank_level, have no
+	// sample range of their own, so fall back to that of the thing
+	builder := sq.Select(
+		"c.id", "c.data_source_id", "c.thing_uid",
+		"COALESCE(c.first_sample, t.first_sample) AS first_sample",
+		"COALESCE(c.last_sample, t.last_sample) AS last_sample",
+	).
+		From("channels c").
+		Join("things t ON t.uid = c.thing_uid").
+		OrderBy("c.id")
+
+	builder = applyMetadataFilter(builder, filter)
+
+	if filter.AfterID > 0 {
+		builder = builder.Where(sq.Gt{"c.id": filter.AfterID})
+	}
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(filter.Limit)
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build sql query")
+	}
 
 	metadata := []Metadata{}
 
-	rows, err := d.DB.Queryx(sql)
+	rows, err := d.DB.Queryx(d.DB.Rebind(sql), args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read metadata from the DB")
 	}
@@ -44,3 +114,108 @@ func (d *DB) GetMetadata(ctx context.Context) ([]Metadata, error) {
 
 	return metadata, nil
 }
+
+// CountMetadata returns the total number of channels matching the filter,
+// ignoring any paging parameters
+func (d *DB) CountMetadata(ctx context.Context, filter *MetadataFilter) (int64, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "counting metadata", "ownerUID", filter.OwnerUID)
+	}
+
+	builder := sq.Select("COUNT(*)").From("channels c").Join("things t ON t.uid = c.thing_uid")
+	builder = applyMetadataFilter(builder, filter)
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build sql query")
+	}
+
+	var count int64
+	err = d.DB.Get(&count, d.DB.Rebind(sql), args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count metadata")
+	}
+
+	return count, nil
+}
+
+// applyMetadataFilter adds where clauses to the builder for each of the
+// filtering parameters set on the filter
+func applyMetadataFilter(builder *sq.SelectBuilder, filter *MetadataFilter) *sq.SelectBuilder {
+	if len(filter.ThingUIDs) > 0 {
+		builder = builder.Where("c.thing_uid = ANY(?)", pq.Array(filter.ThingUIDs))
+	}
+
+	if filter.OwnerUID != "" {
+		builder = builder.Where(
+			"c.thing_uid IN (SELECT t.uid FROM things t JOIN users u ON u.id = t.owner_id WHERE u.uid = ?)",
+			filter.OwnerUID,
+		)
+	}
+
+	if len(filter.DataSourceIDs) > 0 {
+		builder = builder.Where("c.data_source_id = ANY(?)", pq.Array(filter.DataSourceIDs))
+	}
+
+	if !filter.StartDate.IsZero() {
+		builder = builder.Where("COALESCE(c.last_sample, t.last_sample) >= ?", filter.StartDate)
+	}
+
+	if !filter.EndDate.IsZero() {
+		builder = builder.Where("COALESCE(c.first_sample, t.first_sample) <= ?", filter.EndDate)
+	}
+
+	return builder
+}
+
+// UpdateChannelRanges widens the recorded sample range of each of the named
+// channels of a thing to include the given ranges
+func (d *DB) UpdateChannelRanges(ctx context.Context, thingUID string, ranges []ChannelRange) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "updating channel ranges",
+			"thingUID", thingUID,
+			"numChannels", len(ranges),
+		)
+	}
+
+	sql := `UPDATE channels c SET
+		first_sample = LEAST(c.first_sample, :first_sample),
+		last_sample = GREATEST(c.last_sample, :last_sample)
+	FROM data_sources ds
+	WHERE ds.id = c.data_source_id
+	AND c.thing_uid = :thing_uid
+	AND ds.name = :name`
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	for _, r := range ranges {
+		mapArgs := map[string]interface{}{
+			"first_sample": r.FirstSampleUTC,
+			"last_sample":  r.LastSampleUTC,
+			"thing_uid":    thingUID,
+			"name":         r.Name,
+		}
+
+		query, args, err := tx.BindNamed(sql, mapArgs)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "failed to bind named query")
+		}
+
+		_, err = tx.Exec(query, args...)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "failed to update channel range")
+		}
+	}
+
+	return tx.Commit()
+}
@@ -47,7 +47,7 @@ func (s *MetadataSuite) TestGetMetadata() {
 
 	thing := &postgres.Thing{
 		UID:             null.StringFrom("abc123"),
-		OwnerID:         userID,
+		OwnerID:         null.IntFrom(userID),
 		Provider:        null.StringFrom("parrot"),
 		SerialNum:       "PA123",
 		Longitude:       12.2,
@@ -65,9 +65,64 @@ func (s *MetadataSuite) TestGetMetadata() {
 	err = s.db.CreateThing(ctx, thing)
 	assert.Nil(s.T(), err)
 
-	metadata, err := s.db.GetMetadata(ctx)
+	metadata, err := s.db.GetMetadata(ctx, &postgres.MetadataFilter{})
 	assert.Nil(s.T(), err)
 	assert.Len(s.T(), metadata, 7)
+
+	// channels the indexer hasn't recorded a range for, e.g. those not reported
+	// by every sensor, fall back to the range of the thing
+	assert.WithinDuration(s.T(), now, metadata[0].FirstSampleUTC.Time, time.Millisecond)
+	assert.WithinDuration(s.T(), now, metadata[0].LastSampleUTC.Time, time.Millisecond)
+
+	err = s.db.UpdateChannelRanges(ctx, "abc123", []postgres.ChannelRange{
+		{
+			Name:           "air_temperature",
+			FirstSampleUTC: now.Add(-2 * time.Hour),
+			LastSampleUTC:  now.Add(-time.Hour),
+		},
+	})
+	assert.Nil(s.T(), err)
+
+	// ranges are only ever widened
+	err = s.db.UpdateChannelRanges(ctx, "abc123", []postgres.ChannelRange{
+		{
+			Name:           "air_temperature",
+			FirstSampleUTC: now.Add(-90 * time.Minute),
+			LastSampleUTC:  now,
+		},
+	})
+	assert.Nil(s.T(), err)
+
+	metadata, err = s.db.GetMetadata(ctx, &postgres.MetadataFilter{
+		StartDate: now.Add(-3 * time.Hour),
+		EndDate:   now.Add(-time.Hour),
+	})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), metadata, 1)
+	assert.WithinDuration(s.T(), now.Add(-2*time.Hour), metadata[0].FirstSampleUTC.Time, time.Millisecond)
+	assert.WithinDuration(s.T(), now, metadata[0].LastSampleUTC.Time, time.Millisecond)
+
+	metadata, err = s.db.GetMetadata(ctx, &postgres.MetadataFilter{
+		StartDate: now.Add(time.Hour),
+	})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), metadata, 0)
+
+	metadata, err = s.db.GetMetadata(ctx, &postgres.MetadataFilter{OwnerUID: "abc123", Limit: 5})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), metadata, 5)
+
+	metadata, err = s.db.GetMetadata(ctx, &postgres.MetadataFilter{ThingUIDs: []string{"abc123"}, AfterID: metadata[4].ID})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), metadata, 2)
+
+	metadata, err = s.db.GetMetadata(ctx, &postgres.MetadataFilter{ThingUIDs: []string{"foo"}})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), metadata, 0)
+
+	count, err := s.db.CountMetadata(ctx, &postgres.MetadataFilter{OwnerUID: "abc123"})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), int64(7), count)
 }
 
 func TestMetadataSuite(t *testing.T) {
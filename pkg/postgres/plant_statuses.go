@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+// PlantStatus is Parrot's latest assessment of a single variable of a thing,
+// e.g. that the soil moisture is too low and the plant should be watered
+type PlantStatus struct {
+	ThingUID       string     `db:"thing_uid"`
+	Variable       string     `db:"variable"`
+	StatusKey      string     `db:"status_key"`
+	InstructionKey string     `db:"instruction_key"`
+	MinThreshold   null.Float `db:"min_threshold"`
+	MaxThreshold   null.Float `db:"max_threshold"`
+	CurrentValue   null.Float `db:"current_value"`
+	StatusAt       time.Time  `db:"status_at"`
+}
+
+// SavePlantStatuses stores the plant statuses Parrot assessed at statusAt for
+// the thing identified by the location ID. A status is only replaced by a newer
+// one, and variables Parrot no longer reports are removed.
+func (d *DB) SavePlantStatuses(ctx context.Context, locationID string, statusAt time.Time, statuses []PlantStatus) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "saving plant statuses",
+			"locationID", locationID,
+			"statusAt", statusAt,
+			"count", len(statuses),
+		)
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	var thingID int64
+	err = tx.Get(&thingID, `SELECT id FROM things WHERE location_identifier = $1`, locationID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to load thing for plant statuses")
+	}
+
+	sqlQuery := `INSERT INTO plant_statuses
+		(thing_id, variable, status_key, instruction_key, min_threshold, max_threshold, current_value, status_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (thing_id, variable) DO UPDATE SET
+		status_key = EXCLUDED.status_key,
+		instruction_key = EXCLUDED.instruction_key,
+		min_threshold = EXCLUDED.min_threshold,
+		max_threshold = EXCLUDED.max_threshold,
+		current_value = EXCLUDED.current_value,
+		status_at = EXCLUDED.status_at
+	WHERE plant_statuses.status_at <= EXCLUDED.status_at`
+
+	for _, status := range statuses {
+		_, err = tx.Exec(
+			sqlQuery,
+			thingID,
+			status.Variable,
+			status.StatusKey,
+			status.InstructionKey,
+			status.MinThreshold,
+			status.MaxThreshold,
+			status.CurrentValue,
+			statusAt,
+		)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "failed to save plant status")
+		}
+	}
+
+	_, err = tx.Exec(`DELETE FROM plant_statuses WHERE thing_id = $1 AND status_at < $2`, thingID, statusAt)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to delete outdated plant statuses")
+	}
+
+	return tx.Commit()
+}
+
+// GetPlantStatuses returns the stored plant statuses of the given things,
+// keyed by thing UID. Things without any statuses have no entry.
+func (d *DB) GetPlantStatuses(ctx context.Context, thingUIDs []string) (map[string][]PlantStatus, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "loading plant statuses", "count", len(thingUIDs))
+	}
+
+	sqlQuery := `SELECT
+		t.uid AS thing_uid,
+		ps.variable,
+		ps.status_key,
+		ps.instruction_key,
+		ps.min_threshold,
+		ps.max_threshold,
+		ps.current_value,
+		ps.status_at
+	FROM plant_statuses ps
+	JOIN things t ON t.id = ps.thing_id
+	WHERE t.uid = ANY($1)
+	ORDER BY t.uid, ps.variable`
+
+	rows := []PlantStatus{}
+
+	err := d.DB.Select(&rows, sqlQuery, pq.Array(thingUIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load plant statuses")
+	}
+
+	statuses := map[string][]PlantStatus{}
+	for _, row := range rows {
+		statuses[row.ThingUID] = append(statuses[row.ThingUID], row)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,110 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type PlantStatusesSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *PlantStatusesSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *PlantStatusesSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *PlantStatusesSuite) TestSaveAndGetPlantStatuses() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ('user1') RETURNING id`)
+	assert.Nil(s.T(), err)
+
+	err = s.db.CreateThing(ctx, &postgres.Thing{
+		UID:        null.StringFrom("abc123"),
+		OwnerID:    null.IntFrom(userID),
+		Provider:   null.StringFrom("parrot"),
+		SerialNum:  "PA123",
+		LocationID: "loc1",
+	})
+	assert.Nil(s.T(), err)
+
+	err = s.db.SavePlantStatuses(ctx, "unknown", time.Now(), []postgres.PlantStatus{})
+	assert.NotNil(s.T(), err)
+
+	first := time.Date(2019, 6, 11, 9, 0, 0, 0, time.UTC)
+
+	err = s.db.SavePlantStatuses(ctx, "loc1", first, []postgres.PlantStatus{
+		{
+			Variable:       "soil_moisture",
+			StatusKey:      "status_warning",
+			InstructionKey: "soil_moisture_too_low",
+			MinThreshold:   null.FloatFrom(23),
+			CurrentValue:   null.FloatFrom(12.5),
+		},
+		{
+			Variable:     "battery_level",
+			MinThreshold: null.FloatFrom(0),
+			MaxThreshold: null.FloatFrom(100),
+			CurrentValue: null.FloatFrom(71),
+		},
+	})
+	assert.Nil(s.T(), err)
+
+	statuses, err := s.db.GetPlantStatuses(ctx, []string{"abc123", "other"})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), statuses, 1)
+	assert.Len(s.T(), statuses["abc123"], 2)
+	assert.Equal(s.T(), "battery_level", statuses["abc123"][0].Variable)
+	assert.Equal(s.T(), "soil_moisture_too_low", statuses["abc123"][1].InstructionKey)
+	assert.False(s.T(), statuses["abc123"][1].MaxThreshold.Valid)
+	assert.True(s.T(), first.Equal(statuses["abc123"][1].StatusAt))
+
+	// an older assessment doesn't replace the stored one
+	err = s.db.SavePlantStatuses(ctx, "loc1", first.Add(-time.Hour), []postgres.PlantStatus{
+		{Variable: "soil_moisture", StatusKey: "status_ok"},
+	})
+	assert.Nil(s.T(), err)
+
+	statuses, err = s.db.GetPlantStatuses(ctx, []string{"abc123"})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), statuses["abc123"], 2)
+	assert.Equal(s.T(), "status_warning", statuses["abc123"][1].StatusKey)
+
+	// a newer assessment replaces the stored one, removing missing variables
+	err = s.db.SavePlantStatuses(ctx, "loc1", first.Add(time.Hour), []postgres.PlantStatus{
+		{Variable: "soil_moisture", StatusKey: "status_ok", InstructionKey: "soil_moisture_good"},
+	})
+	assert.Nil(s.T(), err)
+
+	statuses, err = s.db.GetPlantStatuses(ctx, []string{"abc123"})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), statuses["abc123"], 1)
+	assert.Equal(s.T(), "status_ok", statuses["abc123"][0].StatusKey)
+	assert.False(s.T(), statuses["abc123"][0].CurrentValue.Valid)
+}
+
+func TestPlantStatusesSuite(t *testing.T) {
+	suite.Run(t, new(PlantStatusesSuite))
+}
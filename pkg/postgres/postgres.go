@@ -18,6 +18,7 @@ type DB struct {
 
 	connStr string
 	verbose bool
+	postgis bool
 }
 
 // NewDB returns a new DB instance which is not yet connected to the database
@@ -40,7 +41,19 @@ func (d *DB) Start() error {
 
 	log := kitlog.NewNopLogger()
 
-	return MigrateUp(d.DB.DB, log)
+	err = MigrateUp(d.DB.DB, log)
+	if err != nil {
+		return err
+	}
+
+	// spatial queries use PostGIS if present, falling back to plain SQL
+	// otherwise
+	err = d.DB.Get(&d.postgis, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis')`)
+	if err != nil {
+		return errors.Wrap(err, "failed to check for postgis extension")
+	}
+
+	return nil
 }
 
 // Stop stops the postgres connection pool
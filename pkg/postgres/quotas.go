@@ -0,0 +1,279 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+// QuotaPeriod is the period over which a quota of requests applies
+type QuotaPeriod string
+
+const (
+	// DailyQuota is a quota of requests per calendar day in UTC
+	DailyQuota = QuotaPeriod("day")
+
+	// MonthlyQuota is a quota of requests per calendar month in UTC
+	MonthlyQuota = QuotaPeriod("month")
+)
+
+// IsValid returns true if the period is one we know about
+func (p QuotaPeriod) IsValid() bool {
+	return p == DailyQuota || p == MonthlyQuota
+}
+
+// End returns the time at which the period containing the given time ends,
+// i.e. when usage within the period is reset
+func (p QuotaPeriod) End(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+
+	if p == MonthlyQuota {
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// AppQuota is the number of requests an app may make to routes requiring a
+// scope within a period
+type AppQuota struct {
+	Scope  ScopeClaim  `db:"scope"`
+	Period QuotaPeriod `db:"period"`
+	Limit  int         `db:"quota"`
+}
+
+// AppUsage is the number of requests an app has made to routes requiring a
+// scope today and this month, along with any quotas applying to them
+type AppUsage struct {
+	Scope        ScopeClaim `db:"scope"`
+	Today        int64      `db:"today"`
+	ThisMonth    int64      `db:"this_month"`
+	DailyQuota   null.Int   `db:"daily_quota"`
+	MonthlyQuota null.Int   `db:"monthly_quota"`
+}
+
+// CountRequest records a request made by the app with the given UID at the
+// given time to a route requiring the given scopes, if doing so is within the
+// app's quotas for each scope. If a quota has already been used up nothing is
+// recorded and the quota is returned, preferring a monthly quota over a daily
+// one as it is reset later. Concurrent requests may take usage slightly over a
+// quota, as they are checked against usage before either is recorded.
+func (d *DB) CountRequest(ctx context.Context, uid string, scopes ScopeClaims, now time.Time) (*AppQuota, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "counting request", "uid", uid, "scopes", scopes)
+	}
+
+	sql := `WITH app AS (
+			SELECT id FROM applications WHERE uid = $1
+		), exceeded AS (
+			SELECT q.scope, q.period, q.quota
+			FROM app_quotas q
+			JOIN app ON app.id = q.app_id
+			WHERE q.scope = ANY($2)
+			AND q.quota <= (
+				SELECT COALESCE(SUM(u.requests), 0)
+				FROM app_usage u
+				WHERE u.app_id = q.app_id
+				AND u.scope = q.scope
+				AND u.day <= $3::DATE
+				AND u.day >= CASE q.period
+					WHEN 'day' THEN $3::DATE
+					ELSE date_trunc('month', $3::DATE)::DATE
+				END
+			)
+			ORDER BY q.period DESC
+			LIMIT 1
+		), counted AS (
+			INSERT INTO app_usage (app_id, scope, day, requests)
+			SELECT app.id, scope, $3::DATE, 1
+			FROM app, unnest($2::TEXT[]) AS scope
+			WHERE NOT EXISTS (SELECT 1 FROM exceeded)
+			ON CONFLICT (app_id, scope, day)
+			DO UPDATE SET requests = app_usage.requests + 1
+		)
+		SELECT scope, period, quota FROM exceeded`
+
+	quotas := []AppQuota{}
+
+	err := d.DB.Select(&quotas, sql, uid, pq.Array(scopeStrings(scopes)), now.UTC().Format("2006-01-02"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count request")
+	}
+
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	return &quotas[0], nil
+}
+
+// ListAppQuotas returns the quotas of the app with the given UID. Clients can
+// unwrap the returned error to check for an sql.ErrNoRows error to determine if
+// no such app exists.
+func (d *DB) ListAppQuotas(ctx context.Context, uid string) ([]AppQuota, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "listing app quotas", "uid", uid)
+	}
+
+	var appID int
+
+	err := d.DB.Get(&appID, `SELECT id FROM applications WHERE uid = $1`, uid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	quotas := []AppQuota{}
+
+	err = d.DB.Select(
+		&quotas,
+		`SELECT scope, period, quota FROM app_quotas WHERE app_id = $1 ORDER BY scope, period`,
+		appID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list app quotas")
+	}
+
+	return quotas, nil
+}
+
+// SetAppQuotas sets the given quotas of the app with the given UID, a limit of
+// zero removing the quota for that scope and period. Quotas not given are left
+// unchanged. Clients can unwrap the returned error to check for an
+// sql.ErrNoRows error to determine if no such app exists, or a ClientError if
+// the quotas are invalid.
+func (d *DB) SetAppQuotas(ctx context.Context, uid string, quotas []AppQuota) ([]AppQuota, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "setting app quotas", "uid", uid)
+	}
+
+	for _, quota := range quotas {
+		if !areKnownClaims(ScopeClaims{quota.Scope}) {
+			return nil, errors.Wrapf(ClientError, "unknown scope: %s", quota.Scope)
+		}
+
+		if !quota.Period.IsValid() {
+			return nil, errors.Wrapf(ClientError, "unknown period: %s", quota.Period)
+		}
+
+		if quota.Limit < 0 {
+			return nil, errors.Wrap(ClientError, "limit must not be negative")
+		}
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create transaction to set app quotas")
+	}
+
+	var appID int
+
+	err = tx.Get(&appID, `SELECT id FROM applications WHERE uid = $1`, uid)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	for _, quota := range quotas {
+		if quota.Limit == 0 {
+			_, err = tx.Exec(
+				`DELETE FROM app_quotas WHERE app_id = $1 AND scope = $2 AND period = $3`,
+				appID, quota.Scope, quota.Period,
+			)
+		} else {
+			_, err = tx.Exec(
+				`INSERT INTO app_quotas (app_id, scope, period, quota)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (app_id, scope, period)
+				DO UPDATE SET quota = EXCLUDED.quota`,
+				appID, quota.Scope, quota.Period, quota.Limit,
+			)
+		}
+
+		if err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(err, "failed to set app quota")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to commit app quotas")
+	}
+
+	return d.ListAppQuotas(ctx, uid)
+}
+
+// GetAppUsage returns the usage of the app with the given UID on the day and
+// in the month containing the given time, for every scope it has used this
+// month or has a quota for. Clients can unwrap the returned error to check for
+// an sql.ErrNoRows error to determine if no such app exists.
+func (d *DB) GetAppUsage(ctx context.Context, uid string, now time.Time) ([]AppUsage, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting app usage", "uid", uid)
+	}
+
+	var appID int
+
+	err := d.DB.Get(&appID, `SELECT id FROM applications WHERE uid = $1`, uid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	sql := `WITH usage AS (
+			SELECT scope,
+				SUM(requests) FILTER (WHERE day = $2::DATE) AS today,
+				SUM(requests) AS this_month
+			FROM app_usage
+			WHERE app_id = $1
+			AND day >= date_trunc('month', $2::DATE)::DATE
+			AND day <= $2::DATE
+			GROUP BY scope
+		), quotas AS (
+			SELECT scope,
+				MAX(quota) FILTER (WHERE period = 'day') AS daily_quota,
+				MAX(quota) FILTER (WHERE period = 'month') AS monthly_quota
+			FROM app_quotas
+			WHERE app_id = $1
+			GROUP BY scope
+		)
+		SELECT COALESCE(u.scope, q.scope) AS scope,
+			COALESCE(u.today, 0) AS today,
+			COALESCE(u.this_month, 0) AS this_month,
+			q.daily_quota,
+			q.monthly_quota
+		FROM usage u
+		FULL OUTER JOIN quotas q ON q.scope = u.scope
+		ORDER BY 1`
+
+	usage := []AppUsage{}
+
+	err = d.DB.Select(&usage, sql, appID, now.UTC().Format("2006-01-02"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app usage")
+	}
+
+	return usage, nil
+}
+
+// scopeStrings returns the given scope claims as plain strings
+func scopeStrings(scopes ScopeClaims) []string {
+	strs := make([]string, len(scopes))
+	for i, scope := range scopes {
+		strs[i] = string(scope)
+	}
+
+	return strs
+}
@@ -0,0 +1,141 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type QuotasSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *QuotasSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *QuotasSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *QuotasSuite) TestCountRequest() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "name", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	quotas, err := s.db.SetAppQuotas(ctx, app.UID, []postgres.AppQuota{
+		{Scope: postgres.GetTimeSeriesDataScope, Period: postgres.DailyQuota, Limit: 2},
+		{Scope: postgres.GetTimeSeriesDataScope, Period: postgres.MonthlyQuota, Limit: 3},
+	})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), quotas, 2)
+
+	day := time.Date(2019, 6, 17, 9, 0, 0, 0, time.UTC)
+	timeseries := postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}
+
+	for i := 0; i < 2; i++ {
+		quota, err := s.db.CountRequest(ctx, app.UID, timeseries, day)
+		assert.Nil(s.T(), err)
+		assert.Nil(s.T(), quota)
+	}
+
+	quota, err := s.db.CountRequest(ctx, app.UID, timeseries, day)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), &postgres.AppQuota{Scope: postgres.GetTimeSeriesDataScope, Period: postgres.DailyQuota, Limit: 2}, quota)
+
+	// scopes without a quota are counted but not limited
+	for i := 0; i < 5; i++ {
+		quota, err = s.db.CountRequest(ctx, app.UID, postgres.ScopeClaims{postgres.GetMetadataScope}, day)
+		assert.Nil(s.T(), err)
+		assert.Nil(s.T(), quota)
+	}
+
+	// the next day the monthly quota applies
+	quota, err = s.db.CountRequest(ctx, app.UID, timeseries, day.AddDate(0, 0, 1))
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), quota)
+
+	quota, err = s.db.CountRequest(ctx, app.UID, timeseries, day.AddDate(0, 0, 1))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), postgres.MonthlyQuota, quota.Period)
+
+	usage, err := s.db.GetAppUsage(ctx, app.UID, day.AddDate(0, 0, 1))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []postgres.AppUsage{
+		{Scope: postgres.GetMetadataScope, Today: 0, ThisMonth: 5},
+		{Scope: postgres.GetTimeSeriesDataScope, Today: 1, ThisMonth: 3, DailyQuota: null.IntFrom(2), MonthlyQuota: null.IntFrom(3)},
+	}, usage)
+
+	// and next month usage starts again
+	quota, err = s.db.CountRequest(ctx, app.UID, timeseries, day.AddDate(0, 1, 0))
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), quota)
+
+	// removing quotas leaves the others unchanged
+	quotas, err = s.db.SetAppQuotas(ctx, app.UID, []postgres.AppQuota{
+		{Scope: postgres.GetTimeSeriesDataScope, Period: postgres.MonthlyQuota, Limit: 0},
+	})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []postgres.AppQuota{
+		{Scope: postgres.GetTimeSeriesDataScope, Period: postgres.DailyQuota, Limit: 2},
+	}, quotas)
+}
+
+func (s *QuotasSuite) TestSetAppQuotasInvalid() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "name", postgres.ScopeClaims{postgres.GetTimeSeriesDataScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	testcases := []struct {
+		label string
+		quota postgres.AppQuota
+	}{
+		{"unknown scope", postgres.AppQuota{Scope: "foo", Period: postgres.DailyQuota, Limit: 1}},
+		{"unknown period", postgres.AppQuota{Scope: postgres.GetTimeSeriesDataScope, Period: "week", Limit: 1}},
+		{"negative limit", postgres.AppQuota{Scope: postgres.GetTimeSeriesDataScope, Period: postgres.DailyQuota, Limit: -1}},
+	}
+
+	for _, tc := range testcases {
+		s.T().Run(tc.label, func(t *testing.T) {
+			_, err := s.db.SetAppQuotas(ctx, app.UID, []postgres.AppQuota{tc.quota})
+			assert.Equal(t, postgres.ClientError, errors.Cause(err))
+		})
+	}
+
+	_, err = s.db.SetAppQuotas(ctx, "unknown", []postgres.AppQuota{})
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	_, err = s.db.GetAppUsage(ctx, "unknown", time.Now())
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+}
+
+func TestQuotaPeriodEnd(t *testing.T) {
+	now := time.Date(2019, 12, 31, 18, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), postgres.DailyQuota.End(now))
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), postgres.MonthlyQuota.End(now))
+}
+
+func TestQuotasSuite(t *testing.T) {
+	suite.Run(t, new(QuotasSuite))
+}
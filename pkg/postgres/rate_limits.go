@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+// RateLimit is the outcome of applying a rate limit to a request. TAT is the
+// theoretical arrival time of the key after the request, i.e. when the key
+// will have used none of its burst.
+type RateLimit struct {
+	Allowed bool      `db:"allowed"`
+	TAT     time.Time `db:"tat"`
+}
+
+// ApplyRateLimit records a request for the given key at the given time, if it
+// is allowed by a limit of one request every emission interval with the given
+// burst. The limit is shared by every process using the database.
+func (d *DB) ApplyRateLimit(ctx context.Context, key string, now time.Time, emission time.Duration, burst int) (*RateLimit, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "applying rate limit", "key", key, "emission", emission, "burst", burst)
+	}
+
+	var limit RateLimit
+
+	err := d.DB.Get(
+		&limit,
+		`SELECT allowed, tat FROM rate_limit($1, $2, make_interval(secs => $3), $4)`,
+		key, now, emission.Seconds(), burst,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply rate limit")
+	}
+
+	return &limit, nil
+}
+
+// DeleteRateLimits removes the state of keys that had used none of their burst
+// by the given time, which is the same as having no state
+func (d *DB) DeleteRateLimits(ctx context.Context, before time.Time) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "deleting rate limits", "before", before)
+	}
+
+	_, err := d.DB.Exec(`DELETE FROM rate_limits WHERE tat < $1`, before)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete rate limits")
+	}
+
+	return nil
+}
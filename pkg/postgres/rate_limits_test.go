@@ -0,0 +1,85 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type RateLimitsSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *RateLimitsSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *RateLimitsSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *RateLimitsSuite) TestApplyRateLimit() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+	clock := clockwork.NewFakeClockAt(time.Date(2019, 6, 16, 9, 0, 0, 0, time.UTC))
+
+	allow := func(key string) *postgres.RateLimit {
+		limit, err := s.db.ApplyRateLimit(ctx, key, clock.Now(), 500*time.Millisecond, 3)
+		assert.Nil(s.T(), err)
+		return limit
+	}
+
+	// a burst of 3 is allowed at once
+	for i := 1; i <= 3; i++ {
+		limit := allow("app1")
+		assert.True(s.T(), limit.Allowed)
+		assert.True(s.T(), clock.Now().Add(time.Duration(i)*500*time.Millisecond).Equal(limit.TAT))
+	}
+
+	limit := allow("app1")
+	assert.False(s.T(), limit.Allowed)
+	assert.True(s.T(), clock.Now().Add(1500*time.Millisecond).Equal(limit.TAT))
+
+	// other keys are limited separately
+	assert.True(s.T(), allow("app2").Allowed)
+
+	// one more request is allowed after each interval
+	clock.Advance(500 * time.Millisecond)
+	assert.True(s.T(), allow("app1").Allowed)
+	assert.False(s.T(), allow("app1").Allowed)
+
+	// and once idle the full burst is available again
+	clock.Advance(time.Minute)
+
+	err := s.db.DeleteRateLimits(ctx, clock.Now())
+	assert.Nil(s.T(), err)
+
+	var count int
+	err = s.db.DB.Get(&count, `SELECT COUNT(*) FROM rate_limits`)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, count)
+
+	for i := 0; i < 3; i++ {
+		assert.True(s.T(), allow("app1").Allowed)
+	}
+	assert.False(s.T(), allow("app1").Allowed)
+}
+
+func TestRateLimitsSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitsSuite))
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// StreamEventsChannel is the name of the channel on which we notify
+	// listeners of new stream events. The payload of each notification is the
+	// id of the event.
+	StreamEventsChannel = "stream_events"
+
+	// streamEventRetention is how long we keep stream events so that clients
+	// that reconnect are able to replay any events they missed
+	streamEventRetention = "1 day"
+
+	// maxStreamEvents is the maximum number of events returned when replaying
+	// missed events
+	maxStreamEvents = 100
+)
+
+// StreamEvent is an event pushed to streaming clients that have subscribed to
+// the thing with the given uid. The payload is arbitrary JSON.
+type StreamEvent struct {
+	ID        int64     `db:"id"`
+	ThingUID  string    `db:"thing_uid"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// PublishStreamEvent saves a new event for the given thing and notifies all
+// listeners of its id. Notifications are only delivered once the transaction
+// commits, so listeners are always able to read the event. Expired events are
+// removed as we go.
+func (d *DB) PublishStreamEvent(ctx context.Context, thingUID string, payload interface{}) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "publishing stream event", "thingUID", thingUID)
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal stream event payload")
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	var id int64
+	err = tx.Get(&id, `INSERT INTO stream_events (thing_uid, payload) VALUES ($1, $2) RETURNING id`, thingUID, string(b))
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to insert stream event")
+	}
+
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, StreamEventsChannel, strconv.FormatInt(id, 10))
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to notify listeners of stream event")
+	}
+
+	_, err = tx.Exec(`DELETE FROM stream_events WHERE created_at < NOW() - interval '` + streamEventRetention + `'`)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to delete expired stream events")
+	}
+
+	return tx.Commit()
+}
+
+// GetStreamEvent returns the stream event with the given id
+func (d *DB) GetStreamEvent(ctx context.Context, id int64) (*StreamEvent, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting stream event", "id", id)
+	}
+
+	var event StreamEvent
+
+	err := d.DB.Get(&event, `SELECT id, thing_uid, payload, created_at FROM stream_events WHERE id = $1`, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get stream event")
+	}
+
+	return &event, nil
+}
+
+// ListStreamEvents returns retained events with an id greater than the given
+// id in the order they were published, optionally only those for the given
+// things. At most 100 events are returned, so callers wanting all events
+// should keep calling this with the id of the last event returned.
+func (d *DB) ListStreamEvents(ctx context.Context, afterID int64, thingUIDs []string) ([]*StreamEvent, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "listing stream events", "afterID", afterID)
+	}
+
+	query := `SELECT id, thing_uid, payload, created_at
+		FROM stream_events
+		WHERE id > $1
+		AND ($2::text[] IS NULL OR thing_uid = ANY($2))
+		ORDER BY id
+		LIMIT $3`
+
+	var uids interface{}
+	if len(thingUIDs) > 0 {
+		uids = pq.Array(thingUIDs)
+	}
+
+	events := []*StreamEvent{}
+
+	err := d.DB.Select(&events, query, afterID, uids, maxStreamEvents)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list stream events")
+	}
+
+	return events, nil
+}
+
+// LastStreamEventID returns the id of the most recently published stream event
+// or 0 if there are none
+func (d *DB) LastStreamEventID(ctx context.Context) (int64, error) {
+	var id int64
+
+	err := d.DB.Get(&id, `SELECT COALESCE(MAX(id), 0) FROM stream_events`)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get last stream event id")
+	}
+
+	return id, nil
+}
+
+// Listen returns a new listener on its own connection that receives
+// notifications sent to the named channel. The listener reconnects
+// automatically if the connection is lost, sending a nil notification once it
+// has done so. Callers are responsible for closing the listener.
+func (d *DB) Listen(channel string) (*pq.Listener, error) {
+	listener := pq.NewListener(d.connStr, 10*time.Second, time.Minute, nil)
+
+	err := listener.Listen(channel)
+	if err != nil {
+		listener.Close()
+		return nil, errors.Wrap(err, "failed to listen for notifications")
+	}
+
+	return listener, nil
+}
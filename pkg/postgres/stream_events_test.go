@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type StreamEventsSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *StreamEventsSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *StreamEventsSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *StreamEventsSuite) TestPublishAndList() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	lastID, err := s.db.LastStreamEventID(ctx)
+	assert.Nil(s.T(), err)
+
+	listener, err := s.db.Listen(postgres.StreamEventsChannel)
+	assert.Nil(s.T(), err)
+	defer listener.Close()
+
+	err = s.db.PublishStreamEvent(ctx, "abc123", map[string]string{"value": "a"})
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishStreamEvent(ctx, "def456", map[string]string{"value": "b"})
+	assert.Nil(s.T(), err)
+
+	// listeners are notified of the id of each event
+	ids := []int64{}
+	for len(ids) < 2 {
+		select {
+		case n := <-listener.NotificationChannel():
+			id, err := strconv.ParseInt(n.Extra, 10, 64)
+			assert.Nil(s.T(), err)
+			ids = append(ids, id)
+		case <-time.After(5 * time.Second):
+			s.T().Fatal("timed out waiting for notification")
+		}
+	}
+
+	event, err := s.db.GetStreamEvent(ctx, ids[0])
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "abc123", event.ThingUID)
+	assert.JSONEq(s.T(), `{"value": "a"}`, string(event.Payload))
+
+	events, err := s.db.ListStreamEvents(ctx, lastID, nil)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), events, 2)
+	assert.Equal(s.T(), ids, []int64{events[0].ID, events[1].ID})
+
+	events, err = s.db.ListStreamEvents(ctx, lastID, []string{"def456"})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), events, 1)
+	assert.Equal(s.T(), "def456", events[0].ThingUID)
+
+	events, err = s.db.ListStreamEvents(ctx, ids[1], nil)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), events, 0)
+}
+
+func TestStreamEventsSuite(t *testing.T) {
+	suite.Run(t, new(StreamEventsSuite))
+}
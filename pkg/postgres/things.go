@@ -2,8 +2,10 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/guregu/null"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
 	"github.com/thingful/kudzu/pkg/logger"
@@ -13,7 +15,7 @@ import (
 type Thing struct {
 	ID              int64       `db:"id"`
 	UID             null.String `db:"uid"`
-	OwnerID         int64       `db:"owner_id"`
+	OwnerID         null.Int    `db:"owner_id"`
 	Provider        null.String `db:"provider"`
 	SerialNum       string      `db:"serial_num"`
 	Latitude        float64     `db:"lat"`
@@ -26,12 +28,38 @@ type Thing struct {
 	Nickname        null.String `db:"nickname"`
 	LastUploadedUTC null.Time   `db:"last_uploaded_sample"`
 	LocationID      string      `db:"location_identifier"`
+	StaleAt         null.Time   `db:"stale_at"`
+
+	Hardware
 
 	// TODO - delete when old server removed
 	DataURL     null.String `db:"data_url"`
 	ResourceURL null.String `db:"resource_url"`
 }
 
+// Hardware is the metadata of a thing's sensor as read from the Parrot
+// configuration. Changes to it are recorded in the hardware history.
+type Hardware struct {
+	FirmwareVersion  null.String   `db:"firmware_version"`
+	HardwareRevision null.String   `db:"hardware_revision"`
+	SensorType       null.String   `db:"sensor_type"`
+	CalibrationData  null.String   `db:"calibration_data"`
+	IsIndoor         null.Bool     `db:"is_indoor"`
+	InPot            null.Bool     `db:"in_pot"`
+	PlantIDs         pq.Int64Array `db:"plant_ids"`
+	AutowateringMode null.String   `db:"autowatering_mode"`
+}
+
+// HardwareChange is an entry of a thing's hardware history, recording the
+// hardware from the time it was inserted
+type HardwareChange struct {
+	ID         int64     `db:"id"`
+	ThingUID   string    `db:"thing_uid"`
+	InsertedAt time.Time `db:"inserted_at"`
+
+	Hardware
+}
+
 // Channel is used to persist channel information to the database
 type Channel struct {
 	Name         string      `db:"name"`
@@ -103,9 +131,13 @@ func (d *DB) CreateThing(ctx context.Context, thing *Thing) error {
 
 	sql := `INSERT INTO things
 		(uid, owner_id, provider, serial_num, lat, long, first_sample, last_sample, created_at,
-		 	indexed_at, updated_at, nickname, last_uploaded_sample, location_identifier)
+		 	indexed_at, updated_at, nickname, last_uploaded_sample, location_identifier,
+			firmware_version, hardware_revision, sensor_type, calibration_data, is_indoor, in_pot,
+			plant_ids, autowatering_mode)
 		VALUES (:uid, :owner_id, :provider, :serial_num, :lat, :long, :first_sample, :last_sample,
-			:created_at, :indexed_at, :updated_at, :nickname, :last_uploaded_sample, :location_identifier)
+			:created_at, :indexed_at, :updated_at, :nickname, :last_uploaded_sample, :location_identifier,
+			:firmware_version, :hardware_revision, :sensor_type, :calibration_data, :is_indoor, :in_pot,
+			:plant_ids, :autowatering_mode)
 		RETURNING id`
 
 	tx, err := d.DB.Beginx()
@@ -127,6 +159,8 @@ func (d *DB) CreateThing(ctx context.Context, thing *Thing) error {
 		return errors.Wrap(err, "failed to execute thing insertion")
 	}
 
+	thing.ID = thingID
+
 	// upsert data_sources
 	datasourceSQL := `INSERT INTO data_sources
 			(name, unit, data_type)
@@ -218,6 +252,85 @@ func (d *DB) UpdateThing(ctx context.Context, thing *Thing) error {
 	return tx.Commit()
 }
 
+// UpdateHardware updates the hardware of the thing identified by the location
+// ID. The database records the previous hardware in the history if it changed.
+func (d *DB) UpdateHardware(ctx context.Context, locationID string, hardware *Hardware) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "updating hardware",
+			"locationID", locationID,
+			"firmwareVersion", hardware.FirmwareVersion.String,
+		)
+	}
+
+	sql := `UPDATE things SET
+		firmware_version = :firmware_version,
+		hardware_revision = :hardware_revision,
+		sensor_type = :sensor_type,
+		calibration_data = :calibration_data,
+		is_indoor = :is_indoor,
+		in_pot = :in_pot,
+		plant_ids = :plant_ids,
+		autowatering_mode = :autowatering_mode
+	WHERE location_identifier = :location_identifier`
+
+	args := map[string]interface{}{
+		"firmware_version":    hardware.FirmwareVersion,
+		"hardware_revision":   hardware.HardwareRevision,
+		"sensor_type":         hardware.SensorType,
+		"calibration_data":    hardware.CalibrationData,
+		"is_indoor":           hardware.IsIndoor,
+		"in_pot":              hardware.InPot,
+		"plant_ids":           hardware.PlantIDs,
+		"autowatering_mode":   hardware.AutowateringMode,
+		"location_identifier": locationID,
+	}
+
+	_, err := d.DB.NamedExec(sql, args)
+	if err != nil {
+		return errors.Wrap(err, "failed to update hardware")
+	}
+
+	return nil
+}
+
+// GetHardwareHistory returns the hardware history of a single thing, oldest
+// first. Clients can unwrap the returned error to check for an sql.ErrNoRows
+// error to determine if the thing doesn't exist.
+func (d *DB) GetHardwareHistory(ctx context.Context, thingUID string) ([]HardwareChange, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting hardware history", "thingUID", thingUID)
+	}
+
+	var thingID int64
+
+	err := d.DB.Get(&thingID, `SELECT id FROM things WHERE uid = $1`, thingUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load thing from DB")
+	}
+
+	sql := `SELECT hc.id, t.uid AS thing_uid, hc.inserted_at, hc.firmware_version,
+			hc.hardware_revision, hc.sensor_type, hc.calibration_data, hc.is_indoor,
+			hc.in_pot, hc.plant_ids, hc.autowatering_mode
+		FROM hardware_changes hc
+		JOIN things t ON t.id = hc.thing_id
+		WHERE hc.thing_id = $1
+		ORDER BY hc.inserted_at, hc.id`
+
+	changes := []HardwareChange{}
+
+	err = d.DB.Select(&changes, sql, thingID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read hardware history")
+	}
+
+	return changes, nil
+}
+
 // UpdateNickname updates just a devices nickname
 func (d *DB) UpdateNickname(ctx context.Context, locationID, nickname string) error {
 	log := logger.FromContext(ctx)
@@ -261,6 +374,44 @@ func (d *DB) UpdateNickname(ctx context.Context, locationID, nickname string) er
 	return tx.Commit()
 }
 
+const (
+	// StatusLive is the status of a thing which has sent data within the last
+	// 30 days
+	StatusLive = "live"
+
+	// StatusStale is the status of a thing which last sent data between 30 and
+	// 90 days ago
+	StatusStale = "stale"
+
+	// StatusDead is the status of a thing which hasn't sent data for over 90
+	// days
+	StatusDead = "dead"
+)
+
+// SQL conditions used to classify things by status. These are shared between
+// the metrics we export and the filters available when listing locations so
+// that the two always agree. They expect the things table to be aliased as t.
+const (
+	liveCondition            = "t.last_sample >= NOW() - interval '30 days'"
+	staleCondition           = "t.last_sample < NOW() - interval '30 days' AND t.last_sample >= NOW() - interval '90 days'"
+	deadCondition            = "t.last_sample < NOW() - interval '90 days'"
+	invalidLocationCondition = "t.lat = 0 AND t.long = 0 AND t.last_sample >= NOW() - interval '90 days'"
+)
+
+// statusConditions maps each thing status to the condition that selects it
+var statusConditions = map[string]string{
+	StatusLive:  liveCondition,
+	StatusStale: staleCondition,
+	StatusDead:  deadCondition,
+}
+
+// IsValidStatus returns true if the given value is a thing status we are able
+// to filter by
+func IsValidStatus(status string) bool {
+	_, ok := statusConditions[status]
+	return ok
+}
+
 // ThingStats is a data structure used to pass
 type ThingStats struct {
 	All             float64 `db:"all_things"`
@@ -289,12 +440,12 @@ func (d *DB) GetThingStats(ctx context.Context) ([]ThingStats, error) {
 		provider
 	FROM (
 		SELECT
-			CASE WHEN last_sample < NOW() - interval '30 days' AND last_sample >= NOW() - interval '90 days' THEN 1 END stale,
-			CASE WHEN last_sample >= NOW() - interval '30 days' THEN 1 END live,
-			CASE WHEN last_sample < NOW() - interval '90 days' THEN 1 END dead,
-			CASE WHEN lat = 0 AND long = 0 AND last_sample >= NOW() - interval '90 days' THEN 1 END invalid_location,
-			provider
-		FROM things
+			CASE WHEN ` + staleCondition + ` THEN 1 END stale,
+			CASE WHEN ` + liveCondition + ` THEN 1 END live,
+			CASE WHEN ` + deadCondition + ` THEN 1 END dead,
+			CASE WHEN ` + invalidLocationCondition + ` THEN 1 END invalid_location,
+			t.provider
+		FROM things t
 	) things GROUP BY provider`
 
 	rows, err := d.DB.Queryx(sql)
@@ -8,6 +8,7 @@ import (
 
 	kitlog "github.com/go-kit/kit/log"
 	"github.com/guregu/null"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
@@ -48,7 +49,7 @@ func (s *ThingsSuite) TestRoundTrip() {
 
 	thing := &postgres.Thing{
 		UID:             null.StringFrom("abc123"),
-		OwnerID:         userID,
+		OwnerID:         null.IntFrom(userID),
 		Provider:        null.StringFrom("parrot"),
 		SerialNum:       "PA123",
 		Longitude:       12.2,
@@ -99,6 +100,55 @@ func (s *ThingsSuite) TestRoundTrip() {
 	assert.Equal(s.T(), "new nickname", readThing.Nickname.String)
 }
 
+func (s *ThingsSuite) TestHardwareHistory() {
+	var userID int64
+
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ('user1') RETURNING id`)
+	assert.Nil(s.T(), err)
+
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	thing := &postgres.Thing{
+		UID:        null.StringFrom("abc123"),
+		OwnerID:    null.IntFrom(userID),
+		SerialNum:  "PA123",
+		LocationID: "loc1",
+		Hardware: postgres.Hardware{
+			FirmwareVersion: null.StringFrom("2016-09-14_hawaii-2.0.3_hardware-config-MP"),
+			SensorType:      null.StringFrom("flower-power"),
+			IsIndoor:        null.BoolFrom(false),
+			PlantIDs:        pq.Int64Array{2518},
+		},
+	}
+
+	err = s.db.CreateThing(ctx, thing)
+	assert.Nil(s.T(), err)
+
+	readThing, err := s.db.GetThing(ctx, "loc1")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), thing.Hardware, readThing.Hardware)
+
+	// saving unchanged hardware doesn't add to the history
+	err = s.db.UpdateHardware(ctx, "loc1", &thing.Hardware)
+	assert.Nil(s.T(), err)
+
+	hardware := thing.Hardware
+	hardware.IsIndoor = null.BoolFrom(true)
+
+	err = s.db.UpdateHardware(ctx, "loc1", &hardware)
+	assert.Nil(s.T(), err)
+
+	history, err := s.db.GetHardwareHistory(ctx, "abc123")
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), history, 2)
+	assert.Equal(s.T(), null.BoolFrom(false), history[0].IsIndoor)
+	assert.Equal(s.T(), null.BoolFrom(true), history[1].IsIndoor)
+	assert.Equal(s.T(), "abc123", history[1].ThingUID)
+
+	_, err = s.db.GetHardwareHistory(ctx, "unknown")
+	assert.NotNil(s.T(), err)
+}
+
 func (s *ThingsSuite) TestGetUnknownThing() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
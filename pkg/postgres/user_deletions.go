@@ -0,0 +1,364 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// UserDeletionQueue is the name of the queue used for jobs that delete or
+	// anonymise a user's data
+	UserDeletionQueue = "user_deletion"
+
+	// DeletionPurge is the deletion mode that removes a user's things from
+	// Thingful and then removes everything we store about them
+	DeletionPurge = "purge"
+
+	// DeletionAnonymise is the deletion mode that keeps the data of a user's
+	// things, but coarsens their coordinates, blanks their nicknames and
+	// detaches them from the user before removing the user
+	DeletionAnonymise = "anonymise"
+
+	// DeletionPending is the state of a deletion that hasn't been attempted yet
+	DeletionPending = "pending"
+
+	// DeletionRunning is the state of a deletion that has been attempted but
+	// not yet completed, e.g. because Thingful was unavailable
+	DeletionRunning = "running"
+
+	// DeletionCompleted is the state of a deletion that has completed
+	DeletionCompleted = "completed"
+
+	// DeletionFailed is the state of a deletion that has used all of its
+	// attempts without completing
+	DeletionFailed = "failed"
+
+	// anonymisedCoordinatePlaces is the number of decimal places we round the
+	// coordinates of anonymised things to, one place being roughly 11km
+	anonymisedCoordinatePlaces = 1
+)
+
+// UserDeletionPayload is the payload of a job on the UserDeletionQueue
+type UserDeletionPayload struct {
+	DeletionID int64 `json:"deletionId"`
+}
+
+// UserDeletion is a request to delete or anonymise a user's data. The UIDs of
+// the user's things are captured when the deletion is requested, as anonymised
+// things are no longer linked to the user.
+type UserDeletion struct {
+	ID          int64          `db:"id"`
+	UserUID     string         `db:"user_uid"`
+	Mode        string         `db:"mode"`
+	ThingUIDs   pq.StringArray `db:"thing_uids"`
+	CreatedAt   time.Time      `db:"created_at"`
+	CompletedAt null.Time      `db:"completed_at"`
+	Attempts    null.Int       `db:"attempts"`
+	LastError   null.String    `db:"last_error"`
+	FailedAt    null.Time      `db:"failed_at"`
+}
+
+// State returns the state of the deletion, which is one of pending, running,
+// completed or failed
+func (u *UserDeletion) State() string {
+	switch {
+	case u.CompletedAt.Valid:
+		return DeletionCompleted
+	case u.FailedAt.Valid:
+		return DeletionFailed
+	case u.Attempts.Int64 > 0:
+		return DeletionRunning
+	default:
+		return DeletionPending
+	}
+}
+
+// IsValidDeletionMode returns true if the mode is one we support
+func IsValidDeletionMode(mode string) bool {
+	return mode == DeletionPurge || mode == DeletionAnonymise
+}
+
+// RequestUserDeletion records a request to delete the user with the given UID
+// in the given mode, and enqueues a job to carry it out. The user's identities
+// are removed immediately so that we stop indexing them and no longer hold
+// their tokens. Clients can unwrap the returned error to check for an
+// sql.ErrNoRows error to determine if no such user exists, or a ClientError if
+// the mode is invalid or a deletion of the user is already in progress.
+func (d *DB) RequestUserDeletion(ctx context.Context, userUID, mode string) (*UserDeletion, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "requesting user deletion", "userUID", userUID, "mode", mode)
+	}
+
+	if !IsValidDeletionMode(mode) {
+		return nil, errors.Wrap(ClientError, "mode must be one of purge or anonymise")
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+
+	var userID int64
+	err = tx.Get(&userID, `SELECT id FROM users WHERE uid = $1 FOR UPDATE`, userUID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to load user")
+	}
+
+	var inProgress bool
+	err = tx.Get(&inProgress, `SELECT EXISTS (
+		SELECT 1 FROM user_deletions ud
+		JOIN jobs j ON j.id = ud.job_id
+		WHERE ud.user_uid = $1
+		AND ud.completed_at IS NULL
+		AND j.failed_at IS NULL
+	)`, userUID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to check for deletions in progress")
+	}
+
+	if inProgress {
+		tx.Rollback()
+		return nil, errors.Wrap(ClientError, "a deletion of the user is already in progress")
+	}
+
+	var deletion UserDeletion
+
+	sqlQuery := `INSERT INTO user_deletions (user_uid, mode, thing_uids)
+		VALUES ($1, $2, ARRAY(SELECT uid FROM things WHERE owner_id = $3 ORDER BY uid))
+		RETURNING id, user_uid, mode, thing_uids, created_at, completed_at`
+
+	err = tx.Get(&deletion, sqlQuery, userUID, mode, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to insert user deletion")
+	}
+
+	jobID, err := enqueueJob(tx, UserDeletionQueue, &UserDeletionPayload{DeletionID: deletion.ID})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE user_deletions SET job_id = $2 WHERE id = $1`, deletion.ID, jobID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to link user deletion to job")
+	}
+
+	_, err = tx.Exec(`DELETE FROM identities WHERE owner_id = $1`, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to delete identities")
+	}
+
+	deletion.Attempts = null.IntFrom(0)
+
+	return &deletion, tx.Commit()
+}
+
+// selectUserDeletion is the query used to read deletions along with the
+// progress of their jobs
+const selectUserDeletion = `SELECT ud.id, ud.user_uid, ud.mode, ud.thing_uids, ud.created_at,
+		ud.completed_at, j.attempts, j.last_error, j.failed_at
+	FROM user_deletions ud
+	LEFT JOIN jobs j ON j.id = ud.job_id`
+
+// GetUserDeletion returns the deletion with the given ID. Clients can unwrap
+// the returned error to check for an sql.ErrNoRows error to determine if no
+// such deletion exists.
+func (d *DB) GetUserDeletion(ctx context.Context, id int64) (*UserDeletion, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting user deletion", "deletionID", id)
+	}
+
+	var deletion UserDeletion
+
+	err := d.DB.Get(&deletion, selectUserDeletion+` WHERE ud.id = $1`, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load user deletion")
+	}
+
+	return &deletion, nil
+}
+
+// GetLatestUserDeletion returns the most recently requested deletion of the
+// user with the given UID. Clients can unwrap the returned error to check for
+// an sql.ErrNoRows error to determine if the user's deletion was never
+// requested.
+func (d *DB) GetLatestUserDeletion(ctx context.Context, userUID string) (*UserDeletion, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting latest user deletion", "userUID", userUID)
+	}
+
+	var deletion UserDeletion
+
+	err := d.DB.Get(&deletion, selectUserDeletion+` WHERE ud.user_uid = $1 ORDER BY ud.id DESC LIMIT 1`, userUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load user deletion")
+	}
+
+	return &deletion, nil
+}
+
+// CollectUserDeletionThings adds any things the user of the deletion still owns
+// to the things of the deletion, in case the indexer created them while the
+// deletion was being requested
+func (d *DB) CollectUserDeletionThings(ctx context.Context, deletion *UserDeletion) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "collecting user deletion things", "deletionID", deletion.ID)
+	}
+
+	sqlQuery := `UPDATE user_deletions ud SET thing_uids = ARRAY(
+		SELECT uid FROM unnest(ud.thing_uids) AS uid
+		UNION
+		SELECT t.uid FROM things t JOIN users u ON u.id = t.owner_id WHERE u.uid = ud.user_uid
+		ORDER BY uid
+	)
+	WHERE ud.id = $1
+	RETURNING ud.thing_uids`
+
+	err := d.DB.Get(&deletion.ThingUIDs, sqlQuery, deletion.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to collect user deletion things")
+	}
+
+	return nil
+}
+
+// PurgeUser removes the things of the deletion along with everything recorded
+// about them, and then the user. Safe to call again after it has succeeded.
+func (d *DB) PurgeUser(ctx context.Context, deletion *UserDeletion) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "purging user", "userUID", deletion.UserUID, "things", len(deletion.ThingUIDs))
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	// channels, location, hardware and plant status history and alerts are
+	// removed along with the things
+	_, err = tx.Exec(`DELETE FROM things WHERE uid = ANY($1)`, deletion.ThingUIDs)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to delete things")
+	}
+
+	err = deleteUser(tx, deletion)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AnonymiseUser coarsens the coordinates and blanks the nicknames of the things
+// of the deletion, detaching them from the user, and then removes the user. The
+// location history of the things is removed as it holds their precise
+// coordinates. Safe to call again after it has succeeded.
+func (d *DB) AnonymiseUser(ctx context.Context, deletion *UserDeletion) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "anonymising user", "userUID", deletion.UserUID, "things", len(deletion.ThingUIDs))
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	sqlQuery := `UPDATE things SET
+		long = ROUND(long::numeric, $2),
+		lat = ROUND(lat::numeric, $2),
+		nickname = '',
+		owner_id = NULL,
+		updated_at = NOW() AT TIME ZONE 'utc'
+	WHERE uid = ANY($1)`
+
+	_, err = tx.Exec(sqlQuery, deletion.ThingUIDs, anonymisedCoordinatePlaces)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to anonymise things")
+	}
+
+	// this runs after the update so also removes the change it recorded
+	_, err = tx.Exec(`DELETE FROM location_changes WHERE thing_id IN (SELECT id FROM things WHERE uid = ANY($1))`, deletion.ThingUIDs)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to delete location history")
+	}
+
+	err = deleteUser(tx, deletion)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteUser removes the user of the deletion, along with their identities and
+// alert rules, any buffered stream events of their things, and the webhook
+// deliveries of events about the user or their things. Deliveries hold the
+// user's UID and the exact coordinates of their things, and any still pending
+// are dropped by the delivery job once removed.
+func deleteUser(tx *sqlx.Tx, deletion *UserDeletion) error {
+	_, err := tx.Exec(`DELETE FROM stream_events WHERE thing_uid = ANY($1)`, deletion.ThingUIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete stream events")
+	}
+
+	sqlQuery := `DELETE FROM webhook_deliveries
+	WHERE payload->'data'->>'userUid' = $1
+	OR payload->'data'->>'thingUid' = ANY($2)`
+
+	_, err = tx.Exec(sqlQuery, deletion.UserUID, deletion.ThingUIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete webhook deliveries")
+	}
+
+	_, err = tx.Exec(`DELETE FROM users WHERE uid = $1`, deletion.UserUID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete user")
+	}
+
+	return nil
+}
+
+// CompleteUserDeletion records that the deletion has been carried out
+func (d *DB) CompleteUserDeletion(ctx context.Context, id int64) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "completing user deletion", "deletionID", id)
+	}
+
+	_, err := d.DB.Exec(`UPDATE user_deletions SET completed_at = NOW() WHERE id = $1 AND completed_at IS NULL`, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to complete user deletion")
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+func TestUserDeletionState(t *testing.T) {
+	now := time.Now()
+
+	testcases := []struct {
+		label    string
+		deletion postgres.UserDeletion
+		expected string
+	}{
+		{
+			label:    "not attempted",
+			deletion: postgres.UserDeletion{Attempts: null.IntFrom(0)},
+			expected: postgres.DeletionPending,
+		},
+		{
+			label:    "retrying",
+			deletion: postgres.UserDeletion{Attempts: null.IntFrom(2), LastError: null.StringFrom("Thingful unavailable")},
+			expected: postgres.DeletionRunning,
+		},
+		{
+			label:    "completed",
+			deletion: postgres.UserDeletion{Attempts: null.IntFrom(1), CompletedAt: null.TimeFrom(now)},
+			expected: postgres.DeletionCompleted,
+		},
+		{
+			label:    "out of attempts",
+			deletion: postgres.UserDeletion{Attempts: null.IntFrom(10), FailedAt: null.TimeFrom(now)},
+			expected: postgres.DeletionFailed,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.deletion.State())
+		})
+	}
+}
+
+type UserDeletionsSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *UserDeletionsSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *UserDeletionsSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+// createUser creates a user with an identity and a single thing
+func (s *UserDeletionsSuite) createUser(ctx context.Context, uid, thingUID string) {
+	var userID int64
+	err := s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ($1) RETURNING id`, uid)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.DB.Exec(`INSERT INTO identities (owner_id, access_token) VALUES ($1, 'access')`, userID)
+	assert.Nil(s.T(), err)
+
+	err = s.db.CreateThing(ctx, &postgres.Thing{
+		UID:        null.StringFrom(thingUID),
+		OwnerID:    null.IntFrom(userID),
+		Provider:   null.StringFrom("parrot"),
+		SerialNum:  "PA" + thingUID,
+		LocationID: "loc" + thingUID,
+		Nickname:   null.StringFrom("Back garden"),
+		Longitude:  -0.123456,
+		Latitude:   51.987654,
+	})
+	assert.Nil(s.T(), err)
+}
+
+// publishEvents registers a webhook subscribed to every event about users and
+// their things, and publishes an event about the user and their thing, as well
+// as an alert about the thing which only identifies the thing. It returns the
+// UID of the app owning the webhook.
+func (s *UserDeletionsSuite) publishEvents(ctx context.Context, uid, thingUID string) string {
+	app, err := s.db.CreateApp(ctx, "app "+uid, postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{
+		postgres.UserIndexedEvent,
+		postgres.ThingCreatedEvent,
+		postgres.AlertFiringEvent,
+	})
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishWebhookEvent(ctx, postgres.UserIndexedEvent, &postgres.UserEventData{UserUID: uid})
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishWebhookEvent(ctx, postgres.ThingCreatedEvent, &postgres.ThingEventData{
+		ThingUID:  thingUID,
+		UserUID:   uid,
+		Longitude: -0.123456,
+		Latitude:  51.987654,
+	})
+	assert.Nil(s.T(), err)
+
+	var appID int64
+	err = s.db.DB.Get(&appID, `SELECT id FROM applications WHERE uid = $1`, app.UID)
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishAppWebhookEvent(ctx, appID, postgres.AlertFiringEvent, map[string]string{"thingUid": thingUID})
+	assert.Nil(s.T(), err)
+
+	return app.UID
+}
+
+// countDeliveries returns the number of deliveries in the log of the app
+func (s *UserDeletionsSuite) countDeliveries(ctx context.Context, appUID string) int {
+	deliveries, err := s.db.ListWebhookDeliveries(ctx, appUID, &postgres.WebhookDeliveryFilter{})
+	assert.Nil(s.T(), err)
+	return len(deliveries)
+}
+
+func (s *UserDeletionsSuite) TestRequestUserDeletion() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	s.createUser(ctx, "user1", "abc123")
+
+	_, err := s.db.RequestUserDeletion(ctx, "user1", "shred")
+	assert.Equal(s.T(), postgres.ClientError, errors.Cause(err))
+
+	_, err = s.db.RequestUserDeletion(ctx, "unknown", postgres.DeletionPurge)
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	deletion, err := s.db.RequestUserDeletion(ctx, "user1", postgres.DeletionPurge)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"abc123"}, []string(deletion.ThingUIDs))
+	assert.Equal(s.T(), postgres.DeletionPending, deletion.State())
+
+	// only one deletion may be in progress
+	_, err = s.db.RequestUserDeletion(ctx, "user1", postgres.DeletionAnonymise)
+	assert.Equal(s.T(), postgres.ClientError, errors.Cause(err))
+
+	var identities int
+	err = s.db.DB.Get(&identities, `SELECT COUNT(*) FROM identities`)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, identities)
+
+	job, err := s.db.NextJob(ctx, []string{postgres.UserDeletionQueue})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), job)
+	assert.JSONEq(s.T(), `{"deletionId":`+strconv.FormatInt(deletion.ID, 10)+`}`, string(job.Payload))
+
+	latest, err := s.db.GetLatestUserDeletion(ctx, "user1")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), deletion.ID, latest.ID)
+	assert.Equal(s.T(), postgres.DeletionRunning, latest.State())
+
+	_, err = s.db.GetLatestUserDeletion(ctx, "user2")
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+}
+
+func (s *UserDeletionsSuite) TestPurgeUser() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	s.createUser(ctx, "user1", "abc123")
+	s.createUser(ctx, "user2", "def456")
+
+	app1 := s.publishEvents(ctx, "user1", "abc123")
+	app2 := s.publishEvents(ctx, "user2", "def456")
+
+	deletion, err := s.db.RequestUserDeletion(ctx, "user1", postgres.DeletionPurge)
+	assert.Nil(s.T(), err)
+
+	err = s.db.CollectUserDeletionThings(ctx, deletion)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"abc123"}, []string(deletion.ThingUIDs))
+
+	err = s.db.PurgeUser(ctx, deletion)
+	assert.Nil(s.T(), err)
+
+	// safe to repeat
+	err = s.db.PurgeUser(ctx, deletion)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.GetThingByUID(ctx, "abc123")
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	_, err = s.db.GetThingByUID(ctx, "def456")
+	assert.Nil(s.T(), err)
+
+	var users []string
+	err = s.db.DB.Select(&users, `SELECT uid FROM users`)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"user2"}, users)
+
+	// only deliveries about the user and their things are removed, from the
+	// webhooks of every app, leaving the user and thing events of user2 sent to
+	// both apps and the alert of user2's thing
+	assert.Equal(s.T(), 2, s.countDeliveries(ctx, app1))
+	assert.Equal(s.T(), 3, s.countDeliveries(ctx, app2))
+
+	err = s.db.CompleteUserDeletion(ctx, deletion.ID)
+	assert.Nil(s.T(), err)
+
+	deletion, err = s.db.GetUserDeletion(ctx, deletion.ID)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), postgres.DeletionCompleted, deletion.State())
+}
+
+func (s *UserDeletionsSuite) TestAnonymiseUser() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	s.createUser(ctx, "user1", "abc123")
+
+	app := s.publishEvents(ctx, "user1", "abc123")
+
+	deletion, err := s.db.RequestUserDeletion(ctx, "user1", postgres.DeletionAnonymise)
+	assert.Nil(s.T(), err)
+
+	err = s.db.AnonymiseUser(ctx, deletion)
+	assert.Nil(s.T(), err)
+
+	thing, err := s.db.GetThingByUID(ctx, "abc123")
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), thing.OwnerID.Valid)
+	assert.Equal(s.T(), "", thing.Nickname.String)
+	assert.Equal(s.T(), -0.1, thing.Longitude)
+	assert.Equal(s.T(), 52.0, thing.Latitude)
+
+	history, err := s.db.GetLocationHistory(ctx, "abc123")
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), history, 0)
+
+	var users int
+	err = s.db.DB.Get(&users, `SELECT COUNT(*) FROM users`)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, users)
+
+	assert.Equal(s.T(), 0, s.countDeliveries(ctx, app))
+
+	// things are still found once detached from the user
+	err = s.db.CollectUserDeletionThings(ctx, deletion)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"abc123"}, []string(deletion.ThingUIDs))
+}
+
+func TestUserDeletionsSuite(t *testing.T) {
+	suite.Run(t, new(UserDeletionsSuite))
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 
+	"github.com/guregu/null"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
@@ -71,6 +72,85 @@ func (d *DB) SaveUser(ctx context.Context, user *User) (int64, error) {
 	return userID, tx.Commit()
 }
 
+// UpdateCredentials replaces the Parrot tokens of an existing user, for example
+// after they have logged in again. Any revoked or failed state is cleared by
+// marking the identity as never indexed, which also makes it the next to be
+// indexed. Clients can unwrap the returned error to check for an sql.ErrNoRows
+// error if the user doesn't exist, or a ClientError if the tokens belong to a
+// different Parrot account.
+func (d *DB) UpdateCredentials(ctx context.Context, user *User) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "updating credentials", "uid", user.UID, "parrotID", user.ParrotID)
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to open transaction")
+	}
+
+	var existing struct {
+		ID       int64       `db:"id"`
+		ParrotID null.String `db:"parrot_id"`
+	}
+
+	err = tx.Get(&existing, `SELECT id, parrot_id FROM users WHERE uid = $1 FOR UPDATE`, user.UID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to load user")
+	}
+
+	if existing.ParrotID.Valid && existing.ParrotID.String != user.ParrotID {
+		tx.Rollback()
+		return errors.Wrap(ClientError, "credentials are for a different Parrot account")
+	}
+
+	if !existing.ParrotID.Valid {
+		_, err = tx.Exec(`UPDATE users SET parrot_id = $1 WHERE id = $2`, user.ParrotID, existing.ID)
+		if err != nil {
+			tx.Rollback()
+			if pqerr, ok := err.(*pq.Error); ok && pqerr.Code == UniqueViolationError {
+				return errors.Wrap(ClientError, "credentials are for a different user's Parrot account")
+			}
+			return errors.Wrap(err, "failed to set parrot id")
+		}
+	}
+
+	sqlQuery := `UPDATE identities SET
+		access_token = $1,
+		refresh_token = $2,
+		revoked_at = NULL,
+		indexed_at = NULL
+	WHERE owner_id = $3`
+
+	result, err := tx.Exec(sqlQuery, user.AccessToken, user.RefreshToken, existing.ID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to update identity")
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to count updated identities")
+	}
+
+	// a user whose identity has been removed gets a new one
+	if updated == 0 {
+		_, err = tx.Exec(
+			`INSERT INTO identities (owner_id, auth_provider, access_token, refresh_token) VALUES ($1, $2, $3, $4)`,
+			existing.ID, user.Provider, user.AccessToken, user.RefreshToken,
+		)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "failed to insert identity")
+		}
+	}
+
+	return tx.Commit()
+}
+
 // DeleteUser attempts to delete the user identified by the supplied uid
 func (d *DB) DeleteUser(ctx context.Context, uid string) error {
 	log := logger.FromContext(ctx)
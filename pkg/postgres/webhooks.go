@@ -0,0 +1,645 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/thingful/kudzu/pkg/logger"
+)
+
+const (
+	// WebhookDeliveryQueue is the name of the queue used for jobs that deliver
+	// an event to a webhook
+	WebhookDeliveryQueue = "webhook_delivery"
+
+	// UserIndexedEvent is sent when all locations of a user have been indexed
+	UserIndexedEvent = "user.indexed"
+
+	// ThingCreatedEvent is sent when a new sensor is indexed for the first time
+	ThingCreatedEvent = "thing.created"
+
+	// ThingLocationChangedEvent is sent when an app moves a sensor
+	ThingLocationChangedEvent = "thing.location_changed"
+
+	// ThingWentStaleEvent is sent when a sensor has not reported for 30 days
+	ThingWentStaleEvent = "thing.went_stale"
+
+	// IdentityRevokedEvent is sent when Parrot rejects the access token of a
+	// user, so their data can no longer be indexed
+	IdentityRevokedEvent = "identity.revoked"
+
+	// AlertFiringEvent is sent when one of the app's alert rules starts firing
+	AlertFiringEvent = "alert.firing"
+
+	// AlertResolvedEvent is sent when a firing alert of the app is resolved
+	AlertResolvedEvent = "alert.resolved"
+
+	// DeliveryStatusPending is the status of deliveries still being attempted
+	DeliveryStatusPending = "pending"
+
+	// DeliveryStatusDelivered is the status of successful deliveries
+	DeliveryStatusDelivered = "delivered"
+
+	// DeliveryStatusFailed is the status of deliveries we have given up on
+	DeliveryStatusFailed = "failed"
+
+	// MaxWebhooksPerApp is the number of webhooks a single app may register
+	MaxWebhooksPerApp = 10
+
+	// MaxWebhookDeliveries is the maximum number of deliveries returned when
+	// listing the delivery log
+	MaxWebhookDeliveries = 100
+)
+
+var (
+	// webhookEvents is a map of the events webhooks may subscribe to
+	webhookEvents = map[string]string{
+		UserIndexedEvent:          "All locations of a user have been indexed",
+		ThingCreatedEvent:         "A new sensor has been indexed",
+		ThingLocationChangedEvent: "A sensor has been moved",
+		ThingWentStaleEvent:       "A sensor has not reported for 30 days",
+		IdentityRevokedEvent:      "The access token of a user was rejected by Parrot",
+		AlertFiringEvent:          "An alert rule of the app started firing",
+		AlertResolvedEvent:        "A firing alert of the app was resolved",
+	}
+
+	// activeAppCondition selects apps whose keys have been neither revoked nor
+	// expired. It expects the applications table to be aliased as a.
+	activeAppCondition = "a.revoked_at IS NULL AND (a.expires_at IS NULL OR a.expires_at > NOW())"
+
+	// deliveryStatusConditions maps each delivery status to the condition that
+	// selects it. They expect the webhook_deliveries table to be aliased as wd.
+	deliveryStatusConditions = map[string]string{
+		DeliveryStatusPending:   "wd.delivered_at IS NULL AND wd.failed_at IS NULL",
+		DeliveryStatusDelivered: "wd.delivered_at IS NOT NULL",
+		DeliveryStatusFailed:    "wd.failed_at IS NOT NULL",
+	}
+)
+
+// IsValidWebhookEvent returns true if webhooks may subscribe to the given event
+func IsValidWebhookEvent(event string) bool {
+	_, ok := webhookEvents[event]
+	return ok
+}
+
+// IsValidDeliveryStatus returns true if the given value is a delivery status
+// we are able to filter by
+func IsValidDeliveryStatus(status string) bool {
+	_, ok := deliveryStatusConditions[status]
+	return ok
+}
+
+// Webhook is a URL registered by an app to which we send events
+type Webhook struct {
+	ID        int64          `db:"id"`
+	UID       string         `db:"uid"`
+	AppUID    string         `db:"app_uid"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"`
+	Events    pq.StringArray `db:"events"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+// WebhookDelivery is a single event sent to a webhook. The payload is the body
+// we post to the webhook, which is the same for every attempt.
+type WebhookDelivery struct {
+	ID          int64       `db:"id"`
+	UID         string      `db:"uid"`
+	WebhookID   int64       `db:"webhook_id"`
+	WebhookUID  string      `db:"webhook_uid"`
+	Event       string      `db:"event"`
+	Payload     []byte      `db:"payload"`
+	Attempts    int         `db:"attempts"`
+	StatusCode  null.Int    `db:"status_code"`
+	LastError   null.String `db:"last_error"`
+	CreatedAt   time.Time   `db:"created_at"`
+	AttemptedAt null.Time   `db:"attempted_at"`
+	DeliveredAt null.Time   `db:"delivered_at"`
+	FailedAt    null.Time   `db:"failed_at"`
+}
+
+// Status returns whether the delivery is pending, delivered or failed
+func (w *WebhookDelivery) Status() string {
+	if w.DeliveredAt.Valid {
+		return DeliveryStatusDelivered
+	}
+
+	if w.FailedAt.Valid {
+		return DeliveryStatusFailed
+	}
+
+	return DeliveryStatusPending
+}
+
+// WebhookDeliveryPayload is the payload of a job on the WebhookDeliveryQueue
+type WebhookDeliveryPayload struct {
+	DeliveryID int64 `json:"deliveryId"`
+}
+
+// WebhookDeliveryFilter restricts the deliveries returned when listing the
+// delivery log of an app. Empty fields are ignored.
+type WebhookDeliveryFilter struct {
+	WebhookUID string
+	Event      string
+	Status     string
+}
+
+// UserEventData is the data sent with events about a user
+type UserEventData struct {
+	UserUID string `json:"userUid"`
+}
+
+// ThingEventData is the data sent with events about a thing
+type ThingEventData struct {
+	ThingUID   string    `json:"thingUid"`
+	UserUID    string    `json:"userUid"`
+	Longitude  float64   `json:"longitude"`
+	Latitude   float64   `json:"latitude"`
+	LastSample null.Time `json:"lastSample"`
+}
+
+// webhookEnvelope is the body posted to webhooks
+type webhookEnvelope struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// CreateWebhook registers a new webhook for the app subscribed to the given
+// events, generating the secret used to sign deliveries. Apps may register at
+// most 10 webhooks.
+func (d *DB) CreateWebhook(ctx context.Context, appUID, url string, events []string) (*Webhook, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "creating webhook", "appUID", appUID, "url", url, "events", fmt.Sprintf("%v", events))
+	}
+
+	for _, event := range events {
+		if !IsValidWebhookEvent(event) {
+			return nil, errors.Wrap(ClientError, fmt.Sprintf("unknown event: %s", event))
+		}
+	}
+
+	uid, err := randomUID(10)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create random UID when creating webhook")
+	}
+
+	b, err := randomBytes(keyLength * 2)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get random bytes when creating webhook")
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+
+	var appID int64
+
+	// lock the app so concurrent requests can't exceed the limit
+	err = tx.Get(&appID, `SELECT id FROM applications WHERE uid = $1 FOR UPDATE`, appUID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to load app")
+	}
+
+	var count int
+	err = tx.Get(&count, `SELECT COUNT(*) FROM webhooks WHERE app_id = $1`, appID)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to count webhooks")
+	}
+
+	if count >= MaxWebhooksPerApp {
+		tx.Rollback()
+		return nil, errors.Wrap(ClientError, fmt.Sprintf("an app may register at most %d webhooks", MaxWebhooksPerApp))
+	}
+
+	webhook := &Webhook{
+		UID:    uid,
+		AppUID: appUID,
+		URL:    url,
+		Secret: fmt.Sprintf("%x", b),
+		Events: pq.StringArray(events),
+	}
+
+	sql := `INSERT INTO webhooks (uid, app_id, url, secret, events)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err = tx.QueryRowx(sql, webhook.UID, appID, webhook.URL, webhook.Secret, webhook.Events).Scan(&webhook.ID, &webhook.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to insert webhook")
+	}
+
+	return webhook, tx.Commit()
+}
+
+// ListWebhooks returns the webhooks registered by the app in the order they
+// were created
+func (d *DB) ListWebhooks(ctx context.Context, appUID string) ([]Webhook, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "listing webhooks", "appUID", appUID)
+	}
+
+	sql := `SELECT w.id, w.uid, a.uid AS app_uid, w.url, w.secret, w.events, w.created_at
+		FROM webhooks w
+		JOIN applications a ON a.id = w.app_id
+		WHERE a.uid = $1
+		ORDER BY w.id`
+
+	webhooks := []Webhook{}
+
+	err := d.DB.Select(&webhooks, sql, appUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list webhooks")
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes the app's webhook with the given uid along with its
+// delivery log. Clients can unwrap the returned error to check for an
+// sql.ErrNoRows error to determine if the webhook does not exist.
+func (d *DB) DeleteWebhook(ctx context.Context, appUID, uid string) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "deleting webhook", "appUID", appUID, "uid", uid)
+	}
+
+	sql := `DELETE FROM webhooks w
+		USING applications a
+		WHERE a.id = w.app_id AND a.uid = $1 AND w.uid = $2
+		RETURNING w.id`
+
+	var id int64
+
+	err := d.DB.Get(&id, sql, appUID, uid)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete webhook")
+	}
+
+	return nil
+}
+
+// GetWebhookDelivery returns the delivery with the given id along with the
+// webhook it is for. Clients can unwrap the returned error to check for an
+// sql.ErrNoRows error to determine if the delivery no longer exists, i.e. the
+// webhook has been deleted.
+func (d *DB) GetWebhookDelivery(ctx context.Context, id int64) (*WebhookDelivery, *Webhook, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "getting webhook delivery", "id", id)
+	}
+
+	var delivery WebhookDelivery
+
+	sql := `SELECT wd.id, wd.uid, wd.webhook_id, w.uid AS webhook_uid, wd.event, wd.payload,
+			wd.attempts, wd.status_code, wd.last_error, wd.created_at, wd.attempted_at,
+			wd.delivered_at, wd.failed_at
+		FROM webhook_deliveries wd
+		JOIN webhooks w ON w.id = wd.webhook_id
+		WHERE wd.id = $1`
+
+	err := d.DB.Get(&delivery, sql, id)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get webhook delivery")
+	}
+
+	var webhook Webhook
+
+	sql = `SELECT w.id, w.uid, a.uid AS app_uid, w.url, w.secret, w.events, w.created_at
+		FROM webhooks w
+		JOIN applications a ON a.id = w.app_id
+		WHERE w.id = $1`
+
+	err = d.DB.Get(&webhook, sql, delivery.WebhookID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get webhook")
+	}
+
+	return &delivery, &webhook, nil
+}
+
+// RecordWebhookAttempt records the outcome of an attempt to deliver the event.
+// A nil error marks the delivery as delivered, otherwise if this was the final
+// attempt the delivery is marked as failed. The status code is 0 if no
+// response was received.
+func (d *DB) RecordWebhookAttempt(ctx context.Context, id int64, statusCode int, deliveryErr error, final bool) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "recording webhook attempt", "id", id, "statusCode", statusCode, "err", deliveryErr)
+	}
+
+	var lastError null.String
+	if deliveryErr != nil {
+		lastError = null.StringFrom(deliveryErr.Error())
+	}
+
+	var code null.Int
+	if statusCode != 0 {
+		code = null.IntFrom(int64(statusCode))
+	}
+
+	sql := `UPDATE webhook_deliveries SET
+		attempts = attempts + 1,
+		attempted_at = NOW(),
+		status_code = $2,
+		last_error = $3,
+		delivered_at = CASE WHEN $3::text IS NULL THEN NOW() END,
+		failed_at = CASE WHEN $3::text IS NOT NULL AND $4 THEN NOW() END
+	WHERE id = $1`
+
+	_, err := d.DB.Exec(sql, id, code, lastError, final)
+	if err != nil {
+		return errors.Wrap(err, "failed to record webhook attempt")
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries returns the most recent deliveries to the app's
+// webhooks matching the filter, newest first
+func (d *DB) ListWebhookDeliveries(ctx context.Context, appUID string, filter *WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log(
+			"msg", "listing webhook deliveries",
+			"appUID", appUID,
+			"webhookUID", filter.WebhookUID,
+			"event", filter.Event,
+			"status", filter.Status,
+		)
+	}
+
+	sql := `SELECT wd.id, wd.uid, wd.webhook_id, w.uid AS webhook_uid, wd.event, wd.payload,
+			wd.attempts, wd.status_code, wd.last_error, wd.created_at, wd.attempted_at,
+			wd.delivered_at, wd.failed_at
+		FROM webhook_deliveries wd
+		JOIN webhooks w ON w.id = wd.webhook_id
+		JOIN applications a ON a.id = w.app_id
+		WHERE a.uid = $1
+		AND ($2 = '' OR w.uid = $2)
+		AND ($3 = '' OR wd.event = $3)`
+
+	if condition, ok := deliveryStatusConditions[filter.Status]; ok {
+		sql = sql + ` AND ` + condition
+	}
+
+	sql = sql + ` ORDER BY wd.id DESC LIMIT $4`
+
+	deliveries := []WebhookDelivery{}
+
+	err := d.DB.Select(&deliveries, sql, appUID, filter.WebhookUID, filter.Event, MaxWebhookDeliveries)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// PublishWebhookEvent creates a delivery of the event for every webhook
+// subscribed to it, and enqueues a job to send each one
+func (d *DB) PublishWebhookEvent(ctx context.Context, event string, data interface{}) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "publishing webhook event", "event", event)
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	err = publishWebhookEvent(tx, event, data)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PublishAppWebhookEvent creates a delivery of the event for each of the
+// webhooks of a single app that are subscribed to it, and enqueues a job to
+// send each one. Nothing is sent if the app no longer holds the webhooks scope
+// or its key has been revoked or has expired.
+func (d *DB) PublishAppWebhookEvent(ctx context.Context, appID int64, event string, data interface{}) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "publishing app webhook event", "appID", appID, "event", event)
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	var webhookIDs []int64
+
+	sql := `SELECT w.id
+		FROM webhooks w
+		JOIN applications a ON a.id = w.app_id
+		WHERE w.app_id = $1
+		AND $2 = ANY(w.events)
+		AND $3 = ANY(a.scope)
+		AND ` + activeAppCondition + `
+		ORDER BY w.id`
+
+	err = tx.Select(&webhookIDs, sql, appID, event, ManageWebhooksScope)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to find webhooks subscribed to event")
+	}
+
+	err = createWebhookDeliveries(tx, webhookIDs, event, data)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// publishWebhookEvent publishes the event within an existing transaction, so
+// that it is only sent if the change it describes is committed.
+//
+// Users are not owned by any one app, so events about users and their things
+// are sent to the webhooks of every app entitled to read them through the API:
+// apps holding both the webhooks and metadata scopes whose keys have not been
+// revoked or expired.
+func publishWebhookEvent(tx *sqlx.Tx, event string, data interface{}) error {
+	var webhookIDs []int64
+
+	sql := `SELECT w.id
+		FROM webhooks w
+		JOIN applications a ON a.id = w.app_id
+		WHERE $1 = ANY(w.events)
+		AND $2 = ANY(a.scope)
+		AND $3 = ANY(a.scope)
+		AND ` + activeAppCondition + `
+		ORDER BY w.id`
+
+	err := tx.Select(&webhookIDs, sql, event, ManageWebhooksScope, GetMetadataScope)
+	if err != nil {
+		return errors.Wrap(err, "failed to find webhooks subscribed to event")
+	}
+
+	return createWebhookDeliveries(tx, webhookIDs, event, data)
+}
+
+// createWebhookDeliveries inserts a delivery of the event for each of the given
+// webhooks, and enqueues a job to send each one
+func createWebhookDeliveries(tx *sqlx.Tx, webhookIDs []int64, event string, data interface{}) error {
+	for _, webhookID := range webhookIDs {
+		uid, err := randomUID(20)
+		if err != nil {
+			return errors.Wrap(err, "failed to create random UID for webhook delivery")
+		}
+
+		b, err := json.Marshal(&webhookEnvelope{
+			ID:        uid,
+			Event:     event,
+			CreatedAt: time.Now().UTC(),
+			Data:      data,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal webhook payload")
+		}
+
+		var deliveryID int64
+
+		err = tx.Get(
+			&deliveryID,
+			`INSERT INTO webhook_deliveries (uid, webhook_id, event, payload) VALUES ($1, $2, $3, $4) RETURNING id`,
+			uid, webhookID, event, string(b),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to insert webhook delivery")
+		}
+
+		_, err = enqueueJob(tx, WebhookDeliveryQueue, &WebhookDeliveryPayload{DeliveryID: deliveryID})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarkStaleThings records the things that have gone stale since we last
+// checked, publishing an event for each, and clears the mark from things that
+// have started reporting again. Returns the number of things that went stale.
+func (d *DB) MarkStaleThings(ctx context.Context) (int, error) {
+	log := logger.FromContext(ctx)
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to begin transaction")
+	}
+
+	_, err = tx.Exec(`UPDATE things t SET stale_at = NULL WHERE t.stale_at IS NOT NULL AND ` + liveCondition)
+	if err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "failed to clear stale things")
+	}
+
+	sql := `UPDATE things t SET stale_at = NOW()
+		FROM users u
+		WHERE u.id = t.owner_id
+		AND t.stale_at IS NULL
+		AND t.uid IS NOT NULL
+		AND t.last_sample < NOW() - interval '30 days'
+		RETURNING t.uid AS thing_uid, u.uid AS user_uid, t.long, t.lat, t.last_sample`
+
+	rows := []struct {
+		ThingUID   string    `db:"thing_uid"`
+		UserUID    string    `db:"user_uid"`
+		Longitude  float64   `db:"long"`
+		Latitude   float64   `db:"lat"`
+		LastSample null.Time `db:"last_sample"`
+	}{}
+
+	err = tx.Select(&rows, sql)
+	if err != nil {
+		tx.Rollback()
+		return 0, errors.Wrap(err, "failed to mark stale things")
+	}
+
+	for _, row := range rows {
+		err = publishWebhookEvent(tx, ThingWentStaleEvent, &ThingEventData{
+			ThingUID:   row.ThingUID,
+			UserUID:    row.UserUID,
+			Longitude:  row.Longitude,
+			Latitude:   row.Latitude,
+			LastSample: row.LastSample,
+		})
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if d.verbose && len(rows) > 0 {
+		log.Log("msg", "marked stale things", "count", len(rows))
+	}
+
+	return len(rows), tx.Commit()
+}
+
+// RevokeIdentity records that the access token of the user with the given id
+// has been rejected, publishing an event the first time this happens
+func (d *DB) RevokeIdentity(ctx context.Context, ownerID int64) error {
+	log := logger.FromContext(ctx)
+
+	if d.verbose {
+		log.Log("msg", "revoking identity", "ownerID", ownerID)
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	sql := `UPDATE identities i SET revoked_at = NOW()
+		FROM users u
+		WHERE u.id = i.owner_id AND i.owner_id = $1 AND i.revoked_at IS NULL
+		RETURNING u.uid`
+
+	uids := []string{}
+
+	err = tx.Select(&uids, sql, ownerID)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to revoke identity")
+	}
+
+	for _, uid := range uids {
+		err = publishWebhookEvent(tx, IdentityRevokedEvent, &UserEventData{UserUID: uid})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,268 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/guregu/null"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/postgres/helper"
+)
+
+type WebhooksSuite struct {
+	suite.Suite
+	db     *postgres.DB
+	logger kitlog.Logger
+}
+
+func (s *WebhooksSuite) SetupTest() {
+	logger := kitlog.NewNopLogger()
+	connStr := os.Getenv("KUDZU_DATABASE_URL")
+
+	s.db = helper.PrepareDB(s.T(), connStr, logger)
+	s.logger = logger
+}
+
+func (s *WebhooksSuite) TearDownTest() {
+	helper.CleanDB(s.T(), s.db)
+}
+
+func (s *WebhooksSuite) TestCreateListDelete() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	webhook, err := s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.ThingCreatedEvent})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), webhook.UID, 10)
+	assert.Len(s.T(), webhook.Secret, 64)
+
+	webhooks, err := s.db.ListWebhooks(ctx, app.UID)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), webhooks, 1)
+	assert.Equal(s.T(), "https://example.com/hook", webhooks[0].URL)
+	assert.Equal(s.T(), []string{postgres.ThingCreatedEvent}, []string(webhooks[0].Events))
+
+	err = s.db.DeleteWebhook(ctx, "other", webhook.UID)
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+
+	err = s.db.DeleteWebhook(ctx, app.UID, webhook.UID)
+	assert.Nil(s.T(), err)
+
+	webhooks, err = s.db.ListWebhooks(ctx, app.UID)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), webhooks, 0)
+}
+
+func (s *WebhooksSuite) TestCreateInvalid() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{"thing.exploded"})
+	assert.Equal(s.T(), postgres.ClientError, errors.Cause(err))
+
+	for i := 0; i < postgres.MaxWebhooksPerApp; i++ {
+		_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.UserIndexedEvent})
+		assert.Nil(s.T(), err)
+	}
+
+	_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.UserIndexedEvent})
+	assert.Equal(s.T(), postgres.ClientError, errors.Cause(err))
+}
+
+func (s *WebhooksSuite) TestPublishAndDeliver() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	webhook, err := s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.UserIndexedEvent})
+	assert.Nil(s.T(), err)
+
+	// not subscribed so no delivery is created
+	err = s.db.PublishWebhookEvent(ctx, postgres.ThingCreatedEvent, &postgres.ThingEventData{ThingUID: "abc123"})
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishWebhookEvent(ctx, postgres.UserIndexedEvent, &postgres.UserEventData{UserUID: "user1"})
+	assert.Nil(s.T(), err)
+
+	job, err := s.db.NextJob(ctx, []string{postgres.WebhookDeliveryQueue})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), job)
+
+	var payload postgres.WebhookDeliveryPayload
+	err = json.Unmarshal(job.Payload, &payload)
+	assert.Nil(s.T(), err)
+
+	delivery, readWebhook, err := s.db.GetWebhookDelivery(ctx, payload.DeliveryID)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), webhook.Secret, readWebhook.Secret)
+	assert.Equal(s.T(), webhook.UID, delivery.WebhookUID)
+	assert.Equal(s.T(), postgres.DeliveryStatusPending, delivery.Status())
+
+	var body map[string]interface{}
+	err = json.Unmarshal(delivery.Payload, &body)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), delivery.UID, body["id"])
+	assert.Equal(s.T(), postgres.UserIndexedEvent, body["event"])
+	assert.Equal(s.T(), map[string]interface{}{"userUid": "user1"}, body["data"])
+
+	err = s.db.RecordWebhookAttempt(ctx, delivery.ID, 500, errors.New("unexpected response: 500"), false)
+	assert.Nil(s.T(), err)
+
+	deliveries, err := s.db.ListWebhookDeliveries(ctx, app.UID, &postgres.WebhookDeliveryFilter{Status: postgres.DeliveryStatusPending})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), deliveries, 1)
+	assert.Equal(s.T(), 1, deliveries[0].Attempts)
+	assert.Equal(s.T(), null.IntFrom(500), deliveries[0].StatusCode)
+
+	err = s.db.RecordWebhookAttempt(ctx, delivery.ID, 200, nil, false)
+	assert.Nil(s.T(), err)
+
+	deliveries, err = s.db.ListWebhookDeliveries(ctx, app.UID, &postgres.WebhookDeliveryFilter{Status: postgres.DeliveryStatusDelivered})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), deliveries, 1)
+	assert.Equal(s.T(), 2, deliveries[0].Attempts)
+	assert.False(s.T(), deliveries[0].LastError.Valid)
+
+	deliveries, err = s.db.ListWebhookDeliveries(ctx, app.UID, &postgres.WebhookDeliveryFilter{Event: postgres.ThingCreatedEvent})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), deliveries, 0)
+
+	// deleting the webhook removes its deliveries
+	err = s.db.DeleteWebhook(ctx, app.UID, webhook.UID)
+	assert.Nil(s.T(), err)
+
+	_, _, err = s.db.GetWebhookDelivery(ctx, delivery.ID)
+	assert.Equal(s.T(), sql.ErrNoRows, errors.Cause(err))
+}
+
+func (s *WebhooksSuite) TestMarkStaleThings() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.ThingWentStaleEvent})
+	assert.Nil(s.T(), err)
+
+	var userID int64
+	err = s.db.DB.Get(&userID, `INSERT INTO users (uid) VALUES ('user1') RETURNING id`)
+	assert.Nil(s.T(), err)
+
+	old := time.Now().Add(-40 * 24 * time.Hour)
+
+	err = s.db.CreateThing(ctx, &postgres.Thing{
+		UID:           null.StringFrom("abc123"),
+		OwnerID:       null.IntFrom(userID),
+		Provider:      null.StringFrom("parrot"),
+		SerialNum:     "PA123",
+		LastSampleUTC: null.TimeFrom(old),
+		LocationID:    "abc123",
+	})
+	assert.Nil(s.T(), err)
+
+	count, err := s.db.MarkStaleThings(ctx)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, count)
+
+	// already marked so not announced again
+	count, err = s.db.MarkStaleThings(ctx)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, count)
+
+	deliveries, err := s.db.ListWebhookDeliveries(ctx, app.UID, &postgres.WebhookDeliveryFilter{})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), deliveries, 1)
+	assert.Equal(s.T(), postgres.ThingWentStaleEvent, deliveries[0].Event)
+}
+
+func (s *WebhooksSuite) TestRevokeIdentity() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	app, err := s.db.CreateApp(ctx, "app", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.IdentityRevokedEvent})
+	assert.Nil(s.T(), err)
+
+	userID, err := s.db.SaveUser(ctx, &postgres.User{
+		UID:         "user1",
+		ParrotID:    "parrot1",
+		AccessToken: "token",
+		Provider:    "parrot",
+	})
+	assert.Nil(s.T(), err)
+
+	err = s.db.RevokeIdentity(ctx, userID)
+	assert.Nil(s.T(), err)
+
+	err = s.db.RevokeIdentity(ctx, userID)
+	assert.Nil(s.T(), err)
+
+	deliveries, err := s.db.ListWebhookDeliveries(ctx, app.UID, &postgres.WebhookDeliveryFilter{Event: postgres.IdentityRevokedEvent})
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), deliveries, 1)
+}
+
+func (s *WebhooksSuite) TestPublishOnlyToEntitledApps() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	entitled, err := s.db.CreateApp(ctx, "entitled", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	withoutMetadata, err := s.db.CreateApp(ctx, "without metadata", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	withoutWebhooks, err := s.db.CreateApp(ctx, "without webhooks", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	revoked, err := s.db.CreateApp(ctx, "revoked", postgres.ScopeClaims{postgres.GetMetadataScope, postgres.ManageWebhooksScope}, 0, 0)
+	assert.Nil(s.T(), err)
+
+	for _, app := range []*postgres.App{entitled, withoutMetadata, withoutWebhooks, revoked} {
+		_, err = s.db.CreateWebhook(ctx, app.UID, "https://example.com/hook", []string{postgres.UserIndexedEvent})
+		assert.Nil(s.T(), err)
+	}
+
+	// scopes changed after the webhooks were registered still apply
+	_, err = s.db.UpdateApp(ctx, withoutMetadata.UID, &postgres.AppUpdate{Scope: postgres.ScopeClaims{postgres.ManageWebhooksScope}})
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.UpdateApp(ctx, withoutWebhooks.UID, &postgres.AppUpdate{Scope: postgres.ScopeClaims{postgres.GetMetadataScope}})
+	assert.Nil(s.T(), err)
+
+	_, err = s.db.RevokeApp(ctx, revoked.UID)
+	assert.Nil(s.T(), err)
+
+	err = s.db.PublishWebhookEvent(ctx, postgres.UserIndexedEvent, &postgres.UserEventData{UserUID: "user1"})
+	assert.Nil(s.T(), err)
+
+	for _, app := range []*postgres.App{entitled, withoutMetadata, withoutWebhooks, revoked} {
+		expected := 0
+		if app == entitled {
+			expected = 1
+		}
+
+		deliveries, err := s.db.ListWebhookDeliveries(ctx, app.UID, &postgres.WebhookDeliveryFilter{})
+		assert.Nil(s.T(), err)
+		assert.Len(s.T(), deliveries, expected, app.Name)
+	}
+}
+
+func TestWebhooksSuite(t *testing.T) {
+	suite.Run(t, new(WebhooksSuite))
+}
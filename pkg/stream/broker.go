@@ -0,0 +1,241 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	registry "github.com/thingful/retryable-registry-prometheus"
+
+	"github.com/thingful/kudzu/pkg/logger"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+const (
+	// subscriptionBuffer is the number of events buffered for each subscriber.
+	// Subscribers that fall further behind than this are disconnected, and
+	// replay the events they missed when they reconnect.
+	subscriptionBuffer = 32
+
+	// pingInterval is how often we check the health of the listener connection
+	// when no notifications have been received
+	pingInterval = 90 * time.Second
+)
+
+var (
+	subscribersGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "grow",
+			Name:      "stream_subscribers_gauge",
+			Help:      "A count of clients currently subscribed to the event stream",
+		},
+	)
+)
+
+func init() {
+	registry.MustRegister(subscribersGauge)
+}
+
+// Config is a state holder we pass in to the broker to configure it.
+type Config struct {
+	DB        *postgres.DB
+	QuitChan  <-chan struct{}
+	ErrChan   chan<- error
+	WaitGroup *sync.WaitGroup
+	Verbose   bool
+}
+
+// Subscription receives the events published for a set of things. The Events
+// channel is closed when the subscription ends, either because the client
+// unsubscribed, fell too far behind, or the broker was stopped.
+type Subscription struct {
+	Events <-chan *postgres.StreamEvent
+
+	events chan *postgres.StreamEvent
+	things map[string]bool
+}
+
+// Broker is an in-process broker that receives events published by any
+// replica via Postgres notifications, and forwards them to the subscriptions
+// of connected clients.
+type Broker struct {
+	*Config
+	logger kitlog.Logger
+
+	mu            sync.Mutex
+	subscriptions map[*Subscription]bool
+}
+
+// NewBroker returns a new Broker instance ready to start work.
+func NewBroker(config *Config, logger kitlog.Logger) *Broker {
+	logger = kitlog.With(logger, "module", "stream")
+
+	return &Broker{
+		Config:        config,
+		logger:        logger,
+		subscriptions: map[*Subscription]bool{},
+	}
+}
+
+// Start starts the broker listening for events until the quit channel is
+// closed. Requires the DB to be started.
+func (b *Broker) Start() {
+	b.logger.Log("msg", "starting stream broker")
+
+	ctx := logger.ToContext(context.Background(), b.logger)
+
+	listener, err := b.DB.Listen(postgres.StreamEventsChannel)
+	if err != nil {
+		b.ErrChan <- err
+		b.WaitGroup.Done()
+		return
+	}
+
+	lastID, err := b.DB.LastStreamEventID(ctx)
+	if err != nil {
+		b.logger.Log("msg", "error getting last stream event id", "err", err)
+	}
+
+	ticker := time.NewTicker(pingInterval)
+
+	for {
+		select {
+		case n := <-listener.NotificationChannel():
+			if n == nil {
+				// the connection was re-established, so we may have missed
+				// notifications in the meantime
+				b.logger.Log("msg", "stream listener reconnected")
+				lastID = b.catchUp(ctx, lastID)
+				continue
+			}
+
+			id, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				b.logger.Log("msg", "invalid stream event notification", "payload", n.Extra)
+				continue
+			}
+
+			event, err := b.DB.GetStreamEvent(ctx, id)
+			if err != nil {
+				b.logger.Log("msg", "error getting stream event", "id", id, "err", err)
+				continue
+			}
+
+			b.Broadcast(event)
+
+			if id > lastID {
+				lastID = id
+			}
+		case <-ticker.C:
+			go listener.Ping()
+		case <-b.QuitChan:
+			b.logger.Log("msg", "stopping stream broker")
+			ticker.Stop()
+			listener.Close()
+			b.closeAll()
+			b.WaitGroup.Done()
+			return
+		}
+	}
+}
+
+// catchUp broadcasts any events published after the given id, returning the
+// id of the last event seen
+func (b *Broker) catchUp(ctx context.Context, lastID int64) int64 {
+	for {
+		events, err := b.DB.ListStreamEvents(ctx, lastID, nil)
+		if err != nil {
+			b.logger.Log("msg", "error listing missed stream events", "err", err)
+			return lastID
+		}
+
+		if len(events) == 0 {
+			return lastID
+		}
+
+		for _, event := range events {
+			b.Broadcast(event)
+			lastID = event.ID
+		}
+	}
+}
+
+// Subscribe returns a new subscription to events for the given things
+func (b *Broker) Subscribe(thingUIDs []string) *Subscription {
+	events := make(chan *postgres.StreamEvent, subscriptionBuffer)
+
+	sub := &Subscription{
+		Events: events,
+		events: events,
+		things: map[string]bool{},
+	}
+
+	for _, uid := range thingUIDs {
+		sub.things[uid] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions[sub] = true
+	subscribersGauge.Inc()
+
+	if b.Verbose {
+		b.logger.Log("msg", "added stream subscription", "things", len(thingUIDs))
+	}
+
+	return sub
+}
+
+// Unsubscribe ends the subscription. It is safe to call this more than once.
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(sub)
+}
+
+// Broadcast sends the event to every subscription to its thing. Subscriptions
+// whose buffer is full are ended rather than blocking other subscribers.
+func (b *Broker) Broadcast(event *postgres.StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscriptions {
+		if !sub.things[event.ThingUID] {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			b.logger.Log("msg", "dropping slow stream subscriber")
+			b.remove(sub)
+		}
+	}
+}
+
+// closeAll ends all current subscriptions
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscriptions {
+		b.remove(sub)
+	}
+}
+
+// remove deletes the subscription and closes its channel. Must be called with
+// the lock held.
+func (b *Broker) remove(sub *Subscription) {
+	if !b.subscriptions[sub] {
+		return
+	}
+
+	delete(b.subscriptions, sub)
+	close(sub.events)
+	subscribersGauge.Dec()
+}
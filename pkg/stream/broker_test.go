@@ -0,0 +1,50 @@
+package stream_test
+
+import (
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/postgres"
+	"github.com/thingful/kudzu/pkg/stream"
+)
+
+func TestBroadcast(t *testing.T) {
+	broker := stream.NewBroker(&stream.Config{}, kitlog.NewNopLogger())
+
+	a := broker.Subscribe([]string{"abc123"})
+	b := broker.Subscribe([]string{"abc123", "def456"})
+
+	broker.Broadcast(&postgres.StreamEvent{ID: 1, ThingUID: "def456"})
+	broker.Broadcast(&postgres.StreamEvent{ID: 2, ThingUID: "abc123"})
+
+	assert.Equal(t, int64(2), (<-a.Events).ID)
+	assert.Equal(t, int64(1), (<-b.Events).ID)
+	assert.Equal(t, int64(2), (<-b.Events).ID)
+
+	broker.Unsubscribe(a)
+	broker.Unsubscribe(a)
+
+	_, ok := <-a.Events
+	assert.False(t, ok)
+}
+
+func TestBroadcastSlowSubscriber(t *testing.T) {
+	broker := stream.NewBroker(&stream.Config{}, kitlog.NewNopLogger())
+
+	sub := broker.Subscribe([]string{"abc123"})
+
+	// a subscriber that never reads is eventually disconnected rather than
+	// blocking the broker
+	for i := 0; i < 100; i++ {
+		broker.Broadcast(&postgres.StreamEvent{ID: int64(i), ThingUID: "abc123"})
+	}
+
+	count := 0
+	for range sub.Events {
+		count++
+	}
+
+	assert.True(t, count < 100)
+}
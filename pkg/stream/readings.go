@@ -0,0 +1,55 @@
+package stream
+
+import (
+	"context"
+	"time"
+
+	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/postgres"
+)
+
+// ReadingsEvent is the payload of the event published whenever a window of
+// readings for a thing has been uploaded to Thingful
+type ReadingsEvent struct {
+	ThingUID string    `json:"thingUid"`
+	Readings []Reading `json:"readings"`
+}
+
+// Reading is the value of each channel of a thing at a single point in time,
+// keyed by channel id
+type Reading struct {
+	RecordedAt time.Time          `json:"recordedAt"`
+	Values     map[string]float64 `json:"values"`
+}
+
+// PublishReadings publishes an event containing the given readings to clients
+// subscribed to the thing, whichever replica they are connected to
+func PublishReadings(ctx context.Context, db *postgres.DB, thingUID string, readings []flowerpower.Reading) error {
+	return db.PublishStreamEvent(ctx, thingUID, NewReadingsEvent(thingUID, readings))
+}
+
+// NewReadingsEvent returns the event for the given readings. Channels are
+// named as they are on Thingful.
+func NewReadingsEvent(thingUID string, readings []flowerpower.Reading) *ReadingsEvent {
+	event := &ReadingsEvent{
+		ThingUID: thingUID,
+		Readings: make([]Reading, 0, len(readings)),
+	}
+
+	for _, r := range readings {
+		event.Readings = append(event.Readings, Reading{
+			RecordedAt: r.Timestamp.UTC(),
+			Values: map[string]float64{
+				"air_temperature":          r.AirTemperature,
+				"fertilizer_level":         r.FertilizerLevel,
+				"light":                    r.Light,
+				"soil_moisture":            r.SoilMoisture,
+				"calibrated_soil_moisture": r.CalibratedSoilMoisture,
+				"water_tank_level":         r.WaterTankLevel,
+				"battery_level":            r.BatteryLevel,
+			},
+		})
+	}
+
+	return event
+}
@@ -0,0 +1,40 @@
+package stream_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thingful/kudzu/pkg/flowerpower"
+	"github.com/thingful/kudzu/pkg/stream"
+)
+
+func TestNewReadingsEvent(t *testing.T) {
+	ts := time.Date(2019, 6, 8, 9, 30, 0, 0, time.UTC)
+
+	event := stream.NewReadingsEvent("abc123", []flowerpower.Reading{
+		{
+			Timestamp:              ts,
+			Light:                  0.5,
+			FertilizerLevel:        1.5,
+			AirTemperature:         12.2,
+			SoilMoisture:           24.1,
+			BatteryLevel:           80,
+			CalibratedSoilMoisture: 23.7,
+		},
+	})
+
+	assert.Equal(t, "abc123", event.ThingUID)
+	assert.Len(t, event.Readings, 1)
+	assert.Equal(t, ts, event.Readings[0].RecordedAt)
+	assert.Equal(t, map[string]float64{
+		"air_temperature":          12.2,
+		"fertilizer_level":         1.5,
+		"light":                    0.5,
+		"soil_moisture":            24.1,
+		"calibrated_soil_moisture": 23.7,
+		"water_tank_level":         0,
+		"battery_level":            80,
+	}, event.Readings[0].Values)
+}
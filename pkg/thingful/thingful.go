@@ -22,7 +22,25 @@ import (
 	"github.com/thingful/thingfulx/schema"
 )
 
+const (
+	// republishWindow is the interval of observations read back from Thingful
+	// and sent again in a single request when republishing a thing's history
+	republishWindow = 10 * 24 * time.Hour
+)
+
 var (
+	// channelBuilders returns the channel we publish for each of the channel
+	// ids of a thing, given its observations
+	channelBuilders = map[string]func([]thingfulx.Observation) channel{
+		"air_temperature":          airTemperatureChannel,
+		"fertilizer_level":         fertilizerChannel,
+		"light":                    lightChannel,
+		"soil_moisture":            soilChannel,
+		"calibrated_soil_moisture": calibratedSoilChannel,
+		"water_tank_level":         waterChannel,
+		"battery_level":            batteryChannel,
+	}
+
 	channelsCount = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "grow",
@@ -173,16 +191,7 @@ func (t *Thingful) CreateThing(ctx context.Context, th *postgres.Thing, readings
 					URL:         fmt.Sprintf("https://api-flower-power-pot.parrot.com/sensor_data/v6/sample/location/%s", th.LocationID),
 					ContentType: "application/json",
 				},
-				Metadata: []thingfulx.Metadata{
-					{
-						Prop: "schema:serialNumber",
-						Val:  th.SerialNum,
-					},
-					{
-						Prop: "sem:hasEndTimeStamp",
-						Val:  th.LastSampleUTC.Time.Format(time.RFC3339),
-					},
-				},
+				Metadata:  buildMetadata(th),
 				ThingType: schema.Expand("thingful:ConnectedDevice"),
 				Location: &thingfulx.Location{
 					Lng: th.Longitude,
@@ -235,6 +244,84 @@ func (t *Thingful) UpdateThing(ctx context.Context, th *postgres.Thing, readings
 		)
 	}
 
+	return t.patchThing(ctx, th, buildChannels(readings, th.Longitude, th.Latitude))
+}
+
+// RepublishObservations sends every observation of the thing back to Thingful
+// at the thing's current coordinates, working through its uploaded history in
+// windows of ten days. Observations are published along with the coordinates
+// of the thing at the time, so this is used once a thing has been anonymised
+// to replace the exact coordinates its earlier observations were sent with.
+func (t *Thingful) RepublishObservations(ctx context.Context, th *postgres.Thing) error {
+	log := logger.FromContext(ctx)
+
+	if !th.FirstSampleUTC.Valid || !th.LastUploadedUTC.Valid {
+		return nil
+	}
+
+	location := &thingfulx.Location{
+		Lng: th.Longitude,
+		Lat: th.Latitude,
+	}
+
+	for from := th.FirstSampleUTC.Time; !from.After(th.LastUploadedUTC.Time); from = from.Add(republishWindow) {
+		to := from.Add(republishWindow)
+		if to.After(th.LastUploadedUTC.Time) {
+			to = th.LastUploadedUTC.Time
+		}
+
+		if t.verbose {
+			log.Log(
+				"msg", "republishing observations to Thingful",
+				"uid", th.UID.String,
+				"from", from,
+				"to", to,
+			)
+		}
+
+		data, err := t.GetData(ctx, []string{th.UID.String}, from, to, true)
+		if err != nil {
+			return errors.Wrap(err, "failed to read observations to republish")
+		}
+
+		channels := []channel{}
+
+		for _, d := range data {
+			for _, c := range d.Attributes.Channels {
+				build, ok := channelBuilders[path.Base(c.ID)]
+				if !ok || len(c.Observations) == 0 {
+					continue
+				}
+
+				obs := []thingfulx.Observation{}
+				for _, o := range c.Observations {
+					obs = append(obs, thingfulx.Observation{
+						RecordedAt: o.RecordedAt,
+						Location:   location,
+						Val:        o.Value,
+					})
+				}
+
+				channels = append(channels, build(obs))
+			}
+		}
+
+		if len(channels) == 0 {
+			continue
+		}
+
+		err = t.patchThing(ctx, th, channels)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchThing sends the title and location of the thing to Thingful, along with
+// the given channels and their observations
+func (t *Thingful) patchThing(ctx context.Context, th *postgres.Thing, channels []channel) error {
 	req := &updateRequest{
 		Data: &updateData{
 			Type: "thing",
@@ -245,7 +332,7 @@ func (t *Thingful) UpdateThing(ctx context.Context, th *postgres.Thing, readings
 					Lng: th.Longitude,
 					Lat: th.Latitude,
 				},
-				Channels: buildChannels(readings, th.Longitude, th.Latitude),
+				Channels: channels,
 			},
 		},
 	}
@@ -266,6 +353,69 @@ func (t *Thingful) UpdateThing(ctx context.Context, th *postgres.Thing, readings
 	return nil
 }
 
+// DeleteThing sends a DELETE request to the Thingful API to remove a Thing
+// along with all of its observations. A Thing that no longer exists is not an
+// error, so deleting is safe to retry.
+func (t *Thingful) DeleteThing(ctx context.Context, uid string) error {
+	log := logger.FromContext(ctx)
+
+	if t.verbose {
+		log.Log(
+			"msg", "deleting thing from Thingful",
+			"uid", uid,
+		)
+	}
+
+	err := t.client.Delete(ctx, fmt.Sprintf("%s/things/%s", t.apiBase, uid), t.apiKey)
+	if err != nil {
+		if err == client.NotFoundError {
+			return nil
+		}
+
+		thingfulErrorCount.With(prometheus.Labels{"operation": "delete"}).Inc()
+		return errors.Wrap(err, "failed to delete thing")
+	}
+
+	return nil
+}
+
+// buildMetadata returns the metadata we publish for a thing, including those
+// parts of its hardware that are useful when interpreting its readings
+func buildMetadata(th *postgres.Thing) []thingfulx.Metadata {
+	metadata := []thingfulx.Metadata{
+		{
+			Prop: "schema:serialNumber",
+			Val:  th.SerialNum,
+		},
+		{
+			Prop: "sem:hasEndTimeStamp",
+			Val:  th.LastSampleUTC.Time.Format(time.RFC3339),
+		},
+	}
+
+	if th.SensorType.Valid {
+		metadata = append(metadata, thingfulx.Metadata{Prop: "thingful:sensorType", Val: th.SensorType.String})
+	}
+
+	if th.FirmwareVersion.Valid {
+		metadata = append(metadata, thingfulx.Metadata{Prop: "thingful:firmwareVersion", Val: th.FirmwareVersion.String})
+	}
+
+	if th.HardwareRevision.Valid {
+		metadata = append(metadata, thingfulx.Metadata{Prop: "thingful:hardwareRevision", Val: th.HardwareRevision.String})
+	}
+
+	if th.IsIndoor.Valid {
+		metadata = append(metadata, thingfulx.Metadata{Prop: "thingful:isIndoor", Val: strconv.FormatBool(th.IsIndoor.Bool)})
+	}
+
+	if th.InPot.Valid {
+		metadata = append(metadata, thingfulx.Metadata{Prop: "thingful:inPot", Val: strconv.FormatBool(th.InPot.Bool)})
+	}
+
+	return metadata
+}
+
 // buildChannels builds a slice of our custom channel type ready for sending to Thingful
 func buildChannels(readings []flowerpower.Reading, long, lat float64) []channel {
 	airObs := []thingfulx.Observation{}
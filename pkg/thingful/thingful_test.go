@@ -2,6 +2,7 @@ package thingful_test
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -88,6 +89,70 @@ func (s *ThingfulSuite) TestCreateThing() {
 	assert.Nil(s.T(), simular.AllStubsCalled())
 }
 
+func (s *ThingfulSuite) TestCreateThingHardwareMetadata() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	createResponseBytes, err := ioutil.ReadFile("./testdata/create_response.json")
+	assert.Nil(s.T(), err)
+
+	simular.ActivateNonDefault(s.httpClient.Client)
+	defer simular.DeactivateAndReset()
+
+	var body []byte
+
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			"POST",
+			"https://thingful.net/things",
+			func(req *http.Request) (*http.Response, error) {
+				body, err = ioutil.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				return simular.NewBytesResponder(200, createResponseBytes)(req)
+			},
+		),
+	)
+
+	postgresThing := &postgres.Thing{
+		Nickname:  null.StringFrom("Plant 1"),
+		SerialNum: "PA1234",
+		Hardware: postgres.Hardware{
+			FirmwareVersion: null.StringFrom("2016-09-14_hawaii-2.0.3_hardware-config-MP"),
+			SensorType:      null.StringFrom("flower-power"),
+			IsIndoor:        null.BoolFrom(true),
+		},
+	}
+
+	_, err = s.thingful.CreateThing(ctx, postgresThing, []flowerpower.Reading{})
+	assert.Nil(s.T(), err)
+
+	var req struct {
+		Data struct {
+			Attributes struct {
+				Metadata []struct {
+					Prop string `json:"prop"`
+					Val  string `json:"val"`
+				} `json:"metadata"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	err = json.Unmarshal(body, &req)
+	assert.Nil(s.T(), err)
+
+	metadata := map[string]string{}
+	for _, m := range req.Data.Attributes.Metadata {
+		metadata[m.Prop] = m.Val
+	}
+
+	assert.Equal(s.T(), "PA1234", metadata["schema:serialNumber"])
+	assert.Equal(s.T(), "flower-power", metadata["thingful:sensorType"])
+	assert.Equal(s.T(), "2016-09-14_hawaii-2.0.3_hardware-config-MP", metadata["thingful:firmwareVersion"])
+	assert.Equal(s.T(), "true", metadata["thingful:isIndoor"])
+	assert.NotContains(s.T(), metadata, "thingful:inPot")
+}
+
 func (s *ThingfulSuite) TestGetData() {
 	ctx := logger.ToContext(context.Background(), s.logger)
 
@@ -134,6 +199,128 @@ func (s *ThingfulSuite) TestGetData() {
 	assert.Nil(s.T(), simular.AllStubsCalled())
 }
 
+func (s *ThingfulSuite) TestRepublishObservations() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	b, err := ioutil.ReadFile("./testdata/get_data_response1.json")
+	assert.Nil(s.T(), err)
+
+	simular.ActivateNonDefault(s.httpClient.Client)
+	defer simular.DeactivateAndReset()
+
+	var body []byte
+
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			"GET",
+			"https://thingful.net/things/6sk90442?from=2019-03-26T00:00:00Z&to=2019-03-27T00:00:00Z",
+			simular.NewBytesResponder(200, b),
+		),
+		simular.NewStubRequest(
+			"PATCH",
+			"https://thingful.net/things/6sk90442",
+			func(req *http.Request) (*http.Response, error) {
+				body, err = ioutil.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				return simular.NewBytesResponder(200, []byte{})(req)
+			},
+		),
+	)
+
+	from, _ := time.Parse(time.RFC3339, "2019-03-26T00:00:00Z")
+	to, _ := time.Parse(time.RFC3339, "2019-03-27T00:00:00Z")
+
+	err = s.thingful.RepublishObservations(ctx, &postgres.Thing{
+		UID:             null.StringFrom("6sk90442"),
+		Longitude:       -7.9,
+		Latitude:        55,
+		FirstSampleUTC:  null.TimeFrom(from),
+		LastUploadedUTC: null.TimeFrom(to),
+	})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), simular.AllStubsCalled())
+
+	var req struct {
+		Data struct {
+			Attributes struct {
+				Location map[string]float64 `json:"location"`
+				Channels []struct {
+					ID           string `json:"id"`
+					Observations []struct {
+						RecordedAt time.Time          `json:"recordedAt"`
+						Location   map[string]float64 `json:"location"`
+						Value      string             `json:"value"`
+					} `json:"observations"`
+				} `json:"channels"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	err = json.Unmarshal(body, &req)
+	assert.Nil(s.T(), err)
+
+	expected := map[string]float64{"long": -7.9, "lat": 55}
+
+	assert.Equal(s.T(), expected, req.Data.Attributes.Location)
+	assert.Len(s.T(), req.Data.Attributes.Channels, 7)
+
+	for _, c := range req.Data.Attributes.Channels {
+		assert.Len(s.T(), c.Observations, 2, c.ID)
+
+		for _, o := range c.Observations {
+			assert.Equal(s.T(), expected, o.Location, c.ID)
+		}
+	}
+
+	assert.Equal(s.T(), "calibrated_soil_moisture", req.Data.Attributes.Channels[0].ID)
+	assert.Equal(s.T(), "2019-03-26T00:19:29Z", req.Data.Attributes.Channels[0].Observations[0].RecordedAt.Format(time.RFC3339))
+	assert.Equal(s.T(), "29.6", req.Data.Attributes.Channels[0].Observations[0].Value)
+}
+
+func (s *ThingfulSuite) TestDeleteThing() {
+	ctx := logger.ToContext(context.Background(), s.logger)
+
+	simular.ActivateNonDefault(s.httpClient.Client)
+	defer simular.DeactivateAndReset()
+
+	simular.RegisterStubRequests(
+		simular.NewStubRequest(
+			"DELETE",
+			"https://thingful.net/things/6sk90442",
+			simular.NewBytesResponder(204, []byte{}),
+			simular.WithHeader(
+				&http.Header{
+					"Authorization": []string{"Bearer foobar"},
+				},
+			),
+		),
+		simular.NewStubRequest(
+			"DELETE",
+			"https://thingful.net/things/6sk90443",
+			simular.NewBytesResponder(404, []byte{}),
+		),
+		simular.NewStubRequest(
+			"DELETE",
+			"https://thingful.net/things/6sk90444",
+			simular.NewBytesResponder(500, []byte{}),
+		),
+	)
+
+	err := s.thingful.DeleteThing(ctx, "6sk90442")
+	assert.Nil(s.T(), err)
+
+	// already deleted
+	err = s.thingful.DeleteThing(ctx, "6sk90443")
+	assert.Nil(s.T(), err)
+
+	err = s.thingful.DeleteThing(ctx, "6sk90444")
+	assert.NotNil(s.T(), err)
+
+	assert.Nil(s.T(), simular.AllStubsCalled())
+}
+
 func TestThingfulClient(t *testing.T) {
 	suite.Run(t, new(ThingfulSuite))
 }